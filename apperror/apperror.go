@@ -0,0 +1,136 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an Error by what went wrong, which decides the HTTP status it is reported with.
+type Kind string
+
+const (
+	// KIND_VALIDATION indicates a request that is malformed or holds invalid values.
+	KIND_VALIDATION Kind = "validation"
+
+	// KIND_UNAUTHORIZED indicates a request without valid credentials.
+	KIND_UNAUTHORIZED Kind = "unauthorized"
+
+	// KIND_FORBIDDEN indicates a request whose credentials do not allow it.
+	KIND_FORBIDDEN Kind = "forbidden"
+
+	// KIND_NOT_FOUND indicates a request for a resource that does not exist.
+	KIND_NOT_FOUND Kind = "not_found"
+
+	// KIND_CONFLICT indicates a request that conflicts with the current state of a resource.
+	KIND_CONFLICT Kind = "conflict"
+
+	// KIND_GONE indicates a request for a resource that existed but has expired.
+	KIND_GONE Kind = "gone"
+
+	// KIND_PRECONDITION_FAILED indicates a conditional request whose precondition does not hold.
+	KIND_PRECONDITION_FAILED Kind = "precondition_failed"
+
+	// KIND_PRECONDITION_REQUIRED indicates a request that must be conditional but is not.
+	KIND_PRECONDITION_REQUIRED Kind = "precondition_required"
+
+	// KIND_TOO_LARGE indicates a request whose content exceeds a size limit.
+	KIND_TOO_LARGE Kind = "too_large"
+
+	// KIND_UNSUPPORTED_MEDIA_TYPE indicates a request whose content has a type that is not accepted.
+	KIND_UNSUPPORTED_MEDIA_TYPE Kind = "unsupported_media_type"
+
+	// KIND_UNPROCESSABLE indicates a well-formed request whose content cannot be processed.
+	KIND_UNPROCESSABLE Kind = "unprocessable"
+
+	// KIND_RATE_LIMITED indicates a request refused because too many were made.
+	KIND_RATE_LIMITED Kind = "rate_limited"
+
+	// KIND_UNAVAILABLE indicates a failure of a service the application depends on, such as an SMS gateway.
+	KIND_UNAVAILABLE Kind = "unavailable"
+
+	// KIND_INTERNAL indicates a failure of the application itself, such as a database outage.
+	KIND_INTERNAL Kind = "internal"
+)
+
+// statuses maps every Kind to the HTTP status it is reported with.
+var statuses = map[Kind]int{
+	KIND_VALIDATION:             http.StatusBadRequest,
+	KIND_UNAUTHORIZED:           http.StatusUnauthorized,
+	KIND_FORBIDDEN:              http.StatusForbidden,
+	KIND_NOT_FOUND:              http.StatusNotFound,
+	KIND_CONFLICT:               http.StatusConflict,
+	KIND_GONE:                   http.StatusGone,
+	KIND_PRECONDITION_FAILED:    http.StatusPreconditionFailed,
+	KIND_PRECONDITION_REQUIRED:  http.StatusPreconditionRequired,
+	KIND_TOO_LARGE:              http.StatusRequestEntityTooLarge,
+	KIND_UNSUPPORTED_MEDIA_TYPE: http.StatusUnsupportedMediaType,
+	KIND_UNPROCESSABLE:          http.StatusUnprocessableEntity,
+	KIND_RATE_LIMITED:           http.StatusTooManyRequests,
+	KIND_UNAVAILABLE:            http.StatusBadGateway,
+	KIND_INTERNAL:               http.StatusInternalServerError,
+}
+
+// Error is an application error with a Kind and a stable, machine-readable Code. Errors are declared once as
+// sentinels, compared with errors.Is and given details by wrapping them, such as with fmt.Errorf and %w.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+var (
+	// ErrInternal is reported in place of errors that are not an Error, so their details never reach clients.
+	ErrInternal = New(KIND_INTERNAL, "INTERNAL_ERROR", "internal server error")
+
+	// ErrValidation is reported for requests that fail validation, along with the fields that were refused.
+	ErrValidation = New(KIND_VALIDATION, "VALIDATION_FAILED", "request validation failed")
+)
+
+// New creates an Error of kind with the given code and message.
+func New(kind Kind, code string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Error returns the message of e.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Status returns the HTTP status e is reported with, 500 for unknown kinds.
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Kind]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Internal reports whether e is a failure on the server side, whose details must be logged rather than shown.
+func (e *Error) Internal() bool {
+	return e.Status() >= http.StatusInternalServerError
+}
+
+// From returns the first Error in the chain of err, or ErrInternal when there is none.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return ErrInternal
+}
+
+// PublicMessage returns the message of err to show clients. Client errors are shown with the details wrapped around
+// their Error, while internal errors are reduced to the message of their Error.
+func PublicMessage(err error) string {
+	appErr := From(err)
+	if appErr.Internal() {
+		return appErr.Message
+	}
+
+	return err.Error()
+}
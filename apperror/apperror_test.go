@@ -0,0 +1,93 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFrom tests finding the Error in the chain of an error, and the status and public message it is reported with.
+func TestFrom(t *testing.T) {
+	errNotFound := New(KIND_NOT_FOUND, "USER_NOT_FOUND", "user not found")
+	errFailed := New(KIND_INTERNAL, "UPDATE_USER_FAILED", "failed to update user")
+	errGateway := New(KIND_UNAVAILABLE, "SEND_FAILED", "failed to send")
+
+	tests := []struct {
+		name          string
+		err           error
+		expected      *Error
+		status        int
+		publicMessage string
+	}{
+		{
+			name:          "sentinel",
+			err:           errNotFound,
+			expected:      errNotFound,
+			status:        http.StatusNotFound,
+			publicMessage: "user not found",
+		},
+		{
+			name:          "wrapped client error keeps its details",
+			err:           fmt.Errorf("%w: id 42", errNotFound),
+			expected:      errNotFound,
+			status:        http.StatusNotFound,
+			publicMessage: "user not found: id 42",
+		},
+		{
+			name:          "wrapped internal error hides its cause",
+			err:           fmt.Errorf("%w: connection refused", errFailed),
+			expected:      errFailed,
+			status:        http.StatusInternalServerError,
+			publicMessage: "failed to update user",
+		},
+		{
+			name:          "joined gateway error hides its cause",
+			err:           errors.Join(errGateway, errors.New("token rejected")),
+			expected:      errGateway,
+			status:        http.StatusBadGateway,
+			publicMessage: "failed to send",
+		},
+		{
+			name:          "plain error",
+			err:           errors.New("pq: relation \"users\" does not exist"),
+			expected:      ErrInternal,
+			status:        http.StatusInternalServerError,
+			publicMessage: "internal server error",
+		},
+		{
+			name:          "unknown kind",
+			err:           New("unknown", "UNKNOWN", "unknown"),
+			status:        http.StatusInternalServerError,
+			publicMessage: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				appErr := From(tt.err)
+				if tt.expected != nil {
+					assert.Same(t, tt.expected, appErr)
+				}
+				assert.Equal(t, tt.status, appErr.Status())
+				assert.Equal(t, tt.publicMessage, PublicMessage(tt.err))
+			},
+		)
+	}
+}
+
+// TestStatuses tests that every kind is reported with its own status.
+func TestStatuses(t *testing.T) {
+	seen := map[int]Kind{}
+	for kind, status := range statuses {
+		assert.NotContains(t, seen, status, "%s and %s share a status", kind, seen[status])
+		seen[status] = kind
+	}
+
+	assert.Equal(t, http.StatusBadRequest, ErrValidation.Status())
+	assert.True(t, ErrInternal.Internal())
+	assert.False(t, ErrValidation.Internal())
+}
@@ -0,0 +1,294 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+const (
+	// EXIT_OK is the process exit code for a command that completed successfully.
+	EXIT_OK = 0
+
+	// EXIT_ERROR is the process exit code for a command that failed while running.
+	EXIT_ERROR = 1
+
+	// EXIT_USAGE is the process exit code for an unknown command, an unknown flag or missing arguments.
+	EXIT_USAGE = 2
+)
+
+// ErrUsage indicates that a command was invoked with invalid arguments; it makes the CLI print usage and exit with 2.
+var ErrUsage = errors.New("invalid usage")
+
+type (
+	// Command describes a CLI command with its flags, help text and either a Run function or nested subcommands.
+	Command struct {
+		Name        string
+		Usage       string
+		Short       string
+		Long        string
+		Flags       func(fs *flag.FlagSet)
+		Run         func(app *App, args []string) error
+		Subcommands []*Command
+	}
+
+	// App carries the dependency injector and output streams shared by every command during a CLI invocation.
+	// Serve is set by the serve command to tell the caller to start the HTTP server afterward.
+	App struct {
+		Injector *do.Injector
+		Out      io.Writer
+		Err      io.Writer
+		Serve    bool
+	}
+
+	// stringList is a repeatable string flag, e.g. --arg a=1 --arg b=2.
+	stringList []string
+)
+
+// String returns the flag values joined by commas.
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set appends a flag value.
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// usageError wraps a message as an ErrUsage error.
+func usageError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUsage, fmt.Sprintf(format, args...))
+}
+
+// DB resolves the main database connection from the injector.
+func (a *App) DB() *gorm.DB {
+	return do.MustInvokeNamed[*gorm.DB](a.Injector, constants.DB)
+}
+
+// Root builds the top-level command tree with every subcommand.
+var Root = func() *Command {
+	return &Command{
+		Name:  programName(),
+		Usage: "<command> [flags]",
+		Short: "Manage and run the application",
+		Subcommands: []*Command{
+			serveCommand(),
+			migrateCommand(),
+			seedCommand(),
+			scriptCommand(),
+			userCommand(),
+			tokenCommand(),
+			configCommand(),
+		},
+	}
+}
+
+// programName returns the executable name shown in help text.
+func programName() string {
+	if len(os.Args) == 0 {
+		return "app"
+	}
+
+	return filepath.Base(os.Args[0])
+}
+
+// Execute resolves the command addressed by args, parses its flags and runs it, returning the process exit code.
+func Execute(app *App, root *Command, args []string) int {
+	cmd := root
+	path := []string{root.Name}
+
+	for len(args) > 0 && len(cmd.Subcommands) > 0 && !strings.HasPrefix(args[0], "-") {
+		if args[0] == "help" {
+			return help(app, cmd, path, args[1:])
+		}
+
+		sub := cmd.find(args[0])
+		if sub == nil {
+			_, _ = fmt.Fprintf(app.Err, "unknown command %q for %q\n", args[0], strings.Join(path, " "))
+			if suggestion := cmd.suggest(args[0]); suggestion != "" {
+				_, _ = fmt.Fprintf(app.Err, "did you mean %q?\n", suggestion)
+			}
+			_, _ = fmt.Fprintf(app.Err, "run '%s --help' for usage\n", strings.Join(path, " "))
+			return EXIT_USAGE
+		}
+
+		cmd = sub
+		path = append(path, sub.Name)
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		printHelp(app.Out, cmd, path, fs)
+		return EXIT_OK
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(app.Err, "%v\n", err)
+		printHelp(app.Err, cmd, path, fs)
+		return EXIT_USAGE
+	}
+
+	if cmd.Run == nil {
+		printHelp(app.Err, cmd, path, fs)
+		return EXIT_USAGE
+	}
+
+	if err := cmd.Run(app, positional); err != nil {
+		_, _ = fmt.Fprintf(app.Err, "error: %v\n", err)
+		if errors.Is(err, ErrUsage) {
+			printHelp(app.Err, cmd, path, fs)
+			return EXIT_USAGE
+		}
+		return EXIT_ERROR
+	}
+
+	return EXIT_OK
+}
+
+// help prints the help text of the command addressed by args below cmd.
+func help(app *App, cmd *Command, path []string, args []string) int {
+	for _, name := range args {
+		sub := cmd.find(name)
+		if sub == nil {
+			_, _ = fmt.Fprintf(app.Err, "unknown command %q for %q\n", name, strings.Join(path, " "))
+			return EXIT_USAGE
+		}
+		cmd = sub
+		path = append(path, sub.Name)
+	}
+
+	fs := flag.NewFlagSet(strings.Join(path, " "), flag.ContinueOnError)
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+
+	printHelp(app.Out, cmd, path, fs)
+	return EXIT_OK
+}
+
+// parseInterspersed parses flags that may appear before, between or after positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// find returns the direct subcommand with the given name, or nil.
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// suggest returns the subcommand name closest to a mistyped name, or "" when none is close enough.
+func (c *Command) suggest(name string) string {
+	names := make([]string, 0, len(c.Subcommands))
+	for _, sub := range c.Subcommands {
+		names = append(names, sub.Name)
+	}
+
+	return closest(name, names)
+}
+
+// closest returns the candidate closest to a mistyped name, or "" when none is within an edit distance of 2.
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := levenshtein(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// printHelp writes the usage line, description, subcommands and flags of cmd.
+func printHelp(w io.Writer, cmd *Command, path []string, fs *flag.FlagSet) {
+	usage := cmd.Usage
+	if usage == "" && len(cmd.Subcommands) > 0 {
+		usage = "<command> [flags]"
+	}
+
+	_, _ = fmt.Fprintf(w, "Usage: %s %s\n", strings.Join(path, " "), usage)
+
+	description := cmd.Long
+	if description == "" {
+		description = cmd.Short
+	}
+	if description != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", description)
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		_, _ = fmt.Fprintln(w, "\nCommands:")
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, sub := range cmd.Subcommands {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Short)
+		}
+		_ = tw.Flush()
+	}
+
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		_, _ = fmt.Fprintln(w, "\nFlags:")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+		fs.SetOutput(io.Discard)
+	}
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(b)]
+}
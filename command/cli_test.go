@@ -0,0 +1,174 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTree builds a small command tree that records the invocation it received.
+func testTree(got *[]string, verbose *bool) *Command {
+	return &Command{
+		Name: "app",
+		Subcommands: []*Command{
+			{
+				Name:  "migrate",
+				Short: "Manage migrations",
+				Subcommands: []*Command{
+					{
+						Name: "up",
+						Flags: func(fs *flag.FlagSet) {
+							fs.BoolVar(verbose, "verbose", false, "print more")
+						},
+						Run: func(app *App, args []string) error {
+							*got = args
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "fail",
+				Run: func(app *App, args []string) error {
+					return errors.New("boom")
+				},
+			},
+			{
+				Name: "strict",
+				Run: func(app *App, args []string) error {
+					return usageError("expected a name")
+				},
+			},
+		},
+	}
+}
+
+// TestExecute verifies command resolution, flag parsing, help output and exit codes of Execute.
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantArgs    []string
+		wantVerbose bool
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			name:     "runs nested subcommand",
+			args:     []string{"migrate", "up", "a", "b"},
+			wantCode: EXIT_OK,
+			wantArgs: []string{"a", "b"},
+		},
+		{
+			name:        "parses interspersed flags",
+			args:        []string{"migrate", "up", "a", "-verbose", "b"},
+			wantCode:    EXIT_OK,
+			wantArgs:    []string{"a", "b"},
+			wantVerbose: true,
+		},
+		{
+			name:     "prints help for --help",
+			args:     []string{"migrate", "--help"},
+			wantCode: EXIT_OK,
+			wantOut:  "Usage: app migrate <command> [flags]",
+		},
+		{
+			name:     "prints help for help command",
+			args:     []string{"help", "migrate", "up"},
+			wantCode: EXIT_OK,
+			wantOut:  "-verbose",
+		},
+		{
+			name:     "suggests close command on typo",
+			args:     []string{"migrat"},
+			wantCode: EXIT_USAGE,
+			wantErr:  `did you mean "migrate"?`,
+		},
+		{
+			name:     "rejects unknown flag",
+			args:     []string{"migrate", "up", "-nope"},
+			wantCode: EXIT_USAGE,
+			wantErr:  "flag provided but not defined: -nope",
+		},
+		{
+			name:     "requires subcommand",
+			args:     []string{"migrate"},
+			wantCode: EXIT_USAGE,
+			wantErr:  "Usage: app migrate",
+		},
+		{
+			name:     "returns error code on failure",
+			args:     []string{"fail"},
+			wantCode: EXIT_ERROR,
+			wantErr:  "error: boom",
+		},
+		{
+			name:     "returns usage code on usage error",
+			args:     []string{"strict"},
+			wantCode: EXIT_USAGE,
+			wantErr:  "expected a name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var got []string
+				var verbose bool
+				var out, errOut bytes.Buffer
+				app := &App{Out: &out, Err: &errOut}
+
+				code := Execute(app, testTree(&got, &verbose), tt.args)
+
+				assert.Equal(t, tt.wantCode, code)
+				assert.Equal(t, tt.wantArgs, got)
+				assert.Equal(t, tt.wantVerbose, verbose)
+				assert.Contains(t, out.String(), tt.wantOut)
+				assert.Contains(t, errOut.String(), tt.wantErr)
+			},
+		)
+	}
+}
+
+// TestServeCommand verifies that the serve command asks the caller to start the server and applies the port flag.
+func TestServeCommand(t *testing.T) {
+	t.Setenv("PORT", "")
+
+	var out, errOut bytes.Buffer
+	app := &App{Out: &out, Err: &errOut}
+	root := &Command{Name: "app", Subcommands: []*Command{serveCommand()}}
+
+	code := Execute(app, root, []string{"serve", "-port", "9090"})
+
+	assert.Equal(t, EXIT_OK, code)
+	assert.True(t, app.Serve)
+	assert.Equal(t, "9090", os.Getenv("PORT"))
+}
+
+// TestLevenshtein verifies the edit distance used for command suggestions.
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "", b: "seed", want: 4},
+		{a: "seed", b: "seed", want: 0},
+		{a: "sed", b: "seed", want: 1},
+		{a: "migrat", b: "migrate", want: 1},
+		{a: "scirpt", b: "script", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.a+"->"+tt.b, func(t *testing.T) {
+				assert.Equal(t, tt.want, levenshtein(tt.a, tt.b))
+			},
+		)
+	}
+}
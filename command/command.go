@@ -1,69 +1,56 @@
 package command
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/samber/do"
-	"gorm.io/gorm"
-
-	"github.com/Caknoooo/go-gin-clean-starter/constants"
-	"github.com/Caknoooo/go-gin-clean-starter/migrations"
-	"github.com/Caknoooo/go-gin-clean-starter/script"
 )
 
-// Commands is a function that processes command-line arguments to handle database migrations, seeding, scripts, or runtime tasks.
-var Commands = func(injector *do.Injector) bool {
-	db := do.MustInvokeNamed[*gorm.DB](injector, constants.DB)
-
-	var scriptName string
-
-	migrate := false
-	seed := false
-	run := false
-	scriptFlag := false
+// exit terminates the process with the given status code; it is a variable so tests can intercept it.
+var exit = os.Exit
 
-	for _, arg := range os.Args[1:] {
-		if arg == "--migrate" {
-			migrate = true
-		}
-		if arg == "--seed" {
-			seed = true
-		}
-		if arg == "--run" {
-			run = true
-		}
-		if strings.HasPrefix(arg, "--script:") {
-			scriptFlag = true
-			scriptName = strings.TrimPrefix(arg, "--script:")
-		}
+// Commands runs the CLI command addressed by os.Args, resolving its dependencies from the injector, and reports
+// whether the HTTP server should be started afterward. Deprecated flags such as --migrate, --seed, --script:<name> and
+// --run are translated to their subcommands with a warning, and an unknown flag among them exits with EXIT_USAGE before
+// any of them runs. A failing command exits the process with a non-zero code.
+var Commands = func(injector *do.Injector) bool {
+	app := &App{
+		Injector: injector,
+		Out:      os.Stdout,
+		Err:      os.Stderr,
 	}
-
-	if migrate {
-		if err := migrations.Migrate(db); err != nil {
-			log.Fatalf("error migration: %v", err)
-		}
-		log.Println("migration completed successfully")
+	root := Root()
+	args := os.Args[1:]
+
+	invocations, legacy, err := translateLegacy(args)
+	if err != nil {
+		_, _ = fmt.Fprintf(app.Err, "error: %v\n", err)
+		_, _ = fmt.Fprintf(app.Err, "run '%s --help' for usage\n", root.Name)
+		exit(EXIT_USAGE)
+		return false
 	}
-
-	if seed {
-		if err := migrations.Seeder(db); err != nil {
-			log.Fatalf("error migration seeder: %v", err)
+	if !legacy {
+		if len(args) == 0 {
+			help(app, root, []string{root.Name}, nil)
+			return false
 		}
-		log.Println("seeder completed successfully")
+		invocations = [][]string{args}
 	}
 
-	if scriptFlag {
-		if err := script.Script(scriptName, db); err != nil {
-			log.Fatalf("error script: %v", err)
+	for _, invocation := range invocations {
+		if legacy {
+			_, _ = fmt.Fprintf(
+				app.Err, "warning: deprecated flags, use '%s %s' instead\n", root.Name, strings.Join(invocation, " "),
+			)
 		}
-		log.Println("script run successfully")
-	}
 
-	if run {
-		return true
+		if code := Execute(app, root, invocation); code != EXIT_OK {
+			exit(code)
+			return false
+		}
 	}
 
-	return false
+	return app.Serve
 }
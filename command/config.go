@@ -0,0 +1,58 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+// configCommand builds the config command group for inspecting the environment configuration.
+func configCommand() *Command {
+	return &Command{
+		Name:  "config",
+		Short: "Inspect the environment configuration",
+		Subcommands: []*Command{
+			{
+				Name:  "show",
+				Short: "Print the configuration with secrets masked",
+				Run: func(app *App, args []string) error {
+					if err := config.LoadEnvFile(); err != nil {
+						return err
+					}
+
+					w := tabwriter.NewWriter(app.Out, 0, 0, 2, ' ', 0)
+					for _, env := range config.ENV_VARS {
+						value := os.Getenv(env.Name)
+						if env.Secret {
+							value = config.MaskSecret(value)
+						}
+						if value == "" {
+							value = "-"
+						}
+						_, _ = fmt.Fprintf(w, "%s\t%s\n", env.Name, value)
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:  "check",
+				Short: "Verify that every required environment variable is set",
+				Run: func(app *App, args []string) error {
+					if err := config.LoadEnvFile(); err != nil {
+						return err
+					}
+
+					if missing := config.MissingEnv(); len(missing) > 0 {
+						return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+					}
+
+					_, _ = fmt.Fprintln(app.Out, "configuration is complete")
+					return nil
+				},
+			},
+		},
+	}
+}
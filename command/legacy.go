@@ -0,0 +1,137 @@
+package command
+
+import (
+	"strings"
+)
+
+// legacyFlagNames lists the deprecated flags and the script flags accepted alongside them, without their
+// :<target> suffix, for suggesting the intended flag when one is mistyped.
+var legacyFlagNames = []string{"--migrate", "--seed", "--script", "--run", "--arg", "--dry-run", "--force"}
+
+// isLegacyFlag reports whether arg is one of the deprecated flags accepted before the subcommand CLI.
+func isLegacyFlag(arg string) bool {
+	return arg == "--migrate" || strings.HasPrefix(arg, "--migrate:") ||
+		arg == "--seed" || strings.HasPrefix(arg, "--seed:") ||
+		strings.HasPrefix(arg, "--script:") ||
+		arg == "--run"
+}
+
+// translateLegacy converts deprecated flags such as --migrate, --seed:users:update, --script:<name> and --run into
+// subcommand invocations, ordered migrate, seed, script and serve like the former flag handling.
+// It reports false when args contain no deprecated flag, and returns an ErrUsage error naming the first argument it
+// does not recognize when they do.
+func translateLegacy(args []string) ([][]string, bool, error) {
+	var migrate, seed, scripts [][]string
+	var scriptFlags []string
+	var unknown error
+	legacy, serve := false, false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--migrate":
+			migrate = append(migrate, []string{"migrate", "up"})
+		case strings.HasPrefix(arg, "--migrate:"):
+			migrate = append(migrate, legacyMigrate(strings.TrimPrefix(arg, "--migrate:")))
+		case arg == "--seed":
+			seed = append(seed, []string{"seed"})
+		case strings.HasPrefix(arg, "--seed:"):
+			seed = append(seed, legacySeed(strings.TrimPrefix(arg, "--seed:")))
+		case strings.HasPrefix(arg, "--script:"):
+			name := strings.TrimPrefix(arg, "--script:")
+			if name == "list" {
+				scripts = append(scripts, []string{"script", "list"})
+			} else {
+				scripts = append(scripts, []string{"script", "run", name})
+			}
+		case arg == "--run":
+			serve = true
+		case arg == "--arg" && i+1 < len(args):
+			i++
+			scriptFlags = append(scriptFlags, "--arg", args[i])
+		case arg == "--arg":
+			if unknown == nil {
+				unknown = usageError("flag needs an argument: --arg")
+			}
+			continue
+		case strings.HasPrefix(arg, "--arg="), arg == "--dry-run", arg == "--force":
+			scriptFlags = append(scriptFlags, arg)
+		default:
+			if unknown == nil {
+				unknown = unknownLegacyFlag(arg)
+			}
+			continue
+		}
+
+		if isLegacyFlag(arg) {
+			legacy = true
+		}
+	}
+
+	if !legacy {
+		return nil, false, nil
+	}
+	if unknown != nil {
+		return nil, true, unknown
+	}
+
+	for i, invocation := range scripts {
+		if invocation[1] == "run" {
+			scripts[i] = append(invocation, scriptFlags...)
+		}
+	}
+
+	invocations := append(append(migrate, seed...), scripts...)
+	if serve {
+		invocations = append(invocations, []string{"serve"})
+	}
+
+	return invocations, true, nil
+}
+
+// unknownLegacyFlag returns the ErrUsage error for an argument that is not a deprecated flag, suggesting the flag
+// closest to it when it looks like a flag. The :<target> suffix is kept, so --scirpt:cleanup suggests --script:cleanup.
+func unknownLegacyFlag(arg string) error {
+	if !strings.HasPrefix(arg, "-") {
+		return usageError("unexpected argument %q", arg)
+	}
+
+	name, target, hasTarget := strings.Cut(arg, ":")
+	suggestion := closest(name, legacyFlagNames)
+	if suggestion == "" {
+		return usageError("unknown flag %q", arg)
+	}
+	if hasTarget {
+		suggestion += ":" + target
+	}
+
+	return usageError("unknown flag %q, did you mean %q?", arg, suggestion)
+}
+
+// legacyMigrate converts the action of a --migrate:<action> flag, e.g. down:2 or create:<name>, into a migrate invocation.
+func legacyMigrate(action string) []string {
+	if name, ok := strings.CutPrefix(action, "create:"); ok {
+		return []string{"migrate", "create", name}
+	}
+	if steps, ok := strings.CutPrefix(action, "down:"); ok {
+		return []string{"migrate", "down", "-n", steps}
+	}
+
+	return []string{"migrate", action}
+}
+
+// legacySeed converts the target of a --seed:<name>[:<count>][:<strategy>] flag into a seed invocation.
+func legacySeed(target string) []string {
+	parts := strings.Split(target, ":")
+	invocation := []string{"seed", parts[0]}
+
+	for _, part := range parts[1:] {
+		if part != "" && strings.Trim(part, "0123456789") == "" {
+			invocation = append(invocation, "-count", part)
+			continue
+		}
+		invocation = append(invocation, "-strategy", part)
+	}
+
+	return invocation
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"os"
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTranslateLegacy verifies that deprecated flags are translated into ordered subcommand invocations.
+func TestTranslateLegacy(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       [][]string
+		wantLegacy bool
+		wantErr    string
+	}{
+		{
+			name:       "subcommand is not legacy",
+			args:       []string{"migrate", "up"},
+			wantLegacy: false,
+		},
+		{
+			name:       "migrate",
+			args:       []string{"--migrate"},
+			want:       [][]string{{"migrate", "up"}},
+			wantLegacy: true,
+		},
+		{
+			name: "migrate actions",
+			args: []string{"--migrate:down:2", "--migrate:status", "--migrate:create:add_avatar"},
+			want: [][]string{
+				{"migrate", "down", "-n", "2"},
+				{"migrate", "status"},
+				{"migrate", "create", "add_avatar"},
+			},
+			wantLegacy: true,
+		},
+		{
+			name: "seed targets",
+			args: []string{"--seed:list", "--seed:users:update", "--seed:fake_users:1000"},
+			want: [][]string{
+				{"seed", "list"},
+				{"seed", "users", "-strategy", "update"},
+				{"seed", "fake_users", "-count", "1000"},
+			},
+			wantLegacy: true,
+		},
+		{
+			name: "script with arguments",
+			args: []string{"--dry-run", "--script:example_script", "--arg", "times=2", "--arg=message=hi"},
+			want: [][]string{
+				{"script", "run", "example_script", "--dry-run", "--arg", "times=2", "--arg=message=hi"},
+			},
+			wantLegacy: true,
+		},
+		{
+			name: "keeps former execution order",
+			args: []string{"--run", "--script:list", "--seed", "--migrate"},
+			want: [][]string{
+				{"migrate", "up"},
+				{"seed"},
+				{"script", "list"},
+				{"serve"},
+			},
+			wantLegacy: true,
+		},
+		{
+			name:       "unknown flag is suggested",
+			args:       []string{"--migrate", "--sed"},
+			wantLegacy: true,
+			wantErr:    `unknown flag "--sed", did you mean "--seed"?`,
+		},
+		{
+			name:       "suggestion keeps the target",
+			args:       []string{"--scirpt:cleanup", "--run"},
+			wantLegacy: true,
+			wantErr:    `unknown flag "--scirpt:cleanup", did you mean "--script:cleanup"?`,
+		},
+		{
+			name:       "unknown flag without suggestion",
+			args:       []string{"--seed", "--verbose"},
+			wantLegacy: true,
+			wantErr:    `unknown flag "--verbose"`,
+		},
+		{
+			name:       "unexpected argument",
+			args:       []string{"--seed", "users"},
+			wantLegacy: true,
+			wantErr:    `unexpected argument "users"`,
+		},
+		{
+			name:       "arg without value",
+			args:       []string{"--script:example_script", "--arg"},
+			wantLegacy: true,
+			wantErr:    "flag needs an argument: --arg",
+		},
+		{
+			name:       "unknown flag without deprecated flags is left to the subcommand parser",
+			args:       []string{"migrate", "--sed"},
+			wantLegacy: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, legacy, err := translateLegacy(tt.args)
+
+				assert.Equal(t, tt.wantLegacy, legacy)
+				assert.Equal(t, tt.want, got)
+				if tt.wantErr != "" {
+					assert.ErrorIs(t, err, ErrUsage)
+					assert.ErrorContains(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			},
+		)
+	}
+}
+
+// TestCommands verifies that Commands dispatches os.Args and exits with the command's code on failure.
+func TestCommands(t *testing.T) {
+	oldArgs, oldExit, oldRoot := os.Args, exit, Root
+	defer func() { os.Args, exit, Root = oldArgs, oldExit, oldRoot }()
+
+	var exitCode int
+	exit = func(code int) { exitCode = code }
+	Root = func() *Command {
+		return &Command{Name: "app", Subcommands: []*Command{serveCommand(), {Name: "migrate"}}}
+	}
+
+	t.Run(
+		"legacy run serves", func(t *testing.T) {
+			os.Args = []string{"app", "--run"}
+			assert.True(t, Commands(do.New()))
+		},
+	)
+
+	t.Run(
+		"unknown command exits with usage code", func(t *testing.T) {
+			os.Args = []string{"app", "bogus"}
+			assert.False(t, Commands(do.New()))
+			assert.Equal(t, EXIT_USAGE, exitCode)
+		},
+	)
+
+	t.Run(
+		"unknown legacy flag exits with usage code", func(t *testing.T) {
+			exitCode = 0
+			os.Args = []string{"app", "--migrate", "--rn"}
+			assert.False(t, Commands(do.New()))
+			assert.Equal(t, EXIT_USAGE, exitCode)
+		},
+	)
+
+	t.Run(
+		"failing legacy flag exits with usage code", func(t *testing.T) {
+			exitCode = 0
+			os.Args = []string{"app", "--migrate:sideways"}
+			assert.False(t, Commands(do.New()))
+			assert.Equal(t, EXIT_USAGE, exitCode)
+		},
+	)
+}
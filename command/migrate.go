@@ -0,0 +1,152 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/script"
+)
+
+// migrateCommand builds the migrate command group for versioned database migrations.
+func migrateCommand() *Command {
+	var steps int
+
+	return &Command{
+		Name:  "migrate",
+		Short: "Manage versioned database migrations",
+		Subcommands: []*Command{
+			{
+				Name:  "up",
+				Short: "Apply all pending migrations",
+				Run: func(app *App, args []string) error {
+					migrator, err := migrations.NewMigrator(app.DB())
+					if err != nil {
+						return err
+					}
+
+					applied, err := migrator.Up(context.Background())
+					if err != nil {
+						return err
+					}
+
+					for _, migration := range applied {
+						_, _ = fmt.Fprintf(app.Out, "applied %s_%s\n", migration.Version, migration.Name)
+					}
+					_, _ = fmt.Fprintf(app.Out, "migration completed successfully, %d applied\n", len(applied))
+					return nil
+				},
+			},
+			{
+				Name:  "down",
+				Short: "Roll back the most recent migrations",
+				Flags: func(fs *flag.FlagSet) {
+					fs.IntVar(&steps, "n", 1, "number of migrations to roll back")
+				},
+				Run: func(app *App, args []string) error {
+					if steps < 1 {
+						return usageError("-n must be at least 1")
+					}
+
+					migrator, err := migrations.NewMigrator(app.DB())
+					if err != nil {
+						return err
+					}
+
+					reverted, err := migrator.Down(context.Background(), steps)
+					if err != nil {
+						return err
+					}
+
+					for _, migration := range reverted {
+						_, _ = fmt.Fprintf(app.Out, "rolled back %s_%s\n", migration.Version, migration.Name)
+					}
+					_, _ = fmt.Fprintf(app.Out, "rollback completed successfully, %d reverted\n", len(reverted))
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Short: "List applied and pending migrations",
+				Run: func(app *App, args []string) error {
+					migrator, err := migrations.NewMigrator(app.DB())
+					if err != nil {
+						return err
+					}
+
+					statuses, err := migrator.Status(context.Background())
+					if err != nil {
+						return err
+					}
+
+					printMigrationStatus(app.Out, statuses)
+					return nil
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "<name>",
+				Short: "Create a new pair of empty up/down SQL migration files",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one migration name")
+					}
+
+					upPath, downPath, err := migrations.Create(args[0])
+					if err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "created migration %s and %s\n", upPath, downPath)
+					return nil
+				},
+			},
+			{
+				Name:  "check",
+				Short: "Compare the live database schema with the GORM entities",
+				Run: func(app *App, args []string) error {
+					models := append(migrations.Models(), &script.ScriptRun{})
+					issues, err := migrations.CheckSchema(app.DB(), models...)
+					if err != nil {
+						return err
+					}
+
+					for _, issue := range issues {
+						_, _ = fmt.Fprintln(app.Out, issue.String())
+					}
+					if len(issues) > 0 {
+						return fmt.Errorf("schema has %d difference(s) from the entities", len(issues))
+					}
+
+					_, _ = fmt.Fprintln(app.Out, "schema matches the entities")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// printMigrationStatus writes the migration status list as an aligned table.
+func printMigrationStatus(out io.Writer, statuses []migrations.MigrationStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Missing {
+			state = "missing"
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+
+	_ = w.Flush()
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/script"
+)
+
+// scriptCommand builds the script command group for listing, running and inspecting registered scripts.
+func scriptCommand() *Command {
+	var pairs stringList
+	var dryRun bool
+	var force bool
+	var limit int
+
+	return &Command{
+		Name:  "script",
+		Short: "Run registered maintenance scripts",
+		Subcommands: []*Command{
+			{
+				Name:  "list",
+				Short: "List registered scripts and their arguments",
+				Run: func(app *App, args []string) error {
+					printScripts(app.Out, script.Registered())
+					return nil
+				},
+			},
+			{
+				Name:  "run",
+				Usage: "<name> [--arg key=value]... [flags]",
+				Short: "Run a script inside a transaction",
+				Flags: func(fs *flag.FlagSet) {
+					fs.Var(&pairs, "arg", "script argument as key=value (repeatable)")
+					fs.BoolVar(&dryRun, "dry-run", false, "run the script and roll back every change")
+					fs.BoolVar(&force, "force", false, "run a one-off script again even if it already succeeded")
+				},
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one script name")
+					}
+
+					scriptArgs, err := script.ParseArgs(pairs)
+					if err != nil {
+						return err
+					}
+
+					if err := script.Execute(
+						context.Background(), app.DB(), args[0], script.Options{
+							Args:   scriptArgs,
+							DryRun: dryRun,
+							Force:  force,
+							Out:    app.Out,
+						},
+					); err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintln(app.Out, "script run successfully")
+					return nil
+				},
+			},
+			{
+				Name:  "history",
+				Usage: "<name> [flags]",
+				Short: "Show the most recent runs of a script",
+				Flags: func(fs *flag.FlagSet) {
+					fs.IntVar(&limit, "limit", 10, "maximum number of runs to show")
+				},
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one script name")
+					}
+
+					runs, err := script.History(app.DB(), args[0], limit)
+					if err != nil {
+						return err
+					}
+
+					w := tabwriter.NewWriter(app.Out, 0, 0, 2, ' ', 0)
+					_, _ = fmt.Fprintln(w, "STARTED AT\tSTATUS\tDRY RUN\tDURATION\tARGS\tERROR")
+					for _, run := range runs {
+						_, _ = fmt.Fprintf(
+							w, "%s\t%s\t%t\t%s\t%s\t%s\n",
+							run.StartedAt.Format("2006-01-02 15:04:05"),
+							run.Status,
+							run.DryRun,
+							run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond),
+							run.Args,
+							run.Error,
+						)
+					}
+					return w.Flush()
+				},
+			},
+		},
+	}
+}
+
+// printScripts writes the registered scripts and their argument schemas.
+func printScripts(out io.Writer, runners []script.Runner) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tDESCRIPTION")
+
+	for _, runner := range runners {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", runner.Name(), runner.Description())
+		for _, arg := range runner.Args() {
+			detail := arg.Description
+			if arg.Required {
+				detail += " (required)"
+			} else if arg.Default != "" {
+				detail += fmt.Sprintf(" (default: %s)", arg.Default)
+			}
+			_, _ = fmt.Fprintf(w, "  --arg %s=\t%s\n", arg.Name, detail)
+		}
+	}
+
+	_ = w.Flush()
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations/seeds"
+)
+
+// seedCommand builds the seed command, which runs the default seeders or the named ones.
+func seedCommand() *Command {
+	var strategy string
+	var count int
+
+	return &Command{
+		Name:  "seed",
+		Usage: "[name...] [flags]",
+		Short: "Seed the database",
+		Long: "Seed the database with every default seeder allowed in the current environment, or only with the\n" +
+			"named seeders and their dependencies. Use 'seed list' to show the registered seeders.",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&strategy, "strategy", "", "upsert strategy for existing rows: skip or update (default: per seeder)")
+			fs.IntVar(&count, "count", 0, "number of records for generating seeders such as fake_users")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) == 1 && args[0] == "list" {
+				printSeeders(app.Out, seeds.Registered())
+				return nil
+			}
+
+			opts := seeds.Options{Count: count}
+			switch upsert := seeds.UpsertStrategy(strategy); upsert {
+			case "":
+			case seeds.UPSERT_SKIP, seeds.UPSERT_UPDATE:
+				opts.Strategy = upsert
+			default:
+				return usageError("invalid strategy %q", strategy)
+			}
+
+			if err := migrations.Seed(app.DB(), args, opts); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintln(app.Out, "seeder completed successfully")
+			return nil
+		},
+	}
+}
+
+// printSeeders writes the registered seeders as an aligned table.
+func printSeeders(out io.Writer, registered []seeds.Seeder) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tDEPENDS ON\tENVIRONMENTS\tMANUAL\tDESCRIPTION")
+
+	for _, seeder := range registered {
+		environments := seeder.Environments
+		if len(environments) == 0 {
+			environments = []string{constants.ENUM_RUN_DEVELOPMENT, constants.ENUM_RUN_TESTING}
+		}
+
+		_, _ = fmt.Fprintf(
+			w, "%s\t%s\t%s\t%t\t%s\n",
+			seeder.Name,
+			strings.Join(seeder.DependsOn, ","),
+			strings.Join(environments, ","),
+			seeder.Manual,
+			seeder.Description,
+		)
+	}
+
+	_ = w.Flush()
+}
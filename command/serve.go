@@ -0,0 +1,33 @@
+package command
+
+import (
+	"flag"
+	"os"
+)
+
+// serveCommand builds the serve command, which tells the caller to start the HTTP server.
+func serveCommand() *Command {
+	var port string
+
+	return &Command{
+		Name:  "serve",
+		Short: "Start the HTTP server",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&port, "port", "", "port to listen on (overrides PORT)")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) > 0 {
+				return usageError("serve takes no arguments")
+			}
+
+			if port != "" {
+				if err := os.Setenv("PORT", port); err != nil {
+					return err
+				}
+			}
+
+			app.Serve = true
+			return nil
+		},
+	}
+}
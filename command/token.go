@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// tokenCommand builds the token command group for maintaining refresh tokens and inspecting access tokens.
+func tokenCommand() *Command {
+	return &Command{
+		Name:  "token",
+		Short: "Maintain refresh tokens and inspect access tokens",
+		Subcommands: []*Command{
+			{
+				Name:  "prune",
+				Short: "Delete expired refresh tokens",
+				Run: func(app *App, args []string) error {
+					refreshTokenRepository := do.MustInvoke[repository.RefreshTokenRepository](app.Injector)
+					if err := refreshTokenRepository.DeleteExpired(context.Background(), nil); err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintln(app.Out, "expired refresh tokens deleted")
+					return nil
+				},
+			},
+			{
+				Name:  "inspect",
+				Usage: "<access-token>",
+				Short: "Validate an access token and print its claims",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one token")
+					}
+
+					jwtService := do.MustInvokeNamed[service.JWTService](app.Injector, constants.JWTService)
+					token, err := jwtService.ValidateToken(args[0])
+					if err != nil {
+						return err
+					}
+					if !token.Valid {
+						return fmt.Errorf("token is not valid")
+					}
+
+					return printJSON(app.Out, token.Claims)
+				},
+			},
+		},
+	}
+}
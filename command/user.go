@@ -0,0 +1,327 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/do"
+	"golang.org/x/term"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+const (
+	// OUTPUT_TABLE renders command output as an aligned text table.
+	OUTPUT_TABLE = "table"
+
+	// OUTPUT_JSON renders command output as indented JSON.
+	OUTPUT_JSON = "json"
+)
+
+// ErrPasswordMismatch indicates that the password confirmation typed at the prompt did not match.
+var ErrPasswordMismatch = errors.New("passwords do not match")
+
+// readPassword prompts on out and reads a password from stdin without echoing it when stdin is a terminal.
+// Piped input is read line by line so the commands can also be scripted.
+var readPassword = func(out io.Writer, prompt string) (string, error) {
+	_, _ = fmt.Fprint(out, prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		_, _ = fmt.Fprintln(out)
+		return string(password), err
+	}
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stdinReader buffers piped stdin across several password prompts.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// userCommand builds the user command group for inspecting and administering user accounts.
+func userCommand() *Command {
+	var name, email, phone string
+	var output string
+	var search string
+	var page, perPage int
+
+	return &Command{
+		Name:  "user",
+		Short: "Inspect and administer user accounts",
+		Subcommands: []*Command{
+			{
+				Name:  "list",
+				Usage: "[flags]",
+				Short: "List users, optionally filtered by name",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&search, "search", "", "only list users whose name contains this text")
+					fs.IntVar(&page, "page", 1, "page number")
+					fs.IntVar(&perPage, "per-page", 20, "users per page")
+					fs.StringVar(&output, "output", OUTPUT_TABLE, "output format: table or json")
+				},
+				Run: func(app *App, args []string) error {
+					if len(args) > 0 {
+						return usageError("list takes no arguments")
+					}
+
+					users, err := userService(app).GetAllUserWithPagination(
+						context.Background(), dto.PaginationRequest{
+							Search:  search,
+							Page:    page,
+							PerPage: perPage,
+						},
+					)
+					if err != nil {
+						return err
+					}
+
+					return printUsers(
+						app.Out, output, users.Data,
+						fmt.Sprintf("page %d of %d, %d users", users.Page, users.MaxPage, users.Count),
+					)
+				},
+			},
+			{
+				Name:  "show",
+				Usage: "<id|email> [flags]",
+				Short: "Print a single user",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&output, "output", OUTPUT_JSON, "output format: table or json")
+				},
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one user id or email")
+					}
+
+					user, err := findUser(context.Background(), userService(app), args[0])
+					if err != nil {
+						return err
+					}
+
+					if output == OUTPUT_JSON {
+						return printJSON(app.Out, user)
+					}
+					return printUsers(app.Out, output, []dto.UserResponse{user}, "")
+				},
+			},
+			{
+				Name:  "create-admin",
+				Usage: "-name <name> -email <email> [flags]",
+				Short: "Create a verified administrator, prompting for the password",
+				Flags: func(fs *flag.FlagSet) {
+					fs.StringVar(&name, "name", "", "full name of the administrator")
+					fs.StringVar(&email, "email", "", "email address of the administrator")
+					fs.StringVar(&phone, "phone", "", "phone number of the administrator")
+				},
+				Run: func(app *App, args []string) error {
+					if len(args) > 0 || name == "" || email == "" {
+						return usageError("-name and -email are required")
+					}
+
+					password, err := promptNewPassword(app.Err)
+					if err != nil {
+						return err
+					}
+
+					user, err := userService(app).CreateAdmin(
+						context.Background(), dto.AdminCreateRequest{
+							Name:        name,
+							PhoneNumber: phone,
+							Email:       email,
+							Password:    password,
+						},
+					)
+					if err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "created admin %s (%s)\n", user.Email, user.ID)
+					return nil
+				},
+			},
+			{
+				Name:  "reset-password",
+				Usage: "<id|email>",
+				Short: "Set a new password, prompting for it, and revoke all sessions",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one user id or email")
+					}
+
+					ctx := context.Background()
+					users := userService(app)
+					user, err := findUser(ctx, users, args[0])
+					if err != nil {
+						return err
+					}
+
+					password, err := promptNewPassword(app.Err)
+					if err != nil {
+						return err
+					}
+
+					if err := users.ResetPassword(ctx, user.ID, password); err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "password reset for %s, all sessions revoked\n", user.Email)
+					return nil
+				},
+			},
+			{
+				Name:  "verify",
+				Usage: "<id|email>",
+				Short: "Mark the email address of a user as verified",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one user id or email")
+					}
+
+					ctx := context.Background()
+					users := userService(app)
+					user, err := findUser(ctx, users, args[0])
+					if err != nil {
+						return err
+					}
+
+					if _, err := users.MarkEmailVerified(ctx, user.ID); err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "email verified for %s\n", user.Email)
+					return nil
+				},
+			},
+			{
+				Name:  "role",
+				Usage: "<id|email> <user|admin>",
+				Short: "Change the role of a user",
+				Run: func(app *App, args []string) error {
+					if len(args) != 2 {
+						return usageError("expected a user id or email and a role")
+					}
+
+					ctx := context.Background()
+					users := userService(app)
+					user, err := findUser(ctx, users, args[0])
+					if err != nil {
+						return err
+					}
+
+					updated, err := users.ChangeRole(ctx, user.ID, args[1])
+					if err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "role of %s changed from %s to %s\n", user.Email, user.Role, updated.Role)
+					return nil
+				},
+			},
+			{
+				Name:  "revoke-sessions",
+				Usage: "<id|email>",
+				Short: "Revoke every refresh token of a user",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageError("expected exactly one user id or email")
+					}
+
+					ctx := context.Background()
+					users := userService(app)
+					user, err := findUser(ctx, users, args[0])
+					if err != nil {
+						return err
+					}
+
+					if err := users.RevokeRefreshToken(ctx, user.ID); err != nil {
+						return err
+					}
+
+					_, _ = fmt.Fprintf(app.Out, "all sessions revoked for %s\n", user.Email)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// userService resolves the user service from the injector.
+func userService(app *App) service.UserService {
+	return do.MustInvoke[service.UserService](app.Injector)
+}
+
+// findUser looks a user up by email when ref contains an @, and by id otherwise.
+func findUser(ctx context.Context, users service.UserService, ref string) (dto.UserResponse, error) {
+	if strings.Contains(ref, "@") {
+		return users.GetUserByEmail(ctx, ref)
+	}
+
+	return users.GetUserById(ctx, ref)
+}
+
+// promptNewPassword asks for a password twice and returns it when both entries match.
+func promptNewPassword(out io.Writer) (string, error) {
+	password, err := readPassword(out, "New password: ")
+	if err != nil {
+		return "", err
+	}
+
+	confirm, err := readPassword(out, "Confirm password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if password != confirm {
+		return "", ErrPasswordMismatch
+	}
+
+	return password, nil
+}
+
+// printUsers writes users in the requested output format, followed by an optional footer for table output.
+func printUsers(out io.Writer, output string, users []dto.UserResponse, footer string) error {
+	switch output {
+	case OUTPUT_JSON:
+		return printJSON(out, users)
+	case OUTPUT_TABLE:
+	default:
+		return usageError("invalid output format %q", output)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tEMAIL\tROLE\tVERIFIED")
+	for _, user := range users {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", user.ID, user.Name, user.Email, user.Role, user.IsVerified)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if footer != "" {
+		_, _ = fmt.Fprintln(out, footer)
+	}
+
+	return nil
+}
+
+// printJSON writes v to out as indented JSON.
+func printJSON(out io.Writer, v any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,203 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// mockUserService is a testify mock of the user service methods used by the user commands.
+type mockUserService struct {
+	service.UserService
+	mock.Mock
+}
+
+// GetUserById returns the mocked user for the given id.
+func (m *mockUserService) GetUserById(ctx context.Context, userId string) (dto.UserResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).(dto.UserResponse), args.Error(1)
+}
+
+// GetUserByEmail returns the mocked user for the given email.
+func (m *mockUserService) GetUserByEmail(ctx context.Context, email string) (dto.UserResponse, error) {
+	args := m.Called(email)
+	return args.Get(0).(dto.UserResponse), args.Error(1)
+}
+
+// CreateAdmin records the admin creation request.
+func (m *mockUserService) CreateAdmin(ctx context.Context, req dto.AdminCreateRequest) (dto.UserResponse, error) {
+	args := m.Called(req)
+	return args.Get(0).(dto.UserResponse), args.Error(1)
+}
+
+// ResetPassword records the password reset.
+func (m *mockUserService) ResetPassword(ctx context.Context, userId string, password string) error {
+	return m.Called(userId, password).Error(0)
+}
+
+// ChangeRole records the role change.
+func (m *mockUserService) ChangeRole(ctx context.Context, userId string, role string) (dto.UserResponse, error) {
+	args := m.Called(userId, role)
+	return args.Get(0).(dto.UserResponse), args.Error(1)
+}
+
+// GetAllUserWithPagination returns the mocked page of users.
+func (m *mockUserService) GetAllUserWithPagination(
+	ctx context.Context,
+	req dto.PaginationRequest,
+) (dto.UserPaginationResponse, error) {
+	args := m.Called(req)
+	return args.Get(0).(dto.UserPaginationResponse), args.Error(1)
+}
+
+// runUserCommand executes the user command tree with the mock service and scripted password input.
+func runUserCommand(users *mockUserService, passwords []string, args ...string) (int, string, string) {
+	injector := do.New()
+	do.ProvideValue[service.UserService](injector, users)
+
+	original := readPassword
+	defer func() { readPassword = original }()
+	readPassword = func(out io.Writer, prompt string) (string, error) {
+		password := passwords[0]
+		passwords = passwords[1:]
+		return password, nil
+	}
+
+	var out, errOut bytes.Buffer
+	app := &App{Injector: injector, Out: &out, Err: &errOut}
+	root := &Command{Name: "app", Subcommands: []*Command{userCommand()}}
+
+	code := Execute(app, root, append([]string{"user"}, args...))
+	return code, out.String(), errOut.String()
+}
+
+// TestUserCommand verifies the admin user subcommands against a mocked user service.
+func TestUserCommand(t *testing.T) {
+	alice := dto.UserResponse{ID: "u-1", Name: "Alice", Email: "alice@example.com", Role: "user"}
+
+	t.Run(
+		"create admin prompts for password twice", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On(
+				"CreateAdmin", dto.AdminCreateRequest{Name: "Root", Email: "root@example.com", Password: "s3cretpass"},
+			).Return(dto.UserResponse{ID: "u-9", Email: "root@example.com"}, nil)
+
+			code, out, _ := runUserCommand(
+				users, []string{"s3cretpass", "s3cretpass"},
+				"create-admin", "-name", "Root", "-email", "root@example.com",
+			)
+
+			assert.Equal(t, EXIT_OK, code)
+			assert.Contains(t, out, "created admin root@example.com (u-9)")
+			users.AssertExpectations(t)
+		},
+	)
+
+	t.Run(
+		"create admin rejects mismatched confirmation", func(t *testing.T) {
+			users := &mockUserService{}
+
+			code, _, errOut := runUserCommand(
+				users, []string{"s3cretpass", "other"},
+				"create-admin", "-name", "Root", "-email", "root@example.com",
+			)
+
+			assert.Equal(t, EXIT_ERROR, code)
+			assert.Contains(t, errOut, ErrPasswordMismatch.Error())
+			users.AssertNotCalled(t, "CreateAdmin", mock.Anything)
+		},
+	)
+
+	t.Run(
+		"reset password resolves user by email", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetUserByEmail", "alice@example.com").Return(alice, nil)
+			users.On("ResetPassword", "u-1", "n3wpassword").Return(nil)
+
+			code, out, _ := runUserCommand(
+				users, []string{"n3wpassword", "n3wpassword"}, "reset-password", "alice@example.com",
+			)
+
+			assert.Equal(t, EXIT_OK, code)
+			assert.Contains(t, out, "password reset for alice@example.com")
+			users.AssertExpectations(t)
+		},
+	)
+
+	t.Run(
+		"role change resolves user by id", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetUserById", "u-1").Return(alice, nil)
+			users.On("ChangeRole", "u-1", "admin").Return(dto.UserResponse{Role: "admin"}, nil)
+
+			code, out, _ := runUserCommand(users, nil, "role", "u-1", "admin")
+
+			assert.Equal(t, EXIT_OK, code)
+			assert.Contains(t, out, "from user to admin")
+		},
+	)
+
+	t.Run(
+		"role change reports service errors", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetUserById", "u-1").Return(alice, nil)
+			users.On("ChangeRole", "u-1", "root").Return(dto.UserResponse{}, dto.ErrInvalidRole)
+
+			code, _, errOut := runUserCommand(users, nil, "role", "u-1", "root")
+
+			assert.Equal(t, EXIT_ERROR, code)
+			assert.Contains(t, errOut, dto.ErrInvalidRole.Error())
+		},
+	)
+
+	t.Run(
+		"list renders json", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetAllUserWithPagination", dto.PaginationRequest{Search: "Ali", Page: 1, PerPage: 20}).
+				Return(dto.UserPaginationResponse{Data: []dto.UserResponse{alice}}, nil)
+
+			code, out, _ := runUserCommand(users, nil, "list", "-search", "Ali", "-output", "json")
+
+			assert.Equal(t, EXIT_OK, code)
+			assert.Contains(t, out, `"email": "alice@example.com"`)
+		},
+	)
+
+	t.Run(
+		"list renders table", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetAllUserWithPagination", mock.Anything).
+				Return(
+					dto.UserPaginationResponse{
+						Data:               []dto.UserResponse{alice},
+						PaginationResponse: dto.PaginationResponse{Page: 1, MaxPage: 1, Count: 1},
+					}, nil,
+				)
+
+			code, out, _ := runUserCommand(users, nil, "list")
+
+			assert.Equal(t, EXIT_OK, code)
+			assert.Contains(t, out, "u-1  Alice  alice@example.com  user  false")
+			assert.Contains(t, out, "page 1 of 1, 1 users")
+		},
+	)
+
+	t.Run(
+		"list rejects unknown output format", func(t *testing.T) {
+			users := &mockUserService{}
+			users.On("GetAllUserWithPagination", mock.Anything).Return(dto.UserPaginationResponse{}, nil)
+
+			code, _, _ := runUserCommand(users, nil, "list", "-output", "xml")
+
+			assert.Equal(t, EXIT_USAGE, code)
+		},
+	)
+}
@@ -11,9 +11,10 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 )
 
-// RunExtension ensures the "uuid-ossp" PostgreSQL extension is installed in the database.
+// RunExtension ensures the "uuid-ossp" and "pg_trgm" PostgreSQL extensions are installed in the database.
 func RunExtension(db *gorm.DB) {
 	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm;")
 }
 
 // loadEnv loads environment variables from a .env file based on the current application environment.
@@ -24,19 +25,10 @@ func loadEnv() {
 		appEnv = constants.ENUM_RUN_DEVELOPMENT
 	}
 
-	var envFile string
-	switch appEnv {
-	case constants.ENUM_RUN_TESTING:
-		envFile = ".env.test"
-	case constants.ENUM_RUN_PRODUCTION:
-		envFile = ".env.prod"
-	default:
-		envFile = ".env"
-	}
-
-	if err := godotenv.Overload(envFile); err != nil {
+	fileName := envFile(appEnv)
+	if err := godotenv.Overload(fileName); err != nil {
 		if !os.IsNotExist(err) || appEnv != constants.ENUM_RUN_PRODUCTION {
-			panic(fmt.Errorf("failed to load %s file: %w", envFile, err))
+			panic(fmt.Errorf("failed to load %s file: %w", fileName, err))
 		}
 	}
 }
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// EnvVar describes an environment variable read by the application.
+// Required variables must be set for the application to start and Secret variables are masked when displayed.
+// RequiredIf, when set, limits Required to configurations where it returns true.
+type EnvVar struct {
+	Name       string
+	Required   bool
+	RequiredIf func() bool
+	Secret     bool
+}
+
+// ENV_VARS lists the environment variables read by the application, in the order they are displayed.
+var ENV_VARS = []EnvVar{
+	{Name: "APP_ENV", Required: true},
+	{Name: "APP_NAME"},
+	{Name: "IS_LOGGER"},
+	{Name: "PORT"},
+	{Name: "DB_HOST", Required: true},
+	{Name: "DB_USER", Required: true},
+	{Name: "DB_PASS", Required: true, Secret: true},
+	{Name: "DB_NAME", Required: true},
+	{Name: "DB_PORT", Required: true},
+	{Name: "JWT_SECRET", Required: true, Secret: true},
+	{Name: "MAIL_DRIVER"},
+	{Name: "MAIL_FILE_DIR"},
+	{Name: "MAIL_TEMPLATE_DIR"},
+	{Name: "MAIL_DEFAULT_LOCALE"},
+	{Name: "SMTP_HOST", Required: true, RequiredIf: usesSMTP},
+	{Name: "SMTP_PORT", Required: true, RequiredIf: usesSMTP},
+	{Name: "SMTP_SENDER_NAME", Required: true, RequiredIf: usesSMTP},
+	{Name: "SMTP_AUTH_EMAIL", Required: true, RequiredIf: usesSMTP},
+	{Name: "SMTP_AUTH_PASSWORD", Required: true, RequiredIf: usesSMTP, Secret: true},
+	{Name: "SMTP_ENCRYPTION"},
+	{Name: "SMTP_INSECURE_SKIP_VERIFY"},
+	{Name: "SMTP_POOL_SIZE"},
+	{Name: "EMAIL_WEBHOOK_SECRET", Secret: true},
+	{Name: "STORAGE_DRIVER"},
+	{Name: "STORAGE_LOCAL_ROOT"},
+	{Name: "STORAGE_BASE_URL"},
+	{Name: "STORAGE_SIGNING_KEY", Secret: true},
+	{Name: "CURSOR_SIGNING_KEY", Secret: true},
+	{Name: "S3_ENDPOINT"},
+	{Name: "S3_REGION"},
+	{Name: "S3_BUCKET", Required: true, RequiredIf: usesS3},
+	{Name: "S3_ACCESS_KEY", Required: true, RequiredIf: usesS3},
+	{Name: "S3_SECRET_KEY", Required: true, RequiredIf: usesS3, Secret: true},
+	{Name: "S3_FORCE_PATH_STYLE"},
+	{Name: "SMS_DRIVER"},
+	{Name: "SMS_FROM"},
+	{Name: "SMS_HTTP_URL", Required: true, RequiredIf: usesHTTPSMS},
+	{Name: "SMS_HTTP_TOKEN", Secret: true},
+	{Name: "PHONE_DEFAULT_COUNTRY_CODE"},
+}
+
+// envFile returns the .env file name used for the given application environment.
+func envFile(appEnv string) string {
+	switch appEnv {
+	case constants.ENUM_RUN_TESTING:
+		return ".env.test"
+	case constants.ENUM_RUN_PRODUCTION:
+		return ".env.prod"
+	default:
+		return ".env"
+	}
+}
+
+// LoadEnvFile loads the .env file of the current application environment, ignoring a missing file.
+func LoadEnvFile() error {
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = constants.ENUM_RUN_DEVELOPMENT
+	}
+
+	if err := godotenv.Overload(envFile(appEnv)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// MissingEnv returns the names of the required environment variables that are unset or empty.
+func MissingEnv() []string {
+	var missing []string
+	for _, env := range ENV_VARS {
+		if env.Required && (env.RequiredIf == nil || env.RequiredIf()) && strings.TrimSpace(os.Getenv(env.Name)) == "" {
+			missing = append(missing, env.Name)
+		}
+	}
+
+	return missing
+}
+
+// MaskSecret hides all but the last two characters of a secret value.
+func MaskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+
+	return strings.Repeat("*", len(value)-2) + value[len(value)-2:]
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMissingEnv verifies that MissingEnv reports required variables that are unset or blank.
+func TestMissingEnv(t *testing.T) {
+	for _, env := range ENV_VARS {
+		t.Setenv(env.Name, "value")
+	}
+
+	assert.Empty(t, MissingEnv())
+
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_HOST", "  ")
+	t.Setenv("APP_NAME", "")
+
+	assert.Equal(t, []string{"DB_HOST", "JWT_SECRET"}, MissingEnv())
+}
+
+// TestMaskSecret verifies that MaskSecret hides all but the last two characters of longer secrets.
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "empty", value: "", want: ""},
+		{name: "short", value: "abcd", want: "****"},
+		{name: "long", value: "supersecret", want: "*********et"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, MaskSecret(tt.value))
+			},
+		)
+	}
+}
+
+// TestMissingEnv_MailDriver verifies that the SMTP variables are only required by the smtp mail driver.
+func TestMissingEnv_MailDriver(t *testing.T) {
+	for _, env := range ENV_VARS {
+		t.Setenv(env.Name, "value")
+	}
+	t.Setenv("SMTP_HOST", "")
+
+	t.Setenv("MAIL_DRIVER", "log")
+	assert.Empty(t, MissingEnv())
+
+	t.Setenv("MAIL_DRIVER", "")
+	assert.Equal(t, []string{"SMTP_HOST"}, MissingEnv())
+}
+
+// TestMissingEnv_StorageDriver verifies that the S3 credentials are only required by the s3 storage driver.
+func TestMissingEnv_StorageDriver(t *testing.T) {
+	for _, env := range ENV_VARS {
+		t.Setenv(env.Name, "value")
+	}
+	t.Setenv("S3_BUCKET", "")
+
+	t.Setenv("STORAGE_DRIVER", "local")
+	assert.Empty(t, MissingEnv())
+
+	t.Setenv("STORAGE_DRIVER", "s3")
+	assert.Equal(t, []string{"S3_BUCKET"}, MissingEnv())
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// MailConfig selects the mail backend and holds the options of every backend.
+// SMTP is only loaded, and only has to be complete, when Driver is smtp.
+type MailConfig struct {
+	Driver             string
+	From               string
+	FileDir            string
+	SMTP               *EmailConfig
+	Encryption         string
+	InsecureSkipVerify bool
+	PoolSize           int
+}
+
+// usesSMTP reports whether the configured mail driver delivers through an SMTP server.
+func usesSMTP() bool {
+	return mailDriver() == constants.ENUM_MAIL_DRIVER_SMTP
+}
+
+// mailDriver returns the configured mail driver, defaulting to smtp.
+func mailDriver() string {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DRIVER")))
+	if driver == "" {
+		return constants.ENUM_MAIL_DRIVER_SMTP
+	}
+
+	return driver
+}
+
+// mailFrom returns the From address of outgoing emails: the sender name, else the SMTP account, else a placeholder.
+func mailFrom() string {
+	for _, key := range []string{"SMTP_SENDER_NAME", "SMTP_AUTH_EMAIL"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+
+	return "no-reply@localhost"
+}
+
+// NewMailConfig initializes and returns a MailConfig from the MAIL_* and SMTP_* environment variables.
+var NewMailConfig = func() (*MailConfig, error) {
+	config := &MailConfig{
+		Driver:  mailDriver(),
+		From:    mailFrom(),
+		FileDir: getEnv("MAIL_FILE_DIR", "storage/mail"),
+	}
+
+	switch config.Driver {
+	case constants.ENUM_MAIL_DRIVER_FILE, constants.ENUM_MAIL_DRIVER_LOG, constants.ENUM_MAIL_DRIVER_MEMORY:
+		return config, nil
+	case constants.ENUM_MAIL_DRIVER_SMTP:
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", config.Driver)
+	}
+
+	smtpConfig, err := NewEmailConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.SMTP = smtpConfig
+
+	config.Encryption = strings.ToLower(getEnv("SMTP_ENCRYPTION", constants.ENUM_SMTP_ENCRYPTION_STARTTLS))
+	if config.Encryption != constants.ENUM_SMTP_ENCRYPTION_STARTTLS &&
+		config.Encryption != constants.ENUM_SMTP_ENCRYPTION_TLS {
+		return nil, fmt.Errorf("unknown SMTP encryption %q", config.Encryption)
+	}
+
+	config.InsecureSkipVerify, err = strconv.ParseBool(getEnv("SMTP_INSECURE_SKIP_VERIFY", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_INSECURE_SKIP_VERIFY: %w", err)
+	}
+
+	config.PoolSize, err = strconv.Atoi(getEnv("SMTP_POOL_SIZE", "2"))
+	if err != nil || config.PoolSize < 1 {
+		return nil, fmt.Errorf("SMTP_POOL_SIZE must be a positive number")
+	}
+
+	return config, nil
+}
+
+// MailTemplateConfig holds the settings used to render email templates.
+// Dir is an optional directory whose files override the embedded templates with the same relative path.
+type MailTemplateConfig struct {
+	Dir           string
+	DefaultLocale string
+	AppName       string
+}
+
+// NewMailTemplateConfig initializes and returns a MailTemplateConfig from the MAIL_TEMPLATE_DIR,
+// MAIL_DEFAULT_LOCALE and APP_NAME environment variables.
+var NewMailTemplateConfig = func() *MailTemplateConfig {
+	config := &MailTemplateConfig{
+		Dir:           strings.TrimSpace(os.Getenv("MAIL_TEMPLATE_DIR")),
+		DefaultLocale: strings.ToLower(strings.TrimSpace(os.Getenv("MAIL_DEFAULT_LOCALE"))),
+		AppName:       strings.TrimSpace(os.Getenv("APP_NAME")),
+	}
+
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = "en"
+	}
+	if config.AppName == "" {
+		config.AppName = "Go Gin Template"
+	}
+
+	return config
+}
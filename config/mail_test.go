@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNewMailConfig validates driver selection, SMTP options and their validation.
+func TestNewMailConfig(t *testing.T) {
+	smtpEnv := map[string]string{
+		"SMTP_HOST":          "smtp.example.com",
+		"SMTP_AUTH_EMAIL":    "user@example.com",
+		"SMTP_AUTH_PASSWORD": "password123",
+	}
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		wantConfig  *MailConfig
+		errContains string
+	}{
+		{
+			name:    "Log driver needs no SMTP settings",
+			envVars: map[string]string{"MAIL_DRIVER": "log"},
+			wantConfig: &MailConfig{
+				Driver:  constants.ENUM_MAIL_DRIVER_LOG,
+				From:    "no-reply@localhost",
+				FileDir: "storage/mail",
+			},
+		},
+		{
+			name:    "File driver with custom directory",
+			envVars: map[string]string{"MAIL_DRIVER": "FILE", "MAIL_FILE_DIR": "/tmp/mail", "SMTP_SENDER_NAME": "App <a@b.c>"},
+			wantConfig: &MailConfig{
+				Driver:  constants.ENUM_MAIL_DRIVER_FILE,
+				From:    "App <a@b.c>",
+				FileDir: "/tmp/mail",
+			},
+		},
+		{
+			name:    "SMTP driver is the default",
+			envVars: smtpEnv,
+			wantConfig: &MailConfig{
+				Driver:  constants.ENUM_MAIL_DRIVER_SMTP,
+				From:    "user@example.com",
+				FileDir: "storage/mail",
+				SMTP: &EmailConfig{
+					Host:         "smtp.example.com",
+					Port:         587,
+					AuthEmail:    "user@example.com",
+					AuthPassword: "password123",
+				},
+				Encryption: constants.ENUM_SMTP_ENCRYPTION_STARTTLS,
+				PoolSize:   2,
+			},
+		},
+		{
+			name:        "SMTP driver requires SMTP settings",
+			envVars:     map[string]string{"MAIL_DRIVER": "smtp"},
+			errContains: "email configuration is incomplete",
+		},
+		{
+			name:        "Unknown driver",
+			envVars:     map[string]string{"MAIL_DRIVER": "pigeon"},
+			errContains: "unknown mail driver",
+		},
+		{
+			name: "Unknown encryption",
+			envVars: map[string]string{
+				"SMTP_HOST": "smtp.example.com", "SMTP_AUTH_EMAIL": "user@example.com",
+				"SMTP_AUTH_PASSWORD": "password123", "SMTP_ENCRYPTION": "ssl3",
+			},
+			errContains: "unknown SMTP encryption",
+		},
+		{
+			name: "Invalid pool size",
+			envVars: map[string]string{
+				"SMTP_HOST": "smtp.example.com", "SMTP_AUTH_EMAIL": "user@example.com",
+				"SMTP_AUTH_PASSWORD": "password123", "SMTP_POOL_SIZE": "0",
+			},
+			errContains: "SMTP_POOL_SIZE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				for _, key := range []string{
+					"MAIL_DRIVER", "MAIL_FILE_DIR", "SMTP_HOST", "SMTP_PORT", "SMTP_SENDER_NAME", "SMTP_AUTH_EMAIL",
+					"SMTP_AUTH_PASSWORD", "SMTP_ENCRYPTION", "SMTP_INSECURE_SKIP_VERIFY", "SMTP_POOL_SIZE",
+				} {
+					t.Setenv(key, tt.envVars[key])
+					if _, ok := tt.envVars[key]; !ok {
+						require.NoError(t, os.Unsetenv(key))
+					}
+				}
+
+				got, err := NewMailConfig()
+
+				if tt.errContains != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.errContains)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantConfig, got)
+			},
+		)
+	}
+}
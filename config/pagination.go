@@ -0,0 +1,20 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"os"
+	"strings"
+)
+
+// NewCursorSigningKey returns CURSOR_SIGNING_KEY, the key that signs pagination cursors, or a key derived from
+// JWT_SECRET when it is unset so that the two secrets are never used interchangeably.
+var NewCursorSigningKey = func() []byte {
+	if key := strings.TrimSpace(os.Getenv("CURSOR_SIGNING_KEY")); key != "" {
+		return []byte(key)
+	}
+
+	mac := hmac.New(sha256.New, []byte(os.Getenv("JWT_SECRET")))
+	mac.Write([]byte("pagination-cursor-signing"))
+	return mac.Sum(nil)
+}
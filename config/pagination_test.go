@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCursorSigningKey validates that the cursor signing key is read from the environment or derived from the JWT
+// secret without reusing it or the storage signing key.
+func TestNewCursorSigningKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		check   func(t *testing.T, key []byte)
+	}{
+		{
+			name:    "Derived from the JWT secret",
+			envVars: map[string]string{"JWT_SECRET": "jwt", "CURSOR_SIGNING_KEY": ""},
+			check: func(t *testing.T, key []byte) {
+				assert.Len(t, key, 32)
+				assert.NotEqual(t, []byte("jwt"), key)
+				assert.NotEqual(t, storageSigningKey(), key, "storage and cursor signatures must not be interchangeable")
+			},
+		},
+		{
+			name:    "Explicit key",
+			envVars: map[string]string{"JWT_SECRET": "jwt", "CURSOR_SIGNING_KEY": " cursor "},
+			check: func(t *testing.T, key []byte) {
+				assert.Equal(t, []byte("cursor"), key)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				for key, value := range tt.envVars {
+					t.Setenv(key, value)
+				}
+				t.Setenv("STORAGE_SIGNING_KEY", "")
+
+				tt.check(t, NewCursorSigningKey())
+			},
+		)
+	}
+}
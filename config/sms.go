@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// SMSConfig selects the SMS backend and holds the options of every backend.
+// HTTPURL is only required when Driver is http; HTTPToken, when set, is sent as a bearer token.
+type SMSConfig struct {
+	Driver    string
+	From      string
+	HTTPURL   string
+	HTTPToken string
+}
+
+// usesHTTPSMS reports whether the configured SMS driver delivers through an HTTP gateway.
+func usesHTTPSMS() bool {
+	return smsDriver() == constants.ENUM_SMS_DRIVER_HTTP
+}
+
+// smsDriver returns the configured SMS driver, defaulting to log.
+func smsDriver() string {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("SMS_DRIVER")))
+	if driver == "" {
+		return constants.ENUM_SMS_DRIVER_LOG
+	}
+
+	return driver
+}
+
+// NewSMSConfig initializes and returns an SMSConfig from the SMS_* environment variables.
+var NewSMSConfig = func() (*SMSConfig, error) {
+	config := &SMSConfig{
+		Driver:    smsDriver(),
+		From:      strings.TrimSpace(os.Getenv("SMS_FROM")),
+		HTTPURL:   strings.TrimSpace(os.Getenv("SMS_HTTP_URL")),
+		HTTPToken: strings.TrimSpace(os.Getenv("SMS_HTTP_TOKEN")),
+	}
+
+	switch config.Driver {
+	case constants.ENUM_SMS_DRIVER_LOG, constants.ENUM_SMS_DRIVER_MEMORY:
+	case constants.ENUM_SMS_DRIVER_HTTP:
+		if config.HTTPURL == "" {
+			return nil, fmt.Errorf("SMS_HTTP_URL is required by the http SMS driver")
+		}
+	default:
+		return nil, fmt.Errorf("unknown SMS driver %q", config.Driver)
+	}
+
+	return config, nil
+}
+
+// PhoneConfig holds the settings used to normalize phone numbers.
+// DefaultCountryCode is the calling code, without a plus sign, assumed for numbers written in national format.
+type PhoneConfig struct {
+	DefaultCountryCode string
+}
+
+// NewPhoneConfig initializes and returns a PhoneConfig from the PHONE_DEFAULT_COUNTRY_CODE environment variable.
+var NewPhoneConfig = func() *PhoneConfig {
+	config := &PhoneConfig{
+		DefaultCountryCode: strings.TrimPrefix(strings.TrimSpace(os.Getenv("PHONE_DEFAULT_COUNTRY_CODE")), "+"),
+	}
+
+	if config.DefaultCountryCode == "" {
+		config.DefaultCountryCode = "62"
+	}
+
+	return config
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNewSMSConfig validates driver selection and the settings required by the HTTP driver.
+func TestNewSMSConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		wantConfig  *SMSConfig
+		errContains string
+	}{
+		{
+			name:       "Log driver is the default",
+			envVars:    map[string]string{},
+			wantConfig: &SMSConfig{Driver: constants.ENUM_SMS_DRIVER_LOG},
+		},
+		{
+			name:       "Memory driver",
+			envVars:    map[string]string{"SMS_DRIVER": "Memory", "SMS_FROM": "App"},
+			wantConfig: &SMSConfig{Driver: constants.ENUM_SMS_DRIVER_MEMORY, From: "App"},
+		},
+		{
+			name: "HTTP driver",
+			envVars: map[string]string{
+				"SMS_DRIVER": "http", "SMS_HTTP_URL": "https://sms.example.com/send", "SMS_HTTP_TOKEN": "secret",
+			},
+			wantConfig: &SMSConfig{
+				Driver:    constants.ENUM_SMS_DRIVER_HTTP,
+				HTTPURL:   "https://sms.example.com/send",
+				HTTPToken: "secret",
+			},
+		},
+		{
+			name:        "HTTP driver requires a URL",
+			envVars:     map[string]string{"SMS_DRIVER": "http"},
+			errContains: "SMS_HTTP_URL",
+		},
+		{
+			name:        "Unknown driver",
+			envVars:     map[string]string{"SMS_DRIVER": "pigeon"},
+			errContains: "unknown SMS driver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				for _, key := range []string{"SMS_DRIVER", "SMS_FROM", "SMS_HTTP_URL", "SMS_HTTP_TOKEN"} {
+					t.Setenv(key, tt.envVars[key])
+					if _, ok := tt.envVars[key]; !ok {
+						require.NoError(t, os.Unsetenv(key))
+					}
+				}
+
+				got, err := NewSMSConfig()
+
+				if tt.errContains != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.errContains)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantConfig, got)
+			},
+		)
+	}
+}
+
+// TestNewPhoneConfig validates the default country code and the removal of a leading plus sign.
+func TestNewPhoneConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Default", value: "", expected: "62"},
+		{name: "Plain code", value: "44", expected: "44"},
+		{name: "Code with plus sign", value: " +1 ", expected: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Setenv("PHONE_DEFAULT_COUNTRY_CODE", tt.value)
+				assert.Equal(t, tt.expected, NewPhoneConfig().DefaultCountryCode)
+			},
+		)
+	}
+}
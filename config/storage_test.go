@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNewStorageConfig validates driver selection, defaults and S3 settings.
+func TestNewStorageConfig(t *testing.T) {
+	s3Env := map[string]string{
+		"STORAGE_DRIVER": "s3",
+		"S3_BUCKET":      "files",
+		"S3_ACCESS_KEY":  "access",
+		"S3_SECRET_KEY":  "secret",
+	}
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		check       func(t *testing.T, config *StorageConfig)
+		errContains string
+	}{
+		{
+			name:    "Local driver is the default",
+			envVars: map[string]string{"JWT_SECRET": "jwt"},
+			check: func(t *testing.T, config *StorageConfig) {
+				assert.Equal(t, constants.ENUM_STORAGE_DRIVER_LOCAL, config.Driver)
+				assert.Equal(t, "assets", config.LocalRoot)
+				assert.Equal(t, "/api/storage", config.BaseURL)
+				assert.NotEmpty(t, config.SigningKey)
+				assert.NotEqual(t, []byte("jwt"), config.SigningKey, "the JWT secret should not be reused as is")
+				assert.Nil(t, config.S3)
+			},
+		},
+		{
+			name: "Explicit local settings",
+			envVars: map[string]string{
+				"STORAGE_DRIVER": "LOCAL", "STORAGE_LOCAL_ROOT": "/data", "STORAGE_BASE_URL": "https://cdn.example.com/",
+				"STORAGE_SIGNING_KEY": "signing",
+			},
+			check: func(t *testing.T, config *StorageConfig) {
+				assert.Equal(t, "/data", config.LocalRoot)
+				assert.Equal(t, "https://cdn.example.com", config.BaseURL)
+				assert.Equal(t, []byte("signing"), config.SigningKey)
+			},
+		},
+		{
+			name:    "S3 driver",
+			envVars: s3Env,
+			check: func(t *testing.T, config *StorageConfig) {
+				assert.Equal(
+					t, &S3Config{Region: "us-east-1", Bucket: "files", AccessKey: "access", SecretKey: "secret"},
+					config.S3,
+				)
+			},
+		},
+		{
+			name: "S3 driver with MinIO endpoint",
+			envVars: map[string]string{
+				"STORAGE_DRIVER": "s3", "S3_BUCKET": "files", "S3_ACCESS_KEY": "access", "S3_SECRET_KEY": "secret",
+				"S3_ENDPOINT": "http://minio:9000/", "S3_REGION": "eu-west-1", "S3_FORCE_PATH_STYLE": "true",
+			},
+			check: func(t *testing.T, config *StorageConfig) {
+				assert.Equal(t, "http://minio:9000", config.S3.Endpoint)
+				assert.Equal(t, "eu-west-1", config.S3.Region)
+				assert.True(t, config.S3.PathStyle)
+			},
+		},
+		{
+			name:        "S3 driver requires credentials",
+			envVars:     map[string]string{"STORAGE_DRIVER": "s3", "S3_BUCKET": "files"},
+			errContains: "S3_ACCESS_KEY",
+		},
+		{
+			name: "Invalid path style flag",
+			envVars: map[string]string{
+				"STORAGE_DRIVER": "s3", "S3_BUCKET": "files", "S3_ACCESS_KEY": "access", "S3_SECRET_KEY": "secret",
+				"S3_FORCE_PATH_STYLE": "maybe",
+			},
+			errContains: "S3_FORCE_PATH_STYLE",
+		},
+		{
+			name:        "Unknown driver",
+			envVars:     map[string]string{"STORAGE_DRIVER": "floppy"},
+			errContains: "unknown storage driver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				for _, key := range []string{
+					"JWT_SECRET", "STORAGE_DRIVER", "STORAGE_LOCAL_ROOT", "STORAGE_BASE_URL", "STORAGE_SIGNING_KEY",
+					"S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_ACCESS_KEY", "S3_SECRET_KEY", "S3_FORCE_PATH_STYLE",
+				} {
+					t.Setenv(key, tt.envVars[key])
+					if _, ok := tt.envVars[key]; !ok {
+						require.NoError(t, os.Unsetenv(key))
+					}
+				}
+
+				got, err := NewStorageConfig()
+
+				if tt.errContains != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.errContains)
+					return
+				}
+				require.NoError(t, err)
+				tt.check(t, got)
+			},
+		)
+	}
+}
@@ -28,4 +28,122 @@ const (
 
 	// JWTService is a constant key used for identifying the JWT service dependency in the dependency injection container.
 	JWTService = "JWTService"
+
+	// ENUM_OUTBOX_PENDING marks an outbox email that is waiting for its next delivery attempt.
+	ENUM_OUTBOX_PENDING = "pending"
+
+	// ENUM_OUTBOX_SENDING marks an outbox email claimed by a dispatcher, which holds it until its lease expires.
+	ENUM_OUTBOX_SENDING = "sending"
+
+	// ENUM_OUTBOX_SENT marks an outbox email that was delivered successfully.
+	ENUM_OUTBOX_SENT = "sent"
+
+	// ENUM_OUTBOX_DEAD marks an outbox email that exhausted its delivery attempts and will not be retried automatically.
+	ENUM_OUTBOX_DEAD = "dead"
+
+	// ENUM_MAIL_DRIVER_SMTP delivers emails through an SMTP server.
+	ENUM_MAIL_DRIVER_SMTP = "smtp"
+
+	// ENUM_MAIL_DRIVER_FILE writes every email as an .eml file into a directory instead of sending it.
+	ENUM_MAIL_DRIVER_FILE = "file"
+
+	// ENUM_MAIL_DRIVER_LOG writes every email to the application log instead of sending it.
+	ENUM_MAIL_DRIVER_LOG = "log"
+
+	// ENUM_MAIL_DRIVER_MEMORY keeps every email in memory so tests can inspect what was sent.
+	ENUM_MAIL_DRIVER_MEMORY = "memory"
+
+	// ENUM_SMTP_ENCRYPTION_STARTTLS upgrades a plain SMTP connection with STARTTLS when the server offers it.
+	ENUM_SMTP_ENCRYPTION_STARTTLS = "starttls"
+
+	// ENUM_SMTP_ENCRYPTION_TLS opens the SMTP connection over implicit TLS, usually on port 465.
+	ENUM_SMTP_ENCRYPTION_TLS = "tls"
+
+	// ENUM_SUPPRESSION_BOUNCE marks an address suppressed after a permanent delivery failure.
+	ENUM_SUPPRESSION_BOUNCE = "bounce"
+
+	// ENUM_SUPPRESSION_COMPLAINT marks an address suppressed after its owner reported an email as spam.
+	ENUM_SUPPRESSION_COMPLAINT = "complaint"
+
+	// ENUM_STORAGE_DRIVER_LOCAL stores files on the local disk and serves them through signed application URLs.
+	ENUM_STORAGE_DRIVER_LOCAL = "local"
+
+	// ENUM_STORAGE_DRIVER_S3 stores files in an S3-compatible bucket and serves them through presigned URLs.
+	ENUM_STORAGE_DRIVER_S3 = "s3"
+
+	// ENUM_STORAGE_DRIVER_MEMORY keeps files in memory so tests can inspect what was stored.
+	ENUM_STORAGE_DRIVER_MEMORY = "memory"
+
+	// ENUM_USER_ACTIVE marks an account that can sign in.
+	ENUM_USER_ACTIVE = "active"
+
+	// ENUM_USER_DEACTIVATED marks an account its owner switched off; it cannot sign in until it is restored, but it
+	// keeps its email address and is never purged.
+	ENUM_USER_DEACTIVATED = "deactivated"
+
+	// ENUM_USER_PENDING_DELETION marks a deleted account that can still be restored until its grace period ends.
+	ENUM_USER_PENDING_DELETION = "pending_deletion"
+
+	// ENUM_USER_PURGED marks an account whose grace period ended and whose data is being removed permanently.
+	ENUM_USER_PURGED = "purged"
+
+	// ENUM_UPLOAD_PENDING marks a resumable upload that is still receiving chunks.
+	ENUM_UPLOAD_PENDING = "pending"
+
+	// ENUM_UPLOAD_COMPLETED marks a resumable upload whose chunks were assembled into a single stored file.
+	ENUM_UPLOAD_COMPLETED = "completed"
+
+	// ENUM_DATA_EXPORT_PENDING marks a personal data export that is waiting to be built.
+	ENUM_DATA_EXPORT_PENDING = "pending"
+
+	// ENUM_DATA_EXPORT_PROCESSING marks a personal data export whose archive is being built.
+	ENUM_DATA_EXPORT_PROCESSING = "processing"
+
+	// ENUM_DATA_EXPORT_COMPLETED marks a personal data export whose archive is stored and can be downloaded.
+	ENUM_DATA_EXPORT_COMPLETED = "completed"
+
+	// ENUM_DATA_EXPORT_FAILED marks a personal data export whose archive could not be built.
+	ENUM_DATA_EXPORT_FAILED = "failed"
+
+	// ENUM_SMS_DRIVER_LOG writes every text message to the application log instead of sending it.
+	ENUM_SMS_DRIVER_LOG = "log"
+
+	// ENUM_SMS_DRIVER_MEMORY keeps every text message in memory so tests can inspect what was sent.
+	ENUM_SMS_DRIVER_MEMORY = "memory"
+
+	// ENUM_SMS_DRIVER_HTTP posts every text message as JSON to the endpoint of an SMS gateway.
+	ENUM_SMS_DRIVER_HTTP = "http"
+
+	// ENUM_AUDIT_ACCOUNT_CREATED records the creation of an account, by registration or by an administrator.
+	ENUM_AUDIT_ACCOUNT_CREATED = "account_created"
+
+	// ENUM_AUDIT_LOGIN records a successful sign-in.
+	ENUM_AUDIT_LOGIN = "login"
+
+	// ENUM_AUDIT_PASSWORD_CHANGED records a new password being set.
+	ENUM_AUDIT_PASSWORD_CHANGED = "password_changed"
+
+	// ENUM_AUDIT_EMAIL_CHANGE_REQUESTED records a request to change the email address, before it is confirmed.
+	ENUM_AUDIT_EMAIL_CHANGE_REQUESTED = "email_change_requested"
+
+	// ENUM_AUDIT_EMAIL_CHANGED records a confirmed change of the email address.
+	ENUM_AUDIT_EMAIL_CHANGED = "email_changed"
+
+	// ENUM_AUDIT_PHONE_VERIFIED records the verification of a phone number with a texted code.
+	ENUM_AUDIT_PHONE_VERIFIED = "phone_verified"
+
+	// ENUM_AUDIT_ROLE_CHANGED records an administrator assigning a new role.
+	ENUM_AUDIT_ROLE_CHANGED = "role_changed"
+
+	// ENUM_AUDIT_ACCOUNT_DEACTIVATED records an account being switched off by its owner.
+	ENUM_AUDIT_ACCOUNT_DEACTIVATED = "account_deactivated"
+
+	// ENUM_AUDIT_ACCOUNT_DELETED records an account being scheduled for deletion.
+	ENUM_AUDIT_ACCOUNT_DELETED = "account_deleted"
+
+	// ENUM_AUDIT_ACCOUNT_RESTORED records a deactivated or deleted account being made active again.
+	ENUM_AUDIT_ACCOUNT_RESTORED = "account_restored"
+
+	// ENUM_AUDIT_DATA_EXPORT_REQUESTED records a request for a copy of the personal data of the account.
+	ENUM_AUDIT_DATA_EXPORT_REQUESTED = "data_export_requested"
 )
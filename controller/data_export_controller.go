@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// DataExportController lets the authenticated user request a copy of their personal data and follow its progress.
+	DataExportController interface {
+		Request(ctx *gin.Context)
+		Get(ctx *gin.Context)
+	}
+
+	// dataExportController handles data export requests by delegating to the DataExportService.
+	dataExportController struct {
+		exportService service.DataExportService
+	}
+)
+
+// NewDataExportController creates and returns a new DataExportController using the provided DataExportService.
+func NewDataExportController(exportService service.DataExportService) DataExportController {
+	return &dataExportController{
+		exportService: exportService,
+	}
+}
+
+// @Summary Request a personal data export
+// @Description Queues a ZIP archive of the authenticated user's profile, sessions, audit events and uploaded files.
+// @Description A time-limited download link is emailed once it is built. While an export is still being built,
+// @Description that export is returned instead of queueing another one.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utils.Response{data=dto.DataExportResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /user/export [post]
+func (c *dataExportController) Request(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.exportService.Request(ctx.Request.Context(), userId, ctx.GetHeader("Accept-Language"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_REQUEST_DATA_EXPORT, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REQUEST_DATA_EXPORT, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+// @Summary Get a personal data export
+// @Description Returns the progress of a data export of the authenticated user, with a signed download URL once it
+// @Description is completed.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Data export ID"
+// @Success 200 {object} utils.Response{data=dto.DataExportResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /user/export/{id} [get]
+func (c *dataExportController) Get(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.exportService.Get(ctx.Request.Context(), userId, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_DATA_EXPORT, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_DATA_EXPORT, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// EmailOutboxController defines the admin actions on the email outbox.
+	EmailOutboxController interface {
+		GetAll(ctx *gin.Context)
+		Requeue(ctx *gin.Context)
+	}
+
+	// emailOutboxController handles email outbox requests by delegating to the EmailOutboxService.
+	emailOutboxController struct {
+		outboxService service.EmailOutboxService
+	}
+)
+
+// NewEmailOutboxController creates and returns a new EmailOutboxController using the provided EmailOutboxService.
+func NewEmailOutboxController(outboxService service.EmailOutboxService) EmailOutboxController {
+	return &emailOutboxController{
+		outboxService: outboxService,
+	}
+}
+
+// @Summary List outbox emails
+// @Description Lists queued, sent and dead-lettered emails, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(pending, sent, dead)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]dto.EmailOutboxResponse,meta=dto.PaginationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/email-outbox [get]
+func (c *emailOutboxController) GetAll(ctx *gin.Context) {
+	var req dto.EmailOutboxListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.outboxService.GetAllWithPagination(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_EMAIL_OUTBOX, err)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_LIST_EMAIL_OUTBOX,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary Requeue a dead email
+// @Description Resets the attempts of a dead-lettered email and schedules it for immediate delivery
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outbox email ID"
+// @Success 200 {object} utils.Response{data=dto.EmailOutboxResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/email-outbox/{id}/requeue [post]
+func (c *emailOutboxController) Requeue(ctx *gin.Context) {
+	result, err := c.outboxService.Requeue(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_REQUEUE_EMAIL, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REQUEUE_EMAIL, result)
+	ctx.JSON(http.StatusOK, res)
+}
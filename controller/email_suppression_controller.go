@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// EmailSuppressionController receives bounce and complaint webhooks and exposes the suppression list to admins.
+	EmailSuppressionController interface {
+		HandleEvents(ctx *gin.Context)
+		HandleDSN(ctx *gin.Context)
+		GetAll(ctx *gin.Context)
+		Clear(ctx *gin.Context)
+	}
+
+	// emailSuppressionController handles suppression requests by delegating to the EmailSuppressionService.
+	emailSuppressionController struct {
+		suppressionService service.EmailSuppressionService
+	}
+)
+
+// NewEmailSuppressionController creates and returns a new EmailSuppressionController using the provided
+// EmailSuppressionService.
+func NewEmailSuppressionController(suppressionService service.EmailSuppressionService) EmailSuppressionController {
+	return &emailSuppressionController{
+		suppressionService: suppressionService,
+	}
+}
+
+// @Summary Receive bounce and complaint events
+// @Description Suppresses the addresses of complaints and hard bounces; soft bounces are ignored.
+// @Description The request must be signed with the X-Webhook-Timestamp and X-Webhook-Signature headers.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Timestamp header string true "Unix time the request was signed at"
+// @Param X-Webhook-Signature header string true "sha256= followed by the hex HMAC-SHA256 of <timestamp>.<body>"
+// @Param request body dto.EmailEventRequest true "Bounce and complaint events"
+// @Success 200 {object} utils.Response{data=dto.EmailEventResult}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /webhooks/email/events [post]
+func (c *emailSuppressionController) HandleEvents(ctx *gin.Context) {
+	var req dto.EmailEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.suppressionService.HandleEvents(ctx.Request.Context(), req.Events)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_PROCESS_EMAIL_EVENTS, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_PROCESS_EMAIL_EVENTS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Receive a delivery status notification
+// @Description Parses an RFC 3464 delivery status notification and suppresses the recipients that failed permanently.
+// @Description The request must be signed with the X-Webhook-Timestamp and X-Webhook-Signature headers.
+// @Tags webhooks
+// @Accept plain
+// @Produce json
+// @Param X-Webhook-Timestamp header string true "Unix time the request was signed at"
+// @Param X-Webhook-Signature header string true "sha256= followed by the hex HMAC-SHA256 of <timestamp>.<body>"
+// @Param request body string true "Raw multipart/report message"
+// @Success 200 {object} utils.Response{data=dto.EmailEventResult}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /webhooks/email/dsn [post]
+func (c *emailSuppressionController) HandleDSN(ctx *gin.Context) {
+	result, err := c.suppressionService.HandleDSN(ctx.Request.Context(), ctx.Request.Body)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_PROCESS_EMAIL_EVENTS, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_PROCESS_EMAIL_EVENTS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary List suppressed addresses
+// @Description Lists the addresses the mailer will not send to, most recently updated first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Filter by email"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]dto.EmailSuppressionResponse,meta=dto.PaginationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/email-suppressions [get]
+func (c *emailSuppressionController) GetAll(ctx *gin.Context) {
+	var req dto.PaginationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.suppressionService.GetAllWithPagination(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_EMAIL_SUPPRESSION, err)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_LIST_EMAIL_SUPPRESSION,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary Clear a suppressed address
+// @Description Removes an address from the suppression list so the mailer sends to it again
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param email path string true "Suppressed email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/email-suppressions/{email} [delete]
+func (c *emailSuppressionController) Clear(ctx *gin.Context) {
+	if err := c.suppressionService.Clear(ctx.Request.Context(), ctx.Param("email")); err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_CLEAR_EMAIL_SUPPRESSION, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CLEAR_EMAIL_SUPPRESSION, nil)
+	ctx.JSON(http.StatusOK, res)
+}
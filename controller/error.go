@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError aborts the request with err, which middleware.ErrorHandler reports with the status and code of its
+// kind and with message.
+func abortWithError(ctx *gin.Context, message string, err error) {
+	ctx.Abort()
+	_ = ctx.Error(err).SetMeta(message)
+}
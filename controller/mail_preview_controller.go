@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// MailPreviewController renders email templates with sample data during development.
+	MailPreviewController interface {
+		List(ctx *gin.Context)
+		Preview(ctx *gin.Context)
+	}
+
+	// mailPreviewController handles mail preview requests by delegating to the template Renderer.
+	mailPreviewController struct {
+		renderer mailer.Renderer
+	}
+)
+
+// NewMailPreviewController creates and returns a new MailPreviewController using the provided Renderer.
+func NewMailPreviewController(renderer mailer.Renderer) MailPreviewController {
+	return &mailPreviewController{
+		renderer: renderer,
+	}
+}
+
+// @Summary List email templates
+// @Description Lists the email templates and their locales; only available in the dev environment
+// @Tags dev
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]mailer.TemplateInfo}
+// @Failure 500 {object} utils.Response
+// @Router /dev/mail [get]
+func (c *mailPreviewController) List(ctx *gin.Context) {
+	templates, err := c.renderer.Templates()
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_MAIL_TEMPLATE, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_MAIL_TEMPLATE, templates)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Preview an email template
+// @Description Renders an email template with its sample data; only available in the dev environment
+// @Tags dev
+// @Produce html,plain,json
+// @Param template path string true "Template name"
+// @Param locale query string false "Locale, defaults to the Accept-Language header"
+// @Param format query string false "Output format" Enums(html, text, json) default(html)
+// @Success 200 {object} utils.Response{data=dto.MailPreviewResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /dev/mail/{template} [get]
+func (c *mailPreviewController) Preview(ctx *gin.Context) {
+	var req dto.MailPreviewRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	if req.Locale == "" {
+		req.Locale = ctx.GetHeader("Accept-Language")
+	}
+
+	name := ctx.Param("template")
+	sample, err := c.renderer.Sample(name)
+	if err != nil {
+		renderFailed(ctx, err)
+		return
+	}
+
+	msg, err := c.renderer.Render(name, req.Locale, sample)
+	if err != nil {
+		renderFailed(ctx, err)
+		return
+	}
+
+	writePreview(ctx, req.Format, name, msg)
+}
+
+// renderFailed aborts the request with an error from rendering a template, reported with 404 for unknown templates.
+func renderFailed(ctx *gin.Context, err error) {
+	abortWithError(ctx, dto.MESSAGE_FAILED_RENDER_MAIL_TEMPLATE, err)
+}
+
+// writePreview writes a rendered email in the requested format.
+func writePreview(ctx *gin.Context, format string, name string, msg mailer.Message) {
+	switch format {
+	case dto.MAIL_PREVIEW_FORMAT_TEXT:
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(msg.Text))
+	case dto.MAIL_PREVIEW_FORMAT_JSON:
+		res := utils.BuildResponseSuccess(
+			dto.MESSAGE_SUCCESS_RENDER_MAIL_TEMPLATE, dto.MailPreviewResponse{
+				Template: name,
+				Subject:  msg.Subject,
+				HTML:     msg.HTML,
+				Text:     msg.Text,
+			},
+		)
+		ctx.JSON(http.StatusOK, res)
+	default:
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+	}
+}
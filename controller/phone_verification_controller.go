@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// PhoneVerificationController lets the authenticated user verify their phone number with a code sent by text
+	// message.
+	PhoneVerificationController interface {
+		SendCode(ctx *gin.Context)
+		Verify(ctx *gin.Context)
+	}
+
+	// phoneVerificationController handles phone verification requests by delegating to the PhoneVerificationService.
+	phoneVerificationController struct {
+		verificationService service.PhoneVerificationService
+	}
+)
+
+// NewPhoneVerificationController creates and returns a new PhoneVerificationController using the provided
+// PhoneVerificationService.
+func NewPhoneVerificationController(verificationService service.PhoneVerificationService) PhoneVerificationController {
+	return &phoneVerificationController{
+		verificationService: verificationService,
+	}
+}
+
+// @Summary Send a phone verification code
+// @Description Texts a 6-digit code to the phone number of the authenticated user, replacing any code sent before.
+// @Description The code expires after 10 minutes and a new one can be requested once a minute.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.PhoneVerificationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /user/phone/send_code [post]
+func (c *phoneVerificationController) SendCode(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.verificationService.SendCode(ctx.Request.Context(), userId)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_SEND_PHONE_CODE, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SEND_PHONE_CODE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Verify the phone number
+// @Description Marks the phone number of the authenticated user as verified when the code texted to it matches.
+// @Description After 5 wrong codes a new code must be requested.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} utils.Response{data=dto.PhoneVerificationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /user/phone/verify [post]
+func (c *phoneVerificationController) Verify(ctx *gin.Context) {
+	var req dto.VerifyPhoneRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.verificationService.VerifyCode(ctx.Request.Context(), userId, req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_VERIFY_PHONE, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_VERIFY_PHONE, result)
+	ctx.JSON(http.StatusOK, res)
+}
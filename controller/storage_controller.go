@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+type (
+	// StorageController serves stored files through the signed URLs of drivers that do not serve them themselves.
+	StorageController interface {
+		Download(ctx *gin.Context)
+	}
+
+	// storageController handles file downloads by delegating to the storage Driver.
+	storageController struct {
+		files storage.Driver
+	}
+)
+
+// NewStorageController creates and returns a new StorageController using the provided storage Driver.
+func NewStorageController(files storage.Driver) StorageController {
+	return &storageController{
+		files: files,
+	}
+}
+
+// @Summary Download a stored file
+// @Description Streams a stored file when the expiry time and signature of its signed URL are valid.
+// @Description Only used by the local storage driver; S3 signed URLs point at the bucket.
+// @Tags storage
+// @Produce octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Unix time the URL expires at"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /storage/{key} [get]
+func (c *storageController) Download(ctx *gin.Context) {
+	verifier, ok := c.files.(storage.SignedURLVerifier)
+	if !ok {
+		abortWithError(ctx, dto.MESSAGE_FAILED_DOWNLOAD_FILE, storage.ErrObjectNotFound)
+		return
+	}
+
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	if err := verifier.VerifySignedURL(key, ctx.Request.URL.Query()); err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_DOWNLOAD_FILE, err)
+		return
+	}
+
+	reader, info, err := c.files.Get(ctx.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidKey) {
+			err = storage.ErrObjectNotFound
+		}
+
+		abortWithError(ctx, dto.MESSAGE_FAILED_DOWNLOAD_FILE, err)
+		return
+	}
+	defer func() { _ = reader.Close() }()
+
+	ctx.DataFromReader(
+		http.StatusOK, info.Size, info.ContentType, reader, map[string]string{
+			"Cache-Control":          "private, max-age=300",
+			"X-Content-Type-Options": "nosniff",
+			"Content-Disposition":    fmt.Sprintf("inline; filename=%q", info.Key[strings.LastIndex(info.Key, "/")+1:]),
+		},
+	)
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// UploadController exposes the resumable upload protocol: create an upload, send its chunks, query its progress,
+	// complete it and attach the completed file to the authenticated user.
+	UploadController interface {
+		Create(ctx *gin.Context)
+		Get(ctx *gin.Context)
+		WriteChunk(ctx *gin.Context)
+		Complete(ctx *gin.Context)
+		Cancel(ctx *gin.Context)
+		AttachAvatar(ctx *gin.Context)
+	}
+
+	// uploadController handles upload requests by delegating to the UploadService.
+	uploadController struct {
+		uploadService service.UploadService
+	}
+)
+
+// NewUploadController creates and returns a new UploadController using the provided UploadService.
+func NewUploadController(uploadService service.UploadService) UploadController {
+	return &uploadController{
+		uploadService: uploadService,
+	}
+}
+
+// @Summary Create a resumable upload
+// @Description Starts an upload of the declared size. Its bytes are then sent as chunks with PATCH /uploads/{id}.
+// @Tags uploads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param upload body dto.UploadCreateRequest true "Upload request"
+// @Success 201 {object} utils.Response{data=dto.UploadResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Router /uploads [post]
+func (c *uploadController) Create(ctx *gin.Context) {
+	var req dto.UploadCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.uploadService.Create(ctx.Request.Context(), userId, req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_CREATE_UPLOAD, err)
+		return
+	}
+
+	ctx.Header(dto.UPLOAD_OFFSET_HEADER, strconv.FormatInt(result.Offset, 10))
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_UPLOAD, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// @Summary Get upload progress
+// @Description Returns an upload and the number of bytes it has received, also sent in the Upload-Offset header.
+// @Description A HEAD request returns only the headers, so a client can find where to resume.
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} utils.Response{data=dto.UploadResponse}
+// @Header 200 {integer} Upload-Offset "Number of bytes received"
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /uploads/{id} [get]
+func (c *uploadController) Get(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.uploadService.Get(ctx.Request.Context(), userId, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_UPLOAD, err)
+		return
+	}
+
+	ctx.Header(dto.UPLOAD_OFFSET_HEADER, strconv.FormatInt(result.Offset, 10))
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_UPLOAD, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Upload a chunk
+// @Description Appends the request body to an upload. The Upload-Offset header must equal the bytes received so far;
+// @Description on a mismatch the client should query the offset and resume from there.
+// @Tags uploads
+// @Accept octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Upload ID"
+// @Param Upload-Offset header integer true "Offset of the chunk within the file"
+// @Param Upload-Checksum header string false "sha256 followed by the base64 SHA-256 digest of the chunk"
+// @Param chunk body string true "Chunk bytes"
+// @Success 200 {object} utils.Response{data=dto.UploadResponse}
+// @Header 200 {integer} Upload-Offset "Number of bytes received"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /uploads/{id} [patch]
+func (c *uploadController) WriteChunk(ctx *gin.Context) {
+	offset, err := strconv.ParseInt(ctx.GetHeader(dto.UPLOAD_OFFSET_HEADER), 10, 64)
+	if err != nil || offset < 0 {
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPLOAD_CHUNK, dto.ErrUploadOffsetInvalid)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.uploadService.WriteChunk(
+		ctx.Request.Context(),
+		userId,
+		ctx.Param("id"),
+		offset,
+		ctx.GetHeader(dto.UPLOAD_CHECKSUM_HEADER),
+		ctx.Request.Body,
+	)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPLOAD_CHUNK, err)
+		return
+	}
+
+	ctx.Header(dto.UPLOAD_OFFSET_HEADER, strconv.FormatInt(result.Offset, 10))
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPLOAD_CHUNK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Complete an upload
+// @Description Assembles the chunks of a fully received upload into a single stored file and verifies its checksum.
+// @Description When the checksum does not match, the received bytes are discarded and the upload restarts at offset 0.
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} utils.Response{data=dto.UploadResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /uploads/{id}/complete [post]
+func (c *uploadController) Complete(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.uploadService.Complete(ctx.Request.Context(), userId, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_COMPLETE_UPLOAD, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_COMPLETE_UPLOAD, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Cancel an upload
+// @Description Removes an upload together with its chunks or its completed file.
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /uploads/{id} [delete]
+func (c *uploadController) Cancel(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	if err := c.uploadService.Cancel(ctx.Request.Context(), userId, ctx.Param("id")); err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_CANCEL_UPLOAD, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CANCEL_UPLOAD, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Use an upload as avatar
+// @Description Processes a completed upload as the authenticated user's profile image and removes the upload.
+// @Tags uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Upload ID"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Router /uploads/{id}/avatar [post]
+func (c *uploadController) AttachAvatar(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.uploadService.AttachAvatar(ctx.Request.Context(), userId, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPDATE_AVATAR, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_AVATAR, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -19,10 +19,17 @@ type (
 		Me(ctx *gin.Context)
 		Refresh(ctx *gin.Context)
 		GetAllUser(ctx *gin.Context)
+		Search(ctx *gin.Context)
 		SendVerificationEmail(ctx *gin.Context)
 		VerifyEmail(ctx *gin.Context)
 		Update(ctx *gin.Context)
+		ConfirmEmailChange(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		UpdateAvatar(ctx *gin.Context)
+		DeleteAvatar(ctx *gin.Context)
+		Deactivate(ctx *gin.Context)
+		Restore(ctx *gin.Context)
+		RestoreById(ctx *gin.Context)
 	}
 
 	// userController manages operations related to user entities by interacting with the UserService.
@@ -46,19 +53,20 @@ func NewUserController(us service.UserService) UserController {
 // @Param user body dto.UserCreateRequest true "User creation request"
 // @Success 200 {object} utils.Response{data=dto.UserResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
 // @Router /user [post]
 func (c *userController) Register(ctx *gin.Context) {
 	var user dto.UserCreateRequest
 	if err := ctx.ShouldBind(&user); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
+	user.Locale = ctx.GetHeader("Accept-Language")
 	result, err := c.userService.Register(ctx.Request.Context(), user)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGISTER_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_REGISTER_USER, err)
 		return
 	}
 
@@ -67,34 +75,93 @@ func (c *userController) Register(ctx *gin.Context) {
 }
 
 // @Summary Get all users with pagination
-// @Description Retrieves a paginated list of users
+// @Description Retrieves a paginated list of users, by page number or by the cursors of an earlier page.
+// @Description Only administrators may list users, since the list exposes email addresses and phone numbers.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param search query string false "Search term"
+// @Security BearerAuth
+// @Param search query string false "Search term, matched against name and email ignoring case"
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
+// @Param filter[role] query string false "Filter by a field, such as filter[role]=admin or filter[created_at][gte]=2025-01-01; operators are eq, ne, in, gte, lte and like"
+// @Param sort query string false "Comma-separated fields to sort by, prefixed with - to sort descending" example(-created_at,name)
+// @Param fields query string false "Comma-separated fields to return" example(id,email)
+// @Param cursor query string false "next_cursor or prev_cursor of an earlier page; continues from it instead of using page"
+// @Param count query string false "How to count the matching rows" Enums(exact, estimate, none)
 // @Success 200 {object} utils.Response{data=[]dto.UserResponse,meta=dto.PaginationResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Router /user [get]
 func (c *userController) GetAllUser(ctx *gin.Context) {
 	var req dto.PaginationRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
+	filters, err := dto.ParseFilters(ctx.Request.URL.Query())
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_USER, err)
+		return
+	}
+	req.Filters = filters
+
 	result, err := c.userService.GetAllUserWithPagination(ctx.Request.Context(), req)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_USER, err)
+		return
+	}
+
+	data, err := utils.SelectFields(result.Data, req.FieldList())
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_LIST_USER, err)
 		return
 	}
 
 	resp := utils.Response{
 		Status:  true,
 		Message: dto.MESSAGE_SUCCESS_GET_LIST_USER,
+		Data:    data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary Search users
+// @Description Finds users whose name, email address or phone number match the query, most relevant first.
+// @Description Words match as prefixes, names and email addresses tolerate typos, and phone numbers match on the
+// @Description digits of the query. Matched fields are returned in highlights, HTML-escaped with matches in <mark>.
+// @Description Only administrators may search, since matches expose email addresses and phone numbers.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query" maxlength(100)
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response{data=[]dto.UserSearchResult,meta=dto.PaginationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /user/search [get]
+func (c *userController) Search(ctx *gin.Context) {
+	var req dto.UserSearchRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.userService.Search(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_SEARCH_USER, err)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_SEARCH_USER,
 		Data:    result.Data,
 		Meta:    result.PaginationResponse,
 	}
@@ -103,50 +170,54 @@ func (c *userController) GetAllUser(ctx *gin.Context) {
 }
 
 // @Summary Get current user
-// @Description Retrieves the authenticated user's details
+// @Description Retrieves the authenticated user's details.
+// @Description The ETag header holds the version of the user, to be sent as If-Match when updating it.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Header 200 {string} ETag "Version of the user"
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /user/me [get]
 func (c *userController) Me(ctx *gin.Context) {
 	userId := ctx.MustGet("user_id").(string)
 
 	result, err := c.userService.GetUserById(ctx.Request.Context(), userId)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_GET_USER, err)
 		return
 	}
 
+	ctx.Header("ETag", utils.ETag(result.Version))
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_USER, result)
 	ctx.JSON(http.StatusOK, res)
 }
 
 // @Summary Login
-// @Description Authenticates a user and returns access and refresh tokens
+// @Description Authenticates a user and returns access and refresh tokens.
+// @Description Deactivated accounts are rejected with 403 and must be restored with POST /user/restore first.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param login body dto.UserLoginRequest true "Login request"
 // @Success 200 {object} utils.Response{data=dto.TokenResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Router /user/login [post]
 func (c *userController) Login(ctx *gin.Context) {
 	var req dto.UserLoginRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
 	result, err := c.userService.Verify(ctx.Request.Context(), req)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_LOGIN, err)
 		return
 	}
 
@@ -162,19 +233,19 @@ func (c *userController) Login(ctx *gin.Context) {
 // @Param email body dto.SendVerificationEmailRequest true "Email request"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /user/send_verification_email [post]
 func (c *userController) SendVerificationEmail(ctx *gin.Context) {
 	var req dto.SendVerificationEmailRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
+	req.Locale = ctx.GetHeader("Accept-Language")
 	err := c.userService.SendVerificationEmail(ctx.Request.Context(), req)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
 		return
 	}
 
@@ -190,19 +261,18 @@ func (c *userController) SendVerificationEmail(ctx *gin.Context) {
 // @Param verify body dto.VerifyEmailRequest true "Verification request"
 // @Success 200 {object} utils.Response{data=dto.VerifyEmailResponse}
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /user/verify_email [post]
 func (c *userController) VerifyEmail(ctx *gin.Context) {
 	var req dto.VerifyEmailRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
 	result, err := c.userService.VerifyEmail(ctx.Request.Context(), req)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_VERIFY_EMAIL, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_VERIFY_EMAIL, err)
 		return
 	}
 
@@ -211,38 +281,110 @@ func (c *userController) VerifyEmail(ctx *gin.Context) {
 }
 
 // @Summary Update user
-// @Description Updates the authenticated user's details
+// @Description Updates the authenticated user's details.
+// @Description If-Match must hold the ETag returned by GET /user/me. The update is refused with 412 when the user was
+// @Description changed since, so the client can fetch it again instead of overwriting the other change.
+// @Description With Content-Type application/merge-patch+json the body is a JSON merge patch (RFC 7396) of name, email
+// @Description and phone_number, where null clears a field. Otherwise empty fields are left unchanged.
+// @Description The response lists the fields that changed. A new email address is returned as pending_email and only
+// @Description applied once confirmed with POST /user/email/confirm, from the link emailed to it.
 // @Tags users
 // @Accept json
+// @Accept application/merge-patch+json
 // @Produce json
 // @Security BearerAuth
+// @Param If-Match header string true "ETag of the user being updated"
 // @Param user body dto.UserUpdateRequest true "Update request"
 // @Success 200 {object} utils.Response{data=dto.UserUpdateResponse}
+// @Header 200 {string} ETag "Version of the updated user"
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 412 {object} utils.Response
+// @Failure 428 {object} utils.Response
 // @Router /user [patch]
 func (c *userController) Update(ctx *gin.Context) {
-	var req dto.UserUpdateRequest
-	if err := ctx.ShouldBind(&req); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+	ifMatch := ctx.GetHeader("If-Match")
+	if ifMatch == "" {
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPDATE_USER, dto.ErrIfMatchRequired)
 		return
 	}
 
 	userId := ctx.MustGet("user_id").(string)
-	result, err := c.userService.Update(ctx.Request.Context(), req, userId)
+
+	var (
+		result dto.UserUpdateResponse
+		err    error
+	)
+	if ctx.ContentType() == utils.MERGE_PATCH_CONTENT_TYPE {
+		var patch []byte
+		if patch, err = ctx.GetRawData(); err != nil {
+			abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+			return
+		}
+
+		req := dto.UserPatchRequest{Patch: patch, IfMatch: ifMatch, Locale: ctx.GetHeader("Accept-Language")}
+		result, err = c.userService.Patch(ctx.Request.Context(), req, userId)
+	} else {
+		var req dto.UserUpdateRequest
+		if err = ctx.ShouldBind(&req); err != nil {
+			abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+			return
+		}
+		req.IfMatch = ifMatch
+		req.Locale = ctx.GetHeader("Accept-Language")
+
+		result, err = c.userService.Update(ctx.Request.Context(), req, userId)
+	}
+	if isValidationError(err) {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_UPDATE_USER, err)
+		return
+	}
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPDATE_USER, err)
 		return
 	}
 
+	ctx.Header("ETag", utils.ETag(result.Version))
 	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_USER, result)
 	ctx.JSON(http.StatusOK, res)
 }
 
+// @Summary Confirm email change
+// @Description Applies the change of email address confirmed by the token of the link emailed to the new address,
+// @Description and marks the new address as verified. The change is refused with 409 when another account uses the
+// @Description address by then.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param confirm body dto.ConfirmEmailChangeRequest true "Confirmation request"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Header 200 {string} ETag "Version of the updated user"
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /user/email/confirm [post]
+func (c *userController) ConfirmEmailChange(ctx *gin.Context) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.userService.ConfirmEmailChange(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_CONFIRM_EMAIL_CHANGE, err)
+		return
+	}
+
+	ctx.Header("ETag", utils.ETag(result.Version))
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CONFIRM_EMAIL_CHANGE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
 // @Summary Delete user
-// @Description Deletes the authenticated user's account
+// @Description Schedules the authenticated user's account for deletion and signs it out everywhere.
+// @Description The account can be restored with POST /user/restore until its grace period ends, then it is purged.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -250,13 +392,13 @@ func (c *userController) Update(ctx *gin.Context) {
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /user [delete]
 func (c *userController) Delete(ctx *gin.Context) {
 	userId := ctx.MustGet("user_id").(string)
 
 	if err := c.userService.Delete(ctx.Request.Context(), userId); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_USER, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_DELETE_USER, err)
 		return
 	}
 
@@ -264,6 +406,141 @@ func (c *userController) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+// @Summary Upload avatar
+// @Description Uploads or replaces the authenticated user's profile image and removes the previous one
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param image formData file true "Profile image (JPEG, PNG, GIF or WebP)"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Router /user/avatar [put]
+func (c *userController) UpdateAvatar(ctx *gin.Context) {
+	var req dto.UserAvatarRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	image, err := req.Image.Open()
+	if err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+	defer func() { _ = image.Close() }()
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.userService.UpdateAvatar(ctx.Request.Context(), userId, image)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_UPDATE_AVATAR, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_AVATAR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete avatar
+// @Description Removes the authenticated user's profile image
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /user/avatar [delete]
+func (c *userController) DeleteAvatar(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.userService.DeleteAvatar(ctx.Request.Context(), userId)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_DELETE_AVATAR, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_AVATAR, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Deactivate user
+// @Description Deactivates the authenticated user's account and signs it out everywhere.
+// @Description The account keeps its data and can be restored at any time with POST /user/restore.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /user/deactivate [post]
+func (c *userController) Deactivate(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.userService.Deactivate(ctx.Request.Context(), userId); err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_DEACTIVATE_USER, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DEACTIVATE_USER, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Restore user
+// @Description Reactivates a deactivated account, or an account pending deletion, and signs it in
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param login body dto.UserLoginRequest true "Credentials of the account to restore"
+// @Success 200 {object} utils.Response{data=dto.TokenResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /user/restore [post]
+func (c *userController) Restore(ctx *gin.Context) {
+	var req dto.UserLoginRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return
+	}
+
+	result, err := c.userService.Restore(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_RESTORE_USER, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESTORE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Restore user as admin
+// @Description Reactivates a deactivated account, or an account pending deletion, on behalf of its owner
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response{data=dto.UserResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/users/{id}/restore [post]
+func (c *userController) RestoreById(ctx *gin.Context) {
+	result, err := c.userService.RestoreById(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, dto.MESSAGE_FAILED_RESTORE_USER, err)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESTORE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
 // @Summary Refresh token
 // @Description Refreshes an access token using a refresh token
 // @Tags users
@@ -277,15 +554,13 @@ func (c *userController) Delete(ctx *gin.Context) {
 func (c *userController) Refresh(ctx *gin.Context) {
 	var req dto.RefreshTokenRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		abortWithValidationErrors(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
 		return
 	}
 
 	result, err := c.userService.RefreshToken(ctx.Request.Context(), req)
 	if err != nil {
-		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REFRESH_TOKEN, err.Error(), nil)
-		ctx.JSON(http.StatusUnauthorized, res)
+		abortWithError(ctx, dto.MESSAGE_FAILED_REFRESH_TOKEN, err)
 		return
 	}
 
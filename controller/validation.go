@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+// init makes the validator that gin binds requests with report fields by their JSON names and translate its messages.
+func init() {
+	if err := utils.RegisterValidator(binding.Validator.Engine().(*validator.Validate)); err != nil {
+		panic(err)
+	}
+}
+
+// abortWithValidationErrors aborts the request with 400 Bad Request and the fields refused by err, translated to the
+// language of the Accept-Language header.
+func abortWithValidationErrors(ctx *gin.Context, message string, err error) {
+	errs := utils.ValidationErrors(err, ctx.GetHeader("Accept-Language"))
+	res := utils.BuildResponseValidationFailed(message, apperror.ErrValidation.Code, errs)
+	ctx.AbortWithStatusJSON(apperror.ErrValidation.Status(), res)
+}
+
+// isValidationError reports whether err means that the fields of a request, or of the document it patches, were
+// refused, rather than that the request failed.
+func isValidationError(err error) bool {
+	var validationErrors validator.ValidationErrors
+	return errors.As(err, &validationErrors) || errors.Is(err, utils.ErrInvalidMergePatch)
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MESSAGE_FAILED_REQUEST_DATA_EXPORT indicates a failure while requesting a personal data export.
+	MESSAGE_FAILED_REQUEST_DATA_EXPORT = "failed request data export"
+
+	// MESSAGE_FAILED_GET_DATA_EXPORT indicates a failure while reading the status of a personal data export.
+	MESSAGE_FAILED_GET_DATA_EXPORT = "failed get data export"
+
+	// MESSAGE_SUCCESS_REQUEST_DATA_EXPORT indicates that a personal data export was queued.
+	MESSAGE_SUCCESS_REQUEST_DATA_EXPORT = "success request data export"
+
+	// MESSAGE_SUCCESS_GET_DATA_EXPORT indicates that the status of a personal data export was read.
+	MESSAGE_SUCCESS_GET_DATA_EXPORT = "success get data export"
+)
+
+var (
+	// ErrDataExportNotFound indicates that no data export with the requested ID belongs to the user.
+	ErrDataExportNotFound = apperror.New(apperror.KIND_NOT_FOUND, "DATA_EXPORT_NOT_FOUND", "data export not found")
+
+	// ErrDataExportExpired indicates that the archive of a data export was removed after its expiry time.
+	ErrDataExportExpired = apperror.New(apperror.KIND_GONE, "DATA_EXPORT_EXPIRED", "data export expired")
+
+	// ErrDataExportVersionConflict indicates that a data export was changed since the version a save was based on was
+	// read, such as by another worker that took over its build.
+	ErrDataExportVersionConflict = apperror.New(
+		apperror.KIND_CONFLICT, "DATA_EXPORT_VERSION_CONFLICT", "data export was modified by another worker",
+	)
+)
+
+type (
+	// DataExportResponse describes a personal data export and its progress.
+	// URL is a signed download URL of the ZIP archive once the export is completed; it stays valid until ExpiresAt.
+	DataExportResponse struct {
+		ID          string     `json:"id"`
+		Status      string     `json:"status"`
+		Size        int64      `json:"size,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+		ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+		URL         string     `json:"url,omitempty"`
+	}
+)
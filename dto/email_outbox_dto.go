@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MESSAGE_FAILED_GET_LIST_EMAIL_OUTBOX indicates a failure while listing outbox emails.
+	MESSAGE_FAILED_GET_LIST_EMAIL_OUTBOX = "failed get list email outbox"
+
+	// MESSAGE_FAILED_REQUEUE_EMAIL indicates a failure while requeueing an outbox email.
+	MESSAGE_FAILED_REQUEUE_EMAIL = "failed requeue email"
+
+	// MESSAGE_SUCCESS_GET_LIST_EMAIL_OUTBOX indicates that outbox emails were listed successfully.
+	MESSAGE_SUCCESS_GET_LIST_EMAIL_OUTBOX = "success get list email outbox"
+
+	// MESSAGE_SUCCESS_REQUEUE_EMAIL indicates that an outbox email was requeued for delivery.
+	MESSAGE_SUCCESS_REQUEUE_EMAIL = "success requeue email"
+)
+
+var (
+	// ErrEmailOutboxNotFound indicates that the requested outbox email does not exist.
+	ErrEmailOutboxNotFound = apperror.New(
+		apperror.KIND_NOT_FOUND, "EMAIL_OUTBOX_NOT_FOUND", "email outbox message not found",
+	)
+
+	// ErrEmailOutboxNotDead indicates that only dead-lettered outbox emails can be requeued.
+	ErrEmailOutboxNotDead = apperror.New(
+		apperror.KIND_CONFLICT, "EMAIL_OUTBOX_NOT_DEAD", "only dead emails can be requeued",
+	)
+
+	// ErrEmailOutboxLeaseLost indicates that the delivery outcome of an outbox email was not recorded because its
+	// lease expired and another dispatcher claimed it in the meantime.
+	ErrEmailOutboxLeaseLost = apperror.New(
+		apperror.KIND_CONFLICT, "EMAIL_OUTBOX_LEASE_LOST", "email outbox lease was lost",
+	)
+
+	// ErrEnqueueEmail indicates that an email could not be written to the outbox.
+	ErrEnqueueEmail = apperror.New(apperror.KIND_INTERNAL, "ENQUEUE_EMAIL_FAILED", "failed to enqueue email")
+)
+
+type (
+	// EmailOutboxListRequest holds the status filter and pagination of an outbox listing.
+	EmailOutboxListRequest struct {
+		Status string `form:"status" binding:"omitempty,oneof=pending sending sent dead"`
+		PaginationRequest
+	}
+
+	// EmailOutboxResponse represents an outbox email returned by the admin API, without its body.
+	EmailOutboxResponse struct {
+		ID            string     `json:"id"`
+		Recipient     string     `json:"recipient"`
+		Subject       string     `json:"subject"`
+		Status        string     `json:"status"`
+		Attempts      int        `json:"attempts"`
+		MaxAttempts   int        `json:"max_attempts"`
+		NextAttemptAt time.Time  `json:"next_attempt_at"`
+		LastError     string     `json:"last_error"`
+		SentAt        *time.Time `json:"sent_at"`
+		CreatedAt     time.Time  `json:"created_at"`
+	}
+
+	// EmailOutboxPaginationResponse represents a page of outbox emails and its pagination metadata.
+	EmailOutboxPaginationResponse struct {
+		Data []EmailOutboxResponse `json:"data"`
+		PaginationResponse
+	}
+)
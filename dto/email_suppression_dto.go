@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MESSAGE_FAILED_PROCESS_EMAIL_EVENTS indicates a failure while processing bounce or complaint notifications.
+	MESSAGE_FAILED_PROCESS_EMAIL_EVENTS = "failed process email events"
+
+	// MESSAGE_FAILED_GET_LIST_EMAIL_SUPPRESSION indicates a failure while listing suppressed addresses.
+	MESSAGE_FAILED_GET_LIST_EMAIL_SUPPRESSION = "failed get list email suppression"
+
+	// MESSAGE_FAILED_CLEAR_EMAIL_SUPPRESSION indicates a failure while removing an address from the suppression list.
+	MESSAGE_FAILED_CLEAR_EMAIL_SUPPRESSION = "failed clear email suppression"
+
+	// MESSAGE_SUCCESS_PROCESS_EMAIL_EVENTS indicates that bounce or complaint notifications were processed.
+	MESSAGE_SUCCESS_PROCESS_EMAIL_EVENTS = "success process email events"
+
+	// MESSAGE_SUCCESS_GET_LIST_EMAIL_SUPPRESSION indicates that suppressed addresses were listed successfully.
+	MESSAGE_SUCCESS_GET_LIST_EMAIL_SUPPRESSION = "success get list email suppression"
+
+	// MESSAGE_SUCCESS_CLEAR_EMAIL_SUPPRESSION indicates that an address was removed from the suppression list.
+	MESSAGE_SUCCESS_CLEAR_EMAIL_SUPPRESSION = "success clear email suppression"
+
+	// EMAIL_EVENT_BOUNCE is the type of a notification about a failed delivery.
+	EMAIL_EVENT_BOUNCE = "bounce"
+
+	// EMAIL_EVENT_COMPLAINT is the type of a notification about a recipient reporting an email as spam.
+	EMAIL_EVENT_COMPLAINT = "complaint"
+
+	// EMAIL_BOUNCE_HARD marks a permanent delivery failure, such as an unknown mailbox.
+	EMAIL_BOUNCE_HARD = "hard"
+
+	// EMAIL_BOUNCE_SOFT marks a temporary delivery failure, such as a full mailbox.
+	EMAIL_BOUNCE_SOFT = "soft"
+)
+
+var (
+	// ErrEmailSuppressionNotFound indicates that the address is not on the suppression list.
+	ErrEmailSuppressionNotFound = apperror.New(
+		apperror.KIND_NOT_FOUND, "EMAIL_SUPPRESSION_NOT_FOUND", "email is not suppressed",
+	)
+
+	// ErrInvalidWebhookSignature indicates that a webhook request is not signed with the configured secret.
+	ErrInvalidWebhookSignature = apperror.New(
+		apperror.KIND_UNAUTHORIZED, "INVALID_WEBHOOK_SIGNATURE", "invalid webhook signature",
+	)
+
+	// ErrWebhookBodyTooLarge indicates that a webhook request body exceeds the accepted size.
+	ErrWebhookBodyTooLarge = apperror.New(
+		apperror.KIND_TOO_LARGE, "WEBHOOK_BODY_TOO_LARGE", "request body too large",
+	)
+)
+
+type (
+	// EmailEventRequest is a batch of bounce and complaint notifications received by the email webhook.
+	EmailEventRequest struct {
+		Events []EmailEvent `json:"events" binding:"required,min=1,dive"`
+	}
+
+	// EmailEvent is a single bounce or complaint notification in the generic webhook format.
+	// A bounce suppresses the address when BounceType is hard, or when it is empty and Status is a 5.x.x code.
+	EmailEvent struct {
+		Type       string `json:"type" binding:"required,oneof=bounce complaint"`
+		Email      string `json:"email" binding:"required,email"`
+		BounceType string `json:"bounce_type" binding:"omitempty,oneof=hard soft"`
+		Status     string `json:"status"`
+		Diagnostic string `json:"diagnostic"`
+	}
+
+	// EmailEventResult reports how many notifications suppressed an address and how many were ignored.
+	EmailEventResult struct {
+		Suppressed int `json:"suppressed"`
+		Ignored    int `json:"ignored"`
+	}
+
+	// EmailSuppressionResponse represents a suppressed address returned by the admin API.
+	EmailSuppressionResponse struct {
+		Email     string    `json:"email"`
+		Reason    string    `json:"reason"`
+		Detail    string    `json:"detail"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	// EmailSuppressionPaginationResponse is a page of suppressed addresses with its pagination metadata.
+	EmailSuppressionPaginationResponse struct {
+		Data []EmailSuppressionResponse `json:"data"`
+		PaginationResponse
+	}
+)
@@ -0,0 +1,40 @@
+package dto
+
+const (
+	// MESSAGE_FAILED_GET_LIST_MAIL_TEMPLATE indicates a failure while listing email templates.
+	MESSAGE_FAILED_GET_LIST_MAIL_TEMPLATE = "failed get list mail template"
+
+	// MESSAGE_FAILED_RENDER_MAIL_TEMPLATE indicates a failure while rendering an email template preview.
+	MESSAGE_FAILED_RENDER_MAIL_TEMPLATE = "failed render mail template"
+
+	// MESSAGE_SUCCESS_GET_LIST_MAIL_TEMPLATE indicates that email templates were listed successfully.
+	MESSAGE_SUCCESS_GET_LIST_MAIL_TEMPLATE = "success get list mail template"
+
+	// MESSAGE_SUCCESS_RENDER_MAIL_TEMPLATE indicates that an email template preview was rendered successfully.
+	MESSAGE_SUCCESS_RENDER_MAIL_TEMPLATE = "success render mail template"
+
+	// MAIL_PREVIEW_FORMAT_HTML previews the HTML part of an email as a web page.
+	MAIL_PREVIEW_FORMAT_HTML = "html"
+
+	// MAIL_PREVIEW_FORMAT_TEXT previews the plain-text part of an email.
+	MAIL_PREVIEW_FORMAT_TEXT = "text"
+
+	// MAIL_PREVIEW_FORMAT_JSON returns the subject and every part of an email in a JSON response.
+	MAIL_PREVIEW_FORMAT_JSON = "json"
+)
+
+type (
+	// MailPreviewRequest selects the locale and output format of an email template preview.
+	MailPreviewRequest struct {
+		Locale string `form:"locale"`
+		Format string `form:"format" binding:"omitempty,oneof=html text json"`
+	}
+
+	// MailPreviewResponse is an email template rendered with its sample data.
+	MailPreviewResponse struct {
+		Template string `json:"template"`
+		Subject  string `json:"subject"`
+		HTML     string `json:"html"`
+		Text     string `json:"text"`
+	}
+)
@@ -1,19 +1,80 @@
 package dto
 
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// FILTER_OPERATOR_EQ matches values equal to the filter value.
+	FILTER_OPERATOR_EQ = "eq"
+	// FILTER_OPERATOR_NE matches values different from the filter value.
+	FILTER_OPERATOR_NE = "ne"
+	// FILTER_OPERATOR_IN matches values equal to one of the comma-separated filter values.
+	FILTER_OPERATOR_IN = "in"
+	// FILTER_OPERATOR_GTE matches values greater than or equal to the filter value.
+	FILTER_OPERATOR_GTE = "gte"
+	// FILTER_OPERATOR_LTE matches values less than or equal to the filter value.
+	FILTER_OPERATOR_LTE = "lte"
+	// FILTER_OPERATOR_LIKE matches values containing the filter value, ignoring case.
+	FILTER_OPERATOR_LIKE = "like"
+
+	// PAGINATION_COUNT_EXACT counts the matching rows, which is the default without a cursor.
+	PAGINATION_COUNT_EXACT = "exact"
+	// PAGINATION_COUNT_ESTIMATE estimates the matching rows from the query planner instead of counting them.
+	PAGINATION_COUNT_ESTIMATE = "estimate"
+	// PAGINATION_COUNT_NONE skips counting, which is the default with a cursor.
+	PAGINATION_COUNT_NONE = "none"
+)
+
+var (
+	// ErrInvalidListQuery is returned when the filters, sort order or fields of a list request are not supported.
+	ErrInvalidListQuery = apperror.New(apperror.KIND_VALIDATION, "INVALID_LIST_QUERY", "invalid list query")
+
+	// ErrInvalidCursor is returned when a pagination cursor was tampered with or was issued for another sort order.
+	ErrInvalidCursor = apperror.New(apperror.KIND_VALIDATION, "INVALID_CURSOR", "invalid cursor")
+)
+
 type (
 	// PaginationRequest represents a request structure for managing pagination parameters such as search, page, and per page.
+	// Sort lists the fields to order by, separated by commas, each prefixed with "-" to sort descending.
+	// Fields lists the fields to return, separated by commas. Filters are bound from the query with ParseFilters.
+	// Cursor is a next_cursor or prev_cursor of an earlier page; when set, the page continues from it and Page is
+	// ignored. Count is one of the PAGINATION_COUNT_* modes.
 	PaginationRequest struct {
-		Search  string `form:"search"`
-		Page    int    `form:"page"`
-		PerPage int    `form:"per_page"`
+		Search  string   `form:"search"`
+		Page    int      `form:"page"`
+		PerPage int      `form:"per_page"`
+		Sort    string   `form:"sort"`
+		Fields  string   `form:"fields"`
+		Cursor  string   `form:"cursor"`
+		Count   string   `form:"count"`
+		Filters []Filter `form:"-"`
+	}
+
+	// Filter is a condition on a single field of a list request, such as filter[role]=admin or
+	// filter[created_at][gte]=2025-01-01.
+	Filter struct {
+		Field    string
+		Operator string
+		Value    string
 	}
 
 	// PaginationResponse represents metadata for paginated responses including page, items per page, total pages, and item count.
+	// Page is 0 for pages read from a cursor. Count and MaxPage are 0 when counting was skipped, and approximate when
+	// CountEstimated is set. NextCursor and PrevCursor are set while there are more rows in either direction.
 	PaginationResponse struct {
-		Page    int   `json:"page"`
-		PerPage int   `json:"per_page"`
-		MaxPage int64 `json:"max_page"`
-		Count   int64 `json:"count"`
+		Page           int    `json:"page"`
+		PerPage        int    `json:"per_page"`
+		MaxPage        int64  `json:"max_page"`
+		Count          int64  `json:"count"`
+		CountEstimated bool   `json:"count_estimated,omitempty"`
+		NextCursor     string `json:"next_cursor,omitempty"`
+		PrevCursor     string `json:"prev_cursor,omitempty"`
 	}
 )
 
@@ -42,3 +103,81 @@ func (p *PaginationRequest) Default() {
 		p.PerPage = 10
 	}
 }
+
+// SortFields returns the fields of Sort in order, keeping their "-" prefix and skipping empty entries.
+func (p *PaginationRequest) SortFields() []string {
+	return splitList(p.Sort)
+}
+
+// FieldList returns the fields of Fields in order, skipping empty entries.
+func (p *PaginationRequest) FieldList() []string {
+	return splitList(p.Fields)
+}
+
+// ParseFilters reads the filter[field] and filter[field][operator] parameters of a query string, ordered by field.
+// A filter without an operator uses FILTER_OPERATOR_EQ. Fields and operators are checked against an allowlist by the
+// repository that applies them.
+func ParseFilters(query url.Values) ([]Filter, error) {
+	var filters []Filter
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") {
+			continue
+		}
+
+		field, operator, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			filters = append(filters, Filter{Field: field, Operator: operator, Value: value})
+		}
+	}
+
+	sort.SliceStable(
+		filters, func(i, j int) bool {
+			if filters[i].Field != filters[j].Field {
+				return filters[i].Field < filters[j].Field
+			}
+			return filters[i].Operator < filters[j].Operator
+		},
+	)
+
+	return filters, nil
+}
+
+// parseFilterKey splits a filter[field] or filter[field][operator] query key into its field and operator.
+func parseFilterKey(key string) (string, string, error) {
+	rest := strings.TrimPrefix(key, "filter")
+	var parts []string
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 2 {
+			return "", "", fmt.Errorf("%w: malformed filter %q", ErrInvalidListQuery, key)
+		}
+
+		parts = append(parts, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	switch len(parts) {
+	case 1:
+		return parts[0], FILTER_OPERATOR_EQ, nil
+	case 2:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("%w: malformed filter %q", ErrInvalidListQuery, key)
+	}
+}
+
+// splitList splits a comma-separated list, trimming spaces and skipping empty entries.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -182,3 +183,76 @@ func TestPaginationResponse_Fields(t *testing.T) {
 	assert.Equal(t, int64(5), resp.MaxPage)
 	assert.Equal(t, int64(100), resp.Count)
 }
+
+// TestParseFilters verifies that filter[field] and filter[field][operator] query parameters are read into filters.
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []Filter
+		wantErr  bool
+	}{
+		{
+			name:  "equality and operators",
+			query: "filter[role]=admin&filter[created_at][gte]=2025-01-01&page=2&sort=-name",
+			expected: []Filter{
+				{Field: "created_at", Operator: FILTER_OPERATOR_GTE, Value: "2025-01-01"},
+				{Field: "role", Operator: FILTER_OPERATOR_EQ, Value: "admin"},
+			},
+		},
+		{
+			name:  "repeated filter",
+			query: "filter[role][ne]=admin&filter[role][ne]=user",
+			expected: []Filter{
+				{Field: "role", Operator: FILTER_OPERATOR_NE, Value: "admin"},
+				{Field: "role", Operator: FILTER_OPERATOR_NE, Value: "user"},
+			},
+		},
+		{
+			name:  "unrelated parameters",
+			query: "search=jane&filters=x",
+		},
+		{
+			name:    "empty field",
+			query:   "filter[]=admin",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed bracket",
+			query:   "filter[role=admin",
+			wantErr: true,
+		},
+		{
+			name:    "too deep",
+			query:   "filter[role][eq][x]=admin",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				query, err := url.ParseQuery(tt.query)
+				assert.NoError(t, err)
+
+				filters, err := ParseFilters(query)
+				if tt.wantErr {
+					assert.ErrorIs(t, err, ErrInvalidListQuery)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, filters)
+			},
+		)
+	}
+}
+
+// TestPaginationRequest_Lists verifies that the sort and fields lists are split, trimmed and stripped of empty entries.
+func TestPaginationRequest_Lists(t *testing.T) {
+	req := PaginationRequest{Sort: "-created_at, name,", Fields: " id ,,email"}
+
+	assert.Equal(t, []string{"-created_at", "name"}, req.SortFields())
+	assert.Equal(t, []string{"id", "email"}, req.FieldList())
+	assert.Empty(t, (&PaginationRequest{}).SortFields())
+}
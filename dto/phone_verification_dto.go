@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MESSAGE_FAILED_SEND_PHONE_CODE indicates a failure while sending a phone verification code.
+	MESSAGE_FAILED_SEND_PHONE_CODE = "failed send phone verification code"
+
+	// MESSAGE_FAILED_VERIFY_PHONE indicates that a phone verification code was not accepted.
+	MESSAGE_FAILED_VERIFY_PHONE = "failed verify phone number"
+
+	// MESSAGE_SUCCESS_SEND_PHONE_CODE indicates that a phone verification code was sent.
+	MESSAGE_SUCCESS_SEND_PHONE_CODE = "success send phone verification code"
+
+	// MESSAGE_SUCCESS_VERIFY_PHONE indicates that a phone number was verified.
+	MESSAGE_SUCCESS_VERIFY_PHONE = "success verify phone number"
+)
+
+var (
+	// ErrPhoneNumberRequired indicates that a phone number cannot be verified because the user has none.
+	ErrPhoneNumberRequired = apperror.New(apperror.KIND_VALIDATION, "PHONE_NUMBER_REQUIRED", "user has no phone number")
+
+	// ErrPhoneAlreadyVerified indicates that the phone number of the user is already verified.
+	ErrPhoneAlreadyVerified = apperror.New(
+		apperror.KIND_CONFLICT, "PHONE_ALREADY_VERIFIED", "phone number already verified",
+	)
+
+	// ErrPhoneCodeResendTooSoon indicates that a new verification code was requested too soon after the last one.
+	ErrPhoneCodeResendTooSoon = apperror.New(
+		apperror.KIND_RATE_LIMITED, "PHONE_CODE_RESEND_TOO_SOON", "phone verification code was sent recently",
+	)
+
+	// ErrPhoneCodeInvalid indicates that a verification code is wrong or that no code was sent to the phone number.
+	ErrPhoneCodeInvalid = apperror.New(
+		apperror.KIND_VALIDATION, "PHONE_CODE_INVALID", "phone verification code invalid",
+	)
+
+	// ErrPhoneCodeExpired indicates that a verification code is no longer valid and a new one must be requested.
+	ErrPhoneCodeExpired = apperror.New(
+		apperror.KIND_VALIDATION, "PHONE_CODE_EXPIRED", "phone verification code expired",
+	)
+
+	// ErrPhoneCodeAttemptsExceeded indicates that too many wrong codes were tried and a new one must be requested.
+	ErrPhoneCodeAttemptsExceeded = apperror.New(
+		apperror.KIND_RATE_LIMITED, "PHONE_CODE_ATTEMPTS_EXCEEDED", "too many wrong phone verification codes",
+	)
+
+	// ErrSendPhoneCode represents an error that occurs when a verification code cannot be sent by text message.
+	ErrSendPhoneCode = apperror.New(
+		apperror.KIND_UNAVAILABLE, "SEND_PHONE_CODE_FAILED", "failed to send phone verification code",
+	)
+)
+
+type (
+	// VerifyPhoneRequest carries the code sent by text message to verify the phone number of the authenticated user.
+	VerifyPhoneRequest struct {
+		Code string `json:"code" form:"code" binding:"required,len=6,numeric"`
+	}
+
+	// PhoneVerificationResponse describes the verification of the phone number of a user.
+	// ExpiresAt is set when a code was sent and VerifiedAt once the number is verified.
+	PhoneVerificationResponse struct {
+		PhoneNumber string     `json:"phone_number"`
+		ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+		VerifiedAt  *time.Time `json:"verified_at,omitempty"`
+	}
+)
@@ -1,5 +1,9 @@
 package dto
 
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
 const (
 	// MESSAGE_SUCCESS_REFRESH_TOKEN represents a success message for a successful token refresh operation.
 	MESSAGE_SUCCESS_REFRESH_TOKEN = "Successfully refreshed token"
@@ -14,6 +18,18 @@ const (
 	MESSAGE_FAILED_EXPIRED_REFRESH_TOKEN = "Refresh token has expired"
 )
 
+var (
+	// ErrRefreshTokenInvalid indicates that a refresh token was not issued by the application or was revoked.
+	ErrRefreshTokenInvalid = apperror.New(
+		apperror.KIND_UNAUTHORIZED, "REFRESH_TOKEN_INVALID", MESSAGE_FAILED_INVALID_REFRESH_TOKEN,
+	)
+
+	// ErrRefreshTokenExpired indicates that a refresh token is past its expiry time.
+	ErrRefreshTokenExpired = apperror.New(
+		apperror.KIND_UNAUTHORIZED, "REFRESH_TOKEN_EXPIRED", MESSAGE_FAILED_EXPIRED_REFRESH_TOKEN,
+	)
+)
+
 // TokenResponse represents a response containing access and refresh tokens along with the associated user role.
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -0,0 +1,6 @@
+package dto
+
+const (
+	// MESSAGE_FAILED_DOWNLOAD_FILE indicates a failure while serving a stored file through a signed URL.
+	MESSAGE_FAILED_DOWNLOAD_FILE = "failed download file"
+)
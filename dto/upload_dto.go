@@ -0,0 +1,124 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MESSAGE_FAILED_CREATE_UPLOAD indicates a failure while starting a resumable upload.
+	MESSAGE_FAILED_CREATE_UPLOAD = "failed create upload"
+
+	// MESSAGE_FAILED_GET_UPLOAD indicates a failure while reading the progress of an upload.
+	MESSAGE_FAILED_GET_UPLOAD = "failed get upload"
+
+	// MESSAGE_FAILED_UPLOAD_CHUNK indicates that a chunk could not be appended to an upload.
+	MESSAGE_FAILED_UPLOAD_CHUNK = "failed upload chunk"
+
+	// MESSAGE_FAILED_COMPLETE_UPLOAD indicates a failure while assembling the chunks of an upload.
+	MESSAGE_FAILED_COMPLETE_UPLOAD = "failed complete upload"
+
+	// MESSAGE_FAILED_CANCEL_UPLOAD indicates a failure while cancelling an upload.
+	MESSAGE_FAILED_CANCEL_UPLOAD = "failed cancel upload"
+
+	// MESSAGE_SUCCESS_CREATE_UPLOAD indicates that a resumable upload was started.
+	MESSAGE_SUCCESS_CREATE_UPLOAD = "success create upload"
+
+	// MESSAGE_SUCCESS_GET_UPLOAD indicates that the progress of an upload was read.
+	MESSAGE_SUCCESS_GET_UPLOAD = "success get upload"
+
+	// MESSAGE_SUCCESS_UPLOAD_CHUNK indicates that a chunk was appended to an upload.
+	MESSAGE_SUCCESS_UPLOAD_CHUNK = "success upload chunk"
+
+	// MESSAGE_SUCCESS_COMPLETE_UPLOAD indicates that the chunks of an upload were assembled into a stored file.
+	MESSAGE_SUCCESS_COMPLETE_UPLOAD = "success complete upload"
+
+	// MESSAGE_SUCCESS_CANCEL_UPLOAD indicates that an upload and its chunks were removed.
+	MESSAGE_SUCCESS_CANCEL_UPLOAD = "success cancel upload"
+
+	// UPLOAD_OFFSET_HEADER carries the number of bytes an upload has received; chunks must be sent at that offset.
+	UPLOAD_OFFSET_HEADER = "Upload-Offset"
+
+	// UPLOAD_CHECKSUM_HEADER optionally carries the checksum of a chunk as "sha256 <base64 digest>".
+	UPLOAD_CHECKSUM_HEADER = "Upload-Checksum"
+)
+
+var (
+	// ErrUploadNotFound indicates that no upload with the requested ID belongs to the user.
+	ErrUploadNotFound = apperror.New(apperror.KIND_NOT_FOUND, "UPLOAD_NOT_FOUND", "upload not found")
+
+	// ErrUploadExpired indicates that an upload was abandoned for longer than its expiry time allows.
+	ErrUploadExpired = apperror.New(apperror.KIND_GONE, "UPLOAD_EXPIRED", "upload expired")
+
+	// ErrUploadTooLarge indicates that the declared size of an upload exceeds the maximum upload size.
+	ErrUploadTooLarge = apperror.New(apperror.KIND_TOO_LARGE, "UPLOAD_TOO_LARGE", "upload exceeds the maximum size")
+
+	// ErrUploadChunkTooLarge indicates that a chunk exceeds the maximum chunk size or the remaining size of the upload.
+	ErrUploadChunkTooLarge = apperror.New(
+		apperror.KIND_TOO_LARGE,
+		"UPLOAD_CHUNK_TOO_LARGE",
+		"chunk exceeds the maximum chunk size or the remaining upload size",
+	)
+
+	// ErrUploadOffsetMismatch indicates that a chunk was not sent at the current offset of the upload.
+	ErrUploadOffsetMismatch = apperror.New(
+		apperror.KIND_CONFLICT, "UPLOAD_OFFSET_MISMATCH", "chunk offset does not match the upload offset",
+	)
+
+	// ErrUploadOffsetInvalid indicates that a chunk was sent without a valid UPLOAD_OFFSET_HEADER.
+	ErrUploadOffsetInvalid = apperror.New(
+		apperror.KIND_VALIDATION, "UPLOAD_OFFSET_INVALID", "invalid "+UPLOAD_OFFSET_HEADER+" header",
+	)
+
+	// ErrUploadChecksumMismatch indicates that a chunk or the assembled file does not match its declared checksum.
+	ErrUploadChecksumMismatch = apperror.New(
+		apperror.KIND_UNPROCESSABLE, "UPLOAD_CHECKSUM_MISMATCH", "checksum mismatch",
+	)
+
+	// ErrUploadChecksumUnsupported indicates that a chunk checksum is malformed or uses an algorithm other than sha256.
+	ErrUploadChecksumUnsupported = apperror.New(
+		apperror.KIND_VALIDATION, "UPLOAD_CHECKSUM_UNSUPPORTED", "unsupported checksum, expected \"sha256 <base64 digest>\"",
+	)
+
+	// ErrUploadIncomplete indicates that an upload was completed before all of its bytes were received.
+	ErrUploadIncomplete = apperror.New(
+		apperror.KIND_CONFLICT, "UPLOAD_INCOMPLETE", "upload has not received all of its bytes",
+	)
+
+	// ErrUploadNotPending indicates that chunks were sent to, or completion requested for, an upload that is completed.
+	ErrUploadNotPending = apperror.New(apperror.KIND_CONFLICT, "UPLOAD_NOT_PENDING", "upload is already completed")
+
+	// ErrUploadNotCompleted indicates that an upload was used before it was completed.
+	ErrUploadNotCompleted = apperror.New(apperror.KIND_CONFLICT, "UPLOAD_NOT_COMPLETED", "upload is not completed")
+
+	// ErrUploadVersionConflict indicates that an upload was changed since the version a save was based on was read.
+	ErrUploadVersionConflict = apperror.New(
+		apperror.KIND_CONFLICT, "UPLOAD_VERSION_CONFLICT", "upload was modified by another request",
+	)
+)
+
+type (
+	// UploadCreateRequest starts a resumable upload of Size bytes.
+	// Checksum is the optional hex SHA-256 of the whole file, verified when the upload is completed.
+	UploadCreateRequest struct {
+		Filename    string `json:"filename" binding:"required,max=255"`
+		ContentType string `json:"content_type" binding:"omitempty,max=255"`
+		Size        int64  `json:"size" binding:"required,min=1"`
+		Checksum    string `json:"checksum" binding:"omitempty,len=64,hexadecimal"`
+	}
+
+	// UploadResponse describes a resumable upload and its progress.
+	// Offset is the number of bytes received so far; URL is a signed download URL once the upload is completed.
+	UploadResponse struct {
+		ID          string    `json:"id"`
+		Filename    string    `json:"filename"`
+		ContentType string    `json:"content_type"`
+		Size        int64     `json:"size"`
+		Offset      int64     `json:"offset"`
+		Checksum    string    `json:"checksum,omitempty"`
+		Status      string    `json:"status"`
+		ExpiresAt   time.Time `json:"expires_at"`
+		URL         string    `json:"url,omitempty"`
+	}
+)
@@ -1,9 +1,11 @@
 package dto
 
 import (
-	"errors"
+	"encoding/json"
 	"mime/multipart"
+	"time"
 
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 )
 
@@ -67,41 +69,105 @@ const (
 
 	// MESSAGE_SUCCESS_VERIFY_EMAIL represents a success message indicating that email verification has been successfully completed.
 	MESSAGE_SUCCESS_VERIFY_EMAIL = "success verify email"
+
+	// MESSAGE_FAILED_UPDATE_AVATAR indicates that uploading or replacing the profile image of a user failed.
+	MESSAGE_FAILED_UPDATE_AVATAR = "failed update avatar"
+
+	// MESSAGE_FAILED_DELETE_AVATAR indicates that removing the profile image of a user failed.
+	MESSAGE_FAILED_DELETE_AVATAR = "failed delete avatar"
+
+	// MESSAGE_SUCCESS_UPDATE_AVATAR indicates that the profile image of a user was uploaded or replaced.
+	MESSAGE_SUCCESS_UPDATE_AVATAR = "success update avatar"
+
+	// MESSAGE_SUCCESS_DELETE_AVATAR indicates that the profile image of a user was removed.
+	MESSAGE_SUCCESS_DELETE_AVATAR = "success delete avatar"
+
+	// MESSAGE_FAILED_DEACTIVATE_USER indicates that deactivating an account failed.
+	MESSAGE_FAILED_DEACTIVATE_USER = "failed deactivate user"
+
+	// MESSAGE_FAILED_RESTORE_USER indicates that restoring a deactivated or deleted account failed.
+	MESSAGE_FAILED_RESTORE_USER = "failed restore user"
+
+	// MESSAGE_SUCCESS_DEACTIVATE_USER indicates that an account was deactivated.
+	MESSAGE_SUCCESS_DEACTIVATE_USER = "success deactivate user"
+
+	// MESSAGE_SUCCESS_RESTORE_USER indicates that a deactivated or deleted account was restored.
+	MESSAGE_SUCCESS_RESTORE_USER = "success restore user"
+
+	// MESSAGE_FAILED_CONFIRM_EMAIL_CHANGE indicates that a pending email address change could not be applied.
+	MESSAGE_FAILED_CONFIRM_EMAIL_CHANGE = "failed confirm email change"
+
+	// MESSAGE_SUCCESS_CONFIRM_EMAIL_CHANGE indicates that a pending email address change was applied.
+	MESSAGE_SUCCESS_CONFIRM_EMAIL_CHANGE = "success confirm email change"
 )
 
 var (
 	// ErrCreateUser represents an error when the creation of a user record fails.
-	ErrCreateUser = errors.New("failed to create user")
-
-	// ErrGetUserById represents an error when failing to retrieve a user by their ID.
-	ErrGetUserById = errors.New("failed to get user by id")
-
-	// ErrGetUserByEmail represents an error when failing to retrieve a user by their email address.
-	ErrGetUserByEmail = errors.New("failed to get user by email")
+	ErrCreateUser = apperror.New(apperror.KIND_INTERNAL, "CREATE_USER_FAILED", "failed to create user")
 
 	// ErrEmailAlreadyExists indicates that an attempt was made to register with an email that is already in use.
-	ErrEmailAlreadyExists = errors.New("email already exist")
+	ErrEmailAlreadyExists = apperror.New(apperror.KIND_CONFLICT, "EMAIL_ALREADY_EXISTS", "email already exist")
 
 	// ErrUpdateUser represents an error that occurs when updating a user record fails.
-	ErrUpdateUser = errors.New("failed to update user")
+	ErrUpdateUser = apperror.New(apperror.KIND_INTERNAL, "UPDATE_USER_FAILED", "failed to update user")
 
 	// ErrUserNotFound indicates that the requested user could not be found in the system.
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = apperror.New(apperror.KIND_NOT_FOUND, "USER_NOT_FOUND", "user not found")
 
 	// ErrEmailNotFound indicates that the specified email address was not found in the system.
-	ErrEmailNotFound = errors.New("email not found")
+	ErrEmailNotFound = apperror.New(apperror.KIND_NOT_FOUND, "EMAIL_NOT_FOUND", "email not found")
 
 	// ErrDeleteUser represents an error that occurs when deleting a user record fails.
-	ErrDeleteUser = errors.New("failed to delete user")
+	ErrDeleteUser = apperror.New(apperror.KIND_INTERNAL, "DELETE_USER_FAILED", "failed to delete user")
+
+	// ErrTokenNotFound indicates that a request to a protected route carries no bearer token.
+	ErrTokenNotFound = apperror.New(apperror.KIND_UNAUTHORIZED, "TOKEN_NOT_FOUND", MESSAGE_FAILED_TOKEN_NOT_FOUND)
+
+	// ErrTokenNotValid indicates that the bearer token of a request is malformed, expired or not signed by the server.
+	ErrTokenNotValid = apperror.New(apperror.KIND_UNAUTHORIZED, "TOKEN_NOT_VALID", MESSAGE_FAILED_TOKEN_NOT_VALID)
+
+	// ErrAccessDenied indicates that the authenticated user does not hold a role allowed on the route.
+	ErrAccessDenied = apperror.New(apperror.KIND_FORBIDDEN, "ACCESS_DENIED", MESSAGE_FAILED_DENIED_ACCESS)
 
 	// ErrTokenInvalid indicates that the provided token is invalid or cannot be processed.
-	ErrTokenInvalid = errors.New("token invalid")
+	ErrTokenInvalid = apperror.New(apperror.KIND_VALIDATION, "TOKEN_INVALID", "token invalid")
 
 	// ErrTokenExpired indicates that the provided token has expired and is no longer valid.
-	ErrTokenExpired = errors.New("token expired")
+	ErrTokenExpired = apperror.New(apperror.KIND_VALIDATION, "TOKEN_EXPIRED", "token expired")
 
 	// ErrAccountAlreadyVerified indicates that the account has already been marked as verified.
-	ErrAccountAlreadyVerified = errors.New("account already verified")
+	ErrAccountAlreadyVerified = apperror.New(
+		apperror.KIND_CONFLICT, "ACCOUNT_ALREADY_VERIFIED", "account already verified",
+	)
+
+	// ErrInvalidRole indicates that the requested role is not one of the roles known to the system.
+	ErrInvalidRole = apperror.New(apperror.KIND_VALIDATION, "INVALID_ROLE", "invalid role")
+
+	// ErrPasswordTooShort indicates that a new password does not meet the minimum length of 8 characters.
+	ErrPasswordTooShort = apperror.New(
+		apperror.KIND_VALIDATION, "PASSWORD_TOO_SHORT", "password must be at least 8 characters",
+	)
+
+	// ErrInvalidCredentials indicates that no account matches the email address and password used to sign in.
+	ErrInvalidCredentials = apperror.New(apperror.KIND_UNAUTHORIZED, "INVALID_CREDENTIALS", "invalid email or password")
+
+	// ErrAccountDeactivated indicates that the account signing in is deactivated and must be restored first.
+	ErrAccountDeactivated = apperror.New(apperror.KIND_FORBIDDEN, "ACCOUNT_DEACTIVATED", "account is deactivated")
+
+	// ErrAccountNotRestorable indicates that an account is neither deactivated nor pending deletion, or was purged.
+	ErrAccountNotRestorable = apperror.New(
+		apperror.KIND_CONFLICT, "ACCOUNT_NOT_RESTORABLE", "account is not deactivated or pending deletion",
+	)
+
+	// ErrVersionConflict indicates that a user was changed since the version an update was based on was read.
+	ErrVersionConflict = apperror.New(
+		apperror.KIND_PRECONDITION_FAILED, "VERSION_CONFLICT", "user was modified by another request",
+	)
+
+	// ErrIfMatchRequired indicates that an update was sent without the If-Match header naming the version it is based on.
+	ErrIfMatchRequired = apperror.New(
+		apperror.KIND_PRECONDITION_REQUIRED, "IF_MATCH_REQUIRED", "If-Match header is required",
+	)
 )
 
 type (
@@ -112,6 +178,7 @@ type (
 		Email       string                `json:"email" form:"email" binding:"required,email"`
 		Password    string                `json:"password" form:"password" binding:"required,min=8"`
 		Image       *multipart.FileHeader `json:"-" form:"image" swaggerignore:"true"`
+		Locale      string                `json:"-" form:"-" swaggerignore:"true"`
 	}
 
 	// UserResponse represents the structure for user data returned in API responses.
@@ -123,6 +190,24 @@ type (
 		Role        string `json:"role"`
 		ImageUrl    string `json:"image_url"`
 		IsVerified  bool   `json:"is_verified"`
+
+		// ImageThumbnails maps thumbnail sizes in pixels, such as "64", to signed URLs of square profile image previews.
+		ImageThumbnails map[string]string `json:"image_thumbnails,omitempty"`
+
+		// EmailSuppressed reports that email to the address bounced or was marked as spam and is no longer sent.
+		EmailSuppressed bool `json:"email_suppressed"`
+
+		// Status is the account state: active, deactivated, pending_deletion or purged.
+		Status string `json:"status"`
+
+		// PurgeAt is when an account pending deletion is removed permanently.
+		PurgeAt *time.Time `json:"purge_at,omitempty"`
+
+		// PhoneVerifiedAt is when the phone number was verified with a code sent to it by text message.
+		PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+
+		// Version is the version of the user, returned in the ETag header.
+		Version int64 `json:"-"`
 	}
 
 	// UserPaginationResponse represents paginated response data for a list of users including metadata and user details.
@@ -142,6 +227,38 @@ type (
 		Name        string `json:"name" form:"name" binding:"omitempty,min=2,max=100"`
 		PhoneNumber string `json:"phone_number" form:"phone_number" binding:"omitempty,min=8,max=20"`
 		Email       string `json:"email" form:"email" binding:"omitempty,email"`
+
+		// IfMatch holds the If-Match header of the request. The update is refused unless it matches the ETag of the
+		// current version of the user; it is not checked when empty.
+		IfMatch string `json:"-" form:"-" swaggerignore:"true"`
+
+		// Locale is the locale of the emails sent when the email address is changed.
+		Locale string `json:"-" form:"-" swaggerignore:"true"`
+	}
+
+	// UserPatchRequest carries a JSON merge patch (RFC 7396) of the profile of the authenticated user.
+	UserPatchRequest struct {
+		// Patch is the merge patch applied to the UserProfile of the user. Members set to null clear the field.
+		Patch json.RawMessage
+
+		// IfMatch holds the If-Match header of the request, checked as for UserUpdateRequest.
+		IfMatch string
+
+		// Locale is the locale of the emails sent when the email address is changed.
+		Locale string
+	}
+
+	// UserProfile holds the fields of a user that its owner can change. Merge patches are applied to it and the result
+	// is validated before it is written. The JSON names of the fields are the names of their columns.
+	UserProfile struct {
+		Name        string `json:"name" binding:"required,min=2,max=100"`
+		Email       string `json:"email" binding:"required,email"`
+		PhoneNumber string `json:"phone_number" binding:"omitempty,min=8,max=20"`
+	}
+
+	// UserAvatarRequest carries the image uploaded to replace the profile image of the authenticated user.
+	UserAvatarRequest struct {
+		Image *multipart.FileHeader `form:"image" binding:"required" swaggerignore:"true"`
 	}
 
 	// UserUpdateResponse represents the response type returned after updating user details in the system.
@@ -152,11 +269,20 @@ type (
 		Role        string `json:"role"`
 		Email       string `json:"email"`
 		IsVerified  bool   `json:"is_verified"`
+		Version     int64  `json:"-"`
+
+		// ChangedFields lists the JSON names of the fields whose value was changed by the update.
+		ChangedFields []string `json:"changed_fields"`
+
+		// PendingEmail is the new email address requested by the update. It replaces Email once the change is
+		// confirmed with the link emailed to it.
+		PendingEmail string `json:"pending_email,omitempty"`
 	}
 
 	// SendVerificationEmailRequest represents a request to send a verification email to the user.
 	SendVerificationEmailRequest struct {
-		Email string `json:"email" form:"email" binding:"required"`
+		Email  string `json:"email" form:"email" binding:"required"`
+		Locale string `json:"-" form:"-" swaggerignore:"true"`
 	}
 
 	// VerifyEmailRequest represents the request structure for verifying user email using a token.
@@ -164,12 +290,25 @@ type (
 		Token string `json:"token" form:"token" binding:"required"`
 	}
 
+	// ConfirmEmailChangeRequest carries the token of the link emailed to confirm a change of email address.
+	ConfirmEmailChangeRequest struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+
 	// VerifyEmailResponse represents the response structure for email verification containing email and verification status.
 	VerifyEmailResponse struct {
 		Email      string `json:"email"`
 		IsVerified bool   `json:"is_verified"`
 	}
 
+	// AdminCreateRequest holds the details of an administrator account created from the command line.
+	AdminCreateRequest struct {
+		Name        string `json:"name" binding:"required,min=2,max=100"`
+		PhoneNumber string `json:"phone_number" binding:"omitempty,min=8,max=20"`
+		Email       string `json:"email" binding:"required,email"`
+		Password    string `json:"password" binding:"required,min=8"`
+	}
+
 	// UserLoginRequest represents the required fields for a user login request.
 	// Email is the user's email address, required for authentication.
 	// Password is the user's password, also required for authentication.
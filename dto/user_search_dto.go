@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+const (
+	// MESSAGE_FAILED_SEARCH_USER indicates a failure while searching users.
+	MESSAGE_FAILED_SEARCH_USER = "failed search user"
+
+	// MESSAGE_SUCCESS_SEARCH_USER indicates that users were searched.
+	MESSAGE_SUCCESS_SEARCH_USER = "success search user"
+)
+
+type (
+	// UserSearchRequest holds a full-text search over the name, email address and phone number of users.
+	UserSearchRequest struct {
+		Query   string `form:"q" binding:"required,max=100"`
+		Page    int    `form:"page"`
+		PerPage int    `form:"per_page"`
+	}
+
+	// UserSearchHit is a user matching a search, with its relevance and the highlighted fields that matched.
+	UserSearchHit struct {
+		User       entity.User
+		Rank       float64
+		Highlights map[string]string
+	}
+
+	// UserSearchRepositoryResponse holds a page of search hits, most relevant first, and pagination metadata.
+	UserSearchRepositoryResponse struct {
+		Hits []UserSearchHit
+		PaginationResponse
+	}
+
+	// UserSearchResult is a user matching a search. Rank orders results by relevance, higher first.
+	// Highlights maps the fields that matched, such as "name", to their HTML-escaped value with matches wrapped in <mark>.
+	UserSearchResult struct {
+		UserResponse
+		Rank       float64           `json:"rank"`
+		Highlights map[string]string `json:"highlights,omitempty"`
+	}
+
+	// UserSearchResponse represents a page of user search results and pagination metadata.
+	UserSearchResponse struct {
+		Data []UserSearchResult `json:"data"`
+		PaginationResponse
+	}
+)
+
+// Pagination returns the page requested by the search.
+func (r UserSearchRequest) Pagination() PaginationRequest {
+	return PaginationRequest{Page: r.Page, PerPage: r.PerPage}
+}
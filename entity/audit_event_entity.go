@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent records a security-relevant action on the account of a user, such as a sign-in or a password change.
+// Type is one of the ENUM_AUDIT_* constants and Details holds what the action changed; events are never updated and
+// are removed together with the user.
+type AuditEvent struct {
+	ID        uuid.UUID         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type      string            `gorm:"type:varchar(50);not null" json:"type"`
+	Details   map[string]string `gorm:"type:jsonb;serializer:json;not null" json:"details,omitempty"`
+	CreatedAt time.Time         `gorm:"type:timestamp with time zone;index" json:"created_at"`
+	User      User              `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// TableName returns the table name used by GORM for the AuditEvent model.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
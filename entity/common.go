@@ -13,6 +13,12 @@ type Timestamp struct {
 	DeletedAt gorm.DeletedAt
 }
 
+// Versioned provides the version number of an entity for optimistic concurrency control. It starts at 1 and is
+// incremented by every update, so an update made from an outdated copy of the entity can be detected and refused.
+type Versioned struct {
+	Version int64 `gorm:"not null;default:1" json:"-"`
+}
+
 // Authorization represents the structure for user authorization details containing a token and role.
 // Token holds the authentication token provided to the user.
 // Role defines the access level, restricted to "user" or "admin".
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExport is a request by a user for a copy of their personal data. It is built in the background into a ZIP
+// archive stored at StorageKey, and both the row and the archive are purged once ExpiresAt passes. Every save
+// increments its version, so a worker whose claim was taken over cannot overwrite the export.
+type DataExport struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Locale      string     `gorm:"type:varchar(100);not null;default:''" json:"-"`
+	StorageKey  string     `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	Size        int64      `gorm:"not null;default:0" json:"size"`
+	LastError   string     `gorm:"type:text;not null;default:''" json:"-"`
+	ClaimedAt   *time.Time `gorm:"type:timestamp with time zone" json:"-"`
+	ExpiresAt   *time.Time `gorm:"type:timestamp with time zone;index" json:"expires_at"`
+	CompletedAt *time.Time `gorm:"type:timestamp with time zone" json:"completed_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp with time zone" json:"updated_at"`
+	User        User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+
+	Versioned
+}
+
+// TableName returns the table name used by GORM for the DataExport model.
+func (DataExport) TableName() string {
+	return "data_exports"
+}
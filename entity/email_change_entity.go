@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailChange is a request by a user to change their email address to NewEmail. It is applied once the link emailed
+// to the new address is followed, identified by the SHA-256 hash of its token, and is void after ExpiresAt.
+type EmailChange struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	NewEmail  string    `gorm:"type:varchar(255);not null" json:"new_email"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone" json:"updated_at"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// TableName returns the table name used by GORM for the EmailChange model.
+func (EmailChange) TableName() string {
+	return "email_changes"
+}
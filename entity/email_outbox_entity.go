@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailOutbox is a queued email written in the same transaction as the change that triggers it and delivered
+// asynchronously by the email dispatcher. While an email is sending, NextAttemptAt holds the expiry of the lease of
+// the dispatcher that claimed it.
+type EmailOutbox struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Recipient     string     `gorm:"type:varchar(255);not null" json:"recipient"`
+	Subject       string     `gorm:"type:varchar(255);not null" json:"subject"`
+	Body          string     `gorm:"type:text;not null" json:"-"`
+	TextBody      string     `gorm:"type:text;not null;default:''" json:"-"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_email_outbox_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts   int        `gorm:"not null;default:8" json:"max_attempts"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp with time zone;not null;index:idx_email_outbox_due,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `gorm:"type:timestamp with time zone" json:"sent_at"`
+
+	Timestamp
+}
+
+// TableName returns the table name used by GORM for the EmailOutbox model.
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
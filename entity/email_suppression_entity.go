@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailSuppression is an address the mailer must not send to anymore because it hard-bounced or its owner
+// reported our email as spam. Email is stored lower-cased so lookups are case-insensitive.
+type EmailSuppression struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Email     string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Reason    string    `gorm:"type:varchar(20);not null" json:"reason"`
+	Detail    string    `gorm:"type:text" json:"detail"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone" json:"updated_at"`
+}
+
+// TableName returns the table name used by GORM for the EmailSuppression model.
+func (EmailSuppression) TableName() string {
+	return "email_suppressions"
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhoneVerification is the one-time code last sent by text message to prove that a user owns PhoneNumber. The code is
+// stored as a bcrypt hash, is void after ExpiresAt and stops being accepted once Attempts wrong codes were tried.
+// A user has at most one verification; sending a new code replaces it.
+type PhoneVerification struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	PhoneNumber string    `gorm:"type:varchar(20);not null" json:"phone_number"`
+	CodeHash    string    `gorm:"type:varchar(255);not null" json:"-"`
+	Attempts    int       `gorm:"not null;default:0" json:"attempts"`
+	ExpiresAt   time.Time `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	CreatedAt   time.Time `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp with time zone" json:"updated_at"`
+	User        User      `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// TableName returns the table name used by GORM for the PhoneVerification model.
+func (PhoneVerification) TableName() string {
+	return "phone_verifications"
+}
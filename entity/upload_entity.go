@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload is a resumable upload that a user sends in chunks. Chunks are kept in storage until the upload is completed,
+// when they are assembled into the object at StorageKey. Uploads are purged once ExpiresAt passes, whether they were
+// abandoned or completed but never used. Every save increments its version, so a save based on an outdated copy is
+// refused.
+type Upload struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Filename    string     `gorm:"type:varchar(255);not null" json:"filename"`
+	ContentType string     `gorm:"type:varchar(255);not null;default:''" json:"content_type"`
+	Size        int64      `gorm:"not null" json:"size"`
+	Received    int64      `gorm:"not null;default:0" json:"received"`
+	Checksum    string     `gorm:"type:varchar(64);not null;default:''" json:"checksum"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	StorageKey  string     `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	ExpiresAt   time.Time  `gorm:"type:timestamp with time zone;not null;index" json:"expires_at"`
+	CompletedAt *time.Time `gorm:"type:timestamp with time zone" json:"completed_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp with time zone" json:"updated_at"`
+	User        User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+
+	Versioned
+}
+
+// TableName returns the table name used by GORM for the Upload model.
+func (Upload) TableName() string {
+	return "uploads"
+}
@@ -1,31 +1,57 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/helpers"
 )
 
 // User represents a system user with authentication and profile information.
+// The email address is only unique among users that are not soft-deleted, so the address of an account pending
+// deletion can be registered again.
 type User struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
 	Name        string    `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
-	Email       string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"email" validate:"required,email"`
+	Email       string    `gorm:"type:varchar(255);uniqueIndex:idx_users_email,where:deleted_at IS NULL;not null" json:"email" validate:"required,email"`
 	PhoneNumber string    `gorm:"type:varchar(20);index" json:"phone_number" validate:"omitempty,required,min=8,max=20"`
 	Password    string    `gorm:"type:varchar(255);not null" json:"-" validate:"required,min=8"`
 	Role        string    `gorm:"type:varchar(50);not null;default:'user'" json:"role" validate:"required,oneof=user admin"`
-	ImageUrl    string    `gorm:"type:varchar(255)" json:"image_url" validate:"omitempty,url"`
 	IsVerified  bool      `gorm:"default:false" json:"is_verified"`
 
+	// ImageUrl holds the storage key of the profile image, or an absolute URL for images stored before keys were used.
+	ImageUrl string `gorm:"type:varchar(255)" json:"image_url" validate:"omitempty,max=255"`
+
+	// EmailSuppressed is set while the address is on the email suppression list.
+	EmailSuppressed bool `gorm:"not null;default:false" json:"email_suppressed"`
+
+	// Status is the account state: active, deactivated, pending_deletion or purged.
+	// Accounts pending deletion are also soft-deleted, so the default GORM scope hides them.
+	Status string `gorm:"type:varchar(20);not null;default:'active';index" json:"status" validate:"omitempty,oneof=active deactivated pending_deletion purged"`
+
+	// PurgeAt is when an account pending deletion is removed permanently; it is nil in every other state.
+	PurgeAt *time.Time `gorm:"type:timestamp with time zone;index" json:"purge_at"`
+
+	// PhoneVerifiedAt is when the owner proved they receive text messages at PhoneNumber; it is nil until then and
+	// again whenever the phone number changes.
+	PhoneVerifiedAt *time.Time `gorm:"type:timestamp with time zone" json:"phone_verified_at"`
+
+	// SearchVector is the full-text document of the name, email address and phone number, generated by the database.
+	// It is never read or written through GORM; it only exists so that migrations create it and schema checks map it.
+	SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('simple', coalesce(name, '')), 'A') || setweight(to_tsvector('simple', coalesce(email, '') || ' ' || replace(coalesce(email, ''), '@', ' ')), 'B') || setweight(to_tsvector('simple', coalesce(phone_number, '')), 'C')) STORED;index:idx_users_search_vector,type:gin;->:false;<-:false" json:"-"`
+
+	Versioned
 	Timestamp
 }
 
 // validate is an instance of a validator used to validate structs based on defined tags.
 var validate = validator.New()
 
-// BeforeCreate is a GORM hook executed before creating a User record to hash the password, set default role, and validate the struct.
+// BeforeCreate is a GORM hook executed before creating a User record to hash the password, set default role, status and version, and validate the struct.
 func (u *User) BeforeCreate(_ *gorm.DB) (err error) {
 	if u.Password != "" {
 		u.Password, err = helpers.HashPassword(u.Password)
@@ -42,6 +68,14 @@ func (u *User) BeforeCreate(_ *gorm.DB) (err error) {
 		u.Role = "user"
 	}
 
+	if u.Status == "" {
+		u.Status = constants.ENUM_USER_ACTIVE
+	}
+
+	if u.Version == 0 {
+		u.Version = 1
+	}
+
 	if err := validate.Struct(u); err != nil {
 		return err
 	}
@@ -46,6 +46,7 @@ func TestUser_BeforeCreate(t *testing.T) {
 			expectError: false,
 			validate: func(t *testing.T, user *User) {
 				assert.Equal(t, "user", user.Role, "Role should default to user")
+				assert.Equal(t, "active", user.Status, "Status should default to active")
 				assert.NotEqual(t, uuid.Nil, user.ID, "ID should be set")
 			},
 		},
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+// ErrInvalidPhoneNumber is returned when a phone number cannot be written in E.164 format.
+var ErrInvalidPhoneNumber = apperror.New(apperror.KIND_VALIDATION, "INVALID_PHONE_NUMBER", "invalid phone number")
+
+var (
+	// phoneSeparators matches the characters people write between the digits of a phone number.
+	phoneSeparators = regexp.MustCompile(`[\s().\-/]`)
+
+	// e164Pattern matches a phone number in E.164 format: a plus sign and up to 15 digits, the first not zero.
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+)
+
+// NormalizePhoneNumber writes a phone number in E.164 format. Spaces, dots, dashes, slashes and parentheses are
+// removed, and an international prefix of + or 00 is kept as is. A number in national format, starting with a single
+// 0 trunk prefix, gets defaultCountryCode instead of that 0, and any other number is taken to start with its country
+// code. It returns ErrInvalidPhoneNumber when the result is not a valid E.164 number.
+func NormalizePhoneNumber(number string, defaultCountryCode string) (string, error) {
+	digits := phoneSeparators.ReplaceAllString(strings.TrimSpace(number), "")
+
+	switch {
+	case strings.HasPrefix(digits, "+"):
+	case strings.HasPrefix(digits, "00"):
+		digits = "+" + digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		if defaultCountryCode == "" {
+			return "", ErrInvalidPhoneNumber
+		}
+		digits = "+" + strings.TrimPrefix(defaultCountryCode, "+") + digits[1:]
+	default:
+		digits = "+" + digits
+	}
+
+	if !e164Pattern.MatchString(digits) {
+		return "", ErrInvalidPhoneNumber
+	}
+
+	return digits, nil
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizePhoneNumber tests writing phone numbers in E.164 format from the formats people commonly use.
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		number      string
+		countryCode string
+		expected    string
+		err         error
+	}{
+		{name: "already E.164", number: "+6281234567890", countryCode: "62", expected: "+6281234567890"},
+		{name: "national format", number: "081234567890", countryCode: "62", expected: "+6281234567890"},
+		{name: "national format with separators", number: "(0812) 3456-7890", countryCode: "62", expected: "+6281234567890"},
+		{name: "international prefix", number: "0044 20 7946 0958", countryCode: "62", expected: "+442079460958"},
+		{name: "plus sign with dots", number: "+1.415.555.2671", countryCode: "62", expected: "+14155552671"},
+		{name: "country code without plus sign", number: "14155552671", countryCode: "62", expected: "+14155552671"},
+		{name: "default code with plus sign", number: "020 7946 0958", countryCode: "+44", expected: "+442079460958"},
+		{name: "national format without default code", number: "081234567890", err: ErrInvalidPhoneNumber},
+		{name: "letters", number: "+62812CALLME", countryCode: "62", err: ErrInvalidPhoneNumber},
+		{name: "too short", number: "+62123", countryCode: "62", err: ErrInvalidPhoneNumber},
+		{name: "too long", number: "+6212345678901234", countryCode: "62", err: ErrInvalidPhoneNumber},
+		{name: "country code zero", number: "+0123456789", countryCode: "62", err: ErrInvalidPhoneNumber},
+		{name: "empty", number: "", countryCode: "62", err: ErrInvalidPhoneNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				result, err := NormalizePhoneNumber(tt.number, tt.countryCode)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			},
+		)
+	}
+}
@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+// ErrNotDSN indicates that a message is not an RFC 3464 delivery status notification.
+var ErrNotDSN = apperror.New(apperror.KIND_VALIDATION, "NOT_DSN", "message is not a delivery status notification")
+
+// DSNRecipient is the per-recipient part of a delivery status notification.
+type DSNRecipient struct {
+	Recipient      string `json:"recipient"`
+	Action         string `json:"action"`
+	Status         string `json:"status"`
+	DiagnosticCode string `json:"diagnostic_code"`
+}
+
+// Permanent reports whether delivery to the recipient failed permanently, i.e. a hard bounce.
+func (r DSNRecipient) Permanent() bool {
+	return strings.EqualFold(r.Action, "failed") && strings.HasPrefix(r.Status, "5")
+}
+
+// ParseDSN parses an RFC 3464 delivery status notification: a multipart/report message with report-type
+// delivery-status whose message/delivery-status part holds one block of per-recipient fields per recipient.
+func ParseDSN(r io.Reader) ([]DSNRecipient, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Join(ErrNotDSN, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || !strings.EqualFold(params["report-type"], "delivery-status") {
+		return nil, ErrNotDSN
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNotDSN
+		}
+		if err != nil {
+			return nil, errors.Join(ErrNotDSN, err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType == "message/delivery-status" || partType == "message/global-delivery-status" {
+			return parseDeliveryStatus(part)
+		}
+	}
+}
+
+// parseDeliveryStatus reads the blank-line separated field blocks of a delivery-status part. The per-message block
+// carries no recipient field, so only the per-recipient blocks produce a DSNRecipient.
+func parseDeliveryStatus(r io.Reader) ([]DSNRecipient, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	var recipients []DSNRecipient
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if recipient, ok := dsnRecipient(fields); ok {
+			recipients = append(recipients, recipient)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(ErrNotDSN, err)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil, ErrNotDSN
+	}
+
+	return recipients, nil
+}
+
+// dsnRecipient builds a DSNRecipient from a per-recipient field block, preferring Final-Recipient.
+func dsnRecipient(fields textproto.MIMEHeader) (DSNRecipient, bool) {
+	address := dsnValue(fields.Get("Final-Recipient"))
+	if address == "" {
+		address = dsnValue(fields.Get("Original-Recipient"))
+	}
+	if address == "" {
+		return DSNRecipient{}, false
+	}
+
+	status, _, _ := strings.Cut(strings.TrimSpace(fields.Get("Status")), " ")
+
+	return DSNRecipient{
+		Recipient:      strings.ToLower(strings.Trim(address, "<>")),
+		Action:         strings.ToLower(strings.TrimSpace(fields.Get("Action"))),
+		Status:         status,
+		DiagnosticCode: dsnValue(fields.Get("Diagnostic-Code")),
+	}, true
+}
+
+// dsnValue strips the type prefix of a typed DSN field such as "rfc822; user@example.com".
+func dsnValue(field string) string {
+	if _, value, found := strings.Cut(field, ";"); found {
+		return strings.TrimSpace(value)
+	}
+
+	return strings.TrimSpace(field)
+}
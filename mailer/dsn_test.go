@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleDSN is a delivery status notification reporting one hard bounce and one delayed delivery.
+const sampleDSN = "From: Mail Delivery System <MAILER-DAEMON@mx.example.com>\r\n" +
+	"To: no-reply@example.com\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"BOUNDARY\"\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: text/plain; charset=us-ascii\r\n" +
+	"\r\n" +
+	"This is the mail system at host mx.example.com.\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"Arrival-Date: Wed, 1 Jan 2025 12:00:00 +0000\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; Gone@Example.com\r\n" +
+	"Original-Recipient: rfc822;gone@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 <gone@example.com>: Recipient address rejected:\r\n" +
+	" User unknown in virtual mailbox table\r\n" +
+	"\r\n" +
+	"Original-Recipient: rfc822; <busy@example.com>\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.2.2 (mailbox full)\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: message/rfc822-headers\r\n" +
+	"\r\n" +
+	"Subject: Verify your account\r\n" +
+	"\r\n" +
+	"--BOUNDARY--\r\n"
+
+// TestParseDSN verifies that recipients, actions, statuses and diagnostics are extracted from a DSN.
+func TestParseDSN(t *testing.T) {
+	recipients, err := ParseDSN(strings.NewReader(sampleDSN))
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+
+	assert.Equal(
+		t, DSNRecipient{
+			Recipient: "gone@example.com",
+			Action:    "failed",
+			Status:    "5.1.1",
+			DiagnosticCode: "550 5.1.1 <gone@example.com>: Recipient address rejected: " +
+				"User unknown in virtual mailbox table",
+		}, recipients[0],
+	)
+	assert.True(t, recipients[0].Permanent())
+
+	assert.Equal(t, DSNRecipient{Recipient: "busy@example.com", Action: "delayed", Status: "4.2.2"}, recipients[1])
+	assert.False(t, recipients[1].Permanent())
+}
+
+// TestParseDSN_NotDSN verifies that ordinary messages and malformed reports are rejected.
+func TestParseDSN_NotDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{
+			name: "plain message",
+			raw:  "From: a@example.com\r\nContent-Type: text/plain\r\n\r\nHello\r\n",
+		},
+		{
+			name: "other report type",
+			raw: "Content-Type: multipart/report; report-type=disposition-notification; boundary=B\r\n\r\n" +
+				"--B\r\nContent-Type: text/plain\r\n\r\nread\r\n--B--\r\n",
+		},
+		{
+			name: "report without delivery status part",
+			raw: "Content-Type: multipart/report; report-type=delivery-status; boundary=B\r\n\r\n" +
+				"--B\r\nContent-Type: text/plain\r\n\r\nbounced\r\n--B--\r\n",
+		},
+		{
+			name: "not a message",
+			raw:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, err := ParseDSN(strings.NewReader(tt.raw))
+				assert.ErrorIs(t, err, ErrNotDSN)
+			},
+		)
+	}
+}
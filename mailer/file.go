@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fileMailer writes every email as an .eml file into dir, so it can be opened in a mail client during development.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer creates a Mailer that writes emails sent from the given address as .eml files into dir.
+func NewFileMailer(from string, dir string) Mailer {
+	return &fileMailer{
+		from: from,
+		dir:  dir,
+	}
+}
+
+// Send writes msg to a new file named after the current time, creating the directory when needed.
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	email, err := compose(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405.000000"), uuid.NewString()[:8])
+	file, err := os.Create(filepath.Join(m.dir, name))
+	if err != nil {
+		return err
+	}
+
+	if _, err := email.WriteTo(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
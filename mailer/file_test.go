@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileMailer verifies that the file backend writes one .eml file per message, creating the directory.
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := NewFileMailer("no-reply@example.com", dir)
+
+	for _, subject := range []string{"first", "second"} {
+		err := m.Send(context.Background(), Message{To: []string{"a@example.com"}, Subject: subject, Text: "Hello"})
+		require.NoError(t, err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "To: a@example.com")
+	assert.Contains(t, string(raw), "Hello")
+}
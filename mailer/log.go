@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// logMailer writes every email to a logger instead of sending it.
+type logMailer struct {
+	from   string
+	logger *log.Logger
+}
+
+// NewLogMailer creates a Mailer that logs emails sent from the given address; a nil logger uses the standard logger.
+func NewLogMailer(from string, logger *log.Logger) Mailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &logMailer{
+		from:   from,
+		logger: logger,
+	}
+}
+
+// Send logs the envelope and body of msg, preferring the text part when there is one.
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	body := msg.Text
+	if body == "" {
+		body = msg.HTML
+	}
+
+	m.logger.Printf("mail from=%q to=%q subject=%q\n%s", m.from, strings.Join(msg.To, ", "), msg.Subject, body)
+	return nil
+}
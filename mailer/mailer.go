@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopkg.in/gomail.v2"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// ErrNoRecipients indicates that a message was sent without any recipient.
+var ErrNoRecipients = errors.New("message has no recipients")
+
+type (
+	// Message is an email to deliver. HTML and Text are both optional; when both are set the email is sent as
+	// multipart/alternative so clients without HTML support show the text part.
+	Message struct {
+		To      []string
+		Subject string
+		HTML    string
+		Text    string
+	}
+
+	// Mailer delivers email messages through a configured backend.
+	Mailer interface {
+		Send(ctx context.Context, msg Message) error
+	}
+)
+
+// New creates the Mailer selected by the driver in cfg.
+func New(cfg *config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case constants.ENUM_MAIL_DRIVER_SMTP:
+		return NewSMTPMailer(cfg), nil
+	case constants.ENUM_MAIL_DRIVER_FILE:
+		return NewFileMailer(cfg.From, cfg.FileDir), nil
+	case constants.ENUM_MAIL_DRIVER_LOG:
+		return NewLogMailer(cfg.From, nil), nil
+	case constants.ENUM_MAIL_DRIVER_MEMORY:
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+// compose builds the MIME message for msg sent from the given address.
+func compose(from string, msg Message) (*gomail.Message, error) {
+	if len(msg.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	email := gomail.NewMessage()
+	email.SetHeader("From", from)
+	email.SetHeader("To", msg.To...)
+	email.SetHeader("Subject", msg.Subject)
+
+	switch {
+	case msg.Text != "" && msg.HTML != "":
+		email.SetBody("text/plain", msg.Text)
+		email.AddAlternative("text/html", msg.HTML)
+	case msg.HTML != "":
+		email.SetBody("text/html", msg.HTML)
+	default:
+		email.SetBody("text/plain", msg.Text)
+	}
+
+	return email, nil
+}
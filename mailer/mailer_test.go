@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNew verifies that New returns the backend selected by the configured driver.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.MailConfig
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "smtp",
+			cfg: &config.MailConfig{
+				Driver:   constants.ENUM_MAIL_DRIVER_SMTP,
+				SMTP:     &config.EmailConfig{Host: "smtp.example.com", Port: 587},
+				PoolSize: 2,
+			},
+			want: &smtpMailer{},
+		},
+		{
+			name: "file",
+			cfg:  &config.MailConfig{Driver: constants.ENUM_MAIL_DRIVER_FILE, FileDir: t.TempDir()},
+			want: &fileMailer{},
+		},
+		{
+			name: "log",
+			cfg:  &config.MailConfig{Driver: constants.ENUM_MAIL_DRIVER_LOG},
+			want: &logMailer{},
+		},
+		{
+			name: "memory",
+			cfg:  &config.MailConfig{Driver: constants.ENUM_MAIL_DRIVER_MEMORY},
+			want: &MemoryMailer{},
+		},
+		{
+			name:    "unknown",
+			cfg:     &config.MailConfig{Driver: "pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := New(tt.cfg)
+
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.IsType(t, tt.want, got)
+			},
+		)
+	}
+}
+
+// TestCompose verifies the MIME structure built for HTML-only, text-only and multipart messages.
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name        string
+		msg         Message
+		contains    []string
+		notContains []string
+		wantErr     error
+	}{
+		{
+			name:        "html only",
+			msg:         Message{To: []string{"a@example.com"}, Subject: "Hi", HTML: "<p>Hello</p>"},
+			contains:    []string{"To: a@example.com", "Subject: Hi", "Content-Type: text/html"},
+			notContains: []string{"multipart/alternative"},
+		},
+		{
+			name:        "text only",
+			msg:         Message{To: []string{"a@example.com"}, Subject: "Hi", Text: "Hello"},
+			contains:    []string{"Content-Type: text/plain", "Hello"},
+			notContains: []string{"text/html"},
+		},
+		{
+			name: "html with text alternative",
+			msg: Message{
+				To: []string{"a@example.com", "b@example.com"}, Subject: "Hi", HTML: "<p>Hello</p>", Text: "Hello",
+			},
+			contains: []string{
+				"To: a@example.com, b@example.com", "multipart/alternative", "text/plain", "text/html",
+			},
+		},
+		{
+			name:    "no recipients",
+			msg:     Message{Subject: "Hi", Text: "Hello"},
+			wantErr: ErrNoRecipients,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				email, err := compose("App <no-reply@example.com>", tt.msg)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				_, err = email.WriteTo(&buf)
+				require.NoError(t, err)
+
+				raw := buf.String()
+				assert.Contains(t, raw, "From: App <no-reply@example.com>")
+				for _, want := range tt.contains {
+					assert.Contains(t, raw, want)
+				}
+				for _, unwanted := range tt.notContains {
+					assert.NotContains(t, raw, unwanted)
+				}
+			},
+		)
+	}
+}
+
+// TestLogMailer verifies that the log backend writes the envelope and prefers the text body.
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLogMailer("no-reply@example.com", log.New(&buf, "", 0))
+
+	err := m.Send(
+		context.Background(),
+		Message{To: []string{"a@example.com"}, Subject: "Hi", HTML: "<p>Hello</p>", Text: "Hello"},
+	)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `to="a@example.com" subject="Hi"`)
+	assert.Contains(t, buf.String(), "Hello")
+	assert.NotContains(t, buf.String(), "<p>")
+
+	assert.ErrorIs(t, m.Send(context.Background(), Message{Subject: "Hi"}), ErrNoRecipients)
+}
@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer keeps every sent email in memory so tests can assert on what was delivered.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates an empty MemoryMailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send records msg.
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Sent returns a copy of every recorded message, oldest first.
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+// SentTo returns the recorded messages addressed to recipient, oldest first.
+func (m *MemoryMailer) SentTo(recipient string) []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var messages []Message
+	for _, msg := range m.messages {
+		for _, to := range msg.To {
+			if to == recipient {
+				messages = append(messages, msg)
+				break
+			}
+		}
+	}
+
+	return messages
+}
+
+// Last returns the most recently recorded message and whether there is one.
+func (m *MemoryMailer) Last() (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.messages) == 0 {
+		return Message{}, false
+	}
+
+	return m.messages[len(m.messages)-1], true
+}
+
+// Reset discards every recorded message.
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
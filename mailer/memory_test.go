@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemoryMailer verifies that the memory backend records, filters and resets sent messages.
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	ctx := context.Background()
+
+	_, ok := m.Last()
+	assert.False(t, ok)
+
+	assert.NoError(t, m.Send(ctx, Message{To: []string{"a@example.com"}, Subject: "first"}))
+	assert.NoError(t, m.Send(ctx, Message{To: []string{"b@example.com", "a@example.com"}, Subject: "second"}))
+	assert.NoError(t, m.Send(ctx, Message{To: []string{"b@example.com"}, Subject: "third"}))
+
+	assert.Len(t, m.Sent(), 3)
+	assert.Len(t, m.SentTo("a@example.com"), 2)
+
+	last, ok := m.Last()
+	assert.True(t, ok)
+	assert.Equal(t, "third", last.Subject)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, m.Send(canceled, Message{To: []string{"a@example.com"}}), context.Canceled)
+
+	m.Reset()
+	assert.Empty(t, m.Sent())
+}
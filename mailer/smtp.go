@@ -0,0 +1,110 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+
+	"gopkg.in/gomail.v2"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// smtpMailer delivers emails through an SMTP server, reusing up to cap(pool) idle authenticated connections.
+type smtpMailer struct {
+	from string
+	dial func() (gomail.SendCloser, error)
+	pool chan gomail.SendCloser
+}
+
+// NewSMTPMailer creates a Mailer that sends through the SMTP server in cfg, using implicit TLS or STARTTLS as
+// configured and keeping a pool of cfg.PoolSize idle connections.
+func NewSMTPMailer(cfg *config.MailConfig) Mailer {
+	dialer := gomail.NewDialer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.AuthEmail, cfg.SMTP.AuthPassword)
+	dialer.SSL = cfg.Encryption == constants.ENUM_SMTP_ENCRYPTION_TLS
+	dialer.TLSConfig = &tls.Config{
+		ServerName:         cfg.SMTP.Host,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	return newSMTPMailer(cfg.From, dialer.Dial, cfg.PoolSize)
+}
+
+// newSMTPMailer creates an smtpMailer that opens connections with dial.
+func newSMTPMailer(from string, dial func() (gomail.SendCloser, error), poolSize int) *smtpMailer {
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	return &smtpMailer{
+		from: from,
+		dial: dial,
+		pool: make(chan gomail.SendCloser, poolSize),
+	}
+}
+
+// Send delivers msg over a pooled connection. A pooled connection may have been closed by the server while idle,
+// so a failure on one is retried once on a freshly dialed connection.
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	email, err := compose(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	conn, pooled, err := m.acquire()
+	if err != nil {
+		return err
+	}
+
+	err = gomail.Send(conn, email)
+	if err != nil && pooled {
+		_ = conn.Close()
+		if conn, err = m.dial(); err != nil {
+			return err
+		}
+		err = gomail.Send(conn, email)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	m.release(conn)
+	return nil
+}
+
+// Shutdown closes every idle pooled connection; it is called when the injector shuts down.
+func (m *smtpMailer) Shutdown() error {
+	for {
+		select {
+		case conn := <-m.pool:
+			_ = conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// acquire returns an idle pooled connection, or dials a new one when the pool is empty.
+func (m *smtpMailer) acquire() (gomail.SendCloser, bool, error) {
+	select {
+	case conn := <-m.pool:
+		return conn, true, nil
+	default:
+		conn, err := m.dial()
+		return conn, false, err
+	}
+}
+
+// release returns conn to the pool, closing it when the pool is full.
+func (m *smtpMailer) release(conn gomail.SendCloser) {
+	select {
+	case m.pool <- conn:
+	default:
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,122 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/gomail.v2"
+)
+
+// fakeConn is a gomail.SendCloser that records deliveries and fails the next failures sends.
+type fakeConn struct {
+	sent     int
+	failures int
+	closed   bool
+}
+
+// Send records a delivery or fails while failures remain.
+func (c *fakeConn) Send(_ string, _ []string, _ io.WriterTo) error {
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("connection reset")
+	}
+	c.sent++
+	return nil
+}
+
+// Close marks the connection as closed.
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+// fakeDialer hands out the prepared connections in order and counts dials.
+type fakeDialer struct {
+	conns []*fakeConn
+	dials int
+	err   error
+}
+
+// dial returns the next prepared connection.
+func (d *fakeDialer) dial() (gomail.SendCloser, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	conn := d.conns[d.dials]
+	d.dials++
+	return conn, nil
+}
+
+// TestSMTPMailer_Send verifies connection reuse, retrying a stale pooled connection and error handling.
+func TestSMTPMailer_Send(t *testing.T) {
+	msg := Message{To: []string{"a@example.com"}, Subject: "Hi", HTML: "<p>Hello</p>"}
+
+	t.Run(
+		"reuses pooled connection", func(t *testing.T) {
+			dialer := &fakeDialer{conns: []*fakeConn{{}}}
+			m := newSMTPMailer("no-reply@example.com", dialer.dial, 1)
+
+			assert.NoError(t, m.Send(context.Background(), msg))
+			assert.NoError(t, m.Send(context.Background(), msg))
+
+			assert.Equal(t, 1, dialer.dials)
+			assert.Equal(t, 2, dialer.conns[0].sent)
+			assert.False(t, dialer.conns[0].closed)
+
+			assert.NoError(t, m.Shutdown())
+			assert.True(t, dialer.conns[0].closed)
+		},
+	)
+
+	t.Run(
+		"redials when pooled connection is stale", func(t *testing.T) {
+			stale := &fakeConn{}
+			fresh := &fakeConn{}
+			dialer := &fakeDialer{conns: []*fakeConn{stale, fresh}}
+			m := newSMTPMailer("no-reply@example.com", dialer.dial, 1)
+
+			assert.NoError(t, m.Send(context.Background(), msg))
+			stale.failures = 1
+
+			assert.NoError(t, m.Send(context.Background(), msg))
+			assert.True(t, stale.closed)
+			assert.Equal(t, 1, fresh.sent)
+			assert.Equal(t, 2, dialer.dials)
+		},
+	)
+
+	t.Run(
+		"does not retry fresh connection", func(t *testing.T) {
+			conn := &fakeConn{failures: 2}
+			dialer := &fakeDialer{conns: []*fakeConn{conn}}
+			m := newSMTPMailer("no-reply@example.com", dialer.dial, 1)
+
+			assert.Error(t, m.Send(context.Background(), msg))
+			assert.True(t, conn.closed)
+			assert.Equal(t, 1, dialer.dials)
+		},
+	)
+
+	t.Run(
+		"returns dial error", func(t *testing.T) {
+			dialer := &fakeDialer{err: errors.New("connection refused")}
+			m := newSMTPMailer("no-reply@example.com", dialer.dial, 1)
+
+			assert.EqualError(t, m.Send(context.Background(), msg), "connection refused")
+		},
+	)
+
+	t.Run(
+		"closes connections beyond pool size", func(t *testing.T) {
+			extra := &fakeConn{}
+			m := newSMTPMailer("no-reply@example.com", (&fakeDialer{}).dial, 1)
+
+			m.release(&fakeConn{})
+			m.release(extra)
+			assert.True(t, extra.closed)
+		},
+	)
+}
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrRecipientSuppressed indicates that every recipient of a message is on the suppression list.
+var ErrRecipientSuppressed = errors.New("recipient is on the email suppression list")
+
+type (
+	// SuppressionList reports which of the given addresses must not receive email anymore.
+	SuppressionList interface {
+		Suppressed(ctx context.Context, recipients []string) ([]string, error)
+	}
+
+	// suppressingMailer drops suppressed recipients before handing a message to the next Mailer.
+	suppressingMailer struct {
+		next Mailer
+		list SuppressionList
+	}
+)
+
+// NewSuppressingMailer wraps next so that messages are never sent to addresses on the suppression list.
+func NewSuppressingMailer(next Mailer, list SuppressionList) Mailer {
+	return &suppressingMailer{
+		next: next,
+		list: list,
+	}
+}
+
+// Send removes suppressed recipients from msg and sends it to the rest, failing with ErrRecipientSuppressed when
+// no recipient is left.
+func (m *suppressingMailer) Send(ctx context.Context, msg Message) error {
+	suppressed, err := m.list.Suppressed(ctx, msg.To)
+	if err != nil {
+		return err
+	}
+
+	if len(suppressed) > 0 {
+		skip := make(map[string]bool, len(suppressed))
+		for _, email := range suppressed {
+			skip[strings.ToLower(email)] = true
+		}
+
+		var to []string
+		for _, recipient := range msg.To {
+			if !skip[strings.ToLower(strings.TrimSpace(recipient))] {
+				to = append(to, recipient)
+			}
+		}
+
+		if len(to) == 0 {
+			return ErrRecipientSuppressed
+		}
+		msg.To = to
+	}
+
+	return m.next.Send(ctx, msg)
+}
@@ -0,0 +1,85 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticSuppressionList suppresses a fixed set of lower-cased addresses.
+type staticSuppressionList struct {
+	emails map[string]bool
+	err    error
+}
+
+// Suppressed returns the given recipients that are in the list.
+func (l staticSuppressionList) Suppressed(_ context.Context, recipients []string) ([]string, error) {
+	var suppressed []string
+	for _, recipient := range recipients {
+		if l.emails[recipient] {
+			suppressed = append(suppressed, recipient)
+		}
+	}
+	return suppressed, l.err
+}
+
+// TestSuppressingMailer verifies that suppressed recipients are dropped before sending.
+func TestSuppressingMailer(t *testing.T) {
+	list := staticSuppressionList{emails: map[string]bool{"gone@example.com": true}}
+
+	tests := []struct {
+		name    string
+		list    SuppressionList
+		to      []string
+		wantTo  []string
+		wantErr error
+	}{
+		{
+			name:   "nobody suppressed",
+			list:   list,
+			to:     []string{"a@example.com"},
+			wantTo: []string{"a@example.com"},
+		},
+		{
+			name:   "suppressed recipient dropped case-insensitively",
+			list:   staticSuppressionList{emails: map[string]bool{"Gone@Example.com": true}},
+			to:     []string{"a@example.com", "Gone@Example.com"},
+			wantTo: []string{"a@example.com"},
+		},
+		{
+			name:    "every recipient suppressed",
+			list:    list,
+			to:      []string{"gone@example.com"},
+			wantErr: ErrRecipientSuppressed,
+		},
+		{
+			name:    "lookup failure",
+			list:    staticSuppressionList{err: errors.New("db down")},
+			to:      []string{"a@example.com"},
+			wantErr: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				memory := NewMemoryMailer()
+				m := NewSuppressingMailer(memory, tt.list)
+
+				err := m.Send(context.Background(), Message{To: tt.to, Subject: "Hi", Text: "Hello"})
+
+				if tt.wantErr != nil {
+					assert.EqualError(t, err, tt.wantErr.Error())
+					assert.Empty(t, memory.Sent())
+					return
+				}
+				require.NoError(t, err)
+				last, _ := memory.Last()
+				assert.Equal(t, tt.wantTo, last.To)
+			},
+		)
+	}
+}
@@ -0,0 +1,377 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+// embeddedTemplates holds the built-in layouts, partials and emails compiled into the binary.
+//
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// ErrTemplateNotFound indicates that no email template exists with the requested name.
+var ErrTemplateNotFound = apperror.New(apperror.KIND_NOT_FOUND, "EMAIL_TEMPLATE_NOT_FOUND", "email template not found")
+
+// templateNamePattern restricts template names and locales to safe path segments.
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type (
+	// TemplateInfo describes an available email template and the locales it is translated to.
+	TemplateInfo struct {
+		Name    string   `json:"name"`
+		Locales []string `json:"locales"`
+	}
+
+	// Renderer renders email templates into messages.
+	// Render executes the named template in the closest available locale, producing the subject, HTML and text parts.
+	// Templates lists the available templates and Sample returns the example data used to preview a template.
+	//
+	// Every email lives in emails/<name>/<locale>.html with an optional <locale>.txt plain-text alternative. Both
+	// define a "subject" and a "content" block; content is wrapped by the "layout" block of layouts/base.html or
+	// layouts/base.txt, and the blocks defined in partials/ are available to every template.
+	Renderer interface {
+		Render(name string, locale string, data any) (Message, error)
+		Templates() ([]TemplateInfo, error)
+		Sample(name string) (map[string]any, error)
+	}
+
+	// renderer implements Renderer on top of a template file system.
+	renderer struct {
+		files         fs.FS
+		defaultLocale string
+		appName       string
+		now           func() time.Time
+	}
+
+	// templateFile is a template source with the path it was read from.
+	templateFile struct {
+		path    string
+		content string
+	}
+)
+
+// NewRenderer creates a Renderer over the embedded templates, overridden by the files in cfg.Dir when it is set.
+func NewRenderer(cfg *config.MailTemplateConfig) Renderer {
+	var files fs.FS
+	files, _ = fs.Sub(embeddedTemplates, "templates")
+	if cfg.Dir != "" {
+		files = overlayFS{upper: os.DirFS(cfg.Dir), lower: files}
+	}
+
+	return newRenderer(files, cfg)
+}
+
+// newRenderer creates a renderer reading templates from files.
+func newRenderer(files fs.FS, cfg *config.MailTemplateConfig) *renderer {
+	return &renderer{
+		files:         files,
+		defaultLocale: cfg.DefaultLocale,
+		appName:       cfg.AppName,
+		now:           time.Now,
+	}
+}
+
+// Render executes the named template with data. The locale is matched exactly, then by its primary language, and
+// finally falls back to the default locale; it may be given as an Accept-Language header value.
+func (r *renderer) Render(name string, locale string, data any) (Message, error) {
+	locale, err := r.resolveLocale(name, locale)
+	if err != nil {
+		return Message{}, err
+	}
+
+	subject, err := r.renderSubject(name, locale, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	html, err := r.renderHTML(name, locale, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	text, err := r.renderText(name, locale, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: subject,
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
+
+// Templates lists every email template with its locales, sorted by name.
+func (r *renderer) Templates() ([]TemplateInfo, error) {
+	entries, err := fs.ReadDir(r.files, "emails")
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []TemplateInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		matches, err := fs.Glob(r.files, path.Join("emails", entry.Name(), "*.html"))
+		if err != nil {
+			return nil, err
+		}
+
+		info := TemplateInfo{Name: entry.Name(), Locales: []string{}}
+		for _, match := range matches {
+			info.Locales = append(info.Locales, strings.TrimSuffix(path.Base(match), ".html"))
+		}
+		templates = append(templates, info)
+	}
+
+	return templates, nil
+}
+
+// Sample returns the example data of a template read from emails/<name>/sample.json, or an empty map without one.
+func (r *renderer) Sample(name string) (map[string]any, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, ErrTemplateNotFound
+	}
+	if _, err := fs.Stat(r.files, path.Join("emails", name)); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	raw, err := fs.ReadFile(r.files, path.Join("emails", name, "sample.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sample := map[string]any{}
+	if err := json.Unmarshal(raw, &sample); err != nil {
+		return nil, fmt.Errorf("invalid sample data for %s: %w", name, err)
+	}
+
+	return sample, nil
+}
+
+// resolveLocale returns the best locale the named template is available in.
+func (r *renderer) resolveLocale(name string, locale string) (string, error) {
+	if !templateNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	for _, candidate := range localeCandidates(locale, r.defaultLocale) {
+		if _, err := fs.Stat(r.files, emailPath(name, candidate, ".html")); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+}
+
+// renderSubject executes the subject block, preferring the text template so the subject is never HTML-escaped.
+func (r *renderer) renderSubject(name string, locale string, data any) (string, error) {
+	for _, ext := range []string{".txt", ".html"} {
+		content, err := fs.ReadFile(r.files, emailPath(name, locale, ext))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		tmpl, err := texttemplate.New(name).Funcs(r.funcs(locale)).Parse(string(content))
+		if err != nil {
+			return "", err
+		}
+		if tmpl.Lookup("subject") == nil {
+			continue
+		}
+
+		var subject bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+			return "", err
+		}
+
+		return strings.Join(strings.Fields(subject.String()), " "), nil
+	}
+
+	return "", fmt.Errorf("email template %s does not define a subject", name)
+}
+
+// renderHTML executes the HTML layout around the HTML content of the template.
+func (r *renderer) renderHTML(name string, locale string, data any) (string, error) {
+	files, err := r.templateFiles(name, locale, ".html")
+	if err != nil {
+		return "", err
+	}
+
+	tmpl := htmltemplate.New(name).Funcs(r.funcs(locale))
+	for _, file := range files {
+		if _, err := tmpl.New(file.path).Parse(file.content); err != nil {
+			return "", err
+		}
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return "", err
+	}
+
+	return body.String(), nil
+}
+
+// renderText executes the text layout around the text content of the template, returning "" when the template
+// has no plain-text alternative.
+func (r *renderer) renderText(name string, locale string, data any) (string, error) {
+	if _, err := fs.Stat(r.files, emailPath(name, locale, ".txt")); errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+
+	files, err := r.templateFiles(name, locale, ".txt")
+	if err != nil {
+		return "", err
+	}
+
+	tmpl := texttemplate.New(name).Funcs(r.funcs(locale))
+	for _, file := range files {
+		if _, err := tmpl.New(file.path).Parse(file.content); err != nil {
+			return "", err
+		}
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(body.String()) + "\n", nil
+}
+
+// templateFiles reads the layout, the partials and the email content with the given extension, in parse order,
+// so that an email can redefine a partial.
+func (r *renderer) templateFiles(name string, locale string, ext string) ([]templateFile, error) {
+	partials, err := fs.Glob(r.files, "partials/*"+ext)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := append([]string{"layouts/base" + ext}, partials...)
+	paths = append(paths, emailPath(name, locale, ext))
+
+	files := make([]templateFile, 0, len(paths))
+	for _, p := range paths {
+		content, err := fs.ReadFile(r.files, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, templateFile{path: p, content: string(content)})
+	}
+
+	return files, nil
+}
+
+// funcs returns the helper functions available to every template.
+func (r *renderer) funcs(locale string) map[string]any {
+	return map[string]any{
+		"appName": func() string { return r.appName },
+		"locale":  func() string { return locale },
+		"year":    func() int { return r.now().Year() },
+		"dict":    dict,
+	}
+}
+
+// emailPath returns the path of the content file of a template in a locale.
+func emailPath(name string, locale string, ext string) string {
+	return path.Join("emails", name, locale+ext)
+}
+
+// localeCandidates returns the locales to try for a requested locale or Accept-Language value, most specific first.
+func localeCandidates(requested string, defaultLocale string) []string {
+	tag, _, _ := strings.Cut(requested, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+
+	var candidates []string
+	if templateNamePattern.MatchString(tag) {
+		candidates = append(candidates, tag)
+		if primary, _, found := strings.Cut(tag, "-"); found {
+			candidates = append(candidates, primary)
+		}
+	}
+
+	return append(candidates, defaultLocale)
+}
+
+// dict builds a map from alternating keys and values so several values can be passed to a partial.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects key and value pairs")
+	}
+
+	values := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		values[key] = pairs[i+1]
+	}
+
+	return values, nil
+}
+
+// overlayFS serves files from upper when they exist there and from lower otherwise; directory listings are merged.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+// Open opens name from upper, falling back to lower.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.upper.Open(name)
+	if err == nil {
+		return file, nil
+	}
+
+	return o.lower.Open(name)
+}
+
+// ReadDir returns the union of the entries of name in both layers, preferring upper entries, sorted by name.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, lowerErr
+	}
+
+	seen := make(map[string]bool, len(upper))
+	entries := append([]fs.DirEntry(nil), upper...)
+	for _, entry := range upper {
+		seen[entry.Name()] = true
+	}
+	for _, entry := range lower {
+		if !seen[entry.Name()] {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
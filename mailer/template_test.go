@@ -0,0 +1,186 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+// testRenderer returns a renderer over the embedded templates with a fixed clock and application name.
+func testRenderer(dir string) Renderer {
+	r := NewRenderer(&config.MailTemplateConfig{Dir: dir, DefaultLocale: "en", AppName: "Acme"})
+	r.(*renderer).now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return r
+}
+
+// TestRenderer_Render verifies the subject, HTML and text parts of the embedded templates and locale selection.
+func TestRenderer_Render(t *testing.T) {
+	data := map[string]any{"Email": "jane@example.com", "VerifyLink": "https://example.com/verify?token=a&b"}
+
+	tests := []struct {
+		name        string
+		template    string
+		locale      string
+		wantSubject string
+		wantHTML    []string
+		wantText    []string
+		wantErr     error
+	}{
+		{
+			name:        "default locale",
+			template:    "verify_email",
+			wantSubject: "Verify your Acme account",
+			wantHTML: []string{
+				`<html lang="en">`, "<title>Verify your Acme account</title>", "Verify My Account",
+				`href="https://example.com/verify?token=a&amp;b"`, "&copy; 2025 Acme",
+			},
+			wantText: []string{"Hello, jane@example.com", "https://example.com/verify?token=a&b", "(c) 2025 Acme"},
+		},
+		{
+			name:        "accept-language header picks primary language",
+			template:    "verify_email",
+			locale:      "id-ID,id;q=0.9,en;q=0.8",
+			wantSubject: "Verifikasi akun Acme Anda",
+			wantHTML:    []string{`<html lang="id">`, "Verifikasi Akun Saya"},
+			wantText:    []string{"Halo, jane@example.com"},
+		},
+		{
+			name:        "unknown locale falls back to default",
+			template:    "verify_email",
+			locale:      "pt_BR",
+			wantSubject: "Verify your Acme account",
+		},
+		{
+			name:     "unknown template",
+			template: "missing",
+			wantErr:  ErrTemplateNotFound,
+		},
+		{
+			name:     "path traversal is rejected",
+			template: "../layouts",
+			wantErr:  ErrTemplateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				msg, err := testRenderer("").Render(tt.template, tt.locale, data)
+
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantSubject, msg.Subject)
+				for _, want := range tt.wantHTML {
+					assert.Contains(t, msg.HTML, want)
+				}
+				for _, want := range tt.wantText {
+					assert.Contains(t, msg.Text, want)
+				}
+				assert.NotContains(t, msg.Text, "<")
+			},
+		)
+	}
+}
+
+// TestRenderer_Override verifies that files in the override directory replace or extend the embedded templates.
+func TestRenderer_Override(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "emails/verify_email/en.txt", `{{ define "subject" }}Custom {{ appName }}{{ end }}`+
+		`{{ define "content" }}Custom text {{ .VerifyLink }}{{ end }}`)
+	writeTemplate(t, dir, "emails/welcome/en.html", `{{ define "subject" }}Welcome {{ .Name }}{{ end }}`+
+		`{{ define "content" }}<p>Hi {{ .Name }}</p>{{ end }}`)
+	writeTemplate(t, dir, "partials/footer.html", `{{ define "footer" }}<p>Custom footer</p>{{ end }}`)
+
+	r := testRenderer(dir)
+
+	msg, err := r.Render("verify_email", "en", map[string]any{"VerifyLink": "https://example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "Custom Acme", msg.Subject)
+	assert.Contains(t, msg.Text, "Custom text https://example.com")
+	assert.Contains(t, msg.HTML, "Custom footer")
+
+	msg, err = r.Render("welcome", "en", map[string]any{"Name": "Jane & Co"})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome Jane & Co", msg.Subject)
+	assert.Contains(t, msg.HTML, "<p>Hi Jane &amp; Co</p>")
+	assert.Empty(t, msg.Text, "templates without a .txt file have no text part")
+
+	templates, err := r.Templates()
+	require.NoError(t, err)
+	assert.Equal(
+		t, []TemplateInfo{
+			{Name: "confirm_email_change", Locales: []string{"en", "id"}},
+			{Name: "data_export", Locales: []string{"en", "id"}},
+			{Name: "email_change_notice", Locales: []string{"en", "id"}},
+			{Name: "verify_email", Locales: []string{"en", "id"}},
+			{Name: "welcome", Locales: []string{"en"}},
+		}, templates,
+	)
+}
+
+// TestRenderer_Sample verifies that sample data is read from sample.json and that unknown templates are reported.
+func TestRenderer_Sample(t *testing.T) {
+	r := testRenderer("")
+
+	sample, err := r.Sample("verify_email")
+	require.NoError(t, err)
+	assert.Equal(t, "jane.doe@example.com", sample["Email"])
+
+	_, err = r.Sample("missing")
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+
+	for _, info := range mustTemplates(t, r) {
+		for _, locale := range info.Locales {
+			sample, err := r.Sample(info.Name)
+			require.NoError(t, err)
+
+			_, err = r.Render(info.Name, locale, sample)
+			assert.NoError(t, err, "%s/%s should render with its sample data", info.Name, locale)
+		}
+	}
+}
+
+// TestLocaleCandidates verifies the locale fallback chain built from requested locales.
+func TestLocaleCandidates(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      []string
+	}{
+		{requested: "", want: []string{"en"}},
+		{requested: "id", want: []string{"id", "en"}},
+		{requested: "pt_BR", want: []string{"pt-br", "pt", "en"}},
+		{requested: "de-DE,de;q=0.9", want: []string{"de-de", "de", "en"}},
+		{requested: "../../etc", want: []string{"en"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, localeCandidates(tt.requested, "en"), "requested=%q", tt.requested)
+	}
+}
+
+// writeTemplate writes a template file below dir, creating its directories.
+func writeTemplate(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+// mustTemplates lists the templates of r or fails the test.
+func mustTemplates(t *testing.T, r Renderer) []TemplateInfo {
+	t.Helper()
+
+	templates, err := r.Templates()
+	require.NoError(t, err)
+	return templates
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/middleware"
 	"github.com/Caknoooo/go-gin-clean-starter/provider"
 	"github.com/Caknoooo/go-gin-clean-starter/routes"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
 )
 
 // args is responsible for processing command-line arguments and determining whether the application should proceed or exit.
@@ -41,10 +43,24 @@ var args = func(injector *do.Injector) bool {
 	return true
 }
 
+// startWorkers launches the background workers that run alongside the HTTP server: the email dispatcher, the upload
+// janitor, the purger of deleted accounts and the data exporter.
+var startWorkers = func(injector *do.Injector) {
+	dispatcher := do.MustInvoke[*service.EmailDispatcher](injector)
+	go dispatcher.Run(context.Background())
+
+	janitor := do.MustInvoke[*service.UploadJanitor](injector)
+	go janitor.Run(context.Background())
+
+	purger := do.MustInvoke[*service.UserPurger](injector)
+	go purger.Run(context.Background())
+
+	exporter := do.MustInvoke[*service.DataExporter](injector)
+	go exporter.Run(context.Background())
+}
+
 // run is a variable that defines a function to configure and run a Gin server with the specified routes and settings.
 var run = func(server *gin.Engine) {
-	server.Static("/assets", "./assets")
-
 	if os.Getenv("IS_LOGGER") == "true" {
 		routes.LoggerRoute(server)
 	}
@@ -85,8 +101,11 @@ func main() {
 		return
 	}
 
+	startWorkers(injector)
+
 	server := gin.Default()
 	server.Use(middleware.CORSMiddleware())
+	server.Use(middleware.ErrorHandler())
 
 	routes.RegisterRoutes(server, injector)
 
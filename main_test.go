@@ -183,6 +183,13 @@ func TestMainFunc(t *testing.T) {
 				return true
 			}
 
+			workersStarted := false
+			originalStartWorkers := startWorkers
+			defer func() { startWorkers = originalStartWorkers }()
+			startWorkers = func(injector *do.Injector) {
+				workersStarted = true
+			}
+
 			runCalled := false
 			originalRun := run
 			defer func() { run = originalRun }()
@@ -192,6 +199,7 @@ func TestMainFunc(t *testing.T) {
 
 			main()
 
+			assert.True(t, workersStarted)
 			assert.True(t, runCalled)
 			mockProvider.AssertExpectations(t)
 			mockRoutes.AssertExpectations(t)
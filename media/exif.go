@@ -0,0 +1,97 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	// EXIF_ORIENTATION_TAG is the TIFF tag holding the orientation of a photo.
+	EXIF_ORIENTATION_TAG = 0x0112
+
+	// EXIF_TYPE_SHORT is the TIFF field type of 16-bit unsigned integers.
+	EXIF_TYPE_SHORT = 3
+)
+
+// jpegOrientation returns the EXIF orientation stored in the APP1 segment of a JPEG file, or 1 (upright) when the file
+// has no readable orientation.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+
+		segment := data[i+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		i = end
+	}
+
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from the first IFD of a TIFF structure, returning 1 when it is absent or
+// malformed.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) != EXIF_ORIENTATION_TAG {
+			continue
+		}
+		if order.Uint16(tiff[entry+2:]) != EXIF_TYPE_SHORT {
+			return 1
+		}
+
+		value := int(order.Uint16(tiff[entry+8:]))
+		if value < 1 || value > 8 {
+			return 1
+		}
+		return value
+	}
+
+	return 1
+}
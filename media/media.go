@@ -0,0 +1,164 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+const (
+	// MAX_IMAGE_SIZE is the largest uploaded image accepted, in bytes.
+	MAX_IMAGE_SIZE = 5 << 20
+
+	// MAX_IMAGE_PIXELS is the largest number of pixels an uploaded image may declare, which guards against images
+	// that are small on disk but exhaust memory once decoded.
+	MAX_IMAGE_PIXELS = 40_000_000
+
+	// MAX_IMAGE_DIMENSION is the longest side of a stored image; larger images are scaled down.
+	MAX_IMAGE_DIMENSION = 2048
+
+	// JPEG_QUALITY is the quality used when re-encoding images as JPEG.
+	JPEG_QUALITY = 85
+)
+
+var (
+	// ErrImageTooLarge indicates that an image exceeds MAX_IMAGE_SIZE bytes or MAX_IMAGE_PIXELS pixels.
+	ErrImageTooLarge = apperror.New(apperror.KIND_TOO_LARGE, "IMAGE_TOO_LARGE", "image is too large")
+
+	// ErrUnsupportedImageType indicates that the content of an upload is not one of ALLOWED_IMAGE_TYPES.
+	ErrUnsupportedImageType = apperror.New(
+		apperror.KIND_UNSUPPORTED_MEDIA_TYPE, "UNSUPPORTED_IMAGE_TYPE", "unsupported image type",
+	)
+
+	// ErrInvalidImage indicates that an upload looks like an allowed image type but cannot be decoded.
+	ErrInvalidImage = apperror.New(apperror.KIND_VALIDATION, "INVALID_IMAGE", "invalid image")
+
+	// ALLOWED_IMAGE_TYPES maps the MIME types accepted for uploads, detected from their content, to the name of the
+	// decoder that must be able to read them.
+	ALLOWED_IMAGE_TYPES = map[string]string{
+		"image/jpeg": "jpeg",
+		"image/png":  "png",
+		"image/gif":  "gif",
+		"image/webp": "webp",
+	}
+
+	// THUMBNAIL_SIZES lists the sides, in pixels, of the square thumbnails generated for every image.
+	THUMBNAIL_SIZES = []int{64, 256, 512}
+)
+
+// Image is an uploaded image after validation and re-encoding, together with its thumbnails.
+// Opaque images are encoded as JPEG and images with transparency as PNG; metadata such as EXIF is never copied.
+type Image struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+	Data        []byte
+	Thumbnails  map[int][]byte
+}
+
+// Process validates an uploaded image and re-encodes it.
+// The type is detected from the content rather than the file name and must be in ALLOWED_IMAGE_TYPES. The EXIF
+// orientation of JPEG images is applied before the metadata is dropped, images larger than MAX_IMAGE_DIMENSION are
+// scaled down and a square thumbnail is generated for every size in THUMBNAIL_SIZES. Only the first frame of an
+// animated GIF is kept.
+func Process(r io.Reader, maxSize int64) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrImageTooLarge, maxSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	decoder, ok := ALLOWED_IMAGE_TYPES[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImageType, contentType)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != decoder {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidImage, contentType)
+	}
+	if config.Width*config.Height > MAX_IMAGE_PIXELS {
+		return nil, fmt.Errorf("%w: more than %d pixels", ErrImageTooLarge, MAX_IMAGE_PIXELS)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	img = fit(img, MAX_IMAGE_DIMENSION)
+
+	result := &Image{
+		ContentType: "image/png",
+		Ext:         "png",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Thumbnails:  make(map[int][]byte, len(THUMBNAIL_SIZES)),
+	}
+	if opaque(img) {
+		result.ContentType, result.Ext = "image/jpeg", "jpg"
+	}
+
+	if result.Data, err = encode(img, result.ContentType); err != nil {
+		return nil, err
+	}
+	for _, size := range THUMBNAIL_SIZES {
+		if result.Thumbnails[size], err = encode(thumbnail(img, size), result.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ThumbnailKey returns the storage key of the thumbnail of the given size of the image stored under key, for example
+// "profile/<id>_64.jpg" for "profile/<id>.jpg".
+func ThumbnailKey(key string, size int) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + strconv.Itoa(size) + ext
+}
+
+// encode writes img as JPEG or PNG.
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEG_QUALITY})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// opaque reports whether every pixel of img is fully opaque.
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+
+	return false
+}
@@ -0,0 +1,259 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// solidImage returns a w by h image filled with c.
+func solidImage(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+// encodePNG returns img encoded as PNG.
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+// encodeJPEG returns img encoded as JPEG.
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF APP1 segment with the given orientation right after the SOI marker of a JPEG file.
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, EXIF_ORIENTATION_TAG)
+	tiff = binary.BigEndian.AppendUint16(tiff, EXIF_TYPE_SHORT)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+// withDimensions rewrites the IHDR chunk of a PNG file to declare the given dimensions.
+func withDimensions(data []byte, w, h uint32) []byte {
+	out := append([]byte{}, data...)
+	binary.BigEndian.PutUint32(out[16:], w)
+	binary.BigEndian.PutUint32(out[20:], h)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+
+	return out
+}
+
+// TestProcess tests that uploads are validated by content and re-encoded with thumbnails.
+func TestProcess(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+
+	var gifBuf bytes.Buffer
+	require.NoError(t, gif.Encode(&gifBuf, solidImage(40, 20, red), nil))
+
+	tests := []struct {
+		name            string
+		data            []byte
+		maxSize         int64
+		wantErr         error
+		wantContentType string
+		wantWidth       int
+		wantHeight      int
+	}{
+		{
+			name:            "opaque PNG is stored as JPEG",
+			data:            encodePNG(t, solidImage(600, 300, red)),
+			maxSize:         MAX_IMAGE_SIZE,
+			wantContentType: "image/jpeg",
+			wantWidth:       600,
+			wantHeight:      300,
+		},
+		{
+			name:            "transparent PNG stays PNG",
+			data:            encodePNG(t, solidImage(100, 100, color.NRGBA{G: 255, A: 100})),
+			maxSize:         MAX_IMAGE_SIZE,
+			wantContentType: "image/png",
+			wantWidth:       100,
+			wantHeight:      100,
+		},
+		{
+			name:            "GIF is converted",
+			data:            gifBuf.Bytes(),
+			maxSize:         MAX_IMAGE_SIZE,
+			wantContentType: "image/jpeg",
+			wantWidth:       40,
+			wantHeight:      20,
+		},
+		{
+			name:            "large image is scaled down",
+			data:            encodePNG(t, solidImage(MAX_IMAGE_DIMENSION*2, 100, red)),
+			maxSize:         MAX_IMAGE_SIZE,
+			wantContentType: "image/jpeg",
+			wantWidth:       MAX_IMAGE_DIMENSION,
+			wantHeight:      50,
+		},
+		{
+			name:            "EXIF orientation is applied",
+			data:            withOrientation(encodeJPEG(t, solidImage(40, 20, red)), 6),
+			maxSize:         MAX_IMAGE_SIZE,
+			wantContentType: "image/jpeg",
+			wantWidth:       20,
+			wantHeight:      40,
+		},
+		{
+			name:    "script disguised as an image",
+			data:    []byte("<?php echo 'hello'; ?>"),
+			maxSize: MAX_IMAGE_SIZE,
+			wantErr: ErrUnsupportedImageType,
+		},
+		{
+			name:    "unsupported image type",
+			data:    []byte("BM" + string(make([]byte, 64))),
+			maxSize: MAX_IMAGE_SIZE,
+			wantErr: ErrUnsupportedImageType,
+		},
+		{
+			name:    "truncated image",
+			data:    encodePNG(t, solidImage(10, 10, red))[:20],
+			maxSize: MAX_IMAGE_SIZE,
+			wantErr: ErrInvalidImage,
+		},
+		{
+			name:    "file too large",
+			data:    encodePNG(t, solidImage(10, 10, red)),
+			maxSize: 10,
+			wantErr: ErrImageTooLarge,
+		},
+		{
+			name:    "too many pixels",
+			data:    withDimensions(encodePNG(t, solidImage(10, 10, red)), 100000, 100000),
+			maxSize: MAX_IMAGE_SIZE,
+			wantErr: ErrImageTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				img, err := Process(bytes.NewReader(tt.data), tt.maxSize)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantContentType, img.ContentType)
+				assert.Equal(t, tt.wantWidth, img.Width)
+				assert.Equal(t, tt.wantHeight, img.Height)
+				assert.NotContains(t, string(img.Data), "Exif", "metadata should be stripped")
+
+				decoded, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantWidth, decoded.Width)
+				assert.Equal(t, tt.wantHeight, decoded.Height)
+
+				require.Len(t, img.Thumbnails, len(THUMBNAIL_SIZES))
+				for _, size := range THUMBNAIL_SIZES {
+					thumb, _, err := image.DecodeConfig(bytes.NewReader(img.Thumbnails[size]))
+					require.NoError(t, err)
+
+					want := min(size, tt.wantWidth, tt.wantHeight)
+					assert.Equal(t, want, thumb.Width)
+					assert.Equal(t, want, thumb.Height)
+				}
+			},
+		)
+	}
+}
+
+// TestOrient tests that every EXIF orientation maps the top-left pixel to the expected corner.
+func TestOrient(t *testing.T) {
+	src := solidImage(3, 2, color.NRGBA{A: 255})
+	marker := color.NRGBA{R: 255, A: 255}
+	src.Set(0, 0, marker)
+
+	tests := []struct {
+		name        string
+		orientation int
+		wantSize    image.Point
+		wantMarker  image.Point
+	}{
+		{name: "upright", orientation: 1, wantSize: image.Pt(3, 2), wantMarker: image.Pt(0, 0)},
+		{name: "mirrored", orientation: 2, wantSize: image.Pt(3, 2), wantMarker: image.Pt(2, 0)},
+		{name: "rotated 180", orientation: 3, wantSize: image.Pt(3, 2), wantMarker: image.Pt(2, 1)},
+		{name: "flipped", orientation: 4, wantSize: image.Pt(3, 2), wantMarker: image.Pt(0, 1)},
+		{name: "transposed", orientation: 5, wantSize: image.Pt(2, 3), wantMarker: image.Pt(0, 0)},
+		{name: "rotated 90 clockwise", orientation: 6, wantSize: image.Pt(2, 3), wantMarker: image.Pt(1, 0)},
+		{name: "transversed", orientation: 7, wantSize: image.Pt(2, 3), wantMarker: image.Pt(1, 2)},
+		{name: "rotated 90 counter-clockwise", orientation: 8, wantSize: image.Pt(2, 3), wantMarker: image.Pt(0, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				out := orient(src, tt.orientation)
+				assert.Equal(t, tt.wantSize, out.Bounds().Size())
+				assert.Equal(t, marker, color.NRGBAModel.Convert(out.At(tt.wantMarker.X, tt.wantMarker.Y)))
+			},
+		)
+	}
+}
+
+// TestJPEGOrientation tests reading the orientation tag from JPEG files.
+func TestJPEGOrientation(t *testing.T) {
+	plain := encodeJPEG(t, solidImage(4, 4, color.White))
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "no EXIF", data: plain, want: 1},
+		{name: "rotated", data: withOrientation(plain, 8), want: 8},
+		{name: "out of range", data: withOrientation(plain, 42), want: 1},
+		{name: "not a JPEG", data: []byte("hello"), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, jpegOrientation(tt.data))
+			},
+		)
+	}
+}
+
+// TestThumbnailKey tests naming the thumbnails of stored images.
+func TestThumbnailKey(t *testing.T) {
+	assert.Equal(t, "profile/abc_64.jpg", ThumbnailKey("profile/abc.jpg", 64))
+	assert.Equal(t, "profile/abc_512", ThumbnailKey("profile/abc", 512))
+}
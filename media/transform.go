@@ -0,0 +1,89 @@
+package media
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// orient returns img transformed according to an EXIF orientation value, so that it displays upright once the
+// orientation tag is gone. Values outside 2..8 leave the image unchanged.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	return dst
+}
+
+// fit scales img down so that its longest side is at most limit pixels, keeping the aspect ratio.
+// Smaller images are returned unchanged.
+func fit(img image.Image, limit int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= limit && h <= limit {
+		return img
+	}
+
+	if w >= h {
+		w, h = limit, max(1, h*limit/w)
+	} else {
+		w, h = max(1, w*limit/h), limit
+	}
+
+	return scale(img, b, w, h)
+}
+
+// thumbnail crops the centre square of img and scales it to size pixels per side.
+// Images smaller than size are cropped but never scaled up.
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	crop := image.Rect(x0, y0, x0+side, y0+side)
+
+	target := min(size, side)
+	return scale(img, crop, target, target)
+}
+
+// scale resamples the src region of img into a new w by h image.
+func scale(img image.Image, src image.Rectangle, w, h int) image.Image {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+
+	return dst
+}
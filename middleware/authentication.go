@@ -1,67 +1,41 @@
 package middleware
 
 import (
-	"net/http"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
-	"github.com/Caknoooo/go-gin-clean-starter/utils"
 )
 
 // Authenticate validates the JWT token from the Authorization header and sets user data in the context for subsequent use.
+// Requests without a valid token are aborted with dto.ErrTokenNotFound or dto.ErrTokenNotValid, which ErrorHandler reports.
 func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
 		if authHeader == "" {
-			response := utils.BuildResponseFailed(
-				dto.MESSAGE_FAILED_PROSES_REQUEST,
-				dto.MESSAGE_FAILED_TOKEN_NOT_FOUND,
-				nil,
-			)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotFound)
 			return
 		}
 
 		if !strings.Contains(authHeader, "Bearer ") {
-			response := utils.BuildResponseFailed(
-				dto.MESSAGE_FAILED_PROSES_REQUEST,
-				dto.MESSAGE_FAILED_TOKEN_NOT_VALID,
-				nil,
-			)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
 			return
 		}
 
 		authHeader = strings.Replace(authHeader, "Bearer ", "", -1)
 		token, err := jwtService.ValidateToken(authHeader)
-		if err != nil {
-			response := utils.BuildResponseFailed(
-				dto.MESSAGE_FAILED_PROSES_REQUEST,
-				dto.MESSAGE_FAILED_TOKEN_NOT_VALID,
-				nil,
-			)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
-			return
-		}
-
-		if !token.Valid {
-			response := utils.BuildResponseFailed(
-				dto.MESSAGE_FAILED_PROSES_REQUEST,
-				dto.MESSAGE_FAILED_DENIED_ACCESS,
-				nil,
-			)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		if err != nil || !token.Valid {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
 			return
 		}
 
 		userId, err := jwtService.GetUserIDByToken(authHeader)
 		if err != nil {
-			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, fmt.Errorf("%w: %w", dto.ErrTokenNotValid, err))
 			return
 		}
 
@@ -64,10 +64,10 @@ func TestAuthenticateMiddleware(t *testing.T) {
 			setupAuth:      func() string { return "" },
 			mockJWTSetup:   func(mock *MockJWTService) {},
 			expectedStatus: http.StatusUnauthorized,
-			expectedResponse: utils.BuildResponseFailed(
+			expectedResponse: utils.BuildResponseError(
 				dto.MESSAGE_FAILED_PROSES_REQUEST,
+				dto.ErrTokenNotFound.Code,
 				dto.MESSAGE_FAILED_TOKEN_NOT_FOUND,
-				nil,
 			),
 		},
 		{
@@ -75,10 +75,10 @@ func TestAuthenticateMiddleware(t *testing.T) {
 			setupAuth:      func() string { return "InvalidTokenFormat" },
 			mockJWTSetup:   func(mock *MockJWTService) {},
 			expectedStatus: http.StatusUnauthorized,
-			expectedResponse: utils.BuildResponseFailed(
+			expectedResponse: utils.BuildResponseError(
 				dto.MESSAGE_FAILED_PROSES_REQUEST,
+				dto.ErrTokenNotValid.Code,
 				dto.MESSAGE_FAILED_TOKEN_NOT_VALID,
-				nil,
 			),
 		},
 		{
@@ -88,10 +88,10 @@ func TestAuthenticateMiddleware(t *testing.T) {
 				mock.On("ValidateToken", "invalidtoken").Return((*jwt.Token)(nil), errors.New("invalid token"))
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedResponse: utils.BuildResponseFailed(
+			expectedResponse: utils.BuildResponseError(
 				dto.MESSAGE_FAILED_PROSES_REQUEST,
+				dto.ErrTokenNotValid.Code,
 				dto.MESSAGE_FAILED_TOKEN_NOT_VALID,
-				nil,
 			),
 		},
 		{
@@ -102,8 +102,12 @@ func TestAuthenticateMiddleware(t *testing.T) {
 				mock.On("ValidateToken", "validtoken").Return(token, nil)
 				mock.On("GetUserIDByToken", "validtoken").Return("", errors.New("user not found"))
 			},
-			expectedStatus:   http.StatusUnauthorized,
-			expectedResponse: utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, "user not found", nil),
+			expectedStatus: http.StatusUnauthorized,
+			expectedResponse: utils.BuildResponseError(
+				dto.MESSAGE_FAILED_PROSES_REQUEST,
+				dto.ErrTokenNotValid.Code,
+				"token not valid: user not found",
+			),
 		},
 		{
 			name:      "Valid token and user ID",
@@ -136,7 +140,7 @@ func TestAuthenticateMiddleware(t *testing.T) {
 				mockJWT := new(MockJWTService)
 				tt.mockJWTSetup(mockJWT)
 
-				router.Use(Authenticate(mockJWT))
+				router.Use(ErrorHandler(), Authenticate(mockJWT))
 				router.GET(
 					"/test", func(c *gin.Context) {
 						if tt.checkContext != nil {
@@ -163,6 +167,7 @@ func TestAuthenticateMiddleware(t *testing.T) {
 					assert.NoError(t, err)
 					assert.Equal(t, tt.expectedResponse.Status, response.Status)
 					assert.Equal(t, tt.expectedResponse.Message, response.Message)
+					assert.Equal(t, tt.expectedResponse.Code, response.Code)
 					assert.Equal(t, tt.expectedResponse.Error, response.Error)
 				}
 
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// RequireRole allows the request only when the authenticated user currently holds one of the given roles.
+// The role is read from the database rather than the token so role changes take effect immediately.
+// Users without an allowed role, or who no longer exist, are denied with dto.ErrAccessDenied, while a failed lookup is
+// reported as an internal error. It must run after Authenticate.
+func RequireRole(userService service.UserService, roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userId := ctx.GetString("user_id")
+		if userId == "" {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotFound)
+			return
+		}
+
+		user, err := userService.GetUserById(ctx.Request.Context(), userId)
+		if errors.Is(err, dto.ErrUserNotFound) {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrAccessDenied)
+			return
+		}
+		if err != nil {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
+			return
+		}
+
+		if !slices.Contains(roles, user.Role) {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrAccessDenied)
+			return
+		}
+
+		ctx.Set("role", user.Role)
+		ctx.Next()
+	}
+}
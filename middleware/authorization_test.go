@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+// MockUserService is a mock of the user service lookups used by the authorization middleware.
+type MockUserService struct {
+	service.UserService
+	mock.Mock
+}
+
+// GetUserById returns the mocked user for the given ID.
+func (m *MockUserService) GetUserById(_ context.Context, userId string) (dto.UserResponse, error) {
+	args := m.Called(userId)
+	return args.Get(0).(dto.UserResponse), args.Error(1)
+}
+
+// TestRequireRole tests that RequireRole admits only users holding one of the allowed roles, and reports failed
+// lookups as internal errors rather than denying access.
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		userId         string
+		setup          func(m *MockUserService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:   "admin is allowed",
+			userId: "admin-id",
+			setup: func(m *MockUserService) {
+				m.On("GetUserById", "admin-id").Return(dto.UserResponse{Role: constants.ENUM_ROLE_ADMIN}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "regular user is forbidden",
+			userId: "user-id",
+			setup: func(m *MockUserService) {
+				m.On("GetUserById", "user-id").Return(dto.UserResponse{Role: constants.ENUM_ROLE_USER}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   dto.ErrAccessDenied.Code,
+		},
+		{
+			name:   "unknown user is forbidden",
+			userId: "missing-id",
+			setup: func(m *MockUserService) {
+				m.On("GetUserById", "missing-id").Return(dto.UserResponse{}, dto.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   dto.ErrAccessDenied.Code,
+		},
+		{
+			name:   "failed lookup is an internal error",
+			userId: "admin-id",
+			setup: func(m *MockUserService) {
+				m.On("GetUserById", "admin-id").Return(dto.UserResponse{}, errors.New("pq: connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   apperror.ErrInternal.Code,
+		},
+		{
+			name:           "unauthenticated request is rejected",
+			setup:          func(m *MockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   dto.ErrTokenNotFound.Code,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				gin.SetMode(gin.TestMode)
+
+				userService := &MockUserService{}
+				tt.setup(userService)
+
+				router := gin.New()
+				router.Use(ErrorHandler())
+				router.GET(
+					"/admin", func(c *gin.Context) {
+						if tt.userId != "" {
+							c.Set("user_id", tt.userId)
+						}
+						c.Next()
+					}, RequireRole(userService, constants.ENUM_ROLE_ADMIN), func(c *gin.Context) {
+						c.Status(http.StatusOK)
+					},
+				)
+
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				if tt.expectedCode != "" {
+					var response utils.Response
+					assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+					assert.Equal(t, tt.expectedCode, response.Code)
+				}
+				userService.AssertExpectations(t)
+			},
+		)
+	}
+}
@@ -26,7 +26,8 @@ func TestCORSMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":      "*",
 				"Access-Control-Allow-Methods":     "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE",
-				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With",
+				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match",
+				"Access-Control-Expose-Headers":    "ETag",
 				"Access-Control-Allow-Credentials": "true",
 			},
 		},
@@ -38,7 +39,8 @@ func TestCORSMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":      "*",
 				"Access-Control-Allow-Methods":     "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE",
-				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With",
+				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match",
+				"Access-Control-Expose-Headers":    "ETag",
 				"Access-Control-Allow-Credentials": "true",
 			},
 		},
@@ -50,7 +52,8 @@ func TestCORSMiddleware(t *testing.T) {
 			expectedHeaders: map[string]string{
 				"Access-Control-Allow-Origin":      "*",
 				"Access-Control-Allow-Methods":     "POST, HEAD, PATCH, OPTIONS, GET, PUT, DELETE",
-				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With",
+				"Access-Control-Allow-Headers":     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match",
+				"Access-Control-Expose-Headers":    "ETag",
 				"Access-Control-Allow-Credentials": "true",
 			},
 		},
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+// ErrorHandler responds to requests whose handlers failed with an error added to the context and wrote no response.
+// The status and the code of the response follow the kind of the apperror.Error in the error chain, and its message
+// is the metadata of the error when it is a string. Errors without an apperror.Error are reported as
+// apperror.ErrInternal, and internal errors are logged while clients only get the message of their apperror.Error.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+
+		appErr := apperror.From(last.Err)
+		if appErr.Internal() {
+			log.Printf("error handler: %s %s: %v", ctx.Request.Method, ctx.Request.URL.Path, last.Err)
+		}
+
+		message, ok := last.Meta.(string)
+		if !ok {
+			message = appErr.Message
+		}
+
+		res := utils.BuildResponseError(message, appErr.Code, apperror.PublicMessage(last.Err))
+		ctx.AbortWithStatusJSON(appErr.Status(), res)
+	}
+}
+
+// abortWithError aborts the request with err, which ErrorHandler reports with the status and code of its kind and
+// with message.
+func abortWithError(ctx *gin.Context, message string, err error) {
+	ctx.Abort()
+	_ = ctx.Error(err).SetMeta(message)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+// TestErrorHandler tests that errors added by handlers are reported with the status and code of their kind, without
+// leaking the details of internal errors.
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	errNotFound := apperror.New(apperror.KIND_NOT_FOUND, "USER_NOT_FOUND", "user not found")
+	errFailed := apperror.New(apperror.KIND_INTERNAL, "UPDATE_USER_FAILED", "failed to update user")
+
+	tests := []struct {
+		name           string
+		handler        gin.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "no error",
+			handler: func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{"status": true})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status": true}`,
+		},
+		{
+			name: "client error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(fmt.Errorf("%w: id 42", errNotFound)).SetMeta("failed get user")
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": false,
+				"message": "failed get user",
+				"code": "USER_NOT_FOUND",
+				"error": "user not found: id 42"
+			}`,
+		},
+		{
+			name: "error without message",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(errNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"status": false,
+				"message": "user not found",
+				"code": "USER_NOT_FOUND",
+				"error": "user not found"
+			}`,
+		},
+		{
+			name: "internal error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(fmt.Errorf("%w: connection refused", errFailed)).SetMeta("failed update user")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": false,
+				"message": "failed update user",
+				"code": "UPDATE_USER_FAILED",
+				"error": "failed to update user"
+			}`,
+		},
+		{
+			name: "unknown error",
+			handler: func(ctx *gin.Context) {
+				_ = ctx.Error(errors.New("pq: connection refused")).SetMeta("failed get user")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"status": false,
+				"message": "failed get user",
+				"code": "INTERNAL_ERROR",
+				"error": "internal server error"
+			}`,
+		},
+		{
+			name: "response already written",
+			handler: func(ctx *gin.Context) {
+				ctx.JSON(http.StatusConflict, gin.H{"status": false})
+				_ = ctx.Error(errNotFound)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"status": false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				router := gin.New()
+				router.Use(ErrorHandler())
+				router.GET("/user", tt.handler)
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/user", nil))
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			},
+		)
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+)
+
+const (
+	// WEBHOOK_SIGNATURE_HEADER carries "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+	WEBHOOK_SIGNATURE_HEADER = "X-Webhook-Signature"
+
+	// WEBHOOK_TIMESTAMP_HEADER carries the Unix time at which the webhook request was signed.
+	WEBHOOK_TIMESTAMP_HEADER = "X-Webhook-Timestamp"
+
+	// WEBHOOK_TOLERANCE is how far the signing time may be from now before a request is rejected as a replay.
+	WEBHOOK_TOLERANCE = 5 * time.Minute
+
+	// WEBHOOK_MAX_BODY_SIZE is the largest webhook body accepted, in bytes.
+	WEBHOOK_MAX_BODY_SIZE = 5 << 20
+)
+
+// webhookNow returns the current time; it is replaced in tests.
+var webhookNow = time.Now
+
+// VerifyWebhookSignature admits a request only when it is signed with secret and was signed recently.
+// The body is buffered so handlers can still read it. Every request is rejected when secret is empty. Rejected
+// requests are aborted with an error that ErrorHandler reports.
+func VerifyWebhookSignature(secret string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, WEBHOOK_MAX_BODY_SIZE+1))
+		if err != nil {
+			abortWithError(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+			return
+		}
+		if len(body) > WEBHOOK_MAX_BODY_SIZE {
+			abortWithError(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.ErrWebhookBodyTooLarge)
+			return
+		}
+
+		timestamp := ctx.GetHeader(WEBHOOK_TIMESTAMP_HEADER)
+		if secret == "" || !recentTimestamp(timestamp) ||
+			!validSignature(secret, timestamp, body, ctx.GetHeader(WEBHOOK_SIGNATURE_HEADER)) {
+			abortWithError(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrInvalidWebhookSignature)
+			return
+		}
+
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		ctx.Next()
+	}
+}
+
+// SignWebhook returns the signature header value of body signed at timestamp with secret.
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature compares the received signature with the expected one in constant time.
+func validSignature(secret string, timestamp string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body)))
+}
+
+// recentTimestamp reports whether the Unix timestamp is within WEBHOOK_TOLERANCE of now.
+func recentTimestamp(timestamp string) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	age := webhookNow().Sub(time.Unix(seconds, 0))
+	return age <= WEBHOOK_TOLERANCE && age >= -WEBHOOK_TOLERANCE
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+// TestVerifyWebhookSignature tests that only recent requests signed with the configured secret are admitted.
+func TestVerifyWebhookSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	originalNow := webhookNow
+	webhookNow = func() time.Time { return now }
+	t.Cleanup(func() { webhookNow = originalNow })
+
+	body := `{"events":[]}`
+	fresh := strconv.FormatInt(now.Unix(), 10)
+	stale := strconv.FormatInt(now.Add(-WEBHOOK_TOLERANCE-time.Second).Unix(), 10)
+
+	tests := []struct {
+		name           string
+		secret         string
+		timestamp      string
+		signature      string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "valid signature",
+			secret:         "s3cret",
+			timestamp:      fresh,
+			signature:      SignWebhook("s3cret", fresh, []byte(body)),
+			body:           body,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong secret",
+			secret:         "s3cret",
+			timestamp:      fresh,
+			signature:      SignWebhook("other", fresh, []byte(body)),
+			body:           body,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   dto.ErrInvalidWebhookSignature.Code,
+		},
+		{
+			name:           "tampered body",
+			secret:         "s3cret",
+			timestamp:      fresh,
+			signature:      SignWebhook("s3cret", fresh, []byte(body)),
+			body:           `{"events":[{}]}`,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   dto.ErrInvalidWebhookSignature.Code,
+		},
+		{
+			name:           "replayed request",
+			secret:         "s3cret",
+			timestamp:      stale,
+			signature:      SignWebhook("s3cret", stale, []byte(body)),
+			body:           body,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   dto.ErrInvalidWebhookSignature.Code,
+		},
+		{
+			name:           "secret not configured",
+			secret:         "",
+			timestamp:      fresh,
+			signature:      SignWebhook("", fresh, []byte(body)),
+			body:           body,
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   dto.ErrInvalidWebhookSignature.Code,
+		},
+		{
+			name:           "body too large",
+			secret:         "s3cret",
+			timestamp:      fresh,
+			body:           strings.Repeat("a", WEBHOOK_MAX_BODY_SIZE+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedCode:   dto.ErrWebhookBodyTooLarge.Code,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var received string
+				router := gin.New()
+				router.Use(ErrorHandler())
+				router.POST(
+					"/webhook", VerifyWebhookSignature(tt.secret), func(ctx *gin.Context) {
+						raw, _ := io.ReadAll(ctx.Request.Body)
+						received = string(raw)
+						ctx.Status(http.StatusOK)
+					},
+				)
+
+				req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+				req.Header.Set(WEBHOOK_TIMESTAMP_HEADER, tt.timestamp)
+				req.Header.Set(WEBHOOK_SIGNATURE_HEADER, tt.signature)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				if tt.expectedStatus == http.StatusOK {
+					assert.Equal(t, tt.body, received, "handler should still be able to read the body")
+				} else {
+					var response utils.Response
+					assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+					assert.Equal(t, tt.expectedCode, response.Code)
+				}
+			},
+		)
+	}
+}
@@ -1,29 +1,38 @@
+-- Reference schema for manual setup. The versioned files in migrations/sql are the source of truth;
+-- apply them with `go run main.go migrate up` instead of running this file on a managed database.
 CREATE DATABASE golang_template;
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
--- migrations/users.sql
+-- migrations/sql/20250101000000_create_users_table.up.sql
 CREATE TABLE users (
-    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
-    nama            VARCHAR(100) NOT NULL,
-    phone_number    VARCHAR(30) NOT NULL,
-    email           VARCHAR(100) NOT NULL,
-    password        VARCHAR(100) NOT NULL,
-    role            VARCHAR(100) NOT NULL,
-    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name         VARCHAR(100) NOT NULL,
+    email        VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(20),
+    password     VARCHAR(255) NOT NULL,
+    role         VARCHAR(50) NOT NULL DEFAULT 'user',
+    image_url    VARCHAR(255),
+    is_verified  BOOLEAN DEFAULT FALSE,
+    created_at   TIMESTAMP WITH TIME ZONE,
+    updated_at   TIMESTAMP WITH TIME ZONE,
+    deleted_at   TIMESTAMP WITH TIME ZONE
 );
 
--- migrations/refresh_tokens.sql
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+CREATE INDEX idx_users_phone_number ON users (phone_number);
+
+-- migrations/sql/20250101000100_create_refresh_tokens_table.up.sql
 CREATE TABLE refresh_tokens (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
-    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    token VARCHAR(255) NOT NULL,
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID NOT NULL,
+    token      VARCHAR(255) NOT NULL,
     expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    deleted_at TIMESTAMP WITH TIME ZONE
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
 );
 
-CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
-CREATE UNIQUE INDEX idx_refresh_tokens_token ON refresh_tokens(token);
\ No newline at end of file
+CREATE UNIQUE INDEX idx_refresh_tokens_token ON refresh_tokens (token);
@@ -1,17 +1,19 @@
 package migrations
 
 import (
-	"gorm.io/gorm"
+	"context"
 
-	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
 )
 
-// Migrate applies automatic database migrations for the specified models, creating or updating tables as needed.
+// Migrate applies every pending versioned migration to the database while holding the migration advisory lock.
 func Migrate(db *gorm.DB) error {
-	if err := db.AutoMigrate(
-		&entity.User{},
-		&entity.RefreshToken{},
-	); err != nil {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
 		return err
 	}
 
@@ -0,0 +1,339 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// SCHEMA_MIGRATIONS_TABLE is the name of the table that records which migration versions have been applied.
+	SCHEMA_MIGRATIONS_TABLE = "schema_migrations"
+
+	// MIGRATION_LOCK_ID is the key of the Postgres advisory lock held while migrations are running.
+	MIGRATION_LOCK_ID int64 = 727_000_001
+
+	// VERSION_LAYOUT is the time layout used to generate migration version prefixes.
+	VERSION_LAYOUT = "20060102150405"
+)
+
+var (
+	// ErrMigrationLocked indicates that another process currently holds the migration advisory lock.
+	ErrMigrationLocked = errors.New("another migration is already running")
+
+	// ErrMigrationIrreversible indicates that a migration cannot be rolled back because it has no down step.
+	ErrMigrationIrreversible = errors.New("migration has no down step")
+
+	// ErrDuplicateMigration indicates that two migrations were registered with the same version.
+	ErrDuplicateMigration = errors.New("duplicate migration version")
+)
+
+type (
+	// Migration represents a single versioned schema change with an up step and an optional down step.
+	Migration struct {
+		Version string
+		Name    string
+		Up      func(tx *gorm.DB) error
+		Down    func(tx *gorm.DB) error
+	}
+
+	// SchemaMigration is the record stored in the schema_migrations table for every applied migration.
+	SchemaMigration struct {
+		Version   string    `gorm:"type:varchar(14);primary_key"`
+		Name      string    `gorm:"type:varchar(255);not null"`
+		AppliedAt time.Time `gorm:"type:timestamp with time zone;not null"`
+	}
+
+	// MigrationStatus describes whether a known or recorded migration version has been applied to the database.
+	MigrationStatus struct {
+		Version   string
+		Name      string
+		Applied   bool
+		AppliedAt *time.Time
+		Missing   bool
+	}
+
+	// Migrator applies and rolls back versioned migrations while holding a Postgres advisory lock.
+	Migrator struct {
+		db         *gorm.DB
+		migrations []Migration
+	}
+)
+
+// TableName returns the table name used by GORM for the SchemaMigration model.
+func (SchemaMigration) TableName() string {
+	return SCHEMA_MIGRATIONS_TABLE
+}
+
+// registered holds Go migrations added through Register, merged with the embedded SQL migrations at load time.
+var registered []Migration
+
+// Register adds a Go migration to the set of migrations known to every Migrator created afterward.
+func Register(migration Migration) {
+	registered = append(registered, migration)
+}
+
+// LoadMigrations returns all embedded SQL migrations and registered Go migrations sorted by version.
+var LoadMigrations = func() ([]Migration, error) {
+	sqlMigrations, err := loadSQLMigrations(sqlFiles, SQL_DIR)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortMigrations(append(sqlMigrations, registered...))
+}
+
+// sortMigrations orders migrations by version and rejects duplicate versions.
+func sortMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+
+	sort.SliceStable(
+		sorted, func(i, j int) bool {
+			return sorted[i].Version < sorted[j].Version
+		},
+	)
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateMigration, sorted[i].Version)
+		}
+	}
+
+	return sorted, nil
+}
+
+// NewMigrator creates a Migrator for the given database using every known migration.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every pending migration in version order and returns the migrations that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(
+		ctx, func(conn *gorm.DB) error {
+			done, err := appliedVersions(conn)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range pendingMigrations(m.migrations, done) {
+				if err := conn.Transaction(
+					func(tx *gorm.DB) error {
+						if err := migration.Up(tx); err != nil {
+							return err
+						}
+
+						return tx.Create(
+							&SchemaMigration{
+								Version:   migration.Version,
+								Name:      migration.Name,
+								AppliedAt: time.Now(),
+							},
+						).Error
+					},
+				); err != nil {
+					return fmt.Errorf("migration %s_%s: %w", migration.Version, migration.Name, err)
+				}
+
+				applied = append(applied, migration)
+			}
+
+			return nil
+		},
+	)
+
+	return applied, err
+}
+
+// Down rolls back the last n applied migrations in reverse version order and returns the migrations that were reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(
+		ctx, func(conn *gorm.DB) error {
+			done, err := appliedVersions(conn)
+			if err != nil {
+				return err
+			}
+
+			for _, migration := range rollbackMigrations(m.migrations, done, n) {
+				if migration.Down == nil {
+					return fmt.Errorf("%w: %s_%s", ErrMigrationIrreversible, migration.Version, migration.Name)
+				}
+
+				if err := conn.Transaction(
+					func(tx *gorm.DB) error {
+						if err := migration.Down(tx); err != nil {
+							return err
+						}
+
+						return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+					},
+				); err != nil {
+					return fmt.Errorf("rollback %s_%s: %w", migration.Version, migration.Name, err)
+				}
+
+				reverted = append(reverted, migration)
+			}
+
+			return nil
+		},
+	)
+
+	return reverted, err
+}
+
+// Status reports every known migration together with its applied state, including recorded versions missing from the code.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn := m.db.WithContext(ctx)
+	if err := ensureSchemaMigrationsTable(conn); err != nil {
+		return nil, err
+	}
+
+	var records []SchemaMigration
+	if err := conn.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	return buildStatus(m.migrations, records), nil
+}
+
+// withLock runs fn on a single pinned connection while holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(
+		func(conn *gorm.DB) error {
+			var locked bool
+			if err := conn.Raw("SELECT pg_try_advisory_lock(?)", MIGRATION_LOCK_ID).Scan(&locked).Error; err != nil {
+				return err
+			}
+
+			if !locked {
+				return ErrMigrationLocked
+			}
+
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", MIGRATION_LOCK_ID)
+
+			if err := ensureSchemaMigrationsTable(conn); err != nil {
+				return err
+			}
+
+			return fn(conn)
+		},
+	)
+}
+
+// ensureSchemaMigrationsTable creates the schema_migrations table if it does not exist yet.
+func ensureSchemaMigrationsTable(db *gorm.DB) error {
+	return db.Exec(
+		`CREATE TABLE IF NOT EXISTS ` + SCHEMA_MIGRATIONS_TABLE + ` (
+			version    VARCHAR(14) PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`,
+	).Error
+}
+
+// appliedVersions returns the set of migration versions recorded in the schema_migrations table.
+func appliedVersions(db *gorm.DB) (map[string]bool, error) {
+	var versions []string
+	if err := db.Model(&SchemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		done[version] = true
+	}
+
+	return done, nil
+}
+
+// pendingMigrations returns the migrations that have not been applied yet, preserving version order.
+func pendingMigrations(migrations []Migration, applied map[string]bool) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending
+}
+
+// rollbackMigrations returns up to n applied migrations ordered from the newest to the oldest.
+func rollbackMigrations(migrations []Migration, applied map[string]bool, n int) []Migration {
+	var rollback []Migration
+	for i := len(migrations) - 1; i >= 0 && len(rollback) < n; i-- {
+		if applied[migrations[i].Version] {
+			rollback = append(rollback, migrations[i])
+		}
+	}
+
+	return rollback
+}
+
+// buildStatus merges known migrations with applied records into a single list ordered by version.
+func buildStatus(migrations []Migration, records []SchemaMigration) []MigrationStatus {
+	recorded := make(map[string]SchemaMigration, len(records))
+	for _, record := range records {
+		recorded[record.Version] = record
+	}
+
+	known := make(map[string]bool, len(migrations))
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+
+		if record, ok := recorded[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range records {
+		if known[record.Version] {
+			continue
+		}
+
+		appliedAt := record.AppliedAt
+		statuses = append(
+			statuses, MigrationStatus{
+				Version:   record.Version,
+				Name:      record.Name,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			},
+		)
+	}
+
+	sort.SliceStable(
+		statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		},
+	)
+
+	return statuses
+}
@@ -0,0 +1,104 @@
+package migrations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versions extracts the version of every migration in order, used to compare migration lists in assertions.
+func versions(migrations []Migration) []string {
+	result := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return result
+}
+
+// TestSortMigrations verifies that migrations are ordered by version and that duplicate versions are rejected.
+func TestSortMigrations(t *testing.T) {
+	t.Run(
+		"orders by version", func(t *testing.T) {
+			sorted, err := sortMigrations(
+				[]Migration{
+					{Version: "20250103000000", Name: "third"},
+					{Version: "20250101000000", Name: "first"},
+					{Version: "20250102000000", Name: "second"},
+				},
+			)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"20250101000000", "20250102000000", "20250103000000"}, versions(sorted))
+		},
+	)
+
+	t.Run(
+		"rejects duplicate versions", func(t *testing.T) {
+			_, err := sortMigrations(
+				[]Migration{
+					{Version: "20250101000000", Name: "a"},
+					{Version: "20250101000000", Name: "b"},
+				},
+			)
+
+			assert.ErrorIs(t, err, ErrDuplicateMigration)
+		},
+	)
+}
+
+// TestPendingAndRollbackMigrations verifies the selection of migrations to apply and to roll back based on applied versions.
+func TestPendingAndRollbackMigrations(t *testing.T) {
+	all := []Migration{
+		{Version: "20250101000000"},
+		{Version: "20250102000000"},
+		{Version: "20250103000000"},
+		{Version: "20250104000000"},
+	}
+	applied := map[string]bool{
+		"20250101000000": true,
+		"20250102000000": true,
+		"20250103000000": true,
+	}
+
+	assert.Equal(t, []string{"20250104000000"}, versions(pendingMigrations(all, applied)))
+	assert.Equal(t, []string{"20250103000000"}, versions(rollbackMigrations(all, applied, 1)))
+	assert.Equal(
+		t,
+		[]string{"20250103000000", "20250102000000", "20250101000000"},
+		versions(rollbackMigrations(all, applied, 10)),
+	)
+	assert.Empty(t, rollbackMigrations(all, map[string]bool{}, 1))
+}
+
+// TestBuildStatus verifies that status merges known migrations with recorded versions and flags versions missing from code.
+func TestBuildStatus(t *testing.T) {
+	appliedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	statuses := buildStatus(
+		[]Migration{
+			{Version: "20250101000000", Name: "create_users_table"},
+			{Version: "20250103000000", Name: "add_index"},
+		},
+		[]SchemaMigration{
+			{Version: "20250101000000", Name: "create_users_table", AppliedAt: appliedAt},
+			{Version: "20250102000000", Name: "removed_migration", AppliedAt: appliedAt},
+		},
+	)
+
+	require.Len(t, statuses, 3)
+
+	assert.Equal(t, "20250101000000", statuses[0].Version)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[0].Missing)
+	assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+
+	assert.Equal(t, "20250102000000", statuses[1].Version)
+	assert.True(t, statuses[1].Applied)
+	assert.True(t, statuses[1].Missing)
+
+	assert.Equal(t, "20250103000000", statuses[2].Version)
+	assert.False(t, statuses[2].Applied)
+	assert.Nil(t, statuses[2].AppliedAt)
+}
@@ -0,0 +1,165 @@
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// SchemaIssue describes a single difference between the live database schema and what a GORM entity expects.
+	SchemaIssue struct {
+		Table   string
+		Column  string
+		Problem string
+	}
+
+	// columnInfo holds the live properties of a database column that are compared against an entity field.
+	columnInfo struct {
+		Nullable bool
+		Length   int64
+		HasLen   bool
+	}
+)
+
+// String formats the schema issue as a single human-readable line.
+func (i SchemaIssue) String() string {
+	if i.Column == "" {
+		return fmt.Sprintf("%s: %s", i.Table, i.Problem)
+	}
+
+	return fmt.Sprintf("%s.%s: %s", i.Table, i.Column, i.Problem)
+}
+
+// typeLengthPattern extracts the length from a column type such as varchar(255).
+var typeLengthPattern = regexp.MustCompile(`\((\d+)\)`)
+
+// Models returns the GORM entities whose tables are managed by the versioned migrations.
+var Models = func() []any {
+	return []any{
+		&entity.User{},
+		&entity.RefreshToken{},
+		&entity.EmailOutbox{},
+		&entity.EmailSuppression{},
+		&entity.Upload{},
+		&entity.DataExport{},
+		&entity.EmailChange{},
+		&entity.PhoneVerification{},
+		&entity.AuditEvent{},
+		&SeedHistory{},
+	}
+}
+
+// CheckSchema compares the live database schema against the given GORM entities and returns every difference found.
+func CheckSchema(db *gorm.DB, models ...any) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+
+		table := stmt.Schema.Table
+		if !db.Migrator().HasTable(table) {
+			issues = append(issues, SchemaIssue{Table: table, Problem: "table does not exist"})
+			continue
+		}
+
+		columnTypes, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := make(map[string]columnInfo, len(columnTypes))
+		for _, columnType := range columnTypes {
+			nullable, _ := columnType.Nullable()
+			length, hasLen := columnType.Length()
+			columns[columnType.Name()] = columnInfo{
+				Nullable: nullable,
+				Length:   length,
+				HasLen:   hasLen,
+			}
+		}
+
+		issues = append(issues, compareColumns(table, stmt.Schema.Fields, columns)...)
+	}
+
+	return issues, nil
+}
+
+// compareColumns reports missing, unexpected, nullability and length differences between entity fields and live columns.
+func compareColumns(table string, fields []*schema.Field, columns map[string]columnInfo) []SchemaIssue {
+	var issues []SchemaIssue
+	expected := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		expected[field.DBName] = true
+
+		column, ok := columns[field.DBName]
+		if !ok {
+			issues = append(issues, SchemaIssue{Table: table, Column: field.DBName, Problem: "column does not exist"})
+			continue
+		}
+
+		if field.NotNull && !field.PrimaryKey && column.Nullable {
+			issues = append(
+				issues,
+				SchemaIssue{Table: table, Column: field.DBName, Problem: "column is nullable but entity requires NOT NULL"},
+			)
+		}
+
+		if size := fieldLength(field); size > 0 && column.HasLen && column.Length > 0 && size != column.Length {
+			issues = append(
+				issues, SchemaIssue{
+					Table:   table,
+					Column:  field.DBName,
+					Problem: fmt.Sprintf("column length is %d but entity expects %d", column.Length, size),
+				},
+			)
+		}
+	}
+
+	var unexpected []string
+	for name := range columns {
+		if !expected[name] {
+			unexpected = append(unexpected, name)
+		}
+	}
+	sort.Strings(unexpected)
+
+	for _, name := range unexpected {
+		issues = append(issues, SchemaIssue{Table: table, Column: name, Problem: "column is not mapped by the entity"})
+	}
+
+	return issues
+}
+
+// fieldLength returns the declared length of a field from its size tag or from a type tag such as varchar(100).
+func fieldLength(field *schema.Field) int64 {
+	if field.Size > 0 {
+		return int64(field.Size)
+	}
+
+	match := typeLengthPattern.FindStringSubmatch(string(field.DataType))
+	if match == nil {
+		return 0
+	}
+
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+// TestCompareColumns verifies that missing, unexpected, nullable and resized columns are reported as schema issues.
+func TestCompareColumns(t *testing.T) {
+	fields := []*schema.Field{
+		{DBName: "id", PrimaryKey: true, NotNull: true, DataType: "uuid"},
+		{DBName: "name", NotNull: true, DataType: "varchar(100)"},
+		{DBName: "email", NotNull: true, DataType: "varchar(255)"},
+		{DBName: "image_url", DataType: "varchar(255)"},
+		{Name: "User"},
+	}
+
+	columns := map[string]columnInfo{
+		"id":    {Nullable: false},
+		"email": {Nullable: true, Length: 100, HasLen: true},
+		"nama":  {Nullable: false, Length: 100, HasLen: true},
+		"image_url": {
+			Nullable: true,
+			Length:   255,
+			HasLen:   true,
+		},
+	}
+
+	issues := compareColumns("users", fields, columns)
+
+	assert.Equal(
+		t, []SchemaIssue{
+			{Table: "users", Column: "name", Problem: "column does not exist"},
+			{Table: "users", Column: "email", Problem: "column is nullable but entity requires NOT NULL"},
+			{Table: "users", Column: "email", Problem: "column length is 100 but entity expects 255"},
+			{Table: "users", Column: "nama", Problem: "column is not mapped by the entity"},
+		}, issues,
+	)
+}
+
+// TestSchemaIssue_String verifies the human-readable formatting of table-level and column-level schema issues.
+func TestSchemaIssue_String(t *testing.T) {
+	assert.Equal(t, "users: table does not exist", SchemaIssue{Table: "users", Problem: "table does not exist"}.String())
+	assert.Equal(
+		t,
+		"users.name: column does not exist",
+		SchemaIssue{Table: "users", Column: "name", Problem: "column does not exist"}.String(),
+	)
+}
@@ -1,16 +1,91 @@
 package migrations
 
 import (
+	"log"
+	"os"
+	"time"
+
 	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/migrations/seeds"
 )
 
-// Seeder seeds the database by executing predefined seeder functions, such as ListUserSeeder, and returns any errors encountered.
+// SEED_HISTORY_TABLE is the name of the table that records every seeder run.
+const SEED_HISTORY_TABLE = "seed_history"
+
+// SeedHistory is the record stored in the seed_history table each time a seeder completes.
+type SeedHistory struct {
+	ID          uint      `gorm:"primary_key;autoIncrement"`
+	Name        string    `gorm:"type:varchar(100);not null;index"`
+	Environment string    `gorm:"type:varchar(20);not null"`
+	Strategy    string    `gorm:"type:varchar(20);not null"`
+	RanAt       time.Time `gorm:"type:timestamp with time zone;not null"`
+}
+
+// TableName returns the table name used by GORM for the SeedHistory model.
+func (SeedHistory) TableName() string {
+	return SEED_HISTORY_TABLE
+}
+
+// CurrentEnv returns the running environment from APP_ENV, defaulting to development.
+func CurrentEnv() string {
+	if env := os.Getenv("APP_ENV"); env != "" {
+		return env
+	}
+
+	return constants.ENUM_RUN_DEVELOPMENT
+}
+
+// Seeder seeds the database by running every non-manual seeder allowed in the current environment in dependency order.
 func Seeder(db *gorm.DB) error {
-	if err := seeds.ListUserSeeder(db); err != nil {
+	return Seed(db, nil, seeds.Options{})
+}
+
+// Seed runs the named seeders (or every default seeder when names is empty) and their dependencies in the current
+// environment, recording each completed run in the seed_history table.
+func Seed(db *gorm.DB, names []string, opts seeds.Options) error {
+	env := CurrentEnv()
+
+	plan, err := seeds.Plan(seeds.Registered(), names, env)
+	if err != nil {
 		return err
 	}
 
+	if len(plan) == 0 {
+		return nil
+	}
+
+	for _, seeder := range plan {
+		runOpts := opts
+		if runOpts.Strategy == "" {
+			runOpts.Strategy = seeder.Strategy
+		}
+		if runOpts.Strategy == "" {
+			runOpts.Strategy = seeds.UPSERT_SKIP
+		}
+
+		if err := db.Transaction(
+			func(tx *gorm.DB) error {
+				if err := seeder.Run(tx, runOpts); err != nil {
+					return err
+				}
+
+				return tx.Create(
+					&SeedHistory{
+						Name:        seeder.Name,
+						Environment: env,
+						Strategy:    string(runOpts.Strategy),
+						RanAt:       time.Now(),
+					},
+				).Error
+			},
+		); err != nil {
+			return err
+		}
+
+		log.Printf("seeded %s (%s)", seeder.Name, runOpts.Strategy)
+	}
+
 	return nil
 }
@@ -1,58 +1,48 @@
 package migrations
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
-	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/migrations/seeds"
 )
 
-// MockSeeder is a mock implementation for seeding operations, used for testing purposes with the `mock` library.
-type MockSeeder struct {
-	mock.Mock
-}
+// TestCurrentEnv verifies that the seeding environment is read from APP_ENV and defaults to development.
+func TestCurrentEnv(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	assert.Equal(t, constants.ENUM_RUN_DEVELOPMENT, CurrentEnv())
 
-// ListUserSeeder seeds user data into the database using a mock implementation for testing purposes.
-func (m *MockSeeder) ListUserSeeder(db *gorm.DB) error {
-	args := m.Called(db)
-	return args.Error(0)
+	t.Setenv("APP_ENV", constants.ENUM_RUN_TESTING)
+	assert.Equal(t, constants.ENUM_RUN_TESTING, CurrentEnv())
 }
 
-// TestSeeder tests the Seeder functionality by mocking the ListUserSeeder function and validating both success and error paths.
-func TestSeeder(t *testing.T) {
-	t.Run(
-		"Success", func(t *testing.T) {
-			mockSeeder := new(MockSeeder)
-
-			mockSeeder.On("ListUserSeeder", mock.AnythingOfType("*gorm.DB")).Return(nil)
-
-			seeds.ListUserSeeder = mockSeeder.ListUserSeeder
+// TestSeeder_Production verifies that no default seeder runs in production and named seeders are refused there.
+func TestSeeder_Production(t *testing.T) {
+	t.Setenv("APP_ENV", constants.ENUM_RUN_PRODUCTION)
 
+	t.Run(
+		"Default seeders are skipped", func(t *testing.T) {
 			err := Seeder(nil)
 
 			assert.NoError(t, err)
-			mockSeeder.AssertExpectations(t)
 		},
 	)
 
 	t.Run(
-		"Error", func(t *testing.T) {
-			mockSeeder := new(MockSeeder)
+		"Named seeder is refused", func(t *testing.T) {
+			err := Seed(nil, []string{"users"}, seeds.Options{})
 
-			expectedErr := errors.New("seeder error")
-			mockSeeder.On("ListUserSeeder", mock.AnythingOfType("*gorm.DB")).Return(expectedErr)
-
-			seeds.ListUserSeeder = mockSeeder.ListUserSeeder
+			assert.ErrorIs(t, err, seeds.ErrSeederNotAllowed)
+		},
+	)
 
-			err := Seeder(nil)
+	t.Run(
+		"Unknown seeder", func(t *testing.T) {
+			err := Seed(nil, []string{"unknown"}, seeds.Options{})
 
-			assert.Error(t, err)
-			assert.Equal(t, expectedErr, err)
-			mockSeeder.AssertExpectations(t)
+			assert.ErrorIs(t, err, seeds.ErrSeederNotFound)
 		},
 	)
 }
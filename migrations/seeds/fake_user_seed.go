@@ -0,0 +1,97 @@
+package seeds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+)
+
+const (
+	// FAKE_USER_DEFAULT_COUNT is the number of users generated when no count is given to the fake_users seeder.
+	FAKE_USER_DEFAULT_COUNT = 100
+
+	// FAKE_USER_PASSWORD is the plain-text password shared by every generated user.
+	FAKE_USER_PASSWORD = "password123"
+
+	// FAKE_USER_EMAIL_DOMAIN is the domain used for generated email addresses so they never reach real inboxes.
+	FAKE_USER_EMAIL_DOMAIN = "example.com"
+
+	// fakeUserBatchSize is the number of rows inserted per statement when seeding generated users.
+	fakeUserBatchSize = 500
+)
+
+// init registers the fake_users seeder, which only runs when requested by name, e.g. seed fake_users -count 1000.
+func init() {
+	Register(
+		Seeder{
+			Name:         "fake_users",
+			Description:  "Generate N realistic users for load testing",
+			Environments: []string{constants.ENUM_RUN_DEVELOPMENT, constants.ENUM_RUN_TESTING},
+			Strategy:     UPSERT_SKIP,
+			Manual:       true,
+			Run:          SeedFakeUsers,
+		},
+	)
+}
+
+// FakeUsers generates count realistic users from the given random seed, with unique emails and unhashed passwords.
+func FakeUsers(count int, seed uint64) []entity.User {
+	faker := gofakeit.New(seed)
+	users := make([]entity.User, 0, count)
+
+	for i := 0; i < count; i++ {
+		person := faker.Person()
+		local := strings.ToLower(fmt.Sprintf("%s.%s.%d", person.FirstName, person.LastName, i))
+		local = strings.NewReplacer(" ", "", "'", "").Replace(local)
+
+		role := constants.ENUM_ROLE_USER
+		if faker.Number(1, 100) <= 2 {
+			role = constants.ENUM_ROLE_ADMIN
+		}
+
+		users = append(
+			users, entity.User{
+				Name:        person.FirstName + " " + person.LastName,
+				Email:       local + "@" + FAKE_USER_EMAIL_DOMAIN,
+				PhoneNumber: faker.Phone(),
+				Password:    FAKE_USER_PASSWORD,
+				Role:        role,
+				IsVerified:  faker.Bool(),
+			},
+		)
+	}
+
+	return users
+}
+
+// SeedFakeUsers inserts opts.Count generated users in batches, hashing the shared password once instead of per row
+// and skipping rows whose email already exists.
+func SeedFakeUsers(db *gorm.DB, opts Options) error {
+	count := opts.Count
+	if count <= 0 {
+		count = FAKE_USER_DEFAULT_COUNT
+	}
+
+	hashed, err := helpers.HashPassword(FAKE_USER_PASSWORD)
+	if err != nil {
+		return err
+	}
+
+	users := FakeUsers(count, uint64(gofakeit.Uint32()))
+	for i := range users {
+		users[i].ID = uuid.New()
+		users[i].Password = hashed
+	}
+
+	return db.Session(&gorm.Session{SkipHooks: true}).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&users, fakeUserBatchSize).Error
+}
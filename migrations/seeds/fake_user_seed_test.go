@@ -0,0 +1,25 @@
+package seeds
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFakeUsers verifies that generated users are deterministic per seed, have unique emails and pass entity validation.
+func TestFakeUsers(t *testing.T) {
+	users := FakeUsers(200, 42)
+	assert.Len(t, users, 200)
+	assert.Equal(t, users, FakeUsers(200, 42), "same seed should generate the same users")
+
+	validate := validator.New()
+	emails := make(map[string]bool, len(users))
+	for _, user := range users {
+		assert.False(t, emails[user.Email], "email %s should be unique", user.Email)
+		emails[user.Email] = true
+
+		assert.NoError(t, validate.Struct(user), "generated user %s should be valid", user.Email)
+		assert.Equal(t, FAKE_USER_PASSWORD, user.Password)
+	}
+}
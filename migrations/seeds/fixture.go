@@ -0,0 +1,207 @@
+package seeds
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FIXTURE_EXTENSIONS lists the fixture formats accepted by LoadFixture, in lookup order.
+var FIXTURE_EXTENSIONS = []string{".json", ".yaml", ".yml", ".csv"}
+
+// ErrFixtureNotFound indicates that no fixture file exists for a base name in any supported format.
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+// FindFixture returns the first existing fixture for basePath (a path without extension) in any supported format.
+func FindFixture(basePath string) (string, error) {
+	for _, ext := range FIXTURE_EXTENSIONS {
+		candidate := basePath + ext
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s.{json,yaml,yml,csv}", ErrFixtureNotFound, basePath)
+}
+
+// LoadFixture decodes a JSON, YAML or CSV fixture file into out, which must be a pointer to a slice of structs.
+// Fields are matched by their json tags in every format so one struct can describe all three.
+func LoadFixture(path string, out any) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func(file *os.File) {
+		err := file.Close()
+		if err != nil {
+			panic(err)
+		}
+	}(file)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.NewDecoder(file).Decode(out)
+	case ".yaml", ".yml":
+		return decodeYAML(file, out)
+	case ".csv":
+		return decodeCSV(file, out)
+	default:
+		return fmt.Errorf("unsupported fixture format: %s", path)
+	}
+}
+
+// decodeYAML decodes YAML into a generic value and re-encodes it as JSON so json tags drive the field mapping.
+func decodeYAML(r io.Reader, out any) error {
+	var raw any
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// decodeCSV maps each CSV row onto a new struct element using the header row and the json tags of the struct fields.
+func decodeCSV(r io.Reader, out any) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("csv fixture target must be a pointer to a slice")
+	}
+	slice = slice.Elem()
+
+	elemType := slice.Type().Elem()
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("csv fixture target must be a slice of structs")
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType).Elem()
+		for i, column := range header {
+			if i >= len(row) {
+				break
+			}
+
+			field, ok := fieldByJSONName(elem, strings.TrimSpace(column))
+			if !ok {
+				continue
+			}
+
+			if err := setFieldFromString(field, row[i]); err != nil {
+				return fmt.Errorf("line %d column %q: %w", line, column, err)
+			}
+		}
+
+		slice.Set(reflect.Append(slice, elem))
+	}
+}
+
+// fieldByJSONName finds a settable struct field by its json tag name, descending into embedded structs.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
+			if field, ok := fieldByJSONName(v.Field(i), name); ok {
+				return field, true
+			}
+			continue
+		}
+
+		tag := strings.Split(structField.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = structField.Name
+		}
+
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// setFieldFromString converts a CSV cell into the kind of the target field and assigns it.
+func setFieldFromString(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		if value == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			return nil
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package seeds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFixture writes content to name inside dir and returns the full path, failing the test on error.
+func writeFixture(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// TestLoadFixture verifies that JSON, YAML and CSV fixtures decode into the same structs using json tags.
+func TestLoadFixture(t *testing.T) {
+	dir := t.TempDir()
+
+	expected := []SeedUserRequest{
+		{Role: "admin", IsVerified: true},
+		{Role: "user", IsVerified: false},
+	}
+	expected[0].Name = "admin"
+	expected[0].Email = "admin@example.com"
+	expected[0].PhoneNumber = "08123456789"
+	expected[0].Password = "admin1234"
+	expected[1].Name = "user"
+	expected[1].Email = "user@example.com"
+	expected[1].PhoneNumber = "08123456780"
+	expected[1].Password = "user1234"
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "json",
+			file: "users.json",
+			content: `[
+				{"name": "admin", "phone_number": "08123456789", "email": "admin@example.com", "password": "admin1234", "role": "admin", "is_verified": true},
+				{"name": "user", "phone_number": "08123456780", "email": "user@example.com", "password": "user1234", "role": "user", "is_verified": false}
+			]`,
+		},
+		{
+			name: "yaml",
+			file: "users.yaml",
+			content: `
+- name: admin
+  phone_number: "08123456789"
+  email: admin@example.com
+  password: admin1234
+  role: admin
+  is_verified: true
+- name: user
+  phone_number: "08123456780"
+  email: user@example.com
+  password: user1234
+  role: user
+  is_verified: false
+`,
+		},
+		{
+			name: "csv",
+			file: "users.csv",
+			content: "name,phone_number,email,password,role,is_verified\n" +
+				"admin,08123456789,admin@example.com,admin1234,admin,true\n" +
+				"user,08123456780,user@example.com,user1234,user,false\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				path := writeFixture(t, dir, tt.file, tt.content)
+
+				var users []SeedUserRequest
+				err := LoadFixture(path, &users)
+
+				require.NoError(t, err)
+				assert.Equal(t, expected, users)
+			},
+		)
+	}
+
+	t.Run(
+		"unsupported format", func(t *testing.T) {
+			path := writeFixture(t, dir, "users.txt", "")
+
+			var users []SeedUserRequest
+			assert.Error(t, LoadFixture(path, &users))
+		},
+	)
+
+	t.Run(
+		"invalid csv value", func(t *testing.T) {
+			path := writeFixture(t, dir, "invalid.csv", "name,is_verified\nadmin,maybe\n")
+
+			var users []SeedUserRequest
+			assert.Error(t, LoadFixture(path, &users))
+		},
+	)
+}
+
+// TestFindFixture verifies that a fixture is found in any supported format and that a missing fixture is reported.
+func TestFindFixture(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "roles.yml", "[]")
+
+	path, err := FindFixture(filepath.Join(dir, "roles"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "roles.yml"), path)
+
+	_, err = FindFixture(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, ErrFixtureNotFound)
+}
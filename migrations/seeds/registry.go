@@ -0,0 +1,185 @@
+package seeds
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+const (
+	// UPSERT_SKIP inserts rows that do not exist yet and leaves existing rows untouched.
+	UPSERT_SKIP UpsertStrategy = "skip"
+
+	// UPSERT_UPDATE inserts rows that do not exist yet and overwrites the seeded columns of existing rows.
+	UPSERT_UPDATE UpsertStrategy = "update"
+)
+
+var (
+	// ErrSeederNotFound indicates that no seeder is registered under the requested name.
+	ErrSeederNotFound = errors.New("seeder not found")
+
+	// ErrSeederNotAllowed indicates that a seeder may not run in the current environment.
+	ErrSeederNotAllowed = errors.New("seeder not allowed in this environment")
+
+	// ErrSeederCycle indicates that the dependencies between seeders form a cycle.
+	ErrSeederCycle = errors.New("seeder dependency cycle")
+)
+
+type (
+	// UpsertStrategy decides how a seeder treats rows that already exist in the database.
+	UpsertStrategy string
+
+	// Options carries run-time parameters passed to a seeder, such as the upsert strategy and a record count.
+	Options struct {
+		Strategy UpsertStrategy
+		Count    int
+	}
+
+	// Seeder describes a named, idempotent unit of seed data with its dependencies and allowed environments.
+	// Environments defaults to dev and test when empty, and Manual seeders only run when requested by name.
+	Seeder struct {
+		Name         string
+		Description  string
+		DependsOn    []string
+		Environments []string
+		Strategy     UpsertStrategy
+		Manual       bool
+		Run          func(db *gorm.DB, opts Options) error
+	}
+)
+
+// registry holds every seeder registered through Register, keyed by name.
+var registry = map[string]Seeder{}
+
+// Register adds a seeder to the registry, replacing any seeder previously registered under the same name.
+func Register(seeder Seeder) {
+	registry[seeder.Name] = seeder
+}
+
+// Registered returns every registered seeder sorted by name.
+func Registered() []Seeder {
+	seeders := make([]Seeder, 0, len(registry))
+	for _, seeder := range registry {
+		seeders = append(seeders, seeder)
+	}
+
+	sort.Slice(
+		seeders, func(i, j int) bool {
+			return seeders[i].Name < seeders[j].Name
+		},
+	)
+
+	return seeders
+}
+
+// AllowedIn reports whether the seeder may run in the given environment.
+func (s Seeder) AllowedIn(env string) bool {
+	environments := s.Environments
+	if len(environments) == 0 {
+		environments = []string{constants.ENUM_RUN_DEVELOPMENT, constants.ENUM_RUN_TESTING}
+	}
+
+	return slices.Contains(environments, env)
+}
+
+// Plan resolves the seeders to run in dependency order for the given environment.
+// With no names it selects every non-manual seeder allowed in env and skips the rest; named seeders must be allowed in env.
+func Plan(seeders []Seeder, names []string, env string) ([]Seeder, error) {
+	byName := make(map[string]Seeder, len(seeders))
+	for _, seeder := range seeders {
+		byName[seeder.Name] = seeder
+	}
+
+	var roots []string
+	if len(names) == 0 {
+		for _, seeder := range seeders {
+			if !seeder.Manual && seeder.AllowedIn(env) {
+				roots = append(roots, seeder.Name)
+			}
+		}
+		sort.Strings(roots)
+	} else {
+		roots = names
+	}
+
+	var ordered []Seeder
+	state := make(map[string]int)
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		seeder, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrSeederNotFound, name)
+		}
+
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("%w: %v", ErrSeederCycle, append(path, name))
+		case 2:
+			return nil
+		}
+
+		if !seeder.AllowedIn(env) {
+			return fmt.Errorf("%w: %s (%s)", ErrSeederNotAllowed, name, env)
+		}
+
+		state[name] = 1
+		for _, dependency := range seeder.DependsOn {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+
+		ordered = append(ordered, seeder)
+		return nil
+	}
+
+	for _, name := range roots {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// UpsertBy inserts record when no row matches column = value, or updates the given columns of the existing row
+// when the strategy is UPSERT_UPDATE. It reports whether a row was written.
+func UpsertBy[T any](
+	db *gorm.DB,
+	strategy UpsertStrategy,
+	record *T,
+	column string,
+	value any,
+	updates map[string]any,
+) (bool, error) {
+	var existing T
+	found := db.Where(column+" = ?", value).Limit(1).Find(&existing)
+	if found.Error != nil {
+		return false, found.Error
+	}
+
+	if found.RowsAffected == 0 {
+		if err := db.Create(record).Error; err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if strategy != UPSERT_UPDATE || len(updates) == 0 {
+		return false, nil
+	}
+
+	var model T
+	if err := db.Model(&model).Where(column+" = ?", value).Updates(updates).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,111 @@
+package seeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// names extracts the name of every seeder in order, used to compare seeder plans in assertions.
+func names(seeders []Seeder) []string {
+	result := make([]string, 0, len(seeders))
+	for _, seeder := range seeders {
+		result = append(result, seeder.Name)
+	}
+	return result
+}
+
+// TestSeeder_AllowedIn verifies the default and explicit environment restrictions of a seeder.
+func TestSeeder_AllowedIn(t *testing.T) {
+	defaults := Seeder{Name: "defaults"}
+	assert.True(t, defaults.AllowedIn(constants.ENUM_RUN_DEVELOPMENT))
+	assert.True(t, defaults.AllowedIn(constants.ENUM_RUN_TESTING))
+	assert.False(t, defaults.AllowedIn(constants.ENUM_RUN_PRODUCTION))
+
+	prod := Seeder{Name: "roles", Environments: []string{constants.ENUM_RUN_PRODUCTION}}
+	assert.True(t, prod.AllowedIn(constants.ENUM_RUN_PRODUCTION))
+	assert.False(t, prod.AllowedIn(constants.ENUM_RUN_DEVELOPMENT))
+}
+
+// TestPlan verifies dependency ordering, environment filtering, manual seeders and error cases when planning seeders.
+func TestPlan(t *testing.T) {
+	seeders := []Seeder{
+		{Name: "users", DependsOn: []string{"roles"}},
+		{Name: "roles", Environments: []string{"dev", "test", "prod"}},
+		{Name: "posts", DependsOn: []string{"users"}},
+		{Name: "fake_users", Manual: true},
+	}
+
+	t.Run(
+		"Default seeders in dependency order", func(t *testing.T) {
+			plan, err := Plan(seeders, nil, constants.ENUM_RUN_DEVELOPMENT)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"roles", "users", "posts"}, names(plan))
+		},
+	)
+
+	t.Run(
+		"Production only runs allowed seeders", func(t *testing.T) {
+			plan, err := Plan(seeders, nil, constants.ENUM_RUN_PRODUCTION)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"roles"}, names(plan))
+		},
+	)
+
+	t.Run(
+		"Named seeder pulls in dependencies", func(t *testing.T) {
+			plan, err := Plan(seeders, []string{"users"}, constants.ENUM_RUN_TESTING)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"roles", "users"}, names(plan))
+		},
+	)
+
+	t.Run(
+		"Manual seeder runs when named", func(t *testing.T) {
+			plan, err := Plan(seeders, []string{"fake_users"}, constants.ENUM_RUN_DEVELOPMENT)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"fake_users"}, names(plan))
+		},
+	)
+
+	t.Run(
+		"Named seeder not allowed", func(t *testing.T) {
+			_, err := Plan(seeders, []string{"users"}, constants.ENUM_RUN_PRODUCTION)
+
+			assert.ErrorIs(t, err, ErrSeederNotAllowed)
+		},
+	)
+
+	t.Run(
+		"Unknown seeder", func(t *testing.T) {
+			_, err := Plan(seeders, []string{"missing"}, constants.ENUM_RUN_DEVELOPMENT)
+
+			assert.ErrorIs(t, err, ErrSeederNotFound)
+		},
+	)
+
+	t.Run(
+		"Dependency cycle", func(t *testing.T) {
+			cyclic := []Seeder{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			}
+
+			_, err := Plan(cyclic, nil, constants.ENUM_RUN_DEVELOPMENT)
+
+			assert.ErrorIs(t, err, ErrSeederCycle)
+		},
+	)
+}
+
+// TestRegistered verifies that the built-in seeders register themselves and are returned sorted by name.
+func TestRegistered(t *testing.T) {
+	assert.Equal(t, []string{"fake_users", "users"}, names(Registered()))
+}
@@ -1,50 +1,60 @@
 package seeds
 
 import (
-	"encoding/json"
-	"io"
-	"os"
 	"path"
 
 	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/helpers"
 )
 
-// ListUserSeeder is a function that seeds user data into the database from a JSON file located in the project directory.
+// USER_FIXTURE is the fixture path, relative to the project root and without extension, loaded by the users seeder.
+var USER_FIXTURE = "migrations/json/users"
+
+// SeedUserRequest represents a single user entry in the users fixture.
+type SeedUserRequest struct {
+	dto.UserCreateRequest
+	Role       string `json:"role" binding:"required,oneof=user admin"`
+	IsVerified bool   `json:"is_verified"`
+}
+
+// init registers the users seeder, which loads the users fixture in development and testing environments.
+func init() {
+	Register(
+		Seeder{
+			Name:         "users",
+			Description:  "Default admin and user accounts from the users fixture",
+			Environments: []string{constants.ENUM_RUN_DEVELOPMENT, constants.ENUM_RUN_TESTING},
+			Strategy:     UPSERT_SKIP,
+			Run: func(db *gorm.DB, opts Options) error {
+				return SeedUsers(db, opts.Strategy)
+			},
+		},
+	)
+}
+
+// ListUserSeeder is a function that seeds user data into the database from the users fixture, skipping existing emails.
 var ListUserSeeder = func(db *gorm.DB) error {
-	projectDir, err := helpers.GetProjectRoot()
-	if err != nil {
-		return err
-	}
+	return SeedUsers(db, UPSERT_SKIP)
+}
 
-	jsonFilePath := path.Join(projectDir, "migrations/json/users.json")
-	jsonFile, err := os.Open(jsonFilePath)
+// SeedUsers upserts every user from the users fixture (JSON, YAML or CSV) by email using the given strategy.
+func SeedUsers(db *gorm.DB, strategy UpsertStrategy) error {
+	projectDir, err := helpers.GetProjectRoot()
 	if err != nil {
 		return err
 	}
-	defer func(jsonFile *os.File) {
-		err := jsonFile.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(jsonFile)
-
-	type SeedUserRequest struct {
-		dto.UserCreateRequest
-		Role       string `json:"role" binding:"required,oneof=user admin"`
-		IsVerified bool   `json:"is_verified"`
-	}
 
-	jsonData, err := io.ReadAll(jsonFile)
+	fixturePath, err := FindFixture(path.Join(projectDir, USER_FIXTURE))
 	if err != nil {
 		return err
 	}
 
 	var seedUsers []SeedUserRequest
-	if err := json.Unmarshal(jsonData, &seedUsers); err != nil {
+	if err := LoadFixture(fixturePath, &seedUsers); err != nil {
 		return err
 	}
 
@@ -65,13 +75,15 @@ var ListUserSeeder = func(db *gorm.DB) error {
 			IsVerified:  seedUser.IsVerified,
 		}
 
-		var existingUser entity.User
-		isData := db.Where("email = ?", user.Email).Find(&existingUser).RowsAffected
-
-		if isData == 0 {
-			if err := db.Create(&user).Error; err != nil {
-				return err
-			}
+		if _, err := UpsertBy(
+			db, strategy, &user, "email", user.Email, map[string]any{
+				"name":         user.Name,
+				"phone_number": user.PhoneNumber,
+				"role":         user.Role,
+				"is_verified":  user.IsVerified,
+			},
+		); err != nil {
+			return err
 		}
 	}
 
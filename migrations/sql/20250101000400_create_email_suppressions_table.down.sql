@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email_suppressed;
+
+DROP TABLE IF EXISTS email_suppressions;
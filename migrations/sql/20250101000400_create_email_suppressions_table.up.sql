@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email      VARCHAR(255) NOT NULL,
+    reason     VARCHAR(20) NOT NULL,
+    detail     TEXT,
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_suppressions_email ON email_suppressions (email);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_suppressed BOOLEAN NOT NULL DEFAULT FALSE;
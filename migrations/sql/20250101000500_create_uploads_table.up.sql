@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS uploads (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    size         BIGINT NOT NULL,
+    received     BIGINT NOT NULL DEFAULT 0,
+    checksum     VARCHAR(64) NOT NULL DEFAULT '',
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    storage_key  VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE,
+    updated_at   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_user_id ON uploads (user_id);
+CREATE INDEX IF NOT EXISTS idx_uploads_expires_at ON uploads (expires_at);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_users_purge_at;
+DROP INDEX IF EXISTS idx_users_status;
+
+-- Fails when a soft-deleted user shares its email address with another user; purge or rename those rows first.
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS purge_at;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
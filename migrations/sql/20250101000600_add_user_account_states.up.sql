@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purge_at TIMESTAMP WITH TIME ZONE;
+
+-- Users soft-deleted before account states existed become pending deletion with a full grace period from now on.
+UPDATE users
+SET status = 'pending_deletion', purge_at = NOW() + INTERVAL '30 days'
+WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_status ON users (status);
+CREATE INDEX IF NOT EXISTS idx_users_purge_at ON users (purge_at);
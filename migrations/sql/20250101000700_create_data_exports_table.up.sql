@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id           UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending',
+    locale       VARCHAR(100) NOT NULL DEFAULT '',
+    storage_key  VARCHAR(255) NOT NULL DEFAULT '',
+    size         BIGINT NOT NULL DEFAULT 0,
+    last_error   TEXT NOT NULL DEFAULT '',
+    claimed_at   TIMESTAMP WITH TIME ZONE,
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE,
+    updated_at   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports (status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports (expires_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_users_phone_number_trgm;
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_users_search_vector;
+
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+
+-- pg_trgm is left installed, as other objects of the database may depend on it.
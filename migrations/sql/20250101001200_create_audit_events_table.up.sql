@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID NOT NULL,
+    type       VARCHAR(50) NOT NULL,
+    details    JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_audit_events_user FOREIGN KEY (user_id)
+        REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_user_id ON audit_events (user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
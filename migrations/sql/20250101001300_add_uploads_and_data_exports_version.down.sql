@@ -0,0 +1,2 @@
+ALTER TABLE data_exports DROP COLUMN IF EXISTS version;
+ALTER TABLE uploads DROP COLUMN IF EXISTS version;
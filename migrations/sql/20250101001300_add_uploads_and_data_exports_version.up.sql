@@ -0,0 +1,2 @@
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS script_runs (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    args        TEXT,
+    dry_run     BOOLEAN NOT NULL DEFAULT FALSE,
+    one_off     BOOLEAN NOT NULL DEFAULT FALSE,
+    forced      BOOLEAN NOT NULL DEFAULT FALSE,
+    status      VARCHAR(20) NOT NULL,
+    error       TEXT,
+    started_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Databases that ran scripts before this migration already have the table, created by the application without these
+-- columns.
+ALTER TABLE script_runs ADD COLUMN IF NOT EXISTS one_off BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE script_runs ADD COLUMN IF NOT EXISTS forced BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_script_runs_name ON script_runs (name);
+
+-- A one-off script completes at most one unforced run, even when two runs race past the check in the application.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_script_runs_one_off_success ON script_runs (name)
+    WHERE status = 'success' AND one_off AND NOT forced AND NOT dry_run;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS seed_history (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    environment VARCHAR(20) NOT NULL,
+    strategy    VARCHAR(20) NOT NULL,
+    ran_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_seed_history_name ON seed_history (name);
@@ -0,0 +1,144 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+)
+
+// SQL_DIR is the directory, relative to the migrations package, that holds versioned SQL migration files.
+const SQL_DIR = "sql"
+
+// sqlFiles embeds the versioned SQL migrations so compiled binaries can migrate without the source tree.
+//
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+var (
+	// sqlFilePattern matches migration file names such as 20250101000000_create_users_table.up.sql.
+	sqlFilePattern = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+	// migrationNamePattern matches characters that are not allowed in a migration name.
+	migrationNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// loadSQLMigrations reads every *.up.sql and *.down.sql file in dir and pairs them into migrations by version.
+func loadSQLMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*Migration)
+	var order []string
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, name, direction := match[1], match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+			order = append(order, version)
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateMigration, version)
+		}
+
+		switch direction {
+		case "up":
+			migration.Up = execSQL(string(content))
+		case "down":
+			migration.Down = execSQL(string(content))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(order))
+	for _, version := range order {
+		migration := byVersion[version]
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %s_%s has no up file", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	return migrations, nil
+}
+
+// execSQL returns a migration step that executes the given SQL script inside the migration transaction.
+func execSQL(script string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		if strings.TrimSpace(script) == "" {
+			return nil
+		}
+
+		return tx.Exec(script).Error
+	}
+}
+
+// Create writes a new pair of empty up/down SQL migration files into the source migrations directory and returns their paths.
+func Create(name string) (string, string, error) {
+	projectDir, err := helpers.GetProjectRoot()
+	if err != nil {
+		return "", "", err
+	}
+
+	return createSQLMigration(filepath.Join(projectDir, "migrations", SQL_DIR), name, time.Now())
+}
+
+// createSQLMigration writes the up/down files for name into dir using the version derived from now.
+func createSQLMigration(dir string, name string, now time.Time) (string, string, error) {
+	slug := strings.Trim(migrationNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", "", fmt.Errorf("invalid migration name: %q", name)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+
+	base := now.UTC().Format(VERSION_LAYOUT) + "_" + slug
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+
+	for _, file := range []struct {
+		path      string
+		direction string
+	}{
+		{path: upPath, direction: "up"},
+		{path: downPath, direction: "down"},
+	} {
+		content := fmt.Sprintf("-- %s (%s)\n", base, file.direction)
+		if err := os.WriteFile(file.path, []byte(content), 0644); err != nil {
+			return "", "", err
+		}
+	}
+
+	return upPath, downPath, nil
+}
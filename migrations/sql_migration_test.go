@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadSQLMigrations verifies that up/down SQL files are paired by version and that malformed sets are rejected.
+func TestLoadSQLMigrations(t *testing.T) {
+	t.Run(
+		"pairs up and down files", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"sql/20250101000000_create_users_table.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+				"sql/20250101000000_create_users_table.down.sql": {Data: []byte("DROP TABLE users;")},
+				"sql/20250102000000_add_index.up.sql":            {Data: []byte("CREATE INDEX i ON users (id);")},
+			}
+
+			migrations, err := loadSQLMigrations(fsys, "sql")
+			require.NoError(t, err)
+			require.Len(t, migrations, 2)
+
+			assert.Equal(t, "20250101000000", migrations[0].Version)
+			assert.Equal(t, "create_users_table", migrations[0].Name)
+			assert.NotNil(t, migrations[0].Up)
+			assert.NotNil(t, migrations[0].Down)
+
+			assert.Equal(t, "add_index", migrations[1].Name)
+			assert.Nil(t, migrations[1].Down)
+		},
+	)
+
+	t.Run(
+		"rejects invalid file names", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"sql/create_users.sql": {Data: []byte("")},
+			}
+
+			_, err := loadSQLMigrations(fsys, "sql")
+			assert.Error(t, err)
+		},
+	)
+
+	t.Run(
+		"rejects down file without up file", func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"sql/20250101000000_create_users_table.down.sql": {Data: []byte("DROP TABLE users;")},
+			}
+
+			_, err := loadSQLMigrations(fsys, "sql")
+			assert.Error(t, err)
+		},
+	)
+}
+
+// TestEmbeddedMigrations verifies that the migrations embedded in the binary load without errors and in version order.
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+
+	for _, migration := range migrations {
+		assert.NotNil(t, migration.Up, "migration %s should have an up step", migration.Version)
+		assert.NotNil(t, migration.Down, "migration %s should have a down step", migration.Version)
+	}
+}
+
+// TestCreateSQLMigration verifies that new migration files are created with a timestamp version and a normalized name.
+func TestCreateSQLMigration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	upPath, downPath, err := createSQLMigration(dir, "Add Users Index", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "20250304050607_add_users_index.up.sql"), upPath)
+	assert.Equal(t, filepath.Join(dir, "20250304050607_add_users_index.down.sql"), downPath)
+
+	_, err = os.Stat(upPath)
+	assert.NoError(t, err)
+	_, err = os.Stat(downPath)
+	assert.NoError(t, err)
+
+	_, _, err = createSQLMigration(dir, "!!!", now)
+	assert.Error(t, err)
+}
@@ -28,5 +28,11 @@ var RegisterDependencies = func(injector *do.Injector) {
 		},
 	)
 
+	ProvideStorageDependencies(injector)
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
 	ProvideUserDependencies(injector)
+	ProvideUploadDependencies(injector)
+	ProvideDataExportDependencies(injector)
+	ProvidePhoneVerificationDependencies(injector)
 }
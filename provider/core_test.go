@@ -74,6 +74,21 @@ func TestRegisterDependencies(t *testing.T) {
 	ProvideUserDependencies = mockUserProv.ProvideUserDependencies
 	defer func() { ProvideUserDependencies = originalProvide }()
 
+	uploadsProvided := false
+	originalProvideUploads := ProvideUploadDependencies
+	ProvideUploadDependencies = func(injector *do.Injector) { uploadsProvided = true }
+	defer func() { ProvideUploadDependencies = originalProvideUploads }()
+
+	exportsProvided := false
+	originalProvideExports := ProvideDataExportDependencies
+	ProvideDataExportDependencies = func(injector *do.Injector) { exportsProvided = true }
+	defer func() { ProvideDataExportDependencies = originalProvideExports }()
+
+	phoneVerificationProvided := false
+	originalProvidePhoneVerification := ProvidePhoneVerificationDependencies
+	ProvidePhoneVerificationDependencies = func(injector *do.Injector) { phoneVerificationProvided = true }
+	defer func() { ProvidePhoneVerificationDependencies = originalProvidePhoneVerification }()
+
 	RegisterDependencies(injector)
 
 	db, err := do.InvokeNamed[*gorm.DB](injector, constants.DB)
@@ -84,6 +99,9 @@ func TestRegisterDependencies(t *testing.T) {
 	assert.NoError(t, err, "should provide JWTService without error")
 	assert.NotNil(t, jwtService, "JWTService should not be nil")
 
+	assert.True(t, uploadsProvided, "should provide the upload dependencies")
+	assert.True(t, exportsProvided, "should provide the data export dependencies")
+	assert.True(t, phoneVerificationProvided, "should provide the phone verification dependencies")
 	mockUserProv.AssertExpectations(t)
 	mockCfg.AssertExpectations(t)
 }
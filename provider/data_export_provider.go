@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// ProvideDataExportDependencies registers the data export service and controller and the exporter that builds queued
+// exports. It depends on the repositories, the storage driver and the template renderer, so it must run after they
+// are provided.
+var ProvideDataExportDependencies = func(injector *do.Injector) {
+	txManager := do.MustInvoke[repository.TxManager](injector)
+	files := do.MustInvoke[storage.Driver](injector)
+	userRepository := do.MustInvoke[repository.UserRepository](injector)
+
+	exportService := service.NewDataExportService(
+		do.MustInvoke[repository.DataExportRepository](injector),
+		userRepository,
+		do.MustInvoke[repository.RefreshTokenRepository](injector),
+		do.MustInvoke[repository.UploadRepository](injector),
+		do.MustInvoke[repository.EmailOutboxRepository](injector),
+		do.MustInvoke[repository.AuditEventRepository](injector),
+		do.MustInvoke[mailer.Renderer](injector),
+		files,
+		txManager,
+	)
+	do.ProvideValue[service.DataExportService](injector, exportService)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.DataExportController, error) {
+			return controller.NewDataExportController(exportService), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (*service.DataExporter, error) {
+			return service.NewDataExporter(exportService, service.DATA_EXPORT_POLL_INTERVAL), nil
+		},
+	)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestProvideDataExportDependencies verifies that the data export repository, service, controller and exporter are
+// provided.
+func TestProvideDataExportDependencies(t *testing.T) {
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, &mockJWTService{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+	ProvideUserDependencies(injector)
+	ProvideDataExportDependencies(injector)
+
+	exportRepository, err := do.Invoke[repository.DataExportRepository](injector)
+	assert.NoError(t, err, "should provide DataExportRepository without error")
+	assert.NotNil(t, exportRepository)
+
+	exportService, err := do.Invoke[service.DataExportService](injector)
+	assert.NoError(t, err, "should provide DataExportService without error")
+	assert.NotNil(t, exportService)
+
+	exportController, err := do.Invoke[controller.DataExportController](injector)
+	assert.NoError(t, err, "should provide DataExportController without error")
+	assert.NotNil(t, exportController)
+
+	exporter, err := do.Invoke[*service.DataExporter](injector)
+	assert.NoError(t, err, "should provide DataExporter without error")
+	assert.NotNil(t, exporter)
+}
+
+// TestProvideDataExportDependencies_MissingUserRepository verifies that ProvideDataExportDependencies panics when the
+// user repository has not been provided.
+func TestProvideDataExportDependencies_MissingUserRepository(t *testing.T) {
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	assert.Panics(
+		t,
+		func() {
+			ProvideDataExportDependencies(injector)
+		},
+		"should panic when UserRepository is missing",
+	)
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// ProvideEmailDependencies registers the mailer, the template renderer, the email outbox service, controller and
+// background dispatcher, the email suppression service and controller, and the mail preview controller.
+// The mailer backend is chosen by MAIL_DRIVER when it is first invoked, so commands that never send email do not need
+// a complete mail configuration. The outbox delivers through it after dropping suppressed recipients. It depends on
+// the repositories, so it must run after they are provided.
+var ProvideEmailDependencies = func(injector *do.Injector) {
+	txManager := do.MustInvoke[repository.TxManager](injector)
+	outboxRepository := do.MustInvoke[repository.EmailOutboxRepository](injector)
+	suppressionRepository := do.MustInvoke[repository.EmailSuppressionRepository](injector)
+	userRepository := do.MustInvoke[repository.UserRepository](injector)
+
+	suppressionService := service.NewEmailSuppressionService(suppressionRepository, userRepository, txManager)
+	do.ProvideValue[service.EmailSuppressionService](injector, suppressionService)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.EmailSuppressionController, error) {
+			return controller.NewEmailSuppressionController(suppressionService), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (mailer.Mailer, error) {
+			mailConfig, err := config.NewMailConfig()
+			if err != nil {
+				return nil, err
+			}
+
+			return mailer.New(mailConfig)
+		},
+	)
+
+	renderer := mailer.NewRenderer(config.NewMailTemplateConfig())
+	do.ProvideValue[mailer.Renderer](injector, renderer)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.MailPreviewController, error) {
+			return controller.NewMailPreviewController(renderer), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (service.EmailOutboxService, error) {
+			mail, err := do.Invoke[mailer.Mailer](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return service.NewEmailOutboxService(
+				outboxRepository,
+				mailer.NewSuppressingMailer(mail, suppressionService),
+			), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.EmailOutboxController, error) {
+			outboxService, err := do.Invoke[service.EmailOutboxService](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return controller.NewEmailOutboxController(outboxService), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (*service.EmailDispatcher, error) {
+			outboxService, err := do.Invoke[service.EmailOutboxService](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return service.NewEmailDispatcher(outboxService, service.OUTBOX_POLL_INTERVAL), nil
+		},
+	)
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// TestProvideEmailDependencies verifies that the mailer, renderer, controllers, outbox and suppression services and
+// dispatcher are provided.
+func TestProvideEmailDependencies(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", constants.ENUM_MAIL_DRIVER_MEMORY)
+
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+
+	mail, err := do.Invoke[mailer.Mailer](injector)
+	assert.NoError(t, err, "should provide Mailer without error")
+	assert.IsType(t, &mailer.MemoryMailer{}, mail, "should use the configured mail driver")
+
+	renderer, err := do.Invoke[mailer.Renderer](injector)
+	assert.NoError(t, err, "should provide Renderer without error")
+	assert.NotNil(t, renderer)
+
+	previewController, err := do.Invoke[controller.MailPreviewController](injector)
+	assert.NoError(t, err, "should provide MailPreviewController without error")
+	assert.NotNil(t, previewController)
+
+	outboxService, err := do.Invoke[service.EmailOutboxService](injector)
+	assert.NoError(t, err, "should provide EmailOutboxService without error")
+	assert.NotNil(t, outboxService)
+
+	outboxController, err := do.Invoke[controller.EmailOutboxController](injector)
+	assert.NoError(t, err, "should provide EmailOutboxController without error")
+	assert.NotNil(t, outboxController)
+
+	dispatcher, err := do.Invoke[*service.EmailDispatcher](injector)
+	assert.NoError(t, err, "should provide EmailDispatcher without error")
+	assert.NotNil(t, dispatcher)
+
+	suppressionService, err := do.Invoke[service.EmailSuppressionService](injector)
+	assert.NoError(t, err, "should provide EmailSuppressionService without error")
+	assert.NotNil(t, suppressionService)
+
+	suppressionController, err := do.Invoke[controller.EmailSuppressionController](injector)
+	assert.NoError(t, err, "should provide EmailSuppressionController without error")
+	assert.NotNil(t, suppressionController)
+}
+
+// TestProvideEmailDependencies_InvalidMailConfig verifies that an invalid mail configuration is reported on invoke.
+func TestProvideEmailDependencies_InvalidMailConfig(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "pigeon")
+
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+
+	_, err := do.Invoke[*service.EmailDispatcher](injector)
+	assert.ErrorContains(t, err, "unknown mail driver")
+}
+
+// TestProvideEmailDependencies_MissingDB verifies that ProvideEmailDependencies panics if the database is missing.
+func TestProvideEmailDependencies_MissingDB(t *testing.T) {
+	assert.Panics(
+		t, func() {
+			ProvideEmailDependencies(do.New())
+		}, "should panic when DB is missing",
+	)
+}
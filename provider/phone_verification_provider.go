@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/sms"
+)
+
+// ProvidePhoneVerificationDependencies registers the SMS sender and the phone verification service and controller.
+// The SMS backend is chosen by SMS_DRIVER when it is first invoked, so commands that never send text messages do not
+// need a complete SMS configuration. It depends on the repositories, so it must run after they are provided.
+var ProvidePhoneVerificationDependencies = func(injector *do.Injector) {
+	txManager := do.MustInvoke[repository.TxManager](injector)
+	userRepository := do.MustInvoke[repository.UserRepository](injector)
+	verificationRepository := do.MustInvoke[repository.PhoneVerificationRepository](injector)
+	auditRepository := do.MustInvoke[repository.AuditEventRepository](injector)
+
+	do.Provide(
+		injector, func(i *do.Injector) (sms.SMSSender, error) {
+			smsConfig, err := config.NewSMSConfig()
+			if err != nil {
+				return nil, err
+			}
+
+			return sms.New(smsConfig)
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (service.PhoneVerificationService, error) {
+			sender, err := do.Invoke[sms.SMSSender](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return service.NewPhoneVerificationService(
+				verificationRepository,
+				userRepository,
+				auditRepository,
+				sender,
+				txManager,
+				config.NewMailTemplateConfig().AppName,
+			), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.PhoneVerificationController, error) {
+			verificationService, err := do.Invoke[service.PhoneVerificationService](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return controller.NewPhoneVerificationController(verificationService), nil
+		},
+	)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/sms"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestProvidePhoneVerificationDependencies verifies that the SMS sender and the phone verification repository,
+// service and controller are provided.
+func TestProvidePhoneVerificationDependencies(t *testing.T) {
+	t.Setenv("SMS_DRIVER", "memory")
+
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, &mockJWTService{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+	ProvideUserDependencies(injector)
+	ProvidePhoneVerificationDependencies(injector)
+
+	verificationRepository, err := do.Invoke[repository.PhoneVerificationRepository](injector)
+	assert.NoError(t, err, "should provide PhoneVerificationRepository without error")
+	assert.NotNil(t, verificationRepository)
+
+	sender, err := do.Invoke[sms.SMSSender](injector)
+	assert.NoError(t, err, "should provide SMSSender without error")
+	assert.IsType(t, &sms.MemorySender{}, sender)
+
+	verificationService, err := do.Invoke[service.PhoneVerificationService](injector)
+	assert.NoError(t, err, "should provide PhoneVerificationService without error")
+	assert.NotNil(t, verificationService)
+
+	verificationController, err := do.Invoke[controller.PhoneVerificationController](injector)
+	assert.NoError(t, err, "should provide PhoneVerificationController without error")
+	assert.NotNil(t, verificationController)
+}
+
+// TestProvidePhoneVerificationDependencies_InvalidSMSConfig verifies that an invalid SMS configuration is reported
+// when the phone verification service is first invoked rather than when dependencies are registered.
+func TestProvidePhoneVerificationDependencies_InvalidSMSConfig(t *testing.T) {
+	t.Setenv("SMS_DRIVER", "pigeon")
+
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, &mockJWTService{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+	ProvideUserDependencies(injector)
+	assert.NotPanics(t, func() { ProvidePhoneVerificationDependencies(injector) })
+
+	_, err := do.Invoke[service.PhoneVerificationService](injector)
+	assert.ErrorContains(t, err, "unknown SMS driver")
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"github.com/samber/do"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+// ProvideRepositoryDependencies registers every repository and the transaction manager, all backed by the main
+// database, so services receive them through their constructors. It must run before the services are provided.
+var ProvideRepositoryDependencies = func(injector *do.Injector) {
+	db := do.MustInvokeNamed[*gorm.DB](injector, constants.DB)
+
+	do.ProvideValue[repository.TxManager](injector, repository.NewTxManager(db))
+	do.ProvideValue[repository.UserRepository](injector, repository.NewUserRepository(db))
+	do.ProvideValue[repository.RefreshTokenRepository](injector, repository.NewRefreshTokenRepository(db))
+	do.ProvideValue[repository.EmailOutboxRepository](injector, repository.NewEmailOutboxRepository(db))
+	do.ProvideValue[repository.EmailSuppressionRepository](injector, repository.NewEmailSuppressionRepository(db))
+	do.ProvideValue[repository.UploadRepository](injector, repository.NewUploadRepository(db))
+	do.ProvideValue[repository.DataExportRepository](injector, repository.NewDataExportRepository(db))
+	do.ProvideValue[repository.EmailChangeRepository](injector, repository.NewEmailChangeRepository(db))
+	do.ProvideValue[repository.PhoneVerificationRepository](injector, repository.NewPhoneVerificationRepository(db))
+	do.ProvideValue[repository.AuditEventRepository](injector, repository.NewAuditEventRepository(db))
+}
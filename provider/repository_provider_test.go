@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+// TestProvideRepositoryDependencies verifies that every repository and the transaction manager are provided.
+func TestProvideRepositoryDependencies(t *testing.T) {
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+
+	ProvideRepositoryDependencies(injector)
+
+	assertProvided[repository.TxManager](t, injector)
+	assertProvided[repository.UserRepository](t, injector)
+	assertProvided[repository.RefreshTokenRepository](t, injector)
+	assertProvided[repository.EmailOutboxRepository](t, injector)
+	assertProvided[repository.EmailSuppressionRepository](t, injector)
+	assertProvided[repository.UploadRepository](t, injector)
+	assertProvided[repository.DataExportRepository](t, injector)
+	assertProvided[repository.EmailChangeRepository](t, injector)
+	assertProvided[repository.PhoneVerificationRepository](t, injector)
+	assertProvided[repository.AuditEventRepository](t, injector)
+}
+
+// TestProvideRepositoryDependencies_MissingDB verifies that ProvideRepositoryDependencies panics if the database is
+// missing.
+func TestProvideRepositoryDependencies_MissingDB(t *testing.T) {
+	assert.Panics(
+		t, func() {
+			ProvideRepositoryDependencies(do.New())
+		}, "should panic when DB is missing",
+	)
+}
+
+// assertProvided asserts that the injector provides a non-nil T.
+func assertProvided[T any](t *testing.T, injector *do.Injector) {
+	t.Helper()
+
+	value, err := do.Invoke[T](injector)
+	assert.NoError(t, err, "should provide %T without error", value)
+	assert.NotNil(t, value)
+}
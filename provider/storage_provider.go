@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// ProvideStorageDependencies registers the file storage driver selected by STORAGE_DRIVER and the controller that
+// serves the signed URLs of the local driver.
+var ProvideStorageDependencies = func(injector *do.Injector) {
+	do.Provide(
+		injector, func(i *do.Injector) (storage.Driver, error) {
+			storageConfig, err := config.NewStorageConfig()
+			if err != nil {
+				return nil, err
+			}
+
+			return storage.New(storageConfig)
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.StorageController, error) {
+			files, err := do.Invoke[storage.Driver](i)
+			if err != nil {
+				return nil, err
+			}
+
+			return controller.NewStorageController(files), nil
+		},
+	)
+}
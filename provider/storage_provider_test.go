@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestProvideStorageDependencies verifies that the configured storage driver and the storage controller are provided.
+func TestProvideStorageDependencies(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", constants.ENUM_STORAGE_DRIVER_MEMORY)
+
+	injector := do.New()
+	ProvideStorageDependencies(injector)
+
+	files, err := do.Invoke[storage.Driver](injector)
+	assert.NoError(t, err, "should provide Driver without error")
+	assert.IsType(t, &storage.MemoryDriver{}, files, "should use the configured storage driver")
+
+	storageController, err := do.Invoke[controller.StorageController](injector)
+	assert.NoError(t, err, "should provide StorageController without error")
+	assert.NotNil(t, storageController)
+}
+
+// TestProvideStorageDependencies_InvalidConfig verifies that an invalid storage configuration is reported on invoke.
+func TestProvideStorageDependencies_InvalidConfig(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "floppy")
+
+	injector := do.New()
+	ProvideStorageDependencies(injector)
+
+	_, err := do.Invoke[controller.StorageController](injector)
+	assert.ErrorContains(t, err, "unknown storage driver")
+}
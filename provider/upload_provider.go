@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// ProvideUploadDependencies registers the upload service and controller and the janitor that purges expired uploads.
+// It depends on the repositories, the storage driver and the user service, so it must run after they are provided.
+var ProvideUploadDependencies = func(injector *do.Injector) {
+	txManager := do.MustInvoke[repository.TxManager](injector)
+	files := do.MustInvoke[storage.Driver](injector)
+	userService := do.MustInvoke[service.UserService](injector)
+	uploadRepository := do.MustInvoke[repository.UploadRepository](injector)
+
+	uploadService := service.NewUploadService(uploadRepository, userService, files, txManager)
+	do.ProvideValue[service.UploadService](injector, uploadService)
+
+	do.Provide(
+		injector, func(i *do.Injector) (controller.UploadController, error) {
+			return controller.NewUploadController(uploadService), nil
+		},
+	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (*service.UploadJanitor, error) {
+			return service.NewUploadJanitor(uploadService, service.UPLOAD_PURGE_INTERVAL), nil
+		},
+	)
+}
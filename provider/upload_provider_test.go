@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/samber/do"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestProvideUploadDependencies verifies that the upload repository, service, controller and janitor are provided.
+func TestProvideUploadDependencies(t *testing.T) {
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, &mockJWTService{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
+	ProvideUserDependencies(injector)
+	ProvideUploadDependencies(injector)
+
+	uploadRepository, err := do.Invoke[repository.UploadRepository](injector)
+	assert.NoError(t, err, "should provide UploadRepository without error")
+	assert.NotNil(t, uploadRepository)
+
+	uploadService, err := do.Invoke[service.UploadService](injector)
+	assert.NoError(t, err, "should provide UploadService without error")
+	assert.NotNil(t, uploadService)
+
+	uploadController, err := do.Invoke[controller.UploadController](injector)
+	assert.NoError(t, err, "should provide UploadController without error")
+	assert.NotNil(t, uploadController)
+
+	janitor, err := do.Invoke[*service.UploadJanitor](injector)
+	assert.NoError(t, err, "should provide UploadJanitor without error")
+	assert.NotNil(t, janitor)
+}
+
+// TestProvideUploadDependencies_MissingUserService verifies that ProvideUploadDependencies panics when the user
+// service has not been provided.
+func TestProvideUploadDependencies_MissingUserService(t *testing.T) {
+	injector := do.New()
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+
+	assert.Panics(
+		t,
+		func() {
+			ProvideUploadDependencies(injector)
+		},
+		"should panic when UserService is missing",
+	)
+}
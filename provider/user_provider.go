@@ -2,27 +2,49 @@ package provider
 
 import (
 	"github.com/samber/do"
-	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
 )
 
-// ProvideUserDependencies initializes and provides user-related dependencies, including repositories, services, and controllers.
+// ProvideUserDependencies initializes and provides user-related dependencies, including services, controllers and the
+// background purger of deleted accounts. It depends on the repositories, the storage driver and the template renderer,
+// so it must run after they are provided.
 var ProvideUserDependencies = func(injector *do.Injector) {
-	db := do.MustInvokeNamed[*gorm.DB](injector, constants.DB)
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	files := do.MustInvoke[storage.Driver](injector)
+	renderer := do.MustInvoke[mailer.Renderer](injector)
 
-	userRepository := repository.NewUserRepository(db)
-	refreshTokenRepository := repository.NewRefreshTokenRepository(db)
-
-	userService := service.NewUserService(userRepository, refreshTokenRepository, jwtService, db)
+	userService := service.NewUserService(
+		do.MustInvoke[repository.UserRepository](injector),
+		do.MustInvoke[repository.RefreshTokenRepository](injector),
+		do.MustInvoke[repository.EmailOutboxRepository](injector),
+		do.MustInvoke[repository.UploadRepository](injector),
+		do.MustInvoke[repository.DataExportRepository](injector),
+		do.MustInvoke[repository.EmailChangeRepository](injector),
+		do.MustInvoke[repository.AuditEventRepository](injector),
+		jwtService,
+		renderer,
+		files,
+		do.MustInvoke[repository.TxManager](injector),
+		config.NewPhoneConfig().DefaultCountryCode,
+	)
+	do.ProvideValue[service.UserService](injector, userService)
 
 	do.Provide(
 		injector, func(i *do.Injector) (controller.UserController, error) {
 			return controller.NewUserController(userService), nil
 		},
 	)
+
+	do.Provide(
+		injector, func(i *do.Injector) (*service.UserPurger, error) {
+			return service.NewUserPurger(userService, service.USER_PURGE_INTERVAL), nil
+		},
+	)
 }
@@ -9,9 +9,13 @@ import (
 	"github.com/samber/do"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
 )
 
 // mockJWTService is a mock implementation of the JWTService interface, used for testing purposes.
@@ -54,27 +58,44 @@ func TestProvideUserDependencies(t *testing.T) {
 
 	mockJWT := &mockJWTService{}
 	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, mockJWT)
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+	ProvideRepositoryDependencies(injector)
+	ProvideEmailDependencies(injector)
 
 	ProvideUserDependencies(injector)
 
 	userController, err := do.Invoke[controller.UserController](injector)
 	assert.NoError(t, err, "should provide UserController without error")
 	assert.NotNil(t, userController, "UserController should not be nil")
+
+	userService, err := do.Invoke[service.UserService](injector)
+	assert.NoError(t, err, "should provide UserService without error")
+	assert.NotNil(t, userService, "UserService should not be nil")
+
+	refreshTokenRepository, err := do.Invoke[repository.RefreshTokenRepository](injector)
+	assert.NoError(t, err, "should provide RefreshTokenRepository without error")
+	assert.NotNil(t, refreshTokenRepository, "RefreshTokenRepository should not be nil")
+
+	purger, err := do.Invoke[*service.UserPurger](injector)
+	assert.NoError(t, err, "should provide UserPurger without error")
+	assert.NotNil(t, purger, "UserPurger should not be nil")
 }
 
-// TestProvideUserDependencies_MissingDB verifies that ProvideUserDependencies panics if the database dependency is missing.
-func TestProvideUserDependencies_MissingDB(t *testing.T) {
+// TestProvideUserDependencies_MissingRepositories verifies that ProvideUserDependencies panics if the repositories have
+// not been provided.
+func TestProvideUserDependencies_MissingRepositories(t *testing.T) {
 	injector := do.New()
-
-	mockJWT := &mockJWTService{}
-	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, mockJWT)
+	do.ProvideNamedValue[*gorm.DB](injector, constants.DB, &gorm.DB{})
+	do.ProvideNamedValue[service.JWTService](injector, constants.JWTService, &mockJWTService{})
+	do.ProvideValue[storage.Driver](injector, storage.NewMemoryDriver())
+	do.ProvideValue[mailer.Renderer](injector, mailer.NewRenderer(config.NewMailTemplateConfig()))
 
 	assert.Panics(
 		t,
 		func() {
 			ProvideUserDependencies(injector)
 		},
-		"should panic when DB is missing",
+		"should panic when the repositories are missing",
 	)
 }
 
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// AuditEventRepository defines the database operations of the audit log of user accounts.
+	// Create stores an event and GetByUserId lists the events of a user.
+	AuditEventRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, event entity.AuditEvent) (entity.AuditEvent, error)
+		GetByUserId(ctx context.Context, tx *gorm.DB, userId string) ([]entity.AuditEvent, error)
+	}
+
+	// auditEventRepository implements AuditEventRepository using GORM.
+	auditEventRepository struct {
+		Repository[entity.AuditEvent]
+	}
+)
+
+// NewAuditEventRepository creates a new AuditEventRepository backed by the given GORM connection.
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{
+		Repository: NewRepository[entity.AuditEvent](db),
+	}
+}
+
+// Create inserts a new audit event and returns it with its generated ID.
+func (r *auditEventRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	event entity.AuditEvent,
+) (entity.AuditEvent, error) {
+	if err := r.Repository.Create(ctx, tx, &event); err != nil {
+		return entity.AuditEvent{}, err
+	}
+
+	return event, nil
+}
+
+// GetByUserId lists the audit events of the user identified by userId, oldest first.
+func (r *auditEventRepository) GetByUserId(
+	ctx context.Context,
+	tx *gorm.DB,
+	userId string,
+) ([]entity.AuditEvent, error) {
+	tx = r.DB(ctx, tx)
+
+	var events []entity.AuditEvent
+	if err := tx.Where("user_id = ?", userId).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
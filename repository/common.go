@@ -1,13 +1,18 @@
 package repository
 
 import (
+	"errors"
 	"math"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 )
 
+// PG_UNIQUE_VIOLATION is the PostgreSQL error code of a write refused by a unique index or constraint.
+const PG_UNIQUE_VIOLATION = "23505"
+
 // Paginate applies pagination to a Gorm database query based on the provided pagination request.
 func Paginate(req dto.PaginationRequest) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -22,3 +27,9 @@ func TotalPage(count, perPage int64) int64 {
 
 	return totalPage
 }
+
+// IsUniqueViolation reports whether err is a write refused by a unique index or constraint.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == PG_UNIQUE_VIOLATION
+}
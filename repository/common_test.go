@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 
@@ -81,3 +84,26 @@ func TestTotalPage(t *testing.T) {
 		)
 	}
 }
+
+// TestIsUniqueViolation tests which errors are writes refused by a unique index.
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: PG_UNIQUE_VIOLATION}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("update: %w", &pgconn.PgError{Code: "23505"}), expected: true},
+		{name: "other database error", err: &pgconn.PgError{Code: PG_SERIALIZATION_FAILURE}},
+		{name: "other error", err: errors.New("failed")},
+		{name: "no error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, IsUniqueViolation(tt.err))
+			},
+		)
+	}
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+)
+
+// listCursor is the signed payload of a pagination cursor: the sort order it was issued for, the values of the sorted
+// columns of the row it points at, and whether it reads the rows before that row instead of after it.
+type listCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Before bool              `json:"b,omitempty"`
+}
+
+// FindPage reads the page of a list query into a slice of T and returns its pagination metadata; db must be scoped to
+// the model of T. Without a cursor the page is read with OFFSET. With one, it is read from the rows sorted after or
+// before the row the cursor points at, which keeps pages stable while rows are inserted and stays fast on deep pages.
+// Rows are counted exactly, estimated or not at all depending on the count mode of the request.
+func FindPage[T any](db *gorm.DB, list ListQuery) ([]T, dto.PaginationResponse, error) {
+	req := list.req
+	meta := dto.PaginationResponse{PerPage: req.PerPage}
+
+	var values []any
+	var before bool
+	if req.Cursor != "" {
+		var err error
+		values, before, err = decodeCursor(req.Cursor, list.orders)
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+
+	query := db.Scopes(list.Where)
+	switch countMode(req) {
+	case dto.PAGINATION_COUNT_EXACT:
+		if err := query.Session(&gorm.Session{}).Count(&meta.Count).Error; err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+		meta.MaxPage = TotalPage(meta.Count, int64(req.PerPage))
+	case dto.PAGINATION_COUNT_ESTIMATE:
+		count, err := estimateCount(query.Session(&gorm.Session{}))
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+		meta.Count, meta.CountEstimated = count, true
+		meta.MaxPage = TotalPage(meta.Count, int64(req.PerPage))
+	}
+
+	orders := list.orders
+	page := query.Session(&gorm.Session{}).Scopes(list.Select).Limit(req.PerPage + 1)
+	if req.Cursor == "" {
+		meta.Page = req.Page
+		page = page.Offset(req.GetOffset())
+	} else {
+		if before {
+			orders = reverseOrders(orders)
+		}
+		page = page.Where(keysetCondition(orders, values))
+	}
+
+	var rows []T
+	result := page.Order(orderBy(orders)).Find(&rows)
+	if result.Error != nil {
+		return nil, dto.PaginationResponse{}, result.Error
+	}
+
+	more := len(rows) > req.PerPage
+	if more {
+		rows = rows[:req.PerPage]
+	}
+	if before {
+		slices.Reverse(rows)
+	}
+
+	hasNext, hasPrev := more, req.Page > 1
+	if req.Cursor != "" {
+		hasNext, hasPrev = more || before, more || !before
+	}
+
+	if len(rows) == 0 {
+		return rows, meta, nil
+	}
+
+	var err error
+	ctx, rowSchema := result.Statement.Context, result.Statement.Schema
+	if hasNext {
+		last := reflect.ValueOf(&rows[len(rows)-1]).Elem()
+		if meta.NextCursor, err = encodeCursor(ctx, rowSchema, last, list.orders, false); err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+	if hasPrev {
+		first := reflect.ValueOf(&rows[0]).Elem()
+		if meta.PrevCursor, err = encodeCursor(ctx, rowSchema, first, list.orders, true); err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+
+	return rows, meta, nil
+}
+
+// countMode returns the count mode of a request, which defaults to an exact count for offset pages and to no count
+// for cursor pages.
+func countMode(req dto.PaginationRequest) string {
+	switch {
+	case req.Count != "":
+		return req.Count
+	case req.Cursor != "":
+		return dto.PAGINATION_COUNT_NONE
+	default:
+		return dto.PAGINATION_COUNT_EXACT
+	}
+}
+
+// keysetCondition returns the condition matching the rows sorted after the row with the given values of the sorted
+// columns. NULLs sort last in ascending and first in descending order, as PostgreSQL sorts them by default.
+func keysetCondition(orders []queryOrder, values []any) clause.Expression {
+	var matches []clause.Expression
+	equal := make([]clause.Expression, 0, len(orders))
+	for i, order := range orders {
+		column := clause.Column{Name: order.field.Column}
+		value := values[i]
+
+		var after clause.Expression
+		switch {
+		case value == nil && order.desc:
+			after = clause.Neq{Column: column, Value: nil}
+		case value == nil:
+		case order.desc:
+			after = clause.Lt{Column: column, Value: value}
+		case order.field.Nullable:
+			after = clause.Or(clause.Gt{Column: column, Value: value}, clause.Eq{Column: column, Value: nil})
+		default:
+			after = clause.Gt{Column: column, Value: value}
+		}
+
+		if after != nil {
+			matches = append(matches, clause.And(append(slices.Clone(equal), after)...))
+		}
+		equal = append(equal, clause.Eq{Column: column, Value: value})
+	}
+
+	if len(matches) == 0 {
+		return clause.Expr{SQL: "FALSE"}
+	}
+
+	return clause.Or(matches...)
+}
+
+// reverseOrders returns the orders with every direction flipped, which reads the rows before a cursor.
+func reverseOrders(orders []queryOrder) []queryOrder {
+	reversed := make([]queryOrder, 0, len(orders))
+	for _, order := range orders {
+		order.desc = !order.desc
+		reversed = append(reversed, order)
+	}
+
+	return reversed
+}
+
+// sortKey describes the sort order of a list query, binding cursors to the order they were issued for.
+func sortKey(orders []queryOrder) string {
+	keys := make([]string, 0, len(orders))
+	for _, order := range orders {
+		if order.desc {
+			keys = append(keys, "-"+order.field.Column)
+		} else {
+			keys = append(keys, order.field.Column)
+		}
+	}
+
+	return strings.Join(keys, ",")
+}
+
+// encodeCursor returns a signed cursor pointing at a row, read through the GORM schema of its model.
+func encodeCursor(
+	ctx context.Context,
+	rowSchema *schema.Schema,
+	row reflect.Value,
+	orders []queryOrder,
+	before bool,
+) (string, error) {
+	cursor := listCursor{Sort: sortKey(orders), Before: before}
+	for _, order := range orders {
+		field := rowSchema.LookUpField(order.field.Column)
+		if field == nil {
+			return "", fmt.Errorf("%s has no column %q", rowSchema.Name, order.field.Column)
+		}
+
+		value, _ := field.ValueOf(ctx, row)
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+			value = nil
+			if !v.IsNil() {
+				value = v.Elem().Interface()
+			}
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values = append(cursor.Values, encoded)
+	}
+
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(signCursor(payload)), nil
+}
+
+// decodeCursor verifies a cursor and returns the values of the sorted columns of the row it points at, and whether
+// it reads the rows before that row.
+func decodeCursor(token string, orders []queryOrder) ([]any, bool, error) {
+	encoding := base64.RawURLEncoding
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, false, dto.ErrInvalidCursor
+	}
+
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, false, dto.ErrInvalidCursor
+	}
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursor(payload)) {
+		return nil, false, dto.ErrInvalidCursor
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, false, dto.ErrInvalidCursor
+	}
+	if cursor.Sort != sortKey(orders) || len(cursor.Values) != len(orders) {
+		return nil, false, fmt.Errorf("%w: issued for another sort order", dto.ErrInvalidCursor)
+	}
+
+	values := make([]any, 0, len(orders))
+	for i, order := range orders {
+		value, err := decodeCursorValue(cursor.Values[i], order.field)
+		if err != nil {
+			return nil, false, err
+		}
+		values = append(values, value)
+	}
+
+	return values, cursor.Before, nil
+}
+
+// decodeCursorValue converts a value of a cursor to the Go type of its column.
+func decodeCursorValue(raw json.RawMessage, field QueryField) (any, error) {
+	if bytes.Equal(raw, []byte("null")) {
+		if !field.Nullable {
+			return nil, dto.ErrInvalidCursor
+		}
+		return nil, nil
+	}
+
+	var value any
+	var err error
+	switch field.Type {
+	case QUERY_TYPE_BOOL:
+		var b bool
+		err = json.Unmarshal(raw, &b)
+		value = b
+	case QUERY_TYPE_NUMBER:
+		var n float64
+		err = json.Unmarshal(raw, &n)
+		value = n
+	case QUERY_TYPE_TIME:
+		var t time.Time
+		err = json.Unmarshal(raw, &t)
+		value = t
+	default:
+		var s string
+		err = json.Unmarshal(raw, &s)
+		value = s
+	}
+
+	if err != nil {
+		return nil, dto.ErrInvalidCursor
+	}
+
+	return value, nil
+}
+
+// signCursor returns the signature of a cursor payload.
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, config.NewCursorSigningKey())
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// estimateCount returns the number of rows the PostgreSQL planner expects the query to return, which is read from
+// table statistics instead of scanning the rows.
+func estimateCount(db *gorm.DB) (int64, error) {
+	stmt := db.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{}).Statement
+	if stmt.Error != nil {
+		return 0, stmt.Error
+	}
+
+	var plan []byte
+	row := stmt.ConnPool.QueryRowContext(stmt.Context, "EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...)
+	if err := row.Scan(&plan); err != nil {
+		return 0, err
+	}
+
+	return planRows(plan)
+}
+
+// planRows returns the estimated row count of the top node of a JSON query plan.
+func planRows(plan []byte) (int64, error) {
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &plans); err != nil {
+		return 0, err
+	}
+	if len(plans) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+
+	return int64(math.Round(plans[0].Plan.Rows)), nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// TestCursor_RoundTrip tests that cursors carry the sorted values of a row and reject tampering and other sort orders.
+func TestCursor_RoundTrip(t *testing.T) {
+	t.Setenv("CURSOR_SIGNING_KEY", "cursor-key")
+
+	userSchema, err := schema.Parse(&entity.User{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+
+	list, err := userQuerySchema.Build(dto.PaginationRequest{Sort: "-purge_at,is_verified,name"})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	user := entity.User{
+		ID:         uuid.New(),
+		Name:       "Jane",
+		IsVerified: true,
+		Timestamp:  entity.Timestamp{CreatedAt: createdAt},
+	}
+
+	token, err := encodeCursor(context.Background(), userSchema, reflect.ValueOf(user), list.orders, true)
+	require.NoError(t, err)
+
+	values, before, err := decodeCursor(token, list.orders)
+	require.NoError(t, err)
+	assert.True(t, before)
+	assert.Equal(t, []any{nil, true, "Jane", user.ID.String()}, values)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	tests := []struct {
+		name    string
+		token   string
+		orders  []queryOrder
+		message string
+	}{
+		{name: "not a cursor", token: "page-2", orders: list.orders},
+		{name: "tampered payload", token: "e30." + signature, orders: list.orders},
+		{name: "tampered signature", token: payload + ".AAAA", orders: list.orders},
+		{
+			name:    "other sort order",
+			token:   token,
+			orders:  reverseOrders(list.orders),
+			message: "issued for another sort order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, _, err := decodeCursor(tt.token, tt.orders)
+				assert.ErrorIs(t, err, dto.ErrInvalidCursor)
+				assert.ErrorContains(t, err, tt.message)
+			},
+		)
+	}
+
+	t.Run(
+		"other signing key", func(t *testing.T) {
+			t.Setenv("CURSOR_SIGNING_KEY", "another-key")
+
+			_, _, err := decodeCursor(token, list.orders)
+			assert.ErrorIs(t, err, dto.ErrInvalidCursor)
+		},
+	)
+
+	t.Run(
+		"times keep their precision", func(t *testing.T) {
+			byCreation, err := userQuerySchema.Build(dto.PaginationRequest{})
+			require.NoError(t, err)
+
+			token, err := encodeCursor(context.Background(), userSchema, reflect.ValueOf(user), byCreation.orders, false)
+			require.NoError(t, err)
+
+			values, before, err := decodeCursor(token, byCreation.orders)
+			require.NoError(t, err)
+			assert.False(t, before)
+			assert.True(t, createdAt.Equal(values[0].(time.Time)))
+		},
+	)
+}
+
+// TestKeysetCondition tests the conditions selecting the rows after a cursor for mixed directions and NULL values.
+func TestKeysetCondition(t *testing.T) {
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		sort    string
+		reverse bool
+		values  []any
+		sql     string
+	}{
+		{
+			name:   "ascending",
+			values: []any{"2025-01-01", "id-1"},
+			sql: `("created_at" > $1 OR ("created_at" = $2 AND "id" > $3)) ` +
+				`AND "users"."deleted_at" IS NULL`,
+		},
+		{
+			name:   "mixed directions",
+			sort:   "-name,email",
+			values: []any{"Jane", "jane@example.com", "id-1"},
+			sql: `("name" < $1 OR ("name" = $2 AND "email" > $3) OR ("name" = $4 AND "email" = $5 AND "id" > $6)) ` +
+				`AND "users"."deleted_at" IS NULL`,
+		},
+		{
+			name:    "reversed",
+			sort:    "-name",
+			reverse: true,
+			values:  []any{"Jane", "id-1"},
+			sql: `("name" > $1 OR ("name" = $2 AND "id" < $3)) ` +
+				`AND "users"."deleted_at" IS NULL`,
+		},
+		{
+			name:   "ascending nullable value",
+			sort:   "purge_at",
+			values: []any{"2025-01-01", "id-1"},
+			sql: `(("purge_at" > $1 OR "purge_at" IS NULL) OR ("purge_at" = $2 AND "id" > $3)) ` +
+				`AND "users"."deleted_at" IS NULL`,
+		},
+		{
+			name:   "ascending NULL sorts last",
+			sort:   "purge_at",
+			values: []any{nil, "id-1"},
+			sql:    `("purge_at" IS NULL AND "id" > $1) AND "users"."deleted_at" IS NULL`,
+		},
+		{
+			name:   "descending NULL sorts first",
+			sort:   "-purge_at",
+			values: []any{nil, "id-1"},
+			sql: `("purge_at" IS NOT NULL OR ("purge_at" IS NULL AND "id" > $1)) ` +
+				`AND "users"."deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				list, err := userQuerySchema.Build(dto.PaginationRequest{Sort: tt.sort})
+				require.NoError(t, err)
+
+				orders := list.orders
+				if tt.reverse {
+					orders = reverseOrders(orders)
+				}
+
+				var users []entity.User
+				stmt := db.Model(&entity.User{}).Where(keysetCondition(orders, tt.values)).Find(&users).Statement
+				assert.Equal(t, `SELECT * FROM "users" WHERE `+tt.sql, stmt.SQL.String())
+			},
+		)
+	}
+}
+
+// TestCountMode tests that offset pages are counted exactly and cursor pages are not counted unless requested.
+func TestCountMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.PaginationRequest
+		expected string
+	}{
+		{name: "offset page", req: dto.PaginationRequest{}, expected: dto.PAGINATION_COUNT_EXACT},
+		{name: "cursor page", req: dto.PaginationRequest{Cursor: "x"}, expected: dto.PAGINATION_COUNT_NONE},
+		{
+			name:     "explicit mode",
+			req:      dto.PaginationRequest{Cursor: "x", Count: dto.PAGINATION_COUNT_ESTIMATE},
+			expected: dto.PAGINATION_COUNT_ESTIMATE,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, countMode(tt.req))
+			},
+		)
+	}
+}
+
+// TestPlanRows tests reading the estimated row count from a PostgreSQL JSON query plan.
+func TestPlanRows(t *testing.T) {
+	rows, err := planRows([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234.6}}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1235), rows)
+
+	_, err = planRows([]byte(`[]`))
+	assert.Error(t, err)
+
+	_, err = planRows([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// DataExportRepository defines the database operations of personal data exports.
+	// Create queues an export, GetById fetches one, GetActiveByUserId finds the export of a user that is still being
+	// built, GetByUserId lists the exports of a user, ClaimPending locks exports waiting to be built, Save persists
+	// the progress of an export, GetExpired lists exports past their expiry time and Delete removes an export.
+	DataExportRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, export entity.DataExport) (entity.DataExport, error)
+		GetById(ctx context.Context, tx *gorm.DB, id string) (entity.DataExport, error)
+		GetActiveByUserId(ctx context.Context, tx *gorm.DB, userId string) (entity.DataExport, error)
+		GetByUserId(ctx context.Context, tx *gorm.DB, userId string) ([]entity.DataExport, error)
+		ClaimPending(
+			ctx context.Context,
+			tx *gorm.DB,
+			claimedBefore time.Time,
+			limit int,
+		) ([]entity.DataExport, error)
+		Save(ctx context.Context, tx *gorm.DB, export entity.DataExport) (entity.DataExport, error)
+		GetExpired(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.DataExport, error)
+		Delete(ctx context.Context, tx *gorm.DB, id string) error
+	}
+
+	// dataExportRepository implements DataExportRepository using GORM.
+	dataExportRepository struct {
+		Repository[entity.DataExport]
+	}
+)
+
+// NewDataExportRepository creates a new DataExportRepository backed by the given GORM connection.
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{
+		Repository: NewRepository[entity.DataExport](db),
+	}
+}
+
+// Create inserts a new data export and returns it with its generated ID.
+func (r *dataExportRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	export entity.DataExport,
+) (entity.DataExport, error) {
+	tx = r.DB(ctx, tx)
+
+	if err := tx.Create(&export).Error; err != nil {
+		return entity.DataExport{}, err
+	}
+
+	return export, nil
+}
+
+// GetById retrieves a data export by its ID.
+func (r *dataExportRepository) GetById(ctx context.Context, tx *gorm.DB, id string) (entity.DataExport, error) {
+	tx = r.DB(ctx, tx)
+
+	var export entity.DataExport
+	if err := tx.Where("id = ?", id).Take(&export).Error; err != nil {
+		return entity.DataExport{}, err
+	}
+
+	return export, nil
+}
+
+// GetActiveByUserId retrieves the most recent export of the user identified by userId that is pending or being
+// built.
+func (r *dataExportRepository) GetActiveByUserId(ctx context.Context, tx *gorm.DB, userId string) (
+	entity.DataExport,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	var export entity.DataExport
+	if err := tx.Where("user_id = ?", userId).
+		Where("status IN ?", []string{constants.ENUM_DATA_EXPORT_PENDING, constants.ENUM_DATA_EXPORT_PROCESSING}).
+		Order("created_at DESC").
+		Take(&export).Error; err != nil {
+		return entity.DataExport{}, err
+	}
+
+	return export, nil
+}
+
+// GetByUserId lists every export of the user identified by userId, oldest first.
+func (r *dataExportRepository) GetByUserId(ctx context.Context, tx *gorm.DB, userId string) (
+	[]entity.DataExport,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	var exports []entity.DataExport
+	if err := tx.Where("user_id = ?", userId).Order("created_at").Find(&exports).Error; err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// ClaimPending selects up to limit exports that are pending, or that were claimed before claimedBefore by a worker
+// that never finished them, and locks them with FOR UPDATE SKIP LOCKED so concurrent workers never build the same
+// export. It must run inside a transaction.
+func (r *dataExportRepository) ClaimPending(
+	ctx context.Context,
+	tx *gorm.DB,
+	claimedBefore time.Time,
+	limit int,
+) ([]entity.DataExport, error) {
+	tx = r.DB(ctx, tx)
+
+	var exports []entity.DataExport
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", constants.ENUM_DATA_EXPORT_PENDING).
+		Or("status = ? AND claimed_at < ?", constants.ENUM_DATA_EXPORT_PROCESSING, claimedBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// Save writes the status, archive details and timestamps of a data export and increments its version, provided the
+// stored version of the export is still export.Version. It returns dto.ErrDataExportVersionConflict when the export
+// was saved since it was read, or no longer exists.
+func (r *dataExportRepository) Save(
+	ctx context.Context,
+	tx *gorm.DB,
+	export entity.DataExport,
+) (entity.DataExport, error) {
+	tx = r.DB(ctx, tx)
+
+	result := tx.Model(&entity.DataExport{}).
+		Where("id = ? AND version = ?", export.ID, export.Version).
+		Updates(
+			map[string]any{
+				"status":       export.Status,
+				"storage_key":  export.StorageKey,
+				"size":         export.Size,
+				"last_error":   export.LastError,
+				"claimed_at":   export.ClaimedAt,
+				"expires_at":   export.ExpiresAt,
+				"completed_at": export.CompletedAt,
+				"version":      gorm.Expr("version + 1"),
+			},
+		)
+	if result.Error != nil {
+		return entity.DataExport{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.DataExport{}, dto.ErrDataExportVersionConflict
+	}
+
+	export.Version++
+	return export, nil
+}
+
+// GetExpired lists up to limit exports whose expiry time is before now, oldest first.
+func (r *dataExportRepository) GetExpired(
+	ctx context.Context,
+	tx *gorm.DB,
+	now time.Time,
+	limit int,
+) ([]entity.DataExport, error) {
+	tx = r.DB(ctx, tx)
+
+	var exports []entity.DataExport
+	if err := tx.Where("expires_at < ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// Delete removes the data export identified by id.
+func (r *dataExportRepository) Delete(ctx context.Context, tx *gorm.DB, id string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Delete(&entity.DataExport{}, "id = ?", id).Error
+}
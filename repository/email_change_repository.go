@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// EmailChangeRepository defines the database operations of pending email address changes.
+	// Create stores a change, GetByTokenHash finds the change confirmed by a token and DeleteByUserId removes every
+	// change of a user.
+	EmailChangeRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, change entity.EmailChange) (entity.EmailChange, error)
+		GetByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.EmailChange, error)
+		DeleteByUserId(ctx context.Context, tx *gorm.DB, userId string) error
+	}
+
+	// emailChangeRepository implements EmailChangeRepository using GORM.
+	emailChangeRepository struct {
+		Repository[entity.EmailChange]
+	}
+)
+
+// NewEmailChangeRepository creates a new EmailChangeRepository backed by the given GORM connection.
+func NewEmailChangeRepository(db *gorm.DB) EmailChangeRepository {
+	return &emailChangeRepository{
+		Repository: NewRepository[entity.EmailChange](db),
+	}
+}
+
+// Create inserts a new email change and returns it with its generated ID.
+func (r *emailChangeRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	change entity.EmailChange,
+) (entity.EmailChange, error) {
+	if err := r.Repository.Create(ctx, tx, &change); err != nil {
+		return entity.EmailChange{}, err
+	}
+
+	return change, nil
+}
+
+// GetByTokenHash retrieves the email change whose confirmation token hashes to tokenHash, locking it until the end of
+// the transaction so that it is applied once.
+func (r *emailChangeRepository) GetByTokenHash(
+	ctx context.Context,
+	tx *gorm.DB,
+	tokenHash string,
+) (entity.EmailChange, error) {
+	tx = r.DB(ctx, tx)
+
+	var change entity.EmailChange
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		Take(&change).Error; err != nil {
+		return entity.EmailChange{}, err
+	}
+
+	return change, nil
+}
+
+// DeleteByUserId removes every email change of the user identified by userId.
+func (r *emailChangeRepository) DeleteByUserId(ctx context.Context, tx *gorm.DB, userId string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Where("user_id = ?", userId).Delete(&entity.EmailChange{}).Error
+}
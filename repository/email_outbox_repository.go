@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// EmailOutboxRepository defines the database operations of the transactional email outbox.
+	// Create queues a message, ClaimDue leases due messages for delivery, SaveOutcome persists a delivery outcome
+	// while the lease still holds, Save persists a message unconditionally, GetById fetches a single message and
+	// GetAllWithPagination lists messages filtered by status.
+	EmailOutboxRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, message entity.EmailOutbox) (entity.EmailOutbox, error)
+		ClaimDue(
+			ctx context.Context,
+			tx *gorm.DB,
+			now time.Time,
+			leaseUntil time.Time,
+			limit int,
+		) ([]entity.EmailOutbox, error)
+		SaveOutcome(ctx context.Context, tx *gorm.DB, message entity.EmailOutbox, leaseUntil time.Time) error
+		Save(ctx context.Context, tx *gorm.DB, message entity.EmailOutbox) error
+		GetById(ctx context.Context, tx *gorm.DB, id string) (entity.EmailOutbox, error)
+		GetAllWithPagination(
+			ctx context.Context,
+			tx *gorm.DB,
+			status string,
+			req dto.PaginationRequest,
+		) ([]entity.EmailOutbox, dto.PaginationResponse, error)
+	}
+
+	// emailOutboxRepository implements EmailOutboxRepository using GORM.
+	emailOutboxRepository struct {
+		Repository[entity.EmailOutbox]
+	}
+)
+
+// NewEmailOutboxRepository creates a new EmailOutboxRepository backed by the given GORM connection.
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &emailOutboxRepository{
+		Repository: NewRepository[entity.EmailOutbox](db),
+	}
+}
+
+// Create inserts a new outbox message and returns it with its generated ID.
+func (r *emailOutboxRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	message entity.EmailOutbox,
+) (entity.EmailOutbox, error) {
+	tx = r.DB(ctx, tx)
+
+	if err := tx.Create(&message).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return message, nil
+}
+
+// ClaimDue leases up to limit messages until leaseUntil: pending messages whose next attempt is due, and sending
+// messages whose lease expired because their dispatcher stopped before recording the outcome. The messages are locked
+// with FOR UPDATE SKIP LOCKED and marked as sending in a transaction of their own, so concurrent dispatchers never
+// claim the same message and no lock is held while the messages are delivered. leaseUntil is truncated to the
+// microsecond precision of the column, so the NextAttemptAt of the returned messages matches the stored lease.
+func (r *emailOutboxRepository) ClaimDue(
+	ctx context.Context,
+	tx *gorm.DB,
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) ([]entity.EmailOutbox, error) {
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	var messages []entity.EmailOutbox
+	err := r.DB(ctx, tx).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where(
+					"status IN ? AND next_attempt_at <= ?",
+					[]string{constants.ENUM_OUTBOX_PENDING, constants.ENUM_OUTBOX_SENDING},
+					now,
+				).
+				Order("next_attempt_at").
+				Limit(limit).
+				Find(&messages).Error; err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				return nil
+			}
+
+			ids := make([]uuid.UUID, 0, len(messages))
+			for i := range messages {
+				ids = append(ids, messages[i].ID)
+				messages[i].Status = constants.ENUM_OUTBOX_SENDING
+				messages[i].NextAttemptAt = leaseUntil
+			}
+
+			return tx.Model(&entity.EmailOutbox{}).
+				Where("id IN ?", ids).
+				Updates(
+					map[string]any{
+						"status":          constants.ENUM_OUTBOX_SENDING,
+						"next_attempt_at": leaseUntil,
+					},
+				).Error
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SaveOutcome writes the delivery outcome of a message claimed by ClaimDue with the lease leaseUntil. The write only
+// applies while the message is still sending under that lease; once the lease expired and another dispatcher claimed
+// the message, its lease differs and dto.ErrEmailOutboxLeaseLost is returned, leaving the outcome to that dispatcher.
+func (r *emailOutboxRepository) SaveOutcome(
+	ctx context.Context,
+	tx *gorm.DB,
+	message entity.EmailOutbox,
+	leaseUntil time.Time,
+) error {
+	tx = r.DB(ctx, tx)
+
+	result := tx.Model(&entity.EmailOutbox{}).
+		Where(
+			"id = ? AND status = ? AND next_attempt_at = ?",
+			message.ID, constants.ENUM_OUTBOX_SENDING, leaseUntil,
+		).
+		Updates(outboxColumns(message))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dto.ErrEmailOutboxLeaseLost
+	}
+
+	return nil
+}
+
+// Save writes the status, attempt counters and delivery details of an outbox message.
+func (r *emailOutboxRepository) Save(ctx context.Context, tx *gorm.DB, message entity.EmailOutbox) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Model(&entity.EmailOutbox{}).
+		Where("id = ?", message.ID).
+		Updates(outboxColumns(message)).Error
+}
+
+// outboxColumns returns the columns of message that Save and SaveOutcome write.
+func outboxColumns(message entity.EmailOutbox) map[string]any {
+	return map[string]any{
+		"status":          message.Status,
+		"attempts":        message.Attempts,
+		"next_attempt_at": message.NextAttemptAt,
+		"last_error":      message.LastError,
+		"sent_at":         message.SentAt,
+	}
+}
+
+// GetById retrieves an outbox message by its ID. An ID that is not a UUID matches no message and returns
+// gorm.ErrRecordNotFound.
+func (r *emailOutboxRepository) GetById(ctx context.Context, tx *gorm.DB, id string) (entity.EmailOutbox, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return entity.EmailOutbox{}, gorm.ErrRecordNotFound
+	}
+
+	tx = r.DB(ctx, tx)
+
+	var message entity.EmailOutbox
+	if err := tx.Where("id = ?", id).Take(&message).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return message, nil
+}
+
+// GetAllWithPagination lists outbox messages, newest first, optionally filtered by status.
+func (r *emailOutboxRepository) GetAllWithPagination(
+	ctx context.Context,
+	tx *gorm.DB,
+	status string,
+	req dto.PaginationRequest,
+) ([]entity.EmailOutbox, dto.PaginationResponse, error) {
+	tx = r.DB(ctx, tx)
+
+	req.Default()
+
+	query := tx.Model(&entity.EmailOutbox{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var messages []entity.EmailOutbox
+	if err := query.Order("created_at DESC").Scopes(Paginate(req)).Find(&messages).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	return messages, dto.PaginationResponse{
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Count:   count,
+		MaxPage: TotalPage(count, int64(req.PerPage)),
+	}, nil
+}
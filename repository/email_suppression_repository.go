@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// EmailSuppressionRepository defines the database operations of the email suppression list.
+	// Upsert adds an address or refreshes its reason, FindSuppressed returns which of the given addresses are
+	// suppressed, Delete removes an address and GetAllWithPagination lists suppressed addresses.
+	EmailSuppressionRepository interface {
+		Upsert(ctx context.Context, tx *gorm.DB, suppression entity.EmailSuppression) error
+		FindSuppressed(ctx context.Context, tx *gorm.DB, emails []string) ([]string, error)
+		Delete(ctx context.Context, tx *gorm.DB, email string) (bool, error)
+		GetAllWithPagination(
+			ctx context.Context,
+			tx *gorm.DB,
+			req dto.PaginationRequest,
+		) ([]entity.EmailSuppression, dto.PaginationResponse, error)
+	}
+
+	// emailSuppressionRepository implements EmailSuppressionRepository using GORM.
+	emailSuppressionRepository struct {
+		Repository[entity.EmailSuppression]
+	}
+)
+
+// NewEmailSuppressionRepository creates a new EmailSuppressionRepository backed by the given GORM connection.
+func NewEmailSuppressionRepository(db *gorm.DB) EmailSuppressionRepository {
+	return &emailSuppressionRepository{
+		Repository: NewRepository[entity.EmailSuppression](db),
+	}
+}
+
+// Upsert inserts the suppression, or updates the reason and detail when the address is already suppressed.
+func (r *emailSuppressionRepository) Upsert(ctx context.Context, tx *gorm.DB, suppression entity.EmailSuppression) error {
+	tx = r.DB(ctx, tx)
+
+	suppression.Email = strings.ToLower(strings.TrimSpace(suppression.Email))
+
+	return tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reason", "detail", "updated_at"}),
+		},
+	).
+		Create(&suppression).Error
+}
+
+// FindSuppressed returns the lower-cased addresses among emails that are on the suppression list.
+func (r *emailSuppressionRepository) FindSuppressed(ctx context.Context, tx *gorm.DB, emails []string) ([]string, error) {
+	tx = r.DB(ctx, tx)
+
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(email)))
+	}
+
+	var suppressed []string
+	if err := tx.Model(&entity.EmailSuppression{}).
+		Where("email IN ?", normalized).
+		Pluck("email", &suppressed).Error; err != nil {
+		return nil, err
+	}
+
+	return suppressed, nil
+}
+
+// Delete removes an address from the suppression list and reports whether it was suppressed.
+func (r *emailSuppressionRepository) Delete(ctx context.Context, tx *gorm.DB, email string) (bool, error) {
+	tx = r.DB(ctx, tx)
+
+	result := tx.Where("email = ?", strings.ToLower(strings.TrimSpace(email))).
+		Delete(&entity.EmailSuppression{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetAllWithPagination lists suppressed addresses, most recent first, optionally filtered by a search on the address.
+func (r *emailSuppressionRepository) GetAllWithPagination(
+	ctx context.Context,
+	tx *gorm.DB,
+	req dto.PaginationRequest,
+) ([]entity.EmailSuppression, dto.PaginationResponse, error) {
+	tx = r.DB(ctx, tx)
+
+	req.Default()
+
+	query := tx.Model(&entity.EmailSuppression{})
+	if req.Search != "" {
+		query = query.Where("email LIKE ?", "%"+strings.ToLower(req.Search)+"%")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	var suppressions []entity.EmailSuppression
+	if err := query.Order("updated_at DESC").Scopes(Paginate(req)).Find(&suppressions).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	return suppressions, dto.PaginationResponse{
+		Page:    req.Page,
+		PerPage: req.PerPage,
+		Count:   count,
+		MaxPage: TotalPage(count, int64(req.PerPage)),
+	}, nil
+}
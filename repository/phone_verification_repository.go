@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// PhoneVerificationRepository defines the database operations of phone number verification codes.
+	// Create stores a verification, GetByUserId finds the verification of a user, IncrementAttempts counts a wrong code
+	// and DeleteByUserId removes the verification of a user.
+	PhoneVerificationRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, verification entity.PhoneVerification) (entity.PhoneVerification, error)
+		GetByUserId(ctx context.Context, tx *gorm.DB, userId string) (entity.PhoneVerification, error)
+		IncrementAttempts(ctx context.Context, tx *gorm.DB, id string) error
+		DeleteByUserId(ctx context.Context, tx *gorm.DB, userId string) error
+	}
+
+	// phoneVerificationRepository implements PhoneVerificationRepository using GORM.
+	phoneVerificationRepository struct {
+		Repository[entity.PhoneVerification]
+	}
+)
+
+// NewPhoneVerificationRepository creates a new PhoneVerificationRepository backed by the given GORM connection.
+func NewPhoneVerificationRepository(db *gorm.DB) PhoneVerificationRepository {
+	return &phoneVerificationRepository{
+		Repository: NewRepository[entity.PhoneVerification](db),
+	}
+}
+
+// Create inserts a new phone verification and returns it with its generated ID.
+func (r *phoneVerificationRepository) Create(
+	ctx context.Context,
+	tx *gorm.DB,
+	verification entity.PhoneVerification,
+) (entity.PhoneVerification, error) {
+	if err := r.Repository.Create(ctx, tx, &verification); err != nil {
+		return entity.PhoneVerification{}, err
+	}
+
+	return verification, nil
+}
+
+// GetByUserId retrieves the phone verification of the user identified by userId, locking it until the end of the
+// transaction so that concurrent checks of a code count every attempt.
+func (r *phoneVerificationRepository) GetByUserId(
+	ctx context.Context,
+	tx *gorm.DB,
+	userId string,
+) (entity.PhoneVerification, error) {
+	tx = r.DB(ctx, tx)
+
+	var verification entity.PhoneVerification
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userId).
+		Take(&verification).Error; err != nil {
+		return entity.PhoneVerification{}, err
+	}
+
+	return verification, nil
+}
+
+// IncrementAttempts adds one to the number of wrong codes tried against the phone verification identified by id.
+func (r *phoneVerificationRepository) IncrementAttempts(ctx context.Context, tx *gorm.DB, id string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Model(&entity.PhoneVerification{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// DeleteByUserId removes the phone verification of the user identified by userId.
+func (r *phoneVerificationRepository) DeleteByUserId(ctx context.Context, tx *gorm.DB, userId string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Where("user_id = ?", userId).Delete(&entity.PhoneVerification{}).Error
+}
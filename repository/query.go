@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+)
+
+const (
+	// QUERY_TYPE_TEXT marks a column whose filter values are compared as text.
+	QUERY_TYPE_TEXT = "text"
+	// QUERY_TYPE_BOOL marks a column whose filter values are parsed as booleans.
+	QUERY_TYPE_BOOL = "bool"
+	// QUERY_TYPE_NUMBER marks a column whose filter values are parsed as numbers.
+	QUERY_TYPE_NUMBER = "number"
+	// QUERY_TYPE_TIME marks a column whose filter values are parsed as RFC 3339 times or dates.
+	QUERY_TYPE_TIME = "time"
+)
+
+type (
+	// QueryField describes how a field of a list request maps to a column and what the request may do with it.
+	// Operators lists the filter operators allowed on the field; a field without operators cannot be filtered.
+	// Select lists the columns loaded when the field is requested; a field without them cannot be selected.
+	// Nullable marks a column that may hold NULL, which cursors have to account for when it is sorted on.
+	QueryField struct {
+		Column    string
+		Type      string
+		Operators []string
+		Sortable  bool
+		Nullable  bool
+		Select    []string
+	}
+
+	// QuerySchema is the allowlist of fields a list request of an entity may filter, sort and select. Only columns named
+	// here are ever written into SQL, and filter values are always bound as parameters.
+	// Search lists the columns matched, ignoring case, by the search term. DefaultSort orders results when the request
+	// does not, and Key is the unique column that breaks ties so pages stay stable and that is always selected.
+	QuerySchema struct {
+		Fields      map[string]QueryField
+		Search      []string
+		DefaultSort []string
+		Key         string
+	}
+
+	// ListQuery holds the validated scopes of a list request. Where is shared by the count and the page query, while
+	// Order and Select only apply to the page. FindPage uses the rest of the request to read the page itself.
+	ListQuery struct {
+		Where  func(db *gorm.DB) *gorm.DB
+		Order  func(db *gorm.DB) *gorm.DB
+		Select func(db *gorm.DB) *gorm.DB
+
+		req    dto.PaginationRequest
+		orders []queryOrder
+	}
+
+	// queryOrder is a column of the ORDER BY clause of a list query together with the field it sorts on.
+	queryOrder struct {
+		field QueryField
+		desc  bool
+	}
+)
+
+// Build validates the search, filters, sort order and fields of a request against the schema and returns the scopes
+// applying them. It returns an error wrapping dto.ErrInvalidListQuery for anything outside the allowlist.
+func (s QuerySchema) Build(req dto.PaginationRequest) (ListQuery, error) {
+	conditions, err := s.conditions(req)
+	if err != nil {
+		return ListQuery{}, err
+	}
+
+	orders, err := s.orders(req.SortFields())
+	if err != nil {
+		return ListQuery{}, err
+	}
+
+	columns, err := s.columns(req.FieldList(), orders)
+	if err != nil {
+		return ListQuery{}, err
+	}
+
+	switch req.Count {
+	case "", dto.PAGINATION_COUNT_EXACT, dto.PAGINATION_COUNT_ESTIMATE, dto.PAGINATION_COUNT_NONE:
+	default:
+		return ListQuery{}, fmt.Errorf("%w: unknown count mode %q", dto.ErrInvalidListQuery, req.Count)
+	}
+
+	req.Default()
+	return ListQuery{
+		Where: func(db *gorm.DB) *gorm.DB {
+			for _, condition := range conditions {
+				db = db.Where(condition)
+			}
+			return db
+		},
+		Order: func(db *gorm.DB) *gorm.DB {
+			return db.Order(orderBy(orders))
+		},
+		Select: func(db *gorm.DB) *gorm.DB {
+			if len(columns) == 0 {
+				return db
+			}
+			return db.Select(columns)
+		},
+		req:    req,
+		orders: orders,
+	}, nil
+}
+
+// conditions returns the search condition followed by one condition per filter of the request.
+func (s QuerySchema) conditions(req dto.PaginationRequest) ([]clause.Expression, error) {
+	var conditions []clause.Expression
+	if search := strings.TrimSpace(req.Search); search != "" && len(s.Search) > 0 {
+		pattern := likePattern(search)
+		matches := make([]clause.Expression, 0, len(s.Search))
+		for _, column := range s.Search {
+			matches = append(matches, ilike(column, pattern))
+		}
+		conditions = append(conditions, clause.Or(matches...))
+	}
+
+	for _, filter := range req.Filters {
+		condition, err := s.condition(filter)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, nil
+}
+
+// condition returns the SQL condition of a filter after checking its field and operator and parsing its value.
+func (s QuerySchema) condition(filter dto.Filter) (clause.Expression, error) {
+	field, ok := s.Fields[filter.Field]
+	if !ok || !allows(field.Operators, filter.Operator) {
+		return nil, fmt.Errorf(
+			"%w: cannot filter %q with %q", dto.ErrInvalidListQuery, filter.Field, filter.Operator,
+		)
+	}
+
+	column := clause.Column{Name: field.Column}
+	if filter.Operator == dto.FILTER_OPERATOR_LIKE {
+		return ilike(field.Column, likePattern(filter.Value)), nil
+	}
+
+	if filter.Operator == dto.FILTER_OPERATOR_IN {
+		values := make([]any, 0)
+		for _, raw := range strings.Split(filter.Value, ",") {
+			value, err := field.parse(filter.Field, strings.TrimSpace(raw))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	}
+
+	value, err := field.parse(filter.Field, filter.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filter.Operator {
+	case dto.FILTER_OPERATOR_EQ:
+		return clause.Eq{Column: column, Value: value}, nil
+	case dto.FILTER_OPERATOR_NE:
+		return clause.Neq{Column: column, Value: value}, nil
+	case dto.FILTER_OPERATOR_GTE:
+		return clause.Gte{Column: column, Value: value}, nil
+	case dto.FILTER_OPERATOR_LTE:
+		return clause.Lte{Column: column, Value: value}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown filter operator %q", dto.ErrInvalidListQuery, filter.Operator)
+	}
+}
+
+// orders returns the ORDER BY columns of a sort list, followed by the default sort when the list is empty and by the
+// key column unless it is already sorted on.
+func (s QuerySchema) orders(sorts []string) ([]queryOrder, error) {
+	if len(sorts) == 0 {
+		sorts = s.DefaultSort
+	}
+
+	orders := make([]queryOrder, 0, len(sorts)+1)
+	seen := make(map[string]bool, len(sorts)+1)
+	for _, sort := range sorts {
+		name := strings.TrimPrefix(sort, "-")
+		field, ok := s.Fields[name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: cannot sort by %q", dto.ErrInvalidListQuery, name)
+		}
+		if seen[field.Column] {
+			continue
+		}
+
+		seen[field.Column] = true
+		orders = append(orders, queryOrder{field: field, desc: strings.HasPrefix(sort, "-")})
+	}
+
+	if s.Key != "" && !seen[s.Key] {
+		orders = append(orders, queryOrder{field: QueryField{Column: s.Key, Type: QUERY_TYPE_TEXT}})
+	}
+
+	return orders, nil
+}
+
+// columns returns the columns to select for a fields list, always including the key and sorted columns that cursors
+// are built from, or nil to select all.
+func (s QuerySchema) columns(fields []string, orders []queryOrder) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	add := func(column string) {
+		if column != "" && !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+	}
+
+	add(s.Key)
+	for _, name := range fields {
+		field, ok := s.Fields[name]
+		if !ok || len(field.Select) == 0 {
+			return nil, fmt.Errorf("%w: cannot select %q", dto.ErrInvalidListQuery, name)
+		}
+		for _, column := range field.Select {
+			add(column)
+		}
+	}
+
+	for _, order := range orders {
+		add(order.field.Column)
+	}
+
+	return columns, nil
+}
+
+// orderBy returns the ORDER BY clause of the given columns.
+func orderBy(orders []queryOrder) clause.OrderBy {
+	columns := make([]clause.OrderByColumn, 0, len(orders))
+	for _, order := range orders {
+		columns = append(
+			columns, clause.OrderByColumn{
+				Column: clause.Column{Name: order.field.Column},
+				Desc:   order.desc,
+			},
+		)
+	}
+
+	return clause.OrderBy{Columns: columns}
+}
+
+// parse converts a filter value of the field to the Go type of its column.
+func (f QueryField) parse(name string, raw string) (any, error) {
+	var value any
+	var err error
+	switch f.Type {
+	case QUERY_TYPE_BOOL:
+		value, err = strconv.ParseBool(raw)
+	case QUERY_TYPE_NUMBER:
+		value, err = strconv.ParseFloat(raw, 64)
+	case QUERY_TYPE_TIME:
+		value, err = parseQueryTime(raw)
+	default:
+		value = raw
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid value %q for %q", dto.ErrInvalidListQuery, raw, name)
+	}
+
+	return value, nil
+}
+
+// parseQueryTime parses an RFC 3339 time or a date, read as midnight UTC.
+func parseQueryTime(raw string) (time.Time, error) {
+	if value, err := time.Parse(time.RFC3339, raw); err == nil {
+		return value, nil
+	}
+
+	return time.Parse(time.DateOnly, raw)
+}
+
+// ilike returns a case-insensitive match of a column against a LIKE pattern.
+func ilike(column string, pattern string) clause.Expression {
+	return clause.Expr{SQL: "? ILIKE ?", Vars: []any{clause.Column{Name: column}, pattern}}
+}
+
+// likePattern returns a LIKE pattern matching values that contain the term, escaping its wildcards.
+func likePattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	return "%" + escaped + "%"
+}
+
+// allows reports whether an operator is among the allowed operators.
+func allows(operators []string, operator string) bool {
+	for _, allowed := range operators {
+		if allowed == operator {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// TestQuerySchema_Build tests that list requests become parameterized SQL limited to the user allowlist.
+func TestQuerySchema_Build(t *testing.T) {
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		req      dto.PaginationRequest
+		sql      string
+		vars     []any
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "default order",
+			req:  dto.PaginationRequest{},
+			sql: `SELECT * FROM "users" WHERE "users"."deleted_at" IS NULL ` +
+				`ORDER BY "created_at","id"`,
+		},
+		{
+			name: "case-insensitive search across columns",
+			req:  dto.PaginationRequest{Search: " 50%_off "},
+			sql: `SELECT * FROM "users" WHERE ("name" ILIKE $1 OR "email" ILIKE $2) ` +
+				`AND "users"."deleted_at" IS NULL ORDER BY "created_at","id"`,
+			vars: []any{`%50\%\_off%`, `%50\%\_off%`},
+		},
+		{
+			name: "filters, sort and fields",
+			req: dto.PaginationRequest{
+				Sort:   "-created_at,name",
+				Fields: "email,image_thumbnails,image_url",
+				Filters: []dto.Filter{
+					{Field: "created_at", Operator: dto.FILTER_OPERATOR_GTE, Value: "2025-01-02"},
+					{Field: "is_verified", Operator: dto.FILTER_OPERATOR_EQ, Value: "true"},
+					{Field: "role", Operator: dto.FILTER_OPERATOR_IN, Value: "admin, user"},
+					{Field: "status", Operator: dto.FILTER_OPERATOR_NE, Value: "deactivated"},
+				},
+			},
+			sql: `SELECT "id","email","image_url","created_at","name" FROM "users" ` +
+				`WHERE "created_at" >= $1 AND "is_verified" = $2 ` +
+				`AND "role" IN ($3,$4) AND "status" <> $5 AND "users"."deleted_at" IS NULL ` +
+				`ORDER BY "created_at" DESC,"name","id"`,
+			vars: []any{
+				time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), true, "admin", "user", "deactivated",
+			},
+		},
+		{
+			name: "like filter",
+			req: dto.PaginationRequest{
+				Filters: []dto.Filter{{Field: "email", Operator: dto.FILTER_OPERATOR_LIKE, Value: "Example"}},
+			},
+			sql: `SELECT * FROM "users" WHERE "email" ILIKE $1 AND "users"."deleted_at" IS NULL ` +
+				`ORDER BY "created_at","id"`,
+			vars: []any{"%Example%"},
+		},
+		{
+			name: "unknown filter field",
+			req: dto.PaginationRequest{
+				Filters: []dto.Filter{{Field: "password", Operator: dto.FILTER_OPERATOR_EQ, Value: "x"}},
+			},
+			wantErr:  true,
+			errorMsg: `cannot filter "password" with "eq"`,
+		},
+		{
+			name: "operator outside the allowlist",
+			req: dto.PaginationRequest{
+				Filters: []dto.Filter{{Field: "created_at", Operator: dto.FILTER_OPERATOR_EQ, Value: "2025-01-01"}},
+			},
+			wantErr:  true,
+			errorMsg: `cannot filter "created_at" with "eq"`,
+		},
+		{
+			name: "invalid value",
+			req: dto.PaginationRequest{
+				Filters: []dto.Filter{{Field: "is_verified", Operator: dto.FILTER_OPERATOR_EQ, Value: "maybe"}},
+			},
+			wantErr:  true,
+			errorMsg: `invalid value "maybe" for "is_verified"`,
+		},
+		{
+			name:     "injection in sort",
+			req:      dto.PaginationRequest{Sort: "name; DROP TABLE users"},
+			wantErr:  true,
+			errorMsg: `cannot sort by "name; DROP TABLE users"`,
+		},
+		{
+			name:     "unsortable field",
+			req:      dto.PaginationRequest{Sort: "email_suppressed"},
+			wantErr:  true,
+			errorMsg: `cannot sort by "email_suppressed"`,
+		},
+		{
+			name:     "unselectable field",
+			req:      dto.PaginationRequest{Fields: "id,created_at"},
+			wantErr:  true,
+			errorMsg: `cannot select "created_at"`,
+		},
+		{
+			name:     "unknown count mode",
+			req:      dto.PaginationRequest{Count: "approximate"},
+			wantErr:  true,
+			errorMsg: `unknown count mode "approximate"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				list, err := userQuerySchema.Build(tt.req)
+				if tt.wantErr {
+					assert.ErrorIs(t, err, dto.ErrInvalidListQuery)
+					assert.ErrorContains(t, err, tt.errorMsg)
+					return
+				}
+				require.NoError(t, err)
+
+				var users []entity.User
+				stmt := db.Model(&entity.User{}).Scopes(list.Where, list.Order, list.Select).Find(&users).Statement
+				assert.Equal(t, tt.sql, stmt.SQL.String())
+				if tt.vars != nil {
+					assert.Equal(t, tt.vars, stmt.Vars)
+				} else {
+					assert.Empty(t, stmt.Vars)
+				}
+			},
+		)
+	}
+}
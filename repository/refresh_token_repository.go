@@ -12,12 +12,32 @@ import (
 // RefreshTokenRepository is an interface for managing refresh tokens in a database.
 // Create adds a new refresh token to the database.
 // FindByToken retrieves a refresh token record by its token value.
+// GetByUserID lists every refresh token issued to a specific user, including revoked ones.
+// GetByUserID lists every refresh token issued to the given user, oldest first, including revoked tokens.
+func (r *refreshTokenRepository) GetByUserID(ctx context.Context, tx *gorm.DB, userID string) (
+	[]entity.RefreshToken,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	var tokens []entity.RefreshToken
+	if err := tx.Unscoped().
+		Where("user_id = ?", userID).
+		Order("created_at").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
 // DeleteByUserID removes all refresh tokens associated with a specific user ID.
 // DeleteByToken deletes a refresh token by its token value.
 // DeleteExpired removes all expired refresh tokens from the database.
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error)
 	FindByToken(ctx context.Context, tx *gorm.DB, token string) (entity.RefreshToken, error)
+	GetByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]entity.RefreshToken, error)
 	DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error
 	DeleteByToken(ctx context.Context, tx *gorm.DB, token string) error
 	DeleteExpired(ctx context.Context, tx *gorm.DB) error
@@ -25,15 +45,14 @@ type RefreshTokenRepository interface {
 
 // refreshTokenRepository is a struct that implements the RefreshTokenRepository interface for managing refresh tokens.
 // It provides methods to create, retrieve, and delete refresh tokens in a database using GORM.
-// The struct contains a GORM DB pointer for database operations.
 type refreshTokenRepository struct {
-	db *gorm.DB
+	Repository[entity.RefreshToken]
 }
 
 // NewRefreshTokenRepository creates a new instance of RefreshTokenRepository for managing refresh tokens using GORM.
 func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
 	return &refreshTokenRepository{
-		db: db,
+		Repository: NewRepository[entity.RefreshToken](db),
 	}
 }
 
@@ -43,11 +62,7 @@ func (r *refreshTokenRepository) Create(
 	tx *gorm.DB,
 	token entity.RefreshToken,
 ) (entity.RefreshToken, error) {
-	if tx == nil {
-		tx = r.db
-	}
-
-	if err := tx.WithContext(ctx).Create(&token).Error; err != nil {
+	if err := r.Repository.Create(ctx, tx, &token); err != nil {
 		return entity.RefreshToken{}, err
 	}
 
@@ -59,12 +74,10 @@ func (r *refreshTokenRepository) FindByToken(ctx context.Context, tx *gorm.DB, t
 	entity.RefreshToken,
 	error,
 ) {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
 	var refreshToken entity.RefreshToken
-	if err := tx.WithContext(ctx).Where("token = ?", token).Preload("User").Take(&refreshToken).Error; err != nil {
+	if err := tx.Where("token = ?", token).Preload("User").Take(&refreshToken).Error; err != nil {
 		return entity.RefreshToken{}, err
 	}
 
@@ -73,11 +86,9 @@ func (r *refreshTokenRepository) FindByToken(ctx context.Context, tx *gorm.DB, t
 
 // DeleteByUserID removes all refresh tokens associated with the given user ID from the database. Returns an error if it fails.
 func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
-	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.RefreshToken{}).Error; err != nil {
+	if err := tx.Where("user_id = ?", userID).Delete(&entity.RefreshToken{}).Error; err != nil {
 		return err
 	}
 
@@ -86,11 +97,9 @@ func (r *refreshTokenRepository) DeleteByUserID(ctx context.Context, tx *gorm.DB
 
 // DeleteByToken deletes a refresh token record from the database based on the provided token string. Returns an error if it fails.
 func (r *refreshTokenRepository) DeleteByToken(ctx context.Context, tx *gorm.DB, token string) error {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
-	if err := tx.WithContext(ctx).Where("token = ?", token).Delete(&entity.RefreshToken{}).Error; err != nil {
+	if err := tx.Where("token = ?", token).Delete(&entity.RefreshToken{}).Error; err != nil {
 		return err
 	}
 
@@ -99,11 +108,9 @@ func (r *refreshTokenRepository) DeleteByToken(ctx context.Context, tx *gorm.DB,
 
 // DeleteExpired removes all refresh tokens from the database that have expired based on the `expires_at` timestamp.
 func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, tx *gorm.DB) error {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
-	if err := tx.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&entity.RefreshToken{}).Error; err != nil {
+	if err := tx.Where("expires_at < ?", time.Now()).Delete(&entity.RefreshToken{}).Error; err != nil {
 		return err
 	}
 
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository provides the database connection and the common CRUD operations for entities of type T. Repositories
+// embed it and add their own queries on top of DB.
+// Every method takes an optional transaction. When it is nil, the transaction carried by the context is used, so
+// repositories called inside TxManager.WithinTransaction join it without passing it along, and the database otherwise.
+type Repository[T any] struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a Repository for entities of type T stored in db.
+func NewRepository[T any](db *gorm.DB) Repository[T] {
+	return Repository[T]{
+		db: db,
+	}
+}
+
+// DB returns the connection to run a query on, bound to ctx: tx when it is given, otherwise the transaction carried by
+// ctx, otherwise the database.
+func (r Repository[T]) DB(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	if tx == nil {
+		tx = TxFromContext(ctx)
+	}
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx)
+}
+
+// Create inserts value, filling in the fields set by hooks and defaults such as its ID.
+func (r Repository[T]) Create(ctx context.Context, tx *gorm.DB, value *T) error {
+	return r.DB(ctx, tx).Create(value).Error
+}
+
+// FindByID retrieves the entity whose primary key is id. It returns gorm.ErrRecordNotFound when there is none.
+func (r Repository[T]) FindByID(ctx context.Context, tx *gorm.DB, id any) (T, error) {
+	var value T
+	if err := r.DB(ctx, tx).Where(primaryKeyIs(id)).Take(&value).Error; err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// Find lists the entities selected by scopes, or every entity when no scope is given.
+func (r Repository[T]) Find(ctx context.Context, tx *gorm.DB, scopes ...func(*gorm.DB) *gorm.DB) ([]T, error) {
+	var values []T
+	if err := r.DB(ctx, tx).Scopes(scopes...).Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Updates writes the non-zero fields of value to the entity with the same primary key.
+func (r Repository[T]) Updates(ctx context.Context, tx *gorm.DB, value *T) error {
+	return r.DB(ctx, tx).Updates(value).Error
+}
+
+// Delete deletes the entity whose primary key is id. Entities with a gorm.DeletedAt field are soft-deleted.
+func (r Repository[T]) Delete(ctx context.Context, tx *gorm.DB, id any) error {
+	return r.DB(ctx, tx).Where(primaryKeyIs(id)).Delete(new(T)).Error
+}
+
+// primaryKeyIs is a condition matching the primary key of the queried table against id.
+func primaryKeyIs(id any) clause.Expression {
+	return clause.Eq{Column: clause.PrimaryColumn, Value: id}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// TestRepository_DB tests that repositories use the given transaction, then the transaction carried by the context,
+// then the database.
+func TestRepository_DB(t *testing.T) {
+	d := &recordingDriver{}
+	db := newRecordingDB(t, d)
+	repo := NewRepository[struct{}](db)
+
+	_, inTx := repo.DB(context.Background(), nil).Statement.ConnPool.(*sql.Tx)
+	assert.False(t, inTx)
+
+	err := NewTxManager(db).WithinTransaction(
+		context.Background(), func(ctx context.Context) error {
+			assert.Same(t, TxFromContext(ctx).Statement.ConnPool, repo.DB(ctx, nil).Statement.ConnPool)
+			assert.Same(t, db.Statement.ConnPool, repo.DB(ctx, db).Statement.ConnPool)
+			return repo.DB(ctx, nil).Exec("A").Error
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "A", "COMMIT"}, d.statements())
+}
+
+// TestRepository_Queries tests the statements of the common CRUD operations.
+func TestRepository_Queries(t *testing.T) {
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, SkipDefaultTransaction: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+
+	var statement string
+	capture := func(tx *gorm.DB) { statement = tx.Statement.SQL.String() }
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:query", capture))
+	require.NoError(t, db.Callback().Delete().After("gorm:delete").Register("test:delete", capture))
+
+	repo := NewRepository[entity.RefreshToken](db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func() error
+		sql  string
+	}{
+		{
+			name: "find by id",
+			run: func() error {
+				_, err := repo.FindByID(ctx, nil, "id-1")
+				return err
+			},
+			sql: `SELECT * FROM "refresh_tokens" WHERE "refresh_tokens"."id" = $1 ` +
+				`AND "refresh_tokens"."deleted_at" IS NULL LIMIT $2`,
+		},
+		{
+			name: "delete",
+			run: func() error {
+				return repo.Delete(ctx, nil, "id-1")
+			},
+			sql: `UPDATE "refresh_tokens" SET "deleted_at"=$1 WHERE "refresh_tokens"."id" = $2 ` +
+				`AND "refresh_tokens"."deleted_at" IS NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				statement = ""
+				assert.NoError(t, tt.run())
+				assert.Equal(t, tt.sql, statement)
+			},
+		)
+	}
+}
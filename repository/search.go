@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// SEARCH_MAX_WORDS caps the words of a search term that become part of its full-text query.
+	SEARCH_MAX_WORDS = 8
+
+	// SEARCH_MIN_DIGITS is the number of digits a search term needs before it is matched against phone numbers.
+	SEARCH_MIN_DIGITS = 4
+
+	// SEARCH_HIGHLIGHT_START and SEARCH_HIGHLIGHT_STOP delimit matches in ts_headline output. Control characters are
+	// used so that matches can be marked up after the rest of the text has been escaped.
+	SEARCH_HIGHLIGHT_START = "\x02"
+	SEARCH_HIGHLIGHT_STOP  = "\x03"
+
+	// SEARCH_HEADLINE_OPTIONS are the ts_headline options that mark every match with the highlight delimiters.
+	SEARCH_HEADLINE_OPTIONS = `StartSel="` + SEARCH_HIGHLIGHT_START + `", StopSel="` + SEARCH_HIGHLIGHT_STOP +
+		`", HighlightAll=true`
+)
+
+// searchTerm is a user-supplied search string prepared for full-text, trigram and phone number matching.
+// TSQuery matches every word of the term as a word prefix and is empty when the term has no words. Digits holds the
+// digits of the term when it has at least SEARCH_MIN_DIGITS of them.
+type searchTerm struct {
+	Text    string
+	TSQuery string
+	Digits  string
+}
+
+// newSearchTerm prepares a search string. Words keep only letters, digits and the characters found in email
+// addresses, so user input can never form tsquery operators.
+func newSearchTerm(text string) searchTerm {
+	term := searchTerm{Text: strings.TrimSpace(text)}
+
+	words := strings.FieldsFunc(
+		strings.ToLower(term.Text), func(r rune) bool {
+			return !isSearchWordRune(r) && !strings.ContainsRune(".@-_+", r)
+		},
+	)
+
+	parts := make([]string, 0, SEARCH_MAX_WORDS)
+	for _, word := range words {
+		word = strings.TrimFunc(
+			word, func(r rune) bool {
+				return !isSearchWordRune(r)
+			},
+		)
+		if word == "" {
+			continue
+		}
+
+		parts = append(parts, "'"+word+"':*")
+		if len(parts) == SEARCH_MAX_WORDS {
+			break
+		}
+	}
+	term.TSQuery = strings.Join(parts, " & ")
+
+	digits := strings.Map(
+		func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, term.Text,
+	)
+	if len(digits) >= SEARCH_MIN_DIGITS {
+		term.Digits = digits
+	}
+
+	return term
+}
+
+// tsquery returns the full-text query of the term as a query variable, which is NULL when the term has no words.
+func (t searchTerm) tsquery() any {
+	if t.TSQuery == "" {
+		return nil
+	}
+
+	return t.TSQuery
+}
+
+// isSearchWordRune reports whether a rune is a letter or a digit.
+func isSearchWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// highlight turns ts_headline output into HTML with every match wrapped in <mark>. It returns an empty string when
+// the headline is missing or has no match.
+func highlight(headline *string) string {
+	if headline == nil || !strings.Contains(*headline, SEARCH_HIGHLIGHT_START) {
+		return ""
+	}
+
+	return strings.NewReplacer(
+		SEARCH_HIGHLIGHT_START, "<mark>",
+		SEARCH_HIGHLIGHT_STOP, "</mark>",
+	).Replace(html.EscapeString(*headline))
+}
+
+// highlightSubstring returns the value as HTML with the first occurrence of match wrapped in <mark>, or an empty
+// string when the value does not contain it.
+func highlightSubstring(value string, match string) string {
+	start := strings.Index(value, match)
+	if match == "" || start < 0 {
+		return ""
+	}
+
+	end := start + len(match)
+	return html.EscapeString(value[:start]) + "<mark>" + html.EscapeString(value[start:end]) + "</mark>" +
+		html.EscapeString(value[end:])
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// TestNewSearchTerm tests that search input becomes a prefix full-text query without tsquery syntax, and that phone
+// numbers are matched on digits only.
+func TestNewSearchTerm(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected searchTerm
+	}{
+		{
+			name:     "words become prefixes",
+			text:     "  Jane DOE ",
+			expected: searchTerm{Text: "Jane DOE", TSQuery: "'jane':* & 'doe':*"},
+		},
+		{
+			name:     "email address",
+			text:     "jane.doe@example.com",
+			expected: searchTerm{Text: "jane.doe@example.com", TSQuery: "'jane.doe@example.com':*"},
+		},
+		{
+			name:     "tsquery operators are dropped",
+			text:     "o'neil & !(admin | x:*) <-> y",
+			expected: searchTerm{Text: "o'neil & !(admin | x:*) <-> y", TSQuery: "'o':* & 'neil':* & 'admin':* & 'x':* & 'y':*"},
+		},
+		{
+			name: "phone number",
+			text: "+62 812-3456",
+			expected: searchTerm{
+				Text:    "+62 812-3456",
+				TSQuery: "'62':* & '812-3456':*",
+				Digits:  "628123456",
+			},
+		},
+		{
+			name:     "too few digits for a phone number",
+			text:     "agent 007",
+			expected: searchTerm{Text: "agent 007", TSQuery: "'agent':* & '007':*"},
+		},
+		{
+			name:     "no words",
+			text:     "-- @@ ..",
+			expected: searchTerm{Text: "-- @@ .."},
+		},
+		{
+			name:     "unicode letters",
+			text:     "Renée",
+			expected: searchTerm{Text: "Renée", TSQuery: "'renée':*"},
+		},
+		{
+			name: "word limit",
+			text: "a b c d e f g h i j",
+			expected: searchTerm{
+				Text:    "a b c d e f g h i j",
+				TSQuery: "'a':* & 'b':* & 'c':* & 'd':* & 'e':* & 'f':* & 'g':* & 'h':*",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, newSearchTerm(tt.text))
+			},
+		)
+	}
+
+	assert.Nil(t, newSearchTerm("??").tsquery())
+	assert.Equal(t, "'x':*", newSearchTerm("x").tsquery())
+}
+
+// TestHighlight tests that headlines become escaped HTML with matches wrapped in <mark>.
+func TestHighlight(t *testing.T) {
+	headline := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		headline *string
+		expected string
+	}{
+		{
+			name:     "match",
+			headline: headline("\x02Jane\x03 <b>Doe</b>"),
+			expected: "<mark>Jane</mark> &lt;b&gt;Doe&lt;/b&gt;",
+		},
+		{name: "no match", headline: headline("Jane Doe")},
+		{name: "no headline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, highlight(tt.headline))
+			},
+		)
+	}
+}
+
+// TestHighlightSubstring tests that the first occurrence of a match is wrapped in <mark> and the rest is escaped.
+func TestHighlightSubstring(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		match    string
+		expected string
+	}{
+		{name: "match", value: "+62812345<6>", match: "8123", expected: "+62<mark>8123</mark>45&lt;6&gt;"},
+		{name: "no match", value: "+62812345", match: "9999"},
+		{name: "empty match", value: "+62812345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, highlightSubstring(tt.value, tt.match))
+			},
+		)
+	}
+}
+
+// TestUserSearchWhere tests that searches combine full-text, trigram and phone matches and bind the term as a
+// parameter.
+func TestUserSearchWhere(t *testing.T) {
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		text string
+		sql  string
+		vars []any
+	}{
+		{
+			name: "words and digits",
+			text: "jane 0812-3456",
+			sql: `($1 <% users.name OR $2 <% users.email OR users.search_vector @@ to_tsquery('simple', $3) ` +
+				`OR users.phone_number LIKE $4)`,
+			vars: []any{"jane 0812-3456", "jane 0812-3456", "'jane':* & '0812-3456':*", "%08123456%"},
+		},
+		{
+			name: "no words",
+			text: "'; DROP TABLE users; --",
+			sql:  `($1 <% users.name OR $2 <% users.email OR users.search_vector @@ to_tsquery('simple', $3))`,
+			vars: []any{"'; DROP TABLE users; --", "'; DROP TABLE users; --", "'drop':* & 'table':* & 'users':*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var users []entity.User
+				stmt := db.Model(&entity.User{}).Scopes(userSearchWhere(newSearchTerm(tt.text))).Find(&users).Statement
+				assert.Equal(
+					t, `SELECT * FROM "users" WHERE `+tt.sql+` AND "users"."deleted_at" IS NULL`, stmt.SQL.String(),
+				)
+				assert.Equal(t, tt.vars, stmt.Vars)
+			},
+		)
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	// TX_MAX_ATTEMPTS is how many times a transaction is run before a serialization failure is returned to the caller.
+	TX_MAX_ATTEMPTS = 3
+
+	// TX_RETRY_BACKOFF is the wait before the first retry of a transaction; it doubles with every further retry.
+	TX_RETRY_BACKOFF = 20 * time.Millisecond
+
+	// PG_SERIALIZATION_FAILURE and PG_DEADLOCK_DETECTED are the PostgreSQL error codes of transactions aborted by a
+	// conflict with a concurrent transaction, which succeed when they are run again.
+	PG_SERIALIZATION_FAILURE = "40001"
+	PG_DEADLOCK_DETECTED     = "40P01"
+)
+
+type (
+	// TxManager runs units of work in database transactions.
+	// WithinTransaction runs fn in a transaction carried by the context passed to fn, and commits it when fn returns nil
+	// or rolls it back when fn returns an error or panics. Called inside another transaction, it runs fn in a savepoint
+	// of that transaction instead, so an error rolls back only the work of fn. A top-level transaction failing with a
+	// serialization failure or a deadlock is rolled back and run again, up to TX_MAX_ATTEMPTS times, so fn must not
+	// have effects outside the database that cannot be repeated.
+	TxManager interface {
+		WithinTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error
+	}
+
+	// txManager implements TxManager with GORM transactions on db.
+	txManager struct {
+		db *gorm.DB
+	}
+
+	// txContextKey is the context key of the transaction carried by a context.
+	txContextKey struct{}
+)
+
+// TxRetryBackoff returns how long to wait before running a transaction again after its attempt failed.
+var TxRetryBackoff = func(attempt int) time.Duration {
+	return TX_RETRY_BACKOFF << (attempt - 1)
+}
+
+// NewTxManager creates a TxManager running transactions on db.
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+// ContextWithTx returns a copy of ctx carrying tx, which repositories use when they are not given a transaction.
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, or nil when there is none.
+func TxFromContext(ctx context.Context) *gorm.DB {
+	tx, _ := ctx.Value(txContextKey{}).(*gorm.DB)
+	return tx
+}
+
+// IsRetryableTxError reports whether err aborted a transaction because of a concurrent transaction, so that running
+// the transaction again can succeed.
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == PG_SERIALIZATION_FAILURE || pgErr.Code == PG_DEADLOCK_DETECTED
+}
+
+// WithinTransaction runs fn in a transaction, or in a savepoint when ctx already carries a transaction. The options
+// only apply to top-level transactions.
+func (m *txManager) WithinTransaction(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+	opts ...*sql.TxOptions,
+) error {
+	run := func(tx *gorm.DB) error {
+		return fn(ContextWithTx(ctx, tx))
+	}
+
+	if tx := TxFromContext(ctx); tx != nil {
+		return tx.WithContext(ctx).Transaction(run)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(run, opts...)
+		if err == nil || attempt == TX_MAX_ATTEMPTS || !IsRetryableTxError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(TxRetryBackoff(attempt)):
+		}
+	}
+}
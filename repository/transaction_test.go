@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recordingDriver is a database/sql driver that records the statements it runs. Statements listed in failures and
+// the commits listed in commitFailures fail with the given errors, each once.
+type recordingDriver struct {
+	mu             sync.Mutex
+	log            []string
+	failures       map[string][]error
+	commitFailures []error
+}
+
+// recordingConn is a connection of a recordingDriver.
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+// recordingTx is a transaction of a recordingDriver.
+type recordingTx struct {
+	driver *recordingDriver
+}
+
+// Open returns a new connection of the driver.
+func (d *recordingDriver) Open(string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+// Connect returns a new connection of the driver.
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+// Driver returns the driver itself.
+func (d *recordingDriver) Driver() driver.Driver {
+	return d
+}
+
+// record logs a statement and returns the next failure configured for it.
+func (d *recordingDriver) record(statement string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.log = append(d.log, regexp.MustCompile(`sp[0-9]+`).ReplaceAllString(statement, "sp"))
+	if failures := d.failures[statement]; len(failures) > 0 {
+		d.failures[statement] = failures[1:]
+		return failures[0]
+	}
+	if statement == "COMMIT" && len(d.commitFailures) > 0 {
+		err := d.commitFailures[0]
+		d.commitFailures = d.commitFailures[1:]
+		return err
+	}
+
+	return nil
+}
+
+// statements returns the statements run so far.
+func (d *recordingDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string(nil), d.log...)
+}
+
+// Prepare is not supported; statements are run through ExecContext.
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+// Close closes the connection.
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+// Begin starts a transaction.
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction.
+func (c *recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	if err := c.driver.record("BEGIN"); err != nil {
+		return nil, err
+	}
+
+	return &recordingTx{driver: c.driver}, nil
+}
+
+// ExecContext records a statement.
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.record(query); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+// Commit records the commit of the transaction.
+func (t *recordingTx) Commit() error {
+	return t.driver.record("COMMIT")
+}
+
+// Rollback records the rollback of the transaction.
+func (t *recordingTx) Rollback() error {
+	return t.driver.record("ROLLBACK")
+}
+
+// newRecordingDB opens a GORM connection on a recordingDriver.
+func newRecordingDB(t *testing.T, d *recordingDriver) *gorm.DB {
+	conn := sql.OpenDB(d)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{Conn: conn}),
+		&gorm.Config{SkipDefaultTransaction: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+
+	return db
+}
+
+// exec runs a statement on the transaction carried by ctx.
+func exec(ctx context.Context, statement string) error {
+	return TxFromContext(ctx).Exec(statement).Error
+}
+
+// TestTxManager_WithinTransaction tests commits, rollbacks, savepoints and retries of transactions.
+func TestTxManager_WithinTransaction(t *testing.T) {
+	backoff := TxRetryBackoff
+	TxRetryBackoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { TxRetryBackoff = backoff })
+
+	serializationFailure := &pgconn.PgError{Code: PG_SERIALIZATION_FAILURE}
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name           string
+		failures       map[string][]error
+		commitFailures []error
+		fn             func(ctx context.Context, tm TxManager) error
+		err            error
+		statements     []string
+	}{
+		{
+			name: "commit",
+			fn: func(ctx context.Context, _ TxManager) error {
+				return exec(ctx, "A")
+			},
+			statements: []string{"BEGIN", "A", "COMMIT"},
+		},
+		{
+			name: "rollback on error",
+			fn: func(ctx context.Context, _ TxManager) error {
+				if err := exec(ctx, "A"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			err:        errFailed,
+			statements: []string{"BEGIN", "A", "ROLLBACK"},
+		},
+		{
+			name: "nested transactions use savepoints",
+			fn: func(ctx context.Context, tm TxManager) error {
+				if err := exec(ctx, "A"); err != nil {
+					return err
+				}
+
+				err := tm.WithinTransaction(
+					ctx, func(ctx context.Context) error {
+						if err := exec(ctx, "B"); err != nil {
+							return err
+						}
+						return errFailed
+					},
+				)
+				if !errors.Is(err, errFailed) {
+					return errors.New("nested error was not returned")
+				}
+
+				return tm.WithinTransaction(
+					ctx, func(ctx context.Context) error {
+						return exec(ctx, "C")
+					},
+				)
+			},
+			statements: []string{
+				"BEGIN", "A",
+				"SAVEPOINT sp", "B", "ROLLBACK TO SAVEPOINT sp",
+				"SAVEPOINT sp", "C",
+				"COMMIT",
+			},
+		},
+		{
+			name:     "serialization failures are retried",
+			failures: map[string][]error{"A": {serializationFailure}},
+			fn: func(ctx context.Context, _ TxManager) error {
+				return exec(ctx, "A")
+			},
+			statements: []string{"BEGIN", "A", "ROLLBACK", "BEGIN", "A", "COMMIT"},
+		},
+		{
+			name:           "failed commits are retried",
+			commitFailures: []error{&pgconn.PgError{Code: PG_DEADLOCK_DETECTED}},
+			fn: func(ctx context.Context, _ TxManager) error {
+				return exec(ctx, "A")
+			},
+			statements: []string{"BEGIN", "A", "COMMIT", "BEGIN", "A", "COMMIT"},
+		},
+		{
+			name: "retries are limited",
+			failures: map[string][]error{
+				"A": {serializationFailure, serializationFailure, serializationFailure, serializationFailure},
+			},
+			fn: func(ctx context.Context, _ TxManager) error {
+				return exec(ctx, "A")
+			},
+			err: serializationFailure,
+			statements: []string{
+				"BEGIN", "A", "ROLLBACK",
+				"BEGIN", "A", "ROLLBACK",
+				"BEGIN", "A", "ROLLBACK",
+			},
+		},
+		{
+			name:     "other errors are not retried",
+			failures: map[string][]error{"A": {errFailed}},
+			fn: func(ctx context.Context, _ TxManager) error {
+				return exec(ctx, "A")
+			},
+			err:        errFailed,
+			statements: []string{"BEGIN", "A", "ROLLBACK"},
+		},
+		{
+			name:     "nested transactions are retried with the whole transaction",
+			failures: map[string][]error{"B": {serializationFailure}},
+			fn: func(ctx context.Context, tm TxManager) error {
+				return tm.WithinTransaction(
+					ctx, func(ctx context.Context) error {
+						return exec(ctx, "B")
+					},
+				)
+			},
+			statements: []string{
+				"BEGIN", "SAVEPOINT sp", "B", "ROLLBACK TO SAVEPOINT sp", "ROLLBACK",
+				"BEGIN", "SAVEPOINT sp", "B", "COMMIT",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				d := &recordingDriver{failures: tt.failures, commitFailures: tt.commitFailures}
+				tm := NewTxManager(newRecordingDB(t, d))
+
+				err := tm.WithinTransaction(
+					context.Background(), func(ctx context.Context) error {
+						return tt.fn(ctx, tm)
+					},
+				)
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+				} else {
+					assert.NoError(t, err)
+				}
+				assert.Equal(t, tt.statements, d.statements())
+			},
+		)
+	}
+
+	t.Run(
+		"panics roll back", func(t *testing.T) {
+			d := &recordingDriver{}
+			tm := NewTxManager(newRecordingDB(t, d))
+
+			assert.PanicsWithValue(
+				t, "boom", func() {
+					_ = tm.WithinTransaction(
+						context.Background(), func(ctx context.Context) error {
+							_ = exec(ctx, "A")
+							panic("boom")
+						},
+					)
+				},
+			)
+			assert.Equal(t, []string{"BEGIN", "A", "ROLLBACK"}, d.statements())
+		},
+	)
+}
+
+// TestIsRetryableTxError tests which errors abort a transaction because of a concurrent transaction.
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "serialization failure", err: &pgconn.PgError{Code: PG_SERIALIZATION_FAILURE}, expected: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: PG_DEADLOCK_DETECTED}, expected: true},
+		{name: "wrapped", err: errors.Join(errors.New("x"), &pgconn.PgError{Code: "40001"}), expected: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "other error", err: errors.New("failed")},
+		{name: "no error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, IsRetryableTxError(tt.err))
+			},
+		)
+	}
+}
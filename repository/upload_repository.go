@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+type (
+	// UploadRepository defines the database operations of resumable uploads.
+	// Create records a new upload, GetById fetches an upload, Lock fetches and locks an upload for the rest of the
+	// transaction, Save persists its progress, Delete removes it, GetExpired lists uploads past their expiry time and
+	// GetByUserId lists the uploads of a user.
+	UploadRepository interface {
+		Create(ctx context.Context, tx *gorm.DB, upload entity.Upload) (entity.Upload, error)
+		GetById(ctx context.Context, tx *gorm.DB, id string) (entity.Upload, error)
+		Lock(ctx context.Context, tx *gorm.DB, id string) (entity.Upload, error)
+		Save(ctx context.Context, tx *gorm.DB, upload entity.Upload) (entity.Upload, error)
+		Delete(ctx context.Context, tx *gorm.DB, id string) error
+		GetExpired(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.Upload, error)
+		GetByUserId(ctx context.Context, tx *gorm.DB, userId string) ([]entity.Upload, error)
+	}
+
+	// uploadRepository implements UploadRepository using GORM.
+	uploadRepository struct {
+		Repository[entity.Upload]
+	}
+)
+
+// NewUploadRepository creates a new UploadRepository backed by the given GORM connection.
+func NewUploadRepository(db *gorm.DB) UploadRepository {
+	return &uploadRepository{
+		Repository: NewRepository[entity.Upload](db),
+	}
+}
+
+// Create inserts a new upload and returns it with its generated ID.
+func (r *uploadRepository) Create(ctx context.Context, tx *gorm.DB, upload entity.Upload) (entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	if err := tx.Create(&upload).Error; err != nil {
+		return entity.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// GetById retrieves an upload by its ID.
+func (r *uploadRepository) GetById(ctx context.Context, tx *gorm.DB, id string) (entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	var upload entity.Upload
+	if err := tx.Where("id = ?", id).Take(&upload).Error; err != nil {
+		return entity.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// Lock retrieves an upload by its ID and locks it with FOR UPDATE, so concurrent chunks for the same upload are
+// applied one at a time. It must run inside a transaction.
+func (r *uploadRepository) Lock(ctx context.Context, tx *gorm.DB, id string) (entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	var upload entity.Upload
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&upload).Error; err != nil {
+		return entity.Upload{}, err
+	}
+
+	return upload, nil
+}
+
+// Save writes the progress, status, storage key and expiry time of an upload and increments its version, provided
+// the stored version of the upload is still upload.Version. It returns dto.ErrUploadVersionConflict when the upload
+// was saved since it was read, or no longer exists.
+func (r *uploadRepository) Save(ctx context.Context, tx *gorm.DB, upload entity.Upload) (entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	result := tx.Model(&entity.Upload{}).
+		Where("id = ? AND version = ?", upload.ID, upload.Version).
+		Updates(
+			map[string]any{
+				"received":     upload.Received,
+				"status":       upload.Status,
+				"storage_key":  upload.StorageKey,
+				"expires_at":   upload.ExpiresAt,
+				"completed_at": upload.CompletedAt,
+				"version":      gorm.Expr("version + 1"),
+			},
+		)
+	if result.Error != nil {
+		return entity.Upload{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.Upload{}, dto.ErrUploadVersionConflict
+	}
+
+	upload.Version++
+	return upload, nil
+}
+
+// Delete removes the upload identified by id.
+func (r *uploadRepository) Delete(ctx context.Context, tx *gorm.DB, id string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Delete(&entity.Upload{}, "id = ?", id).Error
+}
+
+// GetExpired lists up to limit uploads whose expiry time is before now, oldest first.
+func (r *uploadRepository) GetExpired(
+	ctx context.Context,
+	tx *gorm.DB,
+	now time.Time,
+	limit int,
+) ([]entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	var uploads []entity.Upload
+	if err := tx.Where("expires_at < ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// GetByUserId lists every upload of the user identified by userId, pending or completed.
+func (r *uploadRepository) GetByUserId(ctx context.Context, tx *gorm.DB, userId string) ([]entity.Upload, error) {
+	tx = r.DB(ctx, tx)
+
+	var uploads []entity.Upload
+	if err := tx.Where("user_id = ?", userId).Find(&uploads).Error; err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 )
@@ -22,29 +27,127 @@ type (
 		GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error)
 		CheckEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error)
 		Update(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error)
+		UpdateColumns(ctx context.Context, tx *gorm.DB, user entity.User, columns ...string) (entity.User, error)
 		Delete(ctx context.Context, tx *gorm.DB, userId string) error
+		UpdateImageUrl(ctx context.Context, tx *gorm.DB, userId string, imageUrl string) error
+		SetEmailSuppressed(ctx context.Context, tx *gorm.DB, email string, suppressed bool) error
+		GetUserByIdWithDeleted(ctx context.Context, tx *gorm.DB, userId string) (entity.User, error)
+		GetRestorableUsersByEmail(ctx context.Context, tx *gorm.DB, email string) ([]entity.User, error)
+		UpdateStatus(ctx context.Context, tx *gorm.DB, user entity.User, from ...string) (bool, error)
+		GetPurgeable(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]entity.User, error)
+		Purge(ctx context.Context, tx *gorm.DB, userId string) error
+		Search(ctx context.Context, tx *gorm.DB, req dto.UserSearchRequest) (dto.UserSearchRepositoryResponse, error)
 	}
 
 	// userRepository struct represents the implementation of UserRepository interface using GORM for database operations.
 	userRepository struct {
-		db *gorm.DB
+		Repository[entity.User]
+	}
+
+	// userSearchRow is a user read by a search, with its relevance and the ts_headline output of its matched fields.
+	userSearchRow struct {
+		entity.User
+		SearchRank    float64
+		NameHeadline  *string
+		EmailHeadline *string
 	}
 )
 
+// userQuerySchema lists the user fields that list requests may filter, sort and select. Fields match the JSON names of
+// dto.UserResponse, except created_at and updated_at, which can be filtered and sorted on but are not returned.
+var userQuerySchema = QuerySchema{
+	Fields: map[string]QueryField{
+		"id": {
+			Column:    "id",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE, dto.FILTER_OPERATOR_IN},
+			Select:    []string{"id"},
+		},
+		"name": {
+			Column:    "name",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE, dto.FILTER_OPERATOR_LIKE},
+			Sortable:  true,
+			Select:    []string{"name"},
+		},
+		"email": {
+			Column:    "email",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE, dto.FILTER_OPERATOR_LIKE},
+			Sortable:  true,
+			Select:    []string{"email"},
+		},
+		"phone_number": {
+			Column:    "phone_number",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_LIKE},
+			Select:    []string{"phone_number"},
+		},
+		"role": {
+			Column:    "role",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE, dto.FILTER_OPERATOR_IN},
+			Sortable:  true,
+			Select:    []string{"role"},
+		},
+		"is_verified": {
+			Column:    "is_verified",
+			Type:      QUERY_TYPE_BOOL,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE},
+			Sortable:  true,
+			Select:    []string{"is_verified"},
+		},
+		"email_suppressed": {
+			Column:    "email_suppressed",
+			Type:      QUERY_TYPE_BOOL,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE},
+			Select:    []string{"email_suppressed"},
+		},
+		"status": {
+			Column:    "status",
+			Type:      QUERY_TYPE_TEXT,
+			Operators: []string{dto.FILTER_OPERATOR_EQ, dto.FILTER_OPERATOR_NE, dto.FILTER_OPERATOR_IN},
+			Sortable:  true,
+			Select:    []string{"status"},
+		},
+		"image_url":        {Select: []string{"image_url"}},
+		"image_thumbnails": {Select: []string{"image_url"}},
+		"purge_at": {
+			Column:    "purge_at",
+			Type:      QUERY_TYPE_TIME,
+			Operators: []string{dto.FILTER_OPERATOR_GTE, dto.FILTER_OPERATOR_LTE},
+			Sortable:  true,
+			Nullable:  true,
+			Select:    []string{"purge_at"},
+		},
+		"created_at": {
+			Column:    "created_at",
+			Type:      QUERY_TYPE_TIME,
+			Operators: []string{dto.FILTER_OPERATOR_GTE, dto.FILTER_OPERATOR_LTE},
+			Sortable:  true,
+		},
+		"updated_at": {
+			Column:    "updated_at",
+			Type:      QUERY_TYPE_TIME,
+			Operators: []string{dto.FILTER_OPERATOR_GTE, dto.FILTER_OPERATOR_LTE},
+			Sortable:  true,
+		},
+	},
+	Search:      []string{"name", "email"},
+	DefaultSort: []string{"created_at"},
+	Key:         "id",
+}
+
 // NewUserRepository initializes and returns a new instance of UserRepository with the provided GORM database connection.
 func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{
-		db: db,
+		Repository: NewRepository[entity.User](db),
 	}
 }
 
 // Register inserts a new user record into the database and returns the created user or an error if the operation fails.
 func (r *userRepository) Register(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
-	if tx == nil {
-		tx = r.db
-	}
-
-	if err := tx.WithContext(ctx).Create(&user).Error; err != nil {
+	if err := r.Create(ctx, tx, &user); err != nil {
 		return entity.User{}, err
 	}
 
@@ -52,54 +155,40 @@ func (r *userRepository) Register(ctx context.Context, tx *gorm.DB, user entity.
 }
 
 // GetAllUserWithPagination retrieves a paginated list of users and total count based on the provided pagination request.
+// The search, filters, sort order and fields of the request are limited to userQuerySchema, and a cursor in the request
+// continues from an earlier page.
 func (r *userRepository) GetAllUserWithPagination(
 	ctx context.Context,
 	tx *gorm.DB,
 	req dto.PaginationRequest,
 ) (dto.GetAllUserRepositoryResponse, error) {
-	if tx == nil {
-		tx = r.db
-	}
-
-	var users []entity.User
-	var err error
-	var count int64
-
-	req.Default()
+	tx = r.DB(ctx, tx)
 
-	query := tx.WithContext(ctx).Model(&entity.User{})
-	if req.Search != "" {
-		query = query.Where("name LIKE ?", "%"+req.Search+"%")
-	}
-
-	if err := query.Count(&count).Error; err != nil {
+	list, err := userQuerySchema.Build(req)
+	if err != nil {
 		return dto.GetAllUserRepositoryResponse{}, err
 	}
 
-	if err := query.Scopes(Paginate(req)).Find(&users).Error; err != nil {
+	users, meta, err := FindPage[entity.User](tx.Model(&entity.User{}), list)
+	if err != nil {
 		return dto.GetAllUserRepositoryResponse{}, err
 	}
 
-	totalPage := TotalPage(count, int64(req.PerPage))
 	return dto.GetAllUserRepositoryResponse{
-		Users: users,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    req.Page,
-			PerPage: req.PerPage,
-			Count:   count,
-			MaxPage: totalPage,
-		},
-	}, err
+		Users:              users,
+		PaginationResponse: meta,
+	}, nil
 }
 
-// GetUserById retrieves a user by their unique ID using the provided context and database transaction.
+// GetUserById retrieves a user by their unique ID using the provided context and database transaction. An ID that is
+// not a UUID matches no user and returns gorm.ErrRecordNotFound.
 func (r *userRepository) GetUserById(ctx context.Context, tx *gorm.DB, userId string) (entity.User, error) {
-	if tx == nil {
-		tx = r.db
+	if _, err := uuid.Parse(userId); err != nil {
+		return entity.User{}, gorm.ErrRecordNotFound
 	}
 
-	var user entity.User
-	if err := tx.WithContext(ctx).Where("id = ?", userId).Take(&user).Error; err != nil {
+	user, err := r.FindByID(ctx, tx, userId)
+	if err != nil {
 		return entity.User{}, err
 	}
 
@@ -108,12 +197,10 @@ func (r *userRepository) GetUserById(ctx context.Context, tx *gorm.DB, userId st
 
 // GetUserByEmail retrieves a user record from the database by email using the provided context and transaction.
 func (r *userRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error) {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
 	var user entity.User
-	if err := tx.WithContext(ctx).Where("email = ?", email).Take(&user).Error; err != nil {
+	if err := tx.Where("email = ?", email).Take(&user).Error; err != nil {
 		return entity.User{}, err
 	}
 
@@ -122,26 +209,59 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email
 
 // CheckEmail verifies the existence of a user by email and returns the user entity and a boolean indicating existence.
 func (r *userRepository) CheckEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error) {
-	if tx == nil {
-		tx = r.db
-	}
+	tx = r.DB(ctx, tx)
 
 	var user entity.User
-	if err := tx.WithContext(ctx).Where("email = ?", email).Take(&user).Error; err != nil {
+	if err := tx.Where("email = ?", email).Take(&user).Error; err != nil {
 		return entity.User{}, false, err
 	}
 
 	return user, true, nil
 }
 
-// Update updates an existing user record in the database and returns the updated user or an error if the operation fails.
+// Update writes the non-zero fields of user and increments its version, provided the stored version of the user is
+// still user.Version. It returns dto.ErrVersionConflict when the user was updated since it was read, or no longer
+// exists.
 func (r *userRepository) Update(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
-	if tx == nil {
-		tx = r.db
+	tx = r.DB(ctx, tx)
+
+	version := user.Version
+	user.Version++
+
+	result := tx.Model(&user).Where("version = ?", version).Updates(&user)
+	if result.Error != nil {
+		return entity.User{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.User{}, dto.ErrVersionConflict
 	}
 
-	if err := tx.WithContext(ctx).Updates(&user).Error; err != nil {
-		return entity.User{}, err
+	return user, nil
+}
+
+// UpdateColumns writes the given columns of user, including zero values, and increments its version, provided the
+// stored version of the user is still user.Version. It returns dto.ErrVersionConflict when the user was updated since
+// it was read, or no longer exists.
+func (r *userRepository) UpdateColumns(
+	ctx context.Context,
+	tx *gorm.DB,
+	user entity.User,
+	columns ...string,
+) (entity.User, error) {
+	tx = r.DB(ctx, tx)
+
+	version := user.Version
+	user.Version++
+
+	result := tx.Model(&user).
+		Select(append(slices.Clone(columns), "version", "updated_at")).
+		Where("version = ?", version).
+		Updates(&user)
+	if result.Error != nil {
+		return entity.User{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.User{}, dto.ErrVersionConflict
 	}
 
 	return user, nil
@@ -149,13 +269,217 @@ func (r *userRepository) Update(ctx context.Context, tx *gorm.DB, user entity.Us
 
 // Delete removes a user identified by userId from the database, using the provided context and optional transaction.
 func (r *userRepository) Delete(ctx context.Context, tx *gorm.DB, userId string) error {
-	if tx == nil {
-		tx = r.db
+	return r.Repository.Delete(ctx, tx, userId)
+}
+
+// UpdateImageUrl sets the profile image key of the user identified by userId and increments its version; an empty
+// imageUrl removes the image.
+func (r *userRepository) UpdateImageUrl(ctx context.Context, tx *gorm.DB, userId string, imageUrl string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Model(&entity.User{}).
+		Where("id = ?", userId).
+		Updates(map[string]any{"image_url": imageUrl, "version": gorm.Expr("version + 1")}).Error
+}
+
+// SetEmailSuppressed flags or unflags the user owning email, compared case-insensitively, as being on the email
+// suppression list, and increments its version. It is a no-op when no user has that address.
+func (r *userRepository) SetEmailSuppressed(ctx context.Context, tx *gorm.DB, email string, suppressed bool) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Model(&entity.User{}).
+		Where("LOWER(email) = LOWER(?)", email).
+		Updates(map[string]any{"email_suppressed": suppressed, "version": gorm.Expr("version + 1")}).Error
+}
+
+// GetUserByIdWithDeleted retrieves a user by ID, including users that are soft-deleted because they are pending
+// deletion. An ID that is not a UUID matches no user and returns gorm.ErrRecordNotFound.
+func (r *userRepository) GetUserByIdWithDeleted(ctx context.Context, tx *gorm.DB, userId string) (entity.User, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return entity.User{}, gorm.ErrRecordNotFound
 	}
 
-	if err := tx.WithContext(ctx).Delete(&entity.User{}, "id = ?", userId).Error; err != nil {
-		return err
+	tx = r.DB(ctx, tx)
+
+	var user entity.User
+	if err := tx.Unscoped().Where("id = ?", userId).Take(&user).Error; err != nil {
+		return entity.User{}, err
 	}
 
-	return nil
+	return user, nil
+}
+
+// GetRestorableUsersByEmail lists the deactivated users and the users pending deletion that registered with email,
+// most recently changed first. Several users can share an address once older accounts are deleted.
+func (r *userRepository) GetRestorableUsersByEmail(ctx context.Context, tx *gorm.DB, email string) (
+	[]entity.User,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	var users []entity.User
+	if err := tx.Unscoped().
+		Where("email = ?", email).
+		Where("status IN ?", []string{constants.ENUM_USER_DEACTIVATED, constants.ENUM_USER_PENDING_DELETION}).
+		Order("updated_at DESC").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UpdateStatus writes the status, purge_at and deleted_at columns of user and increments its version, but only while
+// the stored status is one of from. It reports whether the user was in one of those states, so concurrent transitions cannot both succeed.
+func (r *userRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, user entity.User, from ...string) (
+	bool,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	result := tx.Unscoped().
+		Model(&entity.User{}).
+		Where("id = ? AND status IN ?", user.ID, from).
+		Updates(
+			map[string]any{
+				"status":     user.Status,
+				"purge_at":   user.PurgeAt,
+				"deleted_at": user.DeletedAt,
+				"version":    gorm.Expr("version + 1"),
+			},
+		)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetPurgeable lists up to limit users to purge: users pending deletion whose grace period ended before now, and
+// users already marked as purged whose removal was interrupted.
+func (r *userRepository) GetPurgeable(ctx context.Context, tx *gorm.DB, now time.Time, limit int) (
+	[]entity.User,
+	error,
+) {
+	tx = r.DB(ctx, tx)
+
+	var users []entity.User
+	if err := tx.Unscoped().
+		Where("status = ? AND purge_at <= ?", constants.ENUM_USER_PENDING_DELETION, now).
+		Or("status = ?", constants.ENUM_USER_PURGED).
+		Order("purge_at").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Purge permanently removes the user identified by userId. Rows referencing the user, such as refresh tokens and
+// uploads, are removed by their ON DELETE CASCADE foreign keys.
+func (r *userRepository) Purge(ctx context.Context, tx *gorm.DB, userId string) error {
+	tx = r.DB(ctx, tx)
+
+	return tx.Unscoped().Delete(&entity.User{}, "id = ?", userId).Error
+}
+
+// Search returns the users whose name, email address or phone number match a search term, most relevant first.
+// Words of the term match as prefixes through the generated search_vector column, names and email addresses also match
+// by pg_trgm word similarity to tolerate typos, and phone numbers match on the digits of the term.
+func (r *userRepository) Search(
+	ctx context.Context,
+	tx *gorm.DB,
+	req dto.UserSearchRequest,
+) (dto.UserSearchRepositoryResponse, error) {
+	tx = r.DB(ctx, tx)
+
+	page := req.Pagination()
+	page.Default()
+	term := newSearchTerm(req.Query)
+
+	query := tx.Model(&entity.User{}).Scopes(userSearchWhere(term))
+
+	var count int64
+	if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return dto.UserSearchRepositoryResponse{}, err
+	}
+
+	var rows []userSearchRow
+	if err := query.Session(&gorm.Session{}).
+		Scopes(userSearchSelect(term), Paginate(page)).
+		Order("search_rank DESC, users.id").
+		Scan(&rows).Error; err != nil {
+		return dto.UserSearchRepositoryResponse{}, err
+	}
+
+	hits := make([]dto.UserSearchHit, 0, len(rows))
+	for _, row := range rows {
+		highlights := make(map[string]string)
+		if name := highlight(row.NameHeadline); name != "" {
+			highlights["name"] = name
+		}
+		if email := highlight(row.EmailHeadline); email != "" {
+			highlights["email"] = email
+		}
+		if phone := highlightSubstring(row.PhoneNumber, term.Digits); phone != "" {
+			highlights["phone_number"] = phone
+		}
+
+		hits = append(
+			hits, dto.UserSearchHit{
+				User:       row.User,
+				Rank:       row.SearchRank,
+				Highlights: highlights,
+			},
+		)
+	}
+
+	return dto.UserSearchRepositoryResponse{
+		Hits: hits,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    page.Page,
+			PerPage: page.PerPage,
+			Count:   count,
+			MaxPage: TotalPage(count, int64(page.PerPage)),
+		},
+	}, nil
+}
+
+// userSearchWhere matches the users a search term finds. Every condition can be served by a GIN index.
+func userSearchWhere(term searchTerm) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		matches := []clause.Expression{
+			clause.Expr{SQL: "? <% users.name", Vars: []any{term.Text}},
+			clause.Expr{SQL: "? <% users.email", Vars: []any{term.Text}},
+		}
+		if term.TSQuery != "" {
+			matches = append(
+				matches, clause.Expr{SQL: "users.search_vector @@ to_tsquery('simple', ?)", Vars: []any{term.TSQuery}},
+			)
+		}
+		if term.Digits != "" {
+			matches = append(
+				matches, clause.Expr{SQL: "users.phone_number LIKE ?", Vars: []any{"%" + term.Digits + "%"}},
+			)
+		}
+
+		return db.Where(clause.Or(matches...))
+	}
+}
+
+// userSearchSelect selects the users found by a search term with their relevance, the full-text rank plus the best
+// trigram word similarity, and the highlighted name and email address.
+func userSearchSelect(term searchTerm) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tsquery := term.tsquery()
+		return db.Select(
+			"users.*, "+
+				"coalesce(ts_rank(users.search_vector, to_tsquery('simple', ?)), 0) + "+
+				"greatest(word_similarity(?, users.name), word_similarity(?, users.email)) AS search_rank, "+
+				"ts_headline('simple', users.name, to_tsquery('simple', ?), ?) AS name_headline, "+
+				"ts_headline('simple', users.email, to_tsquery('simple', ?), ?) AS email_headline",
+			tsquery, term.Text, term.Text, tsquery, SEARCH_HEADLINE_OPTIONS, tsquery, SEARCH_HEADLINE_OPTIONS,
+		)
+	}
 }
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// Admin registers the administrator-only routes, such as inspecting and requeueing outbox emails, clearing
+// suppressed email addresses and restoring user accounts.
+var Admin = func(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	userService := do.MustInvoke[service.UserService](injector)
+	userController := do.MustInvoke[controller.UserController](injector)
+	outboxController := do.MustInvoke[controller.EmailOutboxController](injector)
+	suppressionController := do.MustInvoke[controller.EmailSuppressionController](injector)
+
+	routes := route.Group(
+		"/api/admin",
+		middleware.Authenticate(jwtService),
+		middleware.RequireRole(userService, constants.ENUM_ROLE_ADMIN),
+	)
+	{
+		routes.GET("/email-outbox", outboxController.GetAll)
+		routes.POST("/email-outbox/:id/requeue", outboxController.Requeue)
+		routes.GET("/email-suppressions", suppressionController.GetAll)
+		routes.DELETE("/email-suppressions/:email", suppressionController.Clear)
+		routes.POST("/users/:id/restore", userController.RestoreById)
+	}
+}
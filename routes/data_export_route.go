@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// DataExport registers the routes through which authenticated users request and follow exports of their data.
+var DataExport = func(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	exportController := do.MustInvoke[controller.DataExportController](injector)
+
+	routes := route.Group("/api/user/export", middleware.Authenticate(jwtService))
+	{
+		routes.POST("", exportController.Request)
+		routes.GET("/:id", exportController.Get)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+)
+
+// Dev registers development-only routes, such as the email template preview. It is only called when APP_ENV is dev.
+var Dev = func(route *gin.Engine, injector *do.Injector) {
+	mailPreviewController := do.MustInvoke[controller.MailPreviewController](injector)
+
+	routes := route.Group("/api/dev")
+	{
+		routes.GET("/mail", mailPreviewController.List)
+		routes.GET("/mail/:template", mailPreviewController.Preview)
+	}
+}
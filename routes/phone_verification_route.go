@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// PhoneVerification registers the routes through which authenticated users verify their phone number.
+var PhoneVerification = func(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	verificationController := do.MustInvoke[controller.PhoneVerificationController](injector)
+
+	routes := route.Group("/api/user/phone", middleware.Authenticate(jwtService))
+	{
+		routes.POST("/send_code", verificationController.SendCode)
+		routes.POST("/verify", verificationController.Verify)
+	}
+}
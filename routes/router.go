@@ -1,11 +1,26 @@
 package routes
 
 import (
+	"os"
+
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 )
 
 // RegisterRoutes initializes and registers all application-level routes with the provided Gin engine and dependency injector.
+// Development-only routes are registered when APP_ENV is dev.
 var RegisterRoutes = func(server *gin.Engine, injector *do.Injector) {
 	User(server, injector)
+	Admin(server, injector)
+	Webhook(server, injector)
+	Storage(server, injector)
+	Upload(server, injector)
+	DataExport(server, injector)
+	PhoneVerification(server, injector)
+
+	if os.Getenv("APP_ENV") == constants.ENUM_RUN_DEVELOPMENT {
+		Dev(server, injector)
+	}
 }
@@ -19,10 +19,65 @@ func (m *MockUserRouteRegistrar) User(server *gin.Engine, injector *do.Injector)
 	m.Called(server, injector)
 }
 
+// stubRouteGroups replaces the admin, webhook, storage, upload, data export and phone verification route registrars
+// with no-ops for the duration of a test.
+func stubRouteGroups(t *testing.T) {
+	originalAdmin, originalWebhook, originalStorage := Admin, Webhook, Storage
+	originalUpload, originalDataExport, originalPhoneVerification := Upload, DataExport, PhoneVerification
+	Admin = func(server *gin.Engine, injector *do.Injector) {}
+	Webhook = func(server *gin.Engine, injector *do.Injector) {}
+	Storage = func(server *gin.Engine, injector *do.Injector) {}
+	Upload = func(server *gin.Engine, injector *do.Injector) {}
+	DataExport = func(server *gin.Engine, injector *do.Injector) {}
+	PhoneVerification = func(server *gin.Engine, injector *do.Injector) {}
+	t.Cleanup(
+		func() {
+			Admin, Webhook, Storage = originalAdmin, originalWebhook, originalStorage
+			Upload, DataExport, PhoneVerification = originalUpload, originalDataExport, originalPhoneVerification
+		},
+	)
+}
+
+// TestRegisterRoutes_Dev verifies that the development routes are only registered when APP_ENV is dev.
+func TestRegisterRoutes_Dev(t *testing.T) {
+	stubRouteGroups(t)
+
+	originalUser, originalDev := User, Dev
+	t.Cleanup(func() { User, Dev = originalUser, originalDev })
+	User = func(server *gin.Engine, injector *do.Injector) {}
+
+	tests := []struct {
+		name    string
+		appEnv  string
+		wantDev bool
+	}{
+		{name: "registered in dev", appEnv: "dev", wantDev: true},
+		{name: "skipped in prod", appEnv: "prod", wantDev: false},
+		{name: "skipped in test", appEnv: "test", wantDev: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Setenv("APP_ENV", tt.appEnv)
+
+				called := false
+				Dev = func(server *gin.Engine, injector *do.Injector) { called = true }
+
+				RegisterRoutes(gin.New(), do.New())
+
+				assert.Equal(t, tt.wantDev, called)
+			},
+		)
+	}
+}
+
 // TestRegisterRoutes tests the RegisterRoutes function to ensure routing logic and dependency injection work as expected.
 func TestRegisterRoutes(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
 	mockEngine := gin.Default()
 	mockInjector := do.New()
+	stubRouteGroups(t)
 
 	t.Run(
 		"Successfully registers routes", func(t *testing.T) {
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+)
+
+// Storage registers the route that serves stored files through the signed URLs of the local storage driver.
+var Storage = func(route *gin.Engine, injector *do.Injector) {
+	storageController := do.MustInvoke[controller.StorageController](injector)
+
+	routes := route.Group("/api/storage")
+	{
+		routes.GET("/*key", storageController.Download)
+	}
+}
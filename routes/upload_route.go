@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+)
+
+// Upload registers the routes of the resumable upload protocol for authenticated users.
+var Upload = func(route *gin.Engine, injector *do.Injector) {
+	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	uploadController := do.MustInvoke[controller.UploadController](injector)
+
+	routes := route.Group("/api/uploads", middleware.Authenticate(jwtService))
+	{
+		routes.POST("", uploadController.Create)
+		routes.GET("/:id", uploadController.Get)
+		routes.HEAD("/:id", uploadController.Get)
+		routes.PATCH("/:id", uploadController.WriteChunk)
+		routes.DELETE("/:id", uploadController.Cancel)
+		routes.POST("/:id/complete", uploadController.Complete)
+		routes.POST("/:id/avatar", uploadController.AttachAvatar)
+	}
+}
@@ -13,18 +13,35 @@ import (
 // User registers the routes for user-related operations such as registration, login, and profile management.
 var User = func(route *gin.Engine, injector *do.Injector) {
 	jwtService := do.MustInvokeNamed[service.JWTService](injector, constants.JWTService)
+	userService := do.MustInvoke[service.UserService](injector)
 	userController := do.MustInvoke[controller.UserController](injector)
 
 	routes := route.Group("/api/user")
 	{
 		routes.POST("", userController.Register)
-		routes.GET("", userController.GetAllUser)
+		routes.GET(
+			"",
+			middleware.Authenticate(jwtService),
+			middleware.RequireRole(userService, constants.ENUM_ROLE_ADMIN),
+			userController.GetAllUser,
+		)
+		routes.GET(
+			"/search",
+			middleware.Authenticate(jwtService),
+			middleware.RequireRole(userService, constants.ENUM_ROLE_ADMIN),
+			userController.Search,
+		)
 		routes.POST("/login", userController.Login)
 		routes.POST("/refresh", userController.Refresh)
+		routes.POST("/restore", userController.Restore)
 		routes.DELETE("", middleware.Authenticate(jwtService), userController.Delete)
+		routes.POST("/deactivate", middleware.Authenticate(jwtService), userController.Deactivate)
 		routes.PATCH("", middleware.Authenticate(jwtService), userController.Update)
 		routes.GET("/me", middleware.Authenticate(jwtService), userController.Me)
+		routes.PUT("/avatar", middleware.Authenticate(jwtService), userController.UpdateAvatar)
+		routes.DELETE("/avatar", middleware.Authenticate(jwtService), userController.DeleteAvatar)
 		routes.POST("/verify_email", userController.VerifyEmail)
 		routes.POST("/send_verification_email", userController.SendVerificationEmail)
+		routes.POST("/email/confirm", userController.ConfirmEmailChange)
 	}
 }
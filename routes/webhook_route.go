@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/do"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+)
+
+// Webhook registers the routes called by external providers, such as email bounce and complaint notifications.
+// Requests must be signed with EMAIL_WEBHOOK_SECRET; every request is rejected while it is unset.
+var Webhook = func(route *gin.Engine, injector *do.Injector) {
+	suppressionController := do.MustInvoke[controller.EmailSuppressionController](injector)
+
+	routes := route.Group(
+		"/api/webhooks/email",
+		middleware.VerifyWebhookSignature(os.Getenv("EMAIL_WEBHOOK_SECRET")),
+	)
+	{
+		routes.POST("/events", suppressionController.HandleEvents)
+		routes.POST("/dsn", suppressionController.HandleDSN)
+	}
+}
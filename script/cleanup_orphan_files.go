@@ -0,0 +1,196 @@
+package script
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/media"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+type (
+	// CleanupOrphanFilesScript deletes stored files that nothing references, such as images left behind by interrupted
+	// uploads. Files younger than min_age are kept so uploads whose user has not been saved yet are not removed.
+	CleanupOrphanFilesScript struct {
+		newDriver func() (storage.Driver, error)
+	}
+
+	// referencedSet holds the storage keys that are referenced, and the prefixes below which every key is.
+	referencedSet struct {
+		keys     map[string]bool
+		prefixes []string
+	}
+)
+
+// init registers the orphan file cleanup script with the script registry.
+func init() {
+	Register(NewCleanupOrphanFilesScript(newStorageDriver))
+}
+
+// newStorageDriver creates the storage driver selected by the STORAGE_* environment variables.
+var newStorageDriver = func() (storage.Driver, error) {
+	cfg, err := config.NewStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.New(cfg)
+}
+
+// referencedFiles returns the storage keys of every file the database points at: profile images and their thumbnails,
+// assembled uploads, the chunks of uploads in progress and data export archives. Users pending deletion are included,
+// so their images survive until the account is purged. Expired uploads and exports are left to their janitors.
+var referencedFiles = func(db *gorm.DB) (referencedSet, error) {
+	var imageKeys []string
+	if err := db.Unscoped().
+		Model(&entity.User{}).
+		Where("image_url <> ''").
+		Pluck("image_url", &imageKeys).Error; err != nil {
+		return referencedSet{}, err
+	}
+
+	var uploadKeys []string
+	if err := db.Model(&entity.Upload{}).
+		Where("storage_key <> ''").
+		Pluck("storage_key", &uploadKeys).Error; err != nil {
+		return referencedSet{}, err
+	}
+
+	var pendingUploadIDs []uuid.UUID
+	if err := db.Model(&entity.Upload{}).
+		Where("status = ?", constants.ENUM_UPLOAD_PENDING).
+		Pluck("id", &pendingUploadIDs).Error; err != nil {
+		return referencedSet{}, err
+	}
+
+	var exportKeys []string
+	if err := db.Model(&entity.DataExport{}).
+		Where("storage_key <> ''").
+		Pluck("storage_key", &exportKeys).Error; err != nil {
+		return referencedSet{}, err
+	}
+
+	referenced := referencedSet{
+		keys: make(map[string]bool, len(imageKeys)*(len(media.THUMBNAIL_SIZES)+1)+len(uploadKeys)+len(exportKeys)),
+	}
+	for _, key := range imageKeys {
+		referenced.keys[key] = true
+		for _, size := range media.THUMBNAIL_SIZES {
+			referenced.keys[media.ThumbnailKey(key, size)] = true
+		}
+	}
+	for _, key := range append(uploadKeys, exportKeys...) {
+		referenced.keys[key] = true
+	}
+	for _, id := range pendingUploadIDs {
+		referenced.prefixes = append(referenced.prefixes, service.UploadChunkPrefix(id))
+	}
+
+	return referenced, nil
+}
+
+// contains reports whether key is referenced, either by itself or by being below a referenced prefix.
+func (r referencedSet) contains(key string) bool {
+	if r.keys[key] {
+		return true
+	}
+
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewCleanupOrphanFilesScript initializes and returns a new instance of CleanupOrphanFilesScript that opens its
+// storage driver with newDriver.
+func NewCleanupOrphanFilesScript(newDriver func() (storage.Driver, error)) *CleanupOrphanFilesScript {
+	return &CleanupOrphanFilesScript{
+		newDriver: newDriver,
+	}
+}
+
+// Name returns the name used to invoke the script, e.g. script run cleanup_orphan_files.
+func (s *CleanupOrphanFilesScript) Name() string {
+	return "cleanup_orphan_files"
+}
+
+// Description returns a short summary of what the script does, shown by script list.
+func (s *CleanupOrphanFilesScript) Description() string {
+	return "Deletes stored files that nothing in the database references"
+}
+
+// Args returns the argument schema of the script.
+func (s *CleanupOrphanFilesScript) Args() []Arg {
+	return []Arg{
+		{Name: "prefix", Description: "Only inspect files whose key starts with this prefix", Default: "profile/"},
+		{Name: "min_age", Description: "Keep files modified more recently than this", Default: "24h"},
+	}
+}
+
+// Run lists the stored files below the prefix and deletes those that are unreferenced and older than min_age.
+// Storage is not transactional, so dry runs only report the files that would be deleted.
+func (s *CleanupOrphanFilesScript) Run(rc *RunContext) error {
+	minAge, err := rc.Args.Duration("min_age")
+	if err != nil {
+		return err
+	}
+
+	driver, err := s.newDriver()
+	if err != nil {
+		return err
+	}
+
+	objects, err := driver.List(rc.Context, rc.Args.String("prefix"))
+	if err != nil {
+		return err
+	}
+
+	referenced, err := referencedFiles(rc.DB)
+	if err != nil {
+		return err
+	}
+
+	orphans := orphanedFiles(objects, referenced, time.Now().Add(-minAge))
+	progress := rc.Progress(len(orphans))
+
+	var freed int64
+	for _, orphan := range orphans {
+		if rc.DryRun {
+			rc.Printf("would delete %s (%d bytes)", orphan.Key, orphan.Size)
+		} else {
+			if err := driver.Delete(rc.Context, orphan.Key); err != nil {
+				return err
+			}
+			rc.Printf("deleted %s (%d bytes)", orphan.Key, orphan.Size)
+		}
+
+		freed += orphan.Size
+		progress.Add(1)
+	}
+
+	rc.Printf("%d of %d files orphaned, %d bytes", len(orphans), len(objects), freed)
+	return nil
+}
+
+// orphanedFiles returns the objects that are not referenced and were last modified before cutoff.
+func orphanedFiles(objects []storage.ObjectInfo, referenced referencedSet, cutoff time.Time) []storage.ObjectInfo {
+	var orphans []storage.ObjectInfo
+	for _, object := range objects {
+		if !referenced.contains(object.Key) && object.ModTime.Before(cutoff) {
+			orphans = append(orphans, object)
+		}
+	}
+
+	return orphans
+}
@@ -0,0 +1,144 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestNewCleanupOrphanFilesScript verifies that the script describes itself and its arguments.
+func TestNewCleanupOrphanFilesScript(t *testing.T) {
+	script := NewCleanupOrphanFilesScript(newStorageDriver)
+
+	assert.Equal(t, "cleanup_orphan_files", script.Name())
+	assert.NotEmpty(t, script.Description())
+	assert.Len(t, script.Args(), 2)
+
+	registered, err := Lookup("cleanup_orphan_files")
+	require.NoError(t, err)
+	assert.Equal(t, script.Name(), registered.Name())
+}
+
+// TestCleanupOrphanFilesScript_Run tests that only old, unreferenced files below the prefix are deleted, that the files
+// of uploads and data exports are kept, and that dry runs delete nothing.
+func TestCleanupOrphanFilesScript_Run(t *testing.T) {
+	originalReferencedFiles := referencedFiles
+	t.Cleanup(func() { referencedFiles = originalReferencedFiles })
+	referencedFiles = func(_ *gorm.DB) (referencedSet, error) {
+		return referencedSet{
+			keys: map[string]bool{
+				"exports/kept.zip":    true,
+				"profile/kept.jpg":    true,
+				"profile/kept_64.jpg": true,
+				"uploads/done/file":   true,
+			},
+			prefixes: []string{"uploads/pending/chunks/"},
+		}, nil
+	}
+
+	allKeys := []string{
+		"exports/kept.zip",
+		"exports/old.zip",
+		"profile/kept.jpg",
+		"profile/kept_64.jpg",
+		"profile/orphan.jpg",
+		"uploads/done/file",
+		"uploads/pending/chunks/00000000000000000000",
+	}
+
+	tests := []struct {
+		name     string
+		args     Args
+		dryRun   bool
+		wantErr  bool
+		wantKeys []string
+		wantText string
+	}{
+		{
+			name: "deletes orphans below the prefix",
+			args: Args{"prefix": "profile/", "min_age": "0s"},
+			wantKeys: []string{
+				"exports/kept.zip",
+				"exports/old.zip",
+				"profile/kept.jpg",
+				"profile/kept_64.jpg",
+				"uploads/done/file",
+				"uploads/pending/chunks/00000000000000000000",
+			},
+			wantText: "deleted profile/orphan.jpg",
+		},
+		{
+			name: "keeps upload and export files",
+			args: Args{"prefix": "", "min_age": "0s"},
+			wantKeys: []string{
+				"exports/kept.zip",
+				"profile/kept.jpg",
+				"profile/kept_64.jpg",
+				"uploads/done/file",
+				"uploads/pending/chunks/00000000000000000000",
+			},
+			wantText: "2 of 7 files orphaned",
+		},
+		{
+			name:     "dry run deletes nothing",
+			args:     Args{"prefix": "profile/", "min_age": "0s"},
+			dryRun:   true,
+			wantKeys: allKeys,
+			wantText: "would delete profile/orphan.jpg",
+		},
+		{
+			name:     "recent files are kept",
+			args:     Args{"prefix": "profile/", "min_age": "1h"},
+			wantKeys: allKeys,
+			wantText: "0 of 3 files orphaned",
+		},
+		{
+			name:    "invalid min_age",
+			args:    Args{"prefix": "profile/", "min_age": "tomorrow"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				driver := storage.NewMemoryDriver()
+				for _, key := range allKeys {
+					require.NoError(t, driver.Put(ctx, key, strings.NewReader(key), ""))
+				}
+				time.Sleep(time.Millisecond)
+
+				var out bytes.Buffer
+				script := NewCleanupOrphanFilesScript(func() (storage.Driver, error) { return driver, nil })
+
+				err := script.Run(
+					&RunContext{
+						Context: ctx,
+						Args:    tt.args,
+						DryRun:  tt.dryRun,
+						Out:     &out,
+						name:    script.Name(),
+					},
+				)
+
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantKeys, driver.Keys())
+				assert.Contains(t, out.String(), tt.wantText)
+			},
+		)
+	}
+}
@@ -1,27 +1,50 @@
 package script
 
-import (
-	"fmt"
-
-	"gorm.io/gorm"
-)
-
-// ExampleScript represents a script that operates using a gorm-based database connection.
+// ExampleScript is a minimal script that demonstrates arguments, dry-run output and progress reporting.
 type (
-	ExampleScript struct {
-		db *gorm.DB
-	}
+	ExampleScript struct{}
 )
 
-// NewExampleScript initializes and returns a new instance of ExampleScript with the provided gorm.DB connection.
-func NewExampleScript(db *gorm.DB) *ExampleScript {
-	return &ExampleScript{
-		db: db,
+// init registers the example script with the script registry.
+func init() {
+	Register(NewExampleScript())
+}
+
+// NewExampleScript initializes and returns a new instance of ExampleScript.
+func NewExampleScript() *ExampleScript {
+	return &ExampleScript{}
+}
+
+// Name returns the name used to invoke the script, e.g. script run example_script.
+func (s *ExampleScript) Name() string {
+	return "example_script"
+}
+
+// Description returns a short summary of what the script does, shown by script list.
+func (s *ExampleScript) Description() string {
+	return "Prints a message a number of times"
+}
+
+// Args returns the argument schema of the script.
+func (s *ExampleScript) Args() []Arg {
+	return []Arg{
+		{Name: "message", Description: "Message to print", Default: "example script running"},
+		{Name: "times", Description: "How many times to print the message", Default: "1"},
 	}
 }
 
 // Run executes the script logic and interacts with the underlying database, returning an error if execution fails.
-func (s *ExampleScript) Run() error {
-	fmt.Println("example script running")
+func (s *ExampleScript) Run(rc *RunContext) error {
+	times, err := rc.Args.Int("times")
+	if err != nil {
+		return err
+	}
+
+	progress := rc.Progress(times)
+	for i := 0; i < times; i++ {
+		rc.Printf("%s", rc.Args.String("message"))
+		progress.Add(1)
+	}
+
 	return nil
 }
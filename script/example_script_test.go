@@ -1,65 +1,77 @@
 package script
 
 import (
+	"bytes"
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
-	"gorm.io/gorm"
 )
 
-// MockDB is a type that embeds mock.Mock, allowing it to be used for mocking database interactions in tests.
-type MockDB struct {
-	mock.Mock
-}
-
-// GormDB retrieves a *gorm.DB instance, typically used for mocking database interactions in tests.
-func (m *MockDB) GormDB() *gorm.DB {
-	args := m.Called()
-	return args.Get(0).(*gorm.DB)
-}
-
-// TestNewExampleScript verifies that a new ExampleScript instance is correctly initialized with a mocked gorm.DB connection.
+// TestNewExampleScript verifies that a new ExampleScript instance is correctly initialized and describes itself.
 func TestNewExampleScript(t *testing.T) {
-	mockDB := new(MockDB)
-	mockGormDB := &gorm.DB{}
-	mockDB.On("GormDB").Return(mockGormDB)
-
-	script := NewExampleScript(mockDB.GormDB())
+	script := NewExampleScript()
 
 	assert.NotNil(t, script)
-	assert.Equal(t, mockGormDB, script.db)
-	mockDB.AssertExpectations(t)
+	assert.Equal(t, "example_script", script.Name())
+	assert.NotEmpty(t, script.Description())
+	assert.Len(t, script.Args(), 2)
 }
 
 // TestExampleScript_Run tests the Run method of the ExampleScript, validating success and failure scenarios.
 func TestExampleScript_Run(t *testing.T) {
 	tests := []struct {
-		name    string
-		wantErr bool
+		name      string
+		args      Args
+		dryRun    bool
+		wantErr   bool
+		wantLines int
+		wantText  string
 	}{
 		{
-			name:    "successful run",
-			wantErr: false,
+			name:      "successful run",
+			args:      Args{"message": "hello", "times": "3"},
+			wantLines: 3,
+			wantText:  "[example_script] hello",
+		},
+		{
+			name:      "dry run output is marked",
+			args:      Args{"message": "hello", "times": "1"},
+			dryRun:    true,
+			wantLines: 1,
+			wantText:  "[example_script] (dry-run) hello",
+		},
+		{
+			name:    "invalid times argument",
+			args:    Args{"message": "hello", "times": "many"},
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				mockDB := new(MockDB)
-				mockGormDB := &gorm.DB{}
-				mockDB.On("GormDB").Return(mockGormDB)
+				var out bytes.Buffer
+				script := NewExampleScript()
 
-				script := NewExampleScript(mockDB.GormDB())
-
-				err := script.Run()
+				err := script.Run(
+					&RunContext{
+						Context: context.Background(),
+						Args:    tt.args,
+						DryRun:  tt.dryRun,
+						Out:     &out,
+						name:    script.Name(),
+					},
+				)
 
 				if tt.wantErr {
 					assert.Error(t, err)
-				} else {
-					assert.NoError(t, err)
+					return
 				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantLines, strings.Count(out.String(), tt.wantText))
 			},
 		)
 	}
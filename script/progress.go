@@ -0,0 +1,83 @@
+package script
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// PROGRESS_INTERVAL is the minimum time between two progress lines written by a Progress tracker.
+var PROGRESS_INTERVAL = time.Second
+
+// Progress tracks completed units of a long-running script and periodically reports percentage, elapsed time and ETA.
+type Progress struct {
+	mu       sync.Mutex
+	out      io.Writer
+	name     string
+	total    int
+	done     int
+	started  time.Time
+	reported time.Time
+	now      func() time.Time
+}
+
+// NewProgress creates a Progress tracker for total units of work that writes its reports to out.
+func NewProgress(out io.Writer, name string, total int) *Progress {
+	now := time.Now
+	return &Progress{
+		out:     out,
+		name:    name,
+		total:   total,
+		started: now(),
+		now:     now,
+	}
+}
+
+// Add records n completed units and writes a progress line when the report interval has passed or the work is done.
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+	now := p.now()
+	if p.done < p.total && now.Sub(p.reported) < PROGRESS_INTERVAL {
+		return
+	}
+
+	p.reported = now
+	_, _ = fmt.Fprintln(p.out, p.line(now))
+}
+
+// Done reports the number of completed units.
+func (p *Progress) Done() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.done
+}
+
+// line formats the current progress as "[name] done/total (pct%) elapsed X eta Y".
+func (p *Progress) line(now time.Time) string {
+	elapsed := now.Sub(p.started)
+	if p.total <= 0 {
+		return fmt.Sprintf("[%s] %d done, elapsed %s", p.name, p.done, elapsed.Round(time.Second))
+	}
+
+	percent := float64(p.done) / float64(p.total) * 100
+	return fmt.Sprintf(
+		"[%s] %d/%d (%.1f%%) elapsed %s eta %s",
+		p.name, p.done, p.total, percent,
+		elapsed.Round(time.Second), estimateRemaining(elapsed, p.done, p.total).Round(time.Second),
+	)
+}
+
+// estimateRemaining extrapolates the remaining duration from the average time per completed unit.
+func estimateRemaining(elapsed time.Duration, done int, total int) time.Duration {
+	if done <= 0 || done >= total {
+		return 0
+	}
+
+	perUnit := elapsed / time.Duration(done)
+	return perUnit * time.Duration(total-done)
+}
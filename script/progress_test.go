@@ -0,0 +1,42 @@
+package script
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEstimateRemaining verifies that the ETA is extrapolated from the average time per completed unit.
+func TestEstimateRemaining(t *testing.T) {
+	assert.Equal(t, 30*time.Second, estimateRemaining(10*time.Second, 25, 100))
+	assert.Equal(t, time.Duration(0), estimateRemaining(10*time.Second, 0, 100))
+	assert.Equal(t, time.Duration(0), estimateRemaining(10*time.Second, 100, 100))
+}
+
+// TestProgress_Add verifies that progress lines are throttled by the report interval and always written on completion.
+func TestProgress_Add(t *testing.T) {
+	var out bytes.Buffer
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	progress := NewProgress(&out, "backfill", 4)
+	progress.started = clock
+	progress.now = func() time.Time { return clock }
+
+	progress.Add(1)
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"), "first update should be reported")
+
+	clock = clock.Add(100 * time.Millisecond)
+	progress.Add(1)
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"), "update within the interval should be throttled")
+
+	clock = clock.Add(2 * time.Second)
+	progress.Add(1)
+	assert.Contains(t, out.String(), "[backfill] 3/4 (75.0%) elapsed 2s eta 1s")
+
+	progress.Add(1)
+	assert.Contains(t, out.String(), "[backfill] 4/4 (100.0%)")
+	assert.Equal(t, 4, progress.Done())
+}
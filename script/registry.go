@@ -0,0 +1,187 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrScriptNotFound indicates that no script is registered under the requested name.
+	ErrScriptNotFound = errors.New("script not found")
+
+	// ErrInvalidArgument indicates that a script argument is unknown, missing or malformed.
+	ErrInvalidArgument = errors.New("invalid script argument")
+)
+
+type (
+	// Arg describes a single named argument accepted by a script.
+	Arg struct {
+		Name        string
+		Description string
+		Required    bool
+		Default     string
+	}
+
+	// Args holds the resolved argument values passed to a script run, keyed by argument name.
+	Args map[string]string
+
+	// RunContext carries everything a script needs during a run: the context, the database handle (a transaction that is
+	// rolled back on dry runs), the resolved arguments and an output writer.
+	RunContext struct {
+		Context context.Context
+		DB      *gorm.DB
+		Args    Args
+		DryRun  bool
+		Out     io.Writer
+		name    string
+	}
+
+	// Runner is the common interface every script implements to register itself with the script framework.
+	Runner interface {
+		Name() string
+		Description() string
+		Args() []Arg
+		Run(rc *RunContext) error
+	}
+
+	// OneOff is implemented by scripts, such as data fixes, that must not complete successfully more than once.
+	OneOff interface {
+		OneOff() bool
+	}
+)
+
+// registry holds every script registered through Register, keyed by name.
+var registry = map[string]Runner{}
+
+// Register adds a script to the registry, replacing any script previously registered under the same name.
+func Register(runner Runner) {
+	registry[runner.Name()] = runner
+}
+
+// Lookup returns the script registered under name or ErrScriptNotFound.
+func Lookup(name string) (Runner, error) {
+	runner, ok := registry[name]
+	if !ok {
+		return nil, ErrScriptNotFound
+	}
+
+	return runner, nil
+}
+
+// Registered returns every registered script sorted by name.
+func Registered() []Runner {
+	runners := make([]Runner, 0, len(registry))
+	for _, runner := range registry {
+		runners = append(runners, runner)
+	}
+
+	sort.Slice(
+		runners, func(i, j int) bool {
+			return runners[i].Name() < runners[j].Name()
+		},
+	)
+
+	return runners
+}
+
+// ParseArgs converts key=value pairs into Args, rejecting pairs without a key.
+func ParseArgs(pairs []string) (Args, error) {
+	args := Args{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: expected key=value, got %q", ErrInvalidArgument, pair)
+		}
+
+		args[key] = value
+	}
+
+	return args, nil
+}
+
+// resolveArgs validates the given values against the script's argument schema and fills in defaults.
+func resolveArgs(schema []Arg, values Args) (Args, error) {
+	known := make(map[string]bool, len(schema))
+	resolved := Args{}
+
+	for _, arg := range schema {
+		known[arg.Name] = true
+
+		value, ok := values[arg.Name]
+		if !ok {
+			if arg.Required {
+				return nil, fmt.Errorf("%w: %s is required", ErrInvalidArgument, arg.Name)
+			}
+			value = arg.Default
+		}
+
+		resolved[arg.Name] = value
+	}
+
+	for name := range values {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown argument %s", ErrInvalidArgument, name)
+		}
+	}
+
+	return resolved, nil
+}
+
+// String returns the value of the named argument.
+func (a Args) String(name string) string {
+	return a[name]
+}
+
+// Int returns the named argument parsed as an integer.
+func (a Args) Int(name string) (int, error) {
+	value, err := strconv.Atoi(a[name])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be an integer", ErrInvalidArgument, name)
+	}
+
+	return value, nil
+}
+
+// Bool returns the named argument parsed as a boolean.
+func (a Args) Bool(name string) (bool, error) {
+	value, err := strconv.ParseBool(a[name])
+	if err != nil {
+		return false, fmt.Errorf("%w: %s must be a boolean", ErrInvalidArgument, name)
+	}
+
+	return value, nil
+}
+
+// Duration returns the named argument parsed as a duration such as "90m" or "24h".
+func (a Args) Duration(name string) (time.Duration, error) {
+	value, err := time.ParseDuration(a[name])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be a duration such as 24h", ErrInvalidArgument, name)
+	}
+
+	return value, nil
+}
+
+// Printf writes a formatted line to the run output, prefixed with the script name and a dry-run marker.
+func (rc *RunContext) Printf(format string, args ...any) {
+	prefix := "[" + rc.name + "] "
+	if rc.DryRun {
+		prefix += "(dry-run) "
+	}
+
+	_, _ = fmt.Fprintf(rc.Out, prefix+format+"\n", args...)
+}
+
+// Progress starts a progress tracker for total units of work that reports progress and ETA to the run output.
+func (rc *RunContext) Progress(total int) *Progress {
+	return NewProgress(rc.Out, rc.name, total)
+}
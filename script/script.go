@@ -1,18 +1,219 @@
 package script
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+const (
+	// SCRIPT_RUNS_TABLE is the name of the table that records the history of script runs.
+	SCRIPT_RUNS_TABLE = "script_runs"
+
+	// RUN_STATUS_SUCCESS marks a script run that completed without error.
+	RUN_STATUS_SUCCESS = "success"
+
+	// RUN_STATUS_FAILED marks a script run that returned an error.
+	RUN_STATUS_FAILED = "failed"
+
+	// SCRIPT_LOCK_CLASS is the first key of the Postgres advisory locks held while a script runs; the second key is
+	// the hash of the script name, so different scripts run concurrently but the same script does not.
+	SCRIPT_LOCK_CLASS int32 = 727_000_002
+)
+
+var (
+	// ErrScriptAlreadyRun indicates that a one-off script has already completed successfully.
+	ErrScriptAlreadyRun = errors.New("script has already been run")
+
+	// errDryRunRollback is returned from the run transaction to roll back the changes of a dry run.
+	errDryRunRollback = errors.New("dry run rollback")
+)
+
+type (
+	// Options controls a single script execution.
+	// Args holds the raw argument values, DryRun rolls back every change, Force allows re-running a one-off script and
+	// Out receives script output (stdout when nil).
+	Options struct {
+		Args   Args
+		DryRun bool
+		Force  bool
+		Out    io.Writer
+	}
+
+	// ScriptRun is the record stored in the script_runs table for every script execution. OneOff and Forced record
+	// whether a one-off script ran, and whether it was forced to run again; the table allows a single successful
+	// unforced run of each one-off script.
+	ScriptRun struct {
+		ID         uint      `gorm:"primary_key;autoIncrement"`
+		Name       string    `gorm:"type:varchar(100);not null;index"`
+		Args       string    `gorm:"type:text"`
+		DryRun     bool      `gorm:"not null;default:false"`
+		OneOff     bool      `gorm:"not null;default:false"`
+		Forced     bool      `gorm:"not null;default:false"`
+		Status     string    `gorm:"type:varchar(20);not null"`
+		Error      string    `gorm:"type:text"`
+		StartedAt  time.Time `gorm:"type:timestamp with time zone;not null"`
+		FinishedAt time.Time `gorm:"type:timestamp with time zone;not null"`
+	}
 )
 
+// TableName returns the table name used by GORM for the ScriptRun model.
+func (ScriptRun) TableName() string {
+	return SCRIPT_RUNS_TABLE
+}
+
 // Script runs a specified script by name using the given gorm.DB connection, returning an error if the script is not found.
 func Script(scriptName string, db *gorm.DB) error {
-	switch scriptName {
-	case "example_script":
-		exampleScript := NewExampleScript(db)
-		return exampleScript.Run()
-	default:
-		return errors.New("script not found")
+	return Execute(context.Background(), db, scriptName, Options{})
+}
+
+// Execute validates the arguments of the named script and runs it inside a transaction, rolling the transaction back
+// on dry runs. The transaction holds an advisory lock on the script name, so concurrent runs of a script wait for each
+// other. One-off scripts are refused once they have succeeded unless forced. A successful run is recorded in the
+// transaction of the run, so its changes and its record are committed together, while a failed run is recorded after
+// the rollback.
+func Execute(ctx context.Context, db *gorm.DB, name string, opts Options) error {
+	runner, err := Lookup(name)
+	if err != nil {
+		return err
+	}
+
+	args, err := resolveArgs(runner.Args(), opts.Args)
+	if err != nil {
+		return err
+	}
+
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	run, err := newScriptRun(name, args, opts, isOneOff(runner))
+	if err != nil {
+		return err
+	}
+
+	conn := db.WithContext(ctx)
+	runErr := conn.Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", SCRIPT_LOCK_CLASS, name).Error; err != nil {
+				return err
+			}
+
+			if run.OneOff && !opts.DryRun && !opts.Force {
+				if err := checkNotRun(tx, name); err != nil {
+					return err
+				}
+			}
+
+			rc := &RunContext{
+				Context: ctx,
+				DB:      tx,
+				Args:    args,
+				DryRun:  opts.DryRun,
+				Out:     out,
+				name:    name,
+			}
+
+			if err := runner.Run(rc); err != nil {
+				return err
+			}
+
+			if opts.DryRun {
+				return errDryRunRollback
+			}
+
+			return recordRun(tx, run, nil)
+		},
+	)
+	if errors.Is(runErr, errDryRunRollback) {
+		_, _ = fmt.Fprintf(out, "[%s] dry run finished, all changes rolled back\n", name)
+		return recordRun(conn, run, nil)
+	}
+	if errors.Is(runErr, ErrScriptAlreadyRun) {
+		return runErr
+	}
+	if runErr != nil {
+		if err := recordRun(conn, run, runErr); err != nil {
+			return errors.Join(runErr, err)
+		}
+	}
+
+	return runErr
+}
+
+// checkNotRun returns ErrScriptAlreadyRun when the one-off script name already completed an unforced run, including
+// runs recorded before runs were marked as one-off.
+func checkNotRun(tx *gorm.DB, name string) error {
+	var count int64
+	if err := tx.Model(&ScriptRun{}).
+		Where("name = ? AND status = ? AND NOT forced AND NOT dry_run", name, RUN_STATUS_SUCCESS).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%w: %s (use --force to run it again)", ErrScriptAlreadyRun, name)
+	}
+
+	return nil
+}
+
+// History returns the most recent runs of the named script, newest first.
+func History(db *gorm.DB, name string, limit int) ([]ScriptRun, error) {
+	var runs []ScriptRun
+	if err := db.Where("name = ?", name).Order("started_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// isOneOff reports whether the script declares itself as a one-off that must not run twice.
+func isOneOff(runner Runner) bool {
+	oneOff, ok := runner.(OneOff)
+	return ok && oneOff.OneOff()
+}
+
+// newScriptRun creates the record of a run of the named script that starts now.
+func newScriptRun(name string, args Args, opts Options, oneOff bool) (ScriptRun, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return ScriptRun{}, err
 	}
+
+	return ScriptRun{
+		Name:      name,
+		Args:      string(encodedArgs),
+		DryRun:    opts.DryRun,
+		OneOff:    oneOff,
+		Forced:    oneOff && opts.Force,
+		StartedAt: time.Now(),
+	}, nil
+}
+
+// recordRun stores run in the script_runs table with the outcome runErr. A second successful unforced run of a
+// one-off script is refused by the table with ErrScriptAlreadyRun.
+func recordRun(db *gorm.DB, run ScriptRun, runErr error) error {
+	run.Status = RUN_STATUS_SUCCESS
+	run.FinishedAt = time.Now()
+	if runErr != nil {
+		run.Status = RUN_STATUS_FAILED
+		run.Error = runErr.Error()
+	}
+
+	err := db.Create(&run).Error
+	if repository.IsUniqueViolation(err) {
+		return fmt.Errorf("%w: %s (use --force to run it again)", ErrScriptAlreadyRun, run.Name)
+	}
+
+	return err
 }
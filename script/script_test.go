@@ -1,49 +1,111 @@
 package script
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 // TestScript is a test function that validates the behavior of the Script function with various input scenarios.
 func TestScript(t *testing.T) {
-	tests := []struct {
-		name       string
-		scriptName string
-		wantErr    bool
-		errMsg     string
-	}{
-		{
-			name:       "example script - success",
-			scriptName: "example_script",
-			wantErr:    false,
-		},
-		{
-			name:       "unknown script - error",
-			scriptName: "unknown_script",
-			wantErr:    true,
-			errMsg:     "script not found",
-		},
+	err := Script("unknown_script", &gorm.DB{})
+
+	assert.ErrorIs(t, err, ErrScriptNotFound)
+	assert.Equal(t, "script not found", err.Error())
+}
+
+// TestExecute_InvalidArgs verifies that argument validation fails before the database is touched.
+func TestExecute_InvalidArgs(t *testing.T) {
+	err := Execute(context.Background(), nil, "example_script", Options{Args: Args{"unknown": "1"}})
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+// TestLookupAndRegistered verifies that registered scripts can be looked up by name and listed in name order.
+func TestLookupAndRegistered(t *testing.T) {
+	runner, err := Lookup("example_script")
+	require.NoError(t, err)
+	assert.Equal(t, "example_script", runner.Name())
+
+	registered := Registered()
+	require.NotEmpty(t, registered)
+	for i := 1; i < len(registered); i++ {
+		assert.Less(t, registered[i-1].Name(), registered[i].Name())
 	}
+}
+
+// TestParseArgs verifies the conversion of key=value pairs into script arguments.
+func TestParseArgs(t *testing.T) {
+	args, err := ParseArgs([]string{"user_id=42", "note=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, Args{"user_id": "42", "note": "a=b", "empty": ""}, args)
+
+	_, err = ParseArgs([]string{"novalue"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
 
-	for _, tt := range tests {
-		t.Run(
-			tt.name, func(t *testing.T) {
-				mockDB := new(MockDB)
-				mockGormDB := &gorm.DB{}
-				mockDB.On("GormDB").Return(mockGormDB)
-
-				err := Script(tt.scriptName, mockDB.GormDB())
-
-				if tt.wantErr {
-					assert.Error(t, err)
-					assert.Equal(t, tt.errMsg, err.Error())
-				} else {
-					assert.NoError(t, err)
-				}
-			},
-		)
+	_, err = ParseArgs([]string{"=value"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+// TestResolveArgs verifies default values, required arguments and rejection of unknown arguments.
+func TestResolveArgs(t *testing.T) {
+	schema := []Arg{
+		{Name: "user_id", Required: true},
+		{Name: "limit", Default: "100"},
 	}
+
+	t.Run(
+		"applies defaults", func(t *testing.T) {
+			args, err := resolveArgs(schema, Args{"user_id": "1"})
+
+			require.NoError(t, err)
+			assert.Equal(t, Args{"user_id": "1", "limit": "100"}, args)
+		},
+	)
+
+	t.Run(
+		"missing required", func(t *testing.T) {
+			_, err := resolveArgs(schema, Args{"limit": "5"})
+
+			assert.ErrorIs(t, err, ErrInvalidArgument)
+		},
+	)
+
+	t.Run(
+		"unknown argument", func(t *testing.T) {
+			_, err := resolveArgs(schema, Args{"user_id": "1", "force": "true"})
+
+			assert.ErrorIs(t, err, ErrInvalidArgument)
+		},
+	)
+}
+
+// TestArgs_Typed verifies the typed accessors of script arguments.
+func TestArgs_Typed(t *testing.T) {
+	args := Args{"count": "10", "enabled": "true", "name": "x", "age": "36h"}
+
+	count, err := args.Int("count")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, count)
+
+	enabled, err := args.Bool("enabled")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	_, err = args.Int("name")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	age, err := args.Duration("age")
+	assert.NoError(t, err)
+	assert.Equal(t, 36*time.Hour, age)
+
+	_, err = args.Bool("name")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = args.Duration("name")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
 }
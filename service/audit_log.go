@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+// recordAuditEvent adds an event of eventType with details to the audit log of the user identified by userId, using
+// tx. It is shared by the services that perform security-relevant actions, which call it in the transaction of the
+// action so an event is recorded exactly when the action is.
+func recordAuditEvent(
+	ctx context.Context,
+	auditRepo repository.AuditEventRepository,
+	tx *gorm.DB,
+	userId uuid.UUID,
+	eventType string,
+	details map[string]string,
+) error {
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	_, err := auditRepo.Create(
+		ctx, tx, entity.AuditEvent{
+			UserID:  userId,
+			Type:    eventType,
+			Details: details,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record audit event %s: %w", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"reflect"
+	"time"
+)
+
+const (
+	// DATA_EXPORT_FORMAT_VERSION is the version of the layout of data export archives, recorded in their manifest.
+	DATA_EXPORT_FORMAT_VERSION = 1
+
+	// DATA_EXPORT_MANIFEST is the path of the manifest inside a data export archive.
+	DATA_EXPORT_MANIFEST = "manifest.json"
+)
+
+type (
+	// dataExportManifest describes a data export archive and every file it contains.
+	dataExportManifest struct {
+		FormatVersion int                      `json:"format_version"`
+		ExportID      string                   `json:"export_id"`
+		UserID        string                   `json:"user_id"`
+		GeneratedAt   time.Time                `json:"generated_at"`
+		Files         []dataExportManifestFile `json:"files"`
+	}
+
+	// dataExportManifestFile describes a file of a data export archive. Records is the number of entries of a JSON
+	// list, and SHA256 lets the recipient verify the file.
+	dataExportManifestFile struct {
+		Path        string `json:"path"`
+		Description string `json:"description"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+		SHA256      string `json:"sha256"`
+		Records     *int   `json:"records,omitempty"`
+	}
+
+	// dataExportArchive writes a data export as a ZIP archive, recording each file in the manifest that is written
+	// last by Close.
+	dataExportArchive struct {
+		zip      *zip.Writer
+		manifest dataExportManifest
+	}
+)
+
+// newDataExportArchive creates a dataExportArchive writing to w, described by manifest.
+func newDataExportArchive(w io.Writer, manifest dataExportManifest) *dataExportArchive {
+	manifest.FormatVersion = DATA_EXPORT_FORMAT_VERSION
+	manifest.Files = []dataExportManifestFile{}
+
+	return &dataExportArchive{
+		zip:      zip.NewWriter(w),
+		manifest: manifest,
+	}
+}
+
+// addJSON adds v to the archive as an indented JSON file. When v is a list, the manifest records its length.
+func (a *dataExportArchive) addJSON(name string, description string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := a.addFile(name, description, "application/json", bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Slice {
+		records := value.Len()
+		a.manifest.Files[len(a.manifest.Files)-1].Records = &records
+	}
+
+	return nil
+}
+
+// addFile copies r into the archive under name.
+func (a *dataExportArchive) addFile(name string, description string, contentType string, r io.Reader) error {
+	w, err := a.zip.CreateHeader(
+		&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: a.manifest.GeneratedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, digest), r)
+	if err != nil {
+		return err
+	}
+
+	a.manifest.Files = append(
+		a.manifest.Files, dataExportManifestFile{
+			Path:        name,
+			Description: description,
+			ContentType: contentType,
+			Size:        size,
+			SHA256:      hex.EncodeToString(digest.Sum(nil)),
+		},
+	)
+
+	return nil
+}
+
+// Close writes the manifest and finishes the archive.
+func (a *dataExportArchive) Close() error {
+	data, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	w, err := a.zip.CreateHeader(
+		&zip.FileHeader{
+			Name:     DATA_EXPORT_MANIFEST,
+			Method:   zip.Deflate,
+			Modified: a.manifest.GeneratedAt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	return a.zip.Close()
+}
+
+// dataExportFileName turns a user-supplied file name into a single safe path segment of an archive.
+func dataExportFileName(name string) string {
+	name = path.Base(name)
+	if name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+
+	return name
+}
@@ -0,0 +1,604 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+const (
+	// DATA_EXPORT_TTL is how long a data export archive, and the download link emailed for it, stay available.
+	DATA_EXPORT_TTL = 72 * time.Hour
+
+	// DATA_EXPORT_BATCH_SIZE is the number of data exports claimed and built per batch.
+	DATA_EXPORT_BATCH_SIZE = 5
+
+	// DATA_EXPORT_CLAIM_TIMEOUT is how long a data export may be building before another worker takes it over.
+	DATA_EXPORT_CLAIM_TIMEOUT = 30 * time.Minute
+
+	// DATA_EXPORT_PURGE_BATCH_SIZE is the number of expired data exports removed per query when purging.
+	DATA_EXPORT_PURGE_BATCH_SIZE = 100
+)
+
+type (
+	// DataExportService builds copies of the personal data of users. Request queues an export of the user's profile,
+	// sessions, audit events and uploaded files, Get reports the progress of an export, ProcessPending builds queued
+	// exports into ZIP archives and emails a download link, and PurgeExpired removes exports past their expiry time.
+	DataExportService interface {
+		Request(ctx context.Context, userId string, locale string) (dto.DataExportResponse, error)
+		Get(ctx context.Context, userId string, exportId string) (dto.DataExportResponse, error)
+		ProcessPending(ctx context.Context) (int, error)
+		PurgeExpired(ctx context.Context) (int, error)
+	}
+
+	// dataExportService implements DataExportService, keeping exports in the database and their archives in files.
+	dataExportService struct {
+		exportRepo       repository.DataExportRepository
+		userRepo         repository.UserRepository
+		refreshTokenRepo repository.RefreshTokenRepository
+		uploadRepo       repository.UploadRepository
+		outboxRepo       repository.EmailOutboxRepository
+		auditRepo        repository.AuditEventRepository
+		renderer         mailer.Renderer
+		files            storage.Driver
+		txManager        repository.TxManager
+		now              func() time.Time
+	}
+
+	// dataExportProfile is the profile section of a data export.
+	dataExportProfile struct {
+		ID              string    `json:"id"`
+		Name            string    `json:"name"`
+		Email           string    `json:"email"`
+		PhoneNumber     string    `json:"phone_number"`
+		Role            string    `json:"role"`
+		IsVerified      bool      `json:"is_verified"`
+		Status          string    `json:"status"`
+		EmailSuppressed bool      `json:"email_suppressed"`
+		Image           string    `json:"image,omitempty"`
+		CreatedAt       time.Time `json:"created_at"`
+		UpdatedAt       time.Time `json:"updated_at"`
+	}
+
+	// dataExportSession is a sign-in of the user, represented by the refresh token it was issued.
+	dataExportSession struct {
+		ID        string     `json:"id"`
+		CreatedAt time.Time  `json:"created_at"`
+		ExpiresAt time.Time  `json:"expires_at"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	}
+
+	// dataExportUpload is a resumable upload of the user. File is the path of its content inside the archive.
+	dataExportUpload struct {
+		ID          string     `json:"id"`
+		Filename    string     `json:"filename"`
+		ContentType string     `json:"content_type"`
+		Size        int64      `json:"size"`
+		Received    int64      `json:"received"`
+		Checksum    string     `json:"checksum,omitempty"`
+		Status      string     `json:"status"`
+		CreatedAt   time.Time  `json:"created_at"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+		File        string     `json:"file,omitempty"`
+	}
+
+	// dataExportAuditEvent is a security-relevant action on the account, as recorded in its audit log.
+	dataExportAuditEvent struct {
+		Type       string            `json:"type"`
+		OccurredAt time.Time         `json:"occurred_at"`
+		Details    map[string]string `json:"details,omitempty"`
+	}
+
+	// dataExportRecords holds everything stored about a user that goes into their data export.
+	dataExportRecords struct {
+		user     entity.User
+		sessions []entity.RefreshToken
+		events   []entity.AuditEvent
+		uploads  []entity.Upload
+	}
+)
+
+// NewDataExportService creates a new DataExportService that stores archives in files and reads the data of users from
+// the given repositories. Download links are rendered with renderer and emailed through the email outbox, which is
+// written in the transactions of txManager.
+func NewDataExportService(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	uploadRepo repository.UploadRepository,
+	outboxRepo repository.EmailOutboxRepository,
+	auditRepo repository.AuditEventRepository,
+	renderer mailer.Renderer,
+	files storage.Driver,
+	txManager repository.TxManager,
+) DataExportService {
+	return &dataExportService{
+		exportRepo:       exportRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		uploadRepo:       uploadRepo,
+		outboxRepo:       outboxRepo,
+		auditRepo:        auditRepo,
+		renderer:         renderer,
+		files:            files,
+		txManager:        txManager,
+		now:              time.Now,
+	}
+}
+
+// Request queues an export of the data of an active user; the download link is emailed in locale once the archive
+// is built, and the request is recorded in the audit log. While an earlier export of the user is still being built,
+// that export is returned instead.
+func (s *dataExportService) Request(ctx context.Context, userId string, locale string) (
+	dto.DataExportResponse,
+	error,
+) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.DataExportResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+	if user.Status != constants.ENUM_USER_ACTIVE {
+		return dto.DataExportResponse{}, dto.ErrAccountDeactivated
+	}
+
+	active, err := s.exportRepo.GetActiveByUserId(ctx, nil, userId)
+	if err == nil {
+		return s.toDataExportResponse(ctx, active)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.DataExportResponse{}, err
+	}
+
+	var export entity.DataExport
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			export, err = s.exportRepo.Create(
+				ctx, nil, entity.DataExport{
+					UserID: user.ID,
+					Status: constants.ENUM_DATA_EXPORT_PENDING,
+					Locale: locale,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			return recordAuditEvent(
+				ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_DATA_EXPORT_REQUESTED,
+				map[string]string{"export_id": export.ID.String()},
+			)
+		},
+	)
+	if err != nil {
+		return dto.DataExportResponse{}, err
+	}
+
+	return s.toDataExportResponse(ctx, export)
+}
+
+// Get returns the progress of an export owned by the user, with a download URL once it is completed.
+func (s *dataExportService) Get(ctx context.Context, userId string, exportId string) (dto.DataExportResponse, error) {
+	if _, err := uuid.Parse(exportId); err != nil {
+		return dto.DataExportResponse{}, dto.ErrDataExportNotFound
+	}
+
+	export, err := s.exportRepo.GetById(ctx, nil, exportId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.DataExportResponse{}, dto.ErrDataExportNotFound
+		}
+		return dto.DataExportResponse{}, err
+	}
+	if export.UserID.String() != userId {
+		return dto.DataExportResponse{}, dto.ErrDataExportNotFound
+	}
+	if export.ExpiresAt != nil && !export.ExpiresAt.After(s.now()) {
+		return dto.DataExportResponse{}, dto.ErrDataExportExpired
+	}
+
+	return s.toDataExportResponse(ctx, export)
+}
+
+// ProcessPending claims a batch of queued exports and builds them, returning how many were claimed. An export that
+// cannot be built is marked as failed with the reason; it is not retried.
+func (s *dataExportService) ProcessPending(ctx context.Context) (int, error) {
+	now := s.now()
+
+	var claimed []entity.DataExport
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			exports, err := s.exportRepo.ClaimPending(
+				ctx, nil, now.Add(-DATA_EXPORT_CLAIM_TIMEOUT), DATA_EXPORT_BATCH_SIZE,
+			)
+			if err != nil {
+				return err
+			}
+
+			for i := range exports {
+				exports[i].Status = constants.ENUM_DATA_EXPORT_PROCESSING
+				exports[i].ClaimedAt = &now
+				if exports[i], err = s.exportRepo.Save(ctx, nil, exports[i]); err != nil {
+					return err
+				}
+			}
+
+			claimed = exports
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, export := range claimed {
+		if err := s.build(ctx, export); err != nil {
+			return len(claimed), err
+		}
+	}
+
+	return len(claimed), ctx.Err()
+}
+
+// PurgeExpired removes the exports past their expiry time together with their archives, and returns how many were
+// removed.
+func (s *dataExportService) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		exports, err := s.exportRepo.GetExpired(ctx, nil, s.now(), DATA_EXPORT_PURGE_BATCH_SIZE)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, export := range exports {
+			if export.StorageKey != "" {
+				_ = s.files.Delete(ctx, export.StorageKey)
+			}
+			if err := s.exportRepo.Delete(ctx, nil, export.ID.String()); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(exports) < DATA_EXPORT_PURGE_BATCH_SIZE || ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
+	}
+}
+
+// build writes the archive of a claimed export, stores it and queues the email with its download link. Failures are
+// recorded on the export; only errors saving the outcome, or a cancelled context, are returned. A cancelled export
+// is left processing so another worker takes it over once its claim times out, and an export taken over that way
+// is left to that worker.
+func (s *dataExportService) build(ctx context.Context, export entity.DataExport) error {
+	key := dataExportKey(export.ID)
+
+	user, err := s.userRepo.GetUserById(ctx, nil, export.UserID.String())
+	if err != nil {
+		return s.fail(ctx, export, lookupError(err, dto.ErrUserNotFound, "get user"))
+	}
+
+	size, err := s.store(ctx, export, user, key)
+	if err == nil {
+		err = s.complete(ctx, export, user, key, size)
+	}
+	if errors.Is(err, dto.ErrDataExportVersionConflict) {
+		// Another worker took the export over once its claim timed out; the archive under key is now its own.
+		return nil
+	}
+	if err != nil {
+		_ = s.files.Delete(context.WithoutCancel(ctx), key)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return s.fail(ctx, export, err)
+	}
+
+	return nil
+}
+
+// store writes the archive of export to a temporary file, uploads it under key and returns its size.
+func (s *dataExportService) store(ctx context.Context, export entity.DataExport, user entity.User, key string) (
+	int64,
+	error,
+) {
+	records, err := s.collect(ctx, user)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	if err := s.writeArchive(ctx, file, export, records); err != nil {
+		return 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	if err := s.files.Put(ctx, key, file, "application/zip"); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// collect loads everything stored about user.
+func (s *dataExportService) collect(ctx context.Context, user entity.User) (dataExportRecords, error) {
+	records := dataExportRecords{user: user}
+	userId := user.ID.String()
+
+	var err error
+	if records.sessions, err = s.refreshTokenRepo.GetByUserID(ctx, nil, userId); err != nil {
+		return dataExportRecords{}, err
+	}
+	if records.events, err = s.auditRepo.GetByUserId(ctx, nil, userId); err != nil {
+		return dataExportRecords{}, err
+	}
+	if records.uploads, err = s.uploadRepo.GetByUserId(ctx, nil, userId); err != nil {
+		return dataExportRecords{}, err
+	}
+
+	return records, nil
+}
+
+// writeArchive writes the data export archive of records to w: one JSON file per section, the stored files of the
+// user and a manifest.
+func (s *dataExportService) writeArchive(
+	ctx context.Context,
+	w io.Writer,
+	export entity.DataExport,
+	records dataExportRecords,
+) error {
+	archive := newDataExportArchive(
+		w, dataExportManifest{
+			ExportID:    export.ID.String(),
+			UserID:      records.user.ID.String(),
+			GeneratedAt: s.now().UTC(),
+		},
+	)
+
+	profile := dataExportProfile{
+		ID:              records.user.ID.String(),
+		Name:            records.user.Name,
+		Email:           records.user.Email,
+		PhoneNumber:     records.user.PhoneNumber,
+		Role:            records.user.Role,
+		IsVerified:      records.user.IsVerified,
+		Status:          records.user.Status,
+		EmailSuppressed: records.user.EmailSuppressed,
+		Image:           records.user.ImageUrl,
+		CreatedAt:       records.user.CreatedAt,
+		UpdatedAt:       records.user.UpdatedAt,
+	}
+	if records.user.ImageUrl != "" && !storage.IsExternalURL(records.user.ImageUrl) {
+		profile.Image = "files/profile/" + dataExportFileName(records.user.ImageUrl)
+		if err := s.copyFile(ctx, archive, records.user.ImageUrl, profile.Image, "Profile image"); err != nil {
+			return err
+		}
+	}
+
+	uploads := make([]dataExportUpload, 0, len(records.uploads))
+	for _, upload := range records.uploads {
+		item := dataExportUpload{
+			ID:          upload.ID.String(),
+			Filename:    upload.Filename,
+			ContentType: upload.ContentType,
+			Size:        upload.Size,
+			Received:    upload.Received,
+			Checksum:    upload.Checksum,
+			Status:      upload.Status,
+			CreatedAt:   upload.CreatedAt,
+			CompletedAt: upload.CompletedAt,
+		}
+		if upload.Status == constants.ENUM_UPLOAD_COMPLETED {
+			item.File = path.Join("files/uploads", upload.ID.String(), dataExportFileName(upload.Filename))
+			if err := s.copyFile(ctx, archive, upload.StorageKey, item.File, "Uploaded file"); err != nil {
+				return err
+			}
+		}
+		uploads = append(uploads, item)
+	}
+
+	sessions := make([]dataExportSession, 0, len(records.sessions))
+	for _, session := range records.sessions {
+		item := dataExportSession{
+			ID:        session.ID.String(),
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		}
+		if session.DeletedAt.Valid {
+			item.RevokedAt = &session.DeletedAt.Time
+		}
+		sessions = append(sessions, item)
+	}
+
+	sections := []struct {
+		name        string
+		description string
+		value       any
+	}{
+		{"profile.json", "Account profile", profile},
+		{"sessions.json", "Sign-in sessions, including revoked and expired ones", sessions},
+		{"audit_events.json", "Account activity, oldest first", dataExportAuditEvents(records.events)},
+		{"uploads.json", "Uploaded files", uploads},
+	}
+	for _, section := range sections {
+		if err := archive.addJSON(section.name, section.description, section.value); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// copyFile adds the stored object at key to archive under name. An object that no longer exists is skipped.
+func (s *dataExportService) copyFile(
+	ctx context.Context,
+	archive *dataExportArchive,
+	key string,
+	name string,
+	description string,
+) error {
+	body, info, err := s.files.Get(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	return archive.addFile(name, description, info.ContentType, body)
+}
+
+// complete marks export as completed and queues the email with its download link in the same transaction.
+func (s *dataExportService) complete(
+	ctx context.Context,
+	export entity.DataExport,
+	user entity.User,
+	key string,
+	size int64,
+) error {
+	now := s.now()
+	expiresAt := now.Add(DATA_EXPORT_TTL)
+
+	link, err := s.files.SignedURL(ctx, key, DATA_EXPORT_TTL)
+	if err != nil {
+		return err
+	}
+
+	msg, err := makeDataExportEmail(s.renderer, user.Email, export.Locale, link, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	export.Status = constants.ENUM_DATA_EXPORT_COMPLETED
+	export.StorageKey = key
+	export.Size = size
+	export.LastError = ""
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			if _, err := s.exportRepo.Save(ctx, nil, export); err != nil {
+				return err
+			}
+
+			return enqueueEmail(ctx, s.outboxRepo, nil, msg, now)
+		},
+	)
+}
+
+// fail marks export as failed because of cause. Failed exports expire like completed ones, so they are purged. An
+// export that another worker took over in the meantime is left to that worker.
+func (s *dataExportService) fail(ctx context.Context, export entity.DataExport, cause error) error {
+	expiresAt := s.now().Add(DATA_EXPORT_TTL)
+
+	export.Status = constants.ENUM_DATA_EXPORT_FAILED
+	export.StorageKey = ""
+	export.Size = 0
+	export.LastError = cause.Error()
+	export.ExpiresAt = &expiresAt
+
+	_, err := s.exportRepo.Save(ctx, nil, export)
+	if errors.Is(err, dto.ErrDataExportVersionConflict) {
+		return nil
+	}
+
+	return err
+}
+
+// toDataExportResponse maps an export to the DataExportResponse returned to callers, with a signed URL that stays
+// valid until the export expires once it is completed.
+func (s *dataExportService) toDataExportResponse(ctx context.Context, export entity.DataExport) (
+	dto.DataExportResponse,
+	error,
+) {
+	response := dto.DataExportResponse{
+		ID:          export.ID.String(),
+		Status:      export.Status,
+		Size:        export.Size,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	if export.Status == constants.ENUM_DATA_EXPORT_COMPLETED && export.ExpiresAt != nil {
+		url, err := s.files.SignedURL(ctx, export.StorageKey, export.ExpiresAt.Sub(s.now()))
+		if err != nil {
+			return dto.DataExportResponse{}, err
+		}
+		response.URL = url
+	}
+
+	return response, nil
+}
+
+// dataExportKey returns the storage key of the archive of the export identified by id.
+func dataExportKey(id uuid.UUID) string {
+	return "exports/" + id.String() + ".zip"
+}
+
+// dataExportAuditEvents maps the audit log of a user, kept oldest first, to the audit events of a data export.
+func dataExportAuditEvents(events []entity.AuditEvent) []dataExportAuditEvent {
+	result := make([]dataExportAuditEvent, 0, len(events))
+	for _, event := range events {
+		item := dataExportAuditEvent{Type: event.Type, OccurredAt: event.CreatedAt}
+		if len(event.Details) > 0 {
+			item.Details = event.Details
+		}
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// makeDataExportEmail renders the data_export template in the given locale for receiverEmail with the download
+// link of an export that expires at expiresAt.
+func makeDataExportEmail(
+	renderer mailer.Renderer,
+	receiverEmail string,
+	locale string,
+	link string,
+	expiresAt time.Time,
+) (mailer.Message, error) {
+	msg, err := renderer.Render(
+		"data_export", locale, map[string]any{
+			"Email":        receiverEmail,
+			"DownloadLink": link,
+			"ExpiresAt":    expiresAt.UTC().Format("2006-01-02 15:04 MST"),
+		},
+	)
+	if err != nil {
+		return mailer.Message{}, err
+	}
+
+	msg.To = []string{receiverEmail}
+	return msg, nil
+}
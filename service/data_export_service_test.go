@@ -0,0 +1,176 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestDataExportService_WriteArchive tests that the archive holds every section and stored file of the user and a
+// manifest describing them.
+func TestDataExportService_WriteArchive(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	files := storage.NewMemoryDriver()
+	require.NoError(t, files.Put(ctx, "profile/avatar.png", strings.NewReader("avatar"), "image/png"))
+	require.NoError(t, files.Put(ctx, "uploads/done/file", strings.NewReader("report"), "text/plain"))
+
+	userID := uuid.New()
+	completedAt := now.Add(-time.Hour)
+	records := dataExportRecords{
+		user: entity.User{
+			ID:       userID,
+			Name:     "Jane",
+			Email:    "jane@example.com",
+			Status:   constants.ENUM_USER_ACTIVE,
+			ImageUrl: "profile/avatar.png",
+		},
+		sessions: []entity.RefreshToken{
+			{ID: uuid.New(), UserID: userID, Token: "secret-token", ExpiresAt: now.Add(time.Hour)},
+		},
+		uploads: []entity.Upload{
+			{
+				ID:          uuid.New(),
+				Filename:    "../report.txt",
+				Status:      constants.ENUM_UPLOAD_COMPLETED,
+				StorageKey:  "uploads/done/file",
+				CompletedAt: &completedAt,
+			},
+			{ID: uuid.New(), Filename: "partial.bin", Status: constants.ENUM_UPLOAD_PENDING},
+			{
+				ID:         uuid.New(),
+				Filename:   "gone.txt",
+				Status:     constants.ENUM_UPLOAD_COMPLETED,
+				StorageKey: "uploads/gone/file",
+			},
+		},
+	}
+	records.sessions[0].DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+	s := &dataExportService{files: files, now: func() time.Time { return now }}
+	exportID := uuid.New()
+
+	var buf bytes.Buffer
+	require.NoError(t, s.writeArchive(ctx, &buf, entity.DataExport{ID: exportID}, records))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	contents := make(map[string]string, len(archive.File))
+	for _, file := range archive.File {
+		body, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		contents[file.Name] = string(data)
+	}
+
+	uploadFile := "files/uploads/" + records.uploads[0].ID.String() + "/report.txt"
+	assert.Equal(t, "avatar", contents["files/profile/avatar.png"])
+	assert.Equal(t, "report", contents[uploadFile])
+	assert.Contains(t, contents["profile.json"], `"image": "files/profile/avatar.png"`)
+	assert.Contains(t, contents["sessions.json"], `"revoked_at"`)
+	assert.NotContains(t, contents["sessions.json"], "secret-token", "session tokens are credentials")
+	assert.Contains(t, contents["uploads.json"], `"file": "`+uploadFile+`"`)
+
+	var manifest dataExportManifest
+	require.NoError(t, json.Unmarshal([]byte(contents[DATA_EXPORT_MANIFEST]), &manifest))
+	assert.Equal(t, DATA_EXPORT_FORMAT_VERSION, manifest.FormatVersion)
+	assert.Equal(t, exportID.String(), manifest.ExportID)
+	assert.Equal(t, userID.String(), manifest.UserID)
+	assert.Equal(t, now, manifest.GeneratedAt)
+
+	paths := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+
+		digest := sha256.Sum256([]byte(contents[file.Path]))
+		assert.Equal(t, hex.EncodeToString(digest[:]), file.SHA256, "checksum of %s", file.Path)
+		assert.Equal(t, int64(len(contents[file.Path])), file.Size, "size of %s", file.Path)
+
+		if file.Path == "uploads.json" {
+			require.NotNil(t, file.Records)
+			assert.Equal(t, 3, *file.Records)
+		}
+		if file.Path == "profile.json" {
+			assert.Nil(t, file.Records, "the profile is not a list")
+		}
+	}
+	assert.ElementsMatch(
+		t, []string{
+			"files/profile/avatar.png", uploadFile, "profile.json", "sessions.json", "audit_events.json", "uploads.json",
+		}, paths, "missing stored files are skipped",
+	)
+	assert.Len(t, contents, len(paths)+1)
+}
+
+// TestDataExportAuditEvents tests that the recorded audit log of a user is exported as it is, without details when an
+// event has none.
+func TestDataExportAuditEvents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := dataExportAuditEvents(
+		[]entity.AuditEvent{
+			{Type: constants.ENUM_AUDIT_ACCOUNT_CREATED, Details: map[string]string{}, CreatedAt: start},
+			{Type: constants.ENUM_AUDIT_LOGIN, CreatedAt: start.Add(time.Hour)},
+			{
+				Type:      constants.ENUM_AUDIT_EMAIL_CHANGED,
+				Details:   map[string]string{"old_email": "old@example.com", "new_email": "new@example.com"},
+				CreatedAt: start.Add(2 * time.Hour),
+			},
+		},
+	)
+
+	assert.Equal(
+		t, []dataExportAuditEvent{
+			{Type: constants.ENUM_AUDIT_ACCOUNT_CREATED, OccurredAt: start},
+			{Type: constants.ENUM_AUDIT_LOGIN, OccurredAt: start.Add(time.Hour)},
+			{
+				Type:       constants.ENUM_AUDIT_EMAIL_CHANGED,
+				OccurredAt: start.Add(2 * time.Hour),
+				Details:    map[string]string{"old_email": "old@example.com", "new_email": "new@example.com"},
+			},
+		}, events,
+	)
+	assert.NotNil(t, dataExportAuditEvents(nil), "an empty audit log is exported as an empty list")
+}
+
+// TestDataExportFileName tests that user-supplied file names become a single safe archive path segment.
+func TestDataExportFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain name", in: "report.pdf", want: "report.pdf"},
+		{name: "directories are dropped", in: "profile/avatar.png", want: "avatar.png"},
+		{name: "parent directory", in: "..", want: "file"},
+		{name: "traversal", in: "../../etc/passwd", want: "passwd"},
+		{name: "empty", in: "", want: "file"},
+		{name: "root", in: "/", want: "file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, dataExportFileName(tt.in))
+			},
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DATA_EXPORT_POLL_INTERVAL is how often the data exporter looks for queued exports.
+const DATA_EXPORT_POLL_INTERVAL = 10 * time.Second
+
+// DataExporter builds queued personal data exports and purges expired ones in the background until its context is
+// cancelled.
+type DataExporter struct {
+	exports  DataExportService
+	interval time.Duration
+}
+
+// NewDataExporter creates a DataExporter that looks for queued and expired exports every interval.
+func NewDataExporter(exports DataExportService, interval time.Duration) *DataExporter {
+	return &DataExporter{
+		exports:  exports,
+		interval: interval,
+	}
+}
+
+// Run builds queued exports and purges expired ones every interval until ctx is cancelled. A full batch is followed
+// immediately by the next one so a backlog drains without waiting for the ticker.
+func (e *DataExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			claimed, err := e.exports.ProcessPending(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("data exporter: %v", err)
+				}
+				break
+			}
+			if claimed < DATA_EXPORT_BATCH_SIZE || ctx.Err() != nil {
+				break
+			}
+		}
+
+		purged, err := e.exports.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("data exporter: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("data exporter: purged %d expired exports", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDataExports is a DataExportService stub that scripts the number of exports claimed per call and cancels the
+// exporter after a number of purge runs.
+type fakeDataExports struct {
+	DataExportService
+	mu     sync.Mutex
+	claims []int
+	calls  []string
+	stopAt int
+	purges int
+	cancel context.CancelFunc
+}
+
+// ProcessPending records the call and returns the next scripted number of claimed exports.
+func (f *fakeDataExports) ProcessPending(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, "process")
+	if len(f.claims) == 0 {
+		return 0, errors.New("database unavailable")
+	}
+
+	claimed := f.claims[0]
+	f.claims = f.claims[1:]
+	return claimed, nil
+}
+
+// PurgeExpired records the call and cancels the exporter once stopAt purges happened.
+func (f *fakeDataExports) PurgeExpired(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, "purge")
+	f.purges++
+	if f.purges >= f.stopAt {
+		f.cancel()
+	}
+
+	return 1, nil
+}
+
+// TestDataExporter_Run verifies that full batches are drained before purging, that failures do not stop the
+// exporter and that it stops on cancellation.
+func TestDataExporter_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	exports := &fakeDataExports{
+		claims: []int{DATA_EXPORT_BATCH_SIZE, 1},
+		stopAt: 2,
+		cancel: cancel,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewDataExporter(exports, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("exporter did not stop after cancellation")
+	}
+
+	want := []string{"process", "process", "purge", "process", "purge"}
+	if assert.GreaterOrEqual(t, len(exports.calls), len(want)) {
+		assert.Equal(t, want, exports.calls[:len(want)])
+	}
+}
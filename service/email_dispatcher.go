@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// OUTBOX_POLL_INTERVAL is how often the email dispatcher looks for due outbox emails.
+const OUTBOX_POLL_INTERVAL = 5 * time.Second
+
+// EmailDispatcher delivers outbox emails in the background until its context is cancelled.
+type EmailDispatcher struct {
+	outbox   EmailOutboxService
+	interval time.Duration
+}
+
+// NewEmailDispatcher creates an EmailDispatcher that polls the outbox every interval.
+func NewEmailDispatcher(outbox EmailOutboxService, interval time.Duration) *EmailDispatcher {
+	return &EmailDispatcher{
+		outbox:   outbox,
+		interval: interval,
+	}
+}
+
+// Run dispatches due emails every interval until ctx is cancelled. A full batch is followed immediately by the next one
+// so a backlog drains without waiting for the ticker.
+func (d *EmailDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			attempted, err := d.outbox.DispatchDue(ctx)
+			if err != nil {
+				log.Printf("email dispatcher: %v", err)
+				break
+			}
+			if attempted < OUTBOX_BATCH_SIZE || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
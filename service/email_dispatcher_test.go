@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOutbox is an EmailOutboxService stub that reports a scripted number of attempted emails per dispatch run.
+type fakeOutbox struct {
+	EmailOutboxService
+	mu      sync.Mutex
+	batches []int
+	calls   int
+	cancel  context.CancelFunc
+}
+
+// DispatchDue returns the next scripted batch size and cancels the dispatcher once the script is exhausted.
+func (f *fakeOutbox) DispatchDue(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if len(f.batches) == 0 {
+		f.cancel()
+		return 0, nil
+	}
+
+	attempted := f.batches[0]
+	f.batches = f.batches[1:]
+	return attempted, nil
+}
+
+// TestEmailDispatcher_Run verifies that full batches are drained immediately and that Run stops on cancellation.
+func TestEmailDispatcher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	outbox := &fakeOutbox{batches: []int{OUTBOX_BATCH_SIZE, OUTBOX_BATCH_SIZE, 3}, cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		NewEmailDispatcher(outbox, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("dispatcher did not stop after cancellation")
+	}
+
+	assert.Equal(t, 4, outbox.calls)
+}
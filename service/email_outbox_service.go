@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+const (
+	// OUTBOX_MAX_ATTEMPTS is the number of delivery attempts after which an outbox email is dead-lettered.
+	OUTBOX_MAX_ATTEMPTS = 8
+
+	// OUTBOX_BASE_BACKOFF is the delay before the first retry; every further retry doubles it.
+	OUTBOX_BASE_BACKOFF = 30 * time.Second
+
+	// OUTBOX_MAX_BACKOFF caps the delay between two delivery attempts.
+	OUTBOX_MAX_BACKOFF = 6 * time.Hour
+
+	// OUTBOX_BATCH_SIZE is the maximum number of emails delivered by a single dispatch run.
+	OUTBOX_BATCH_SIZE = 50
+
+	// OUTBOX_LEASE is how long a dispatch run holds the emails it claimed. Emails whose outcome was not recorded by
+	// then, because the run stopped, are claimed again by a later run.
+	OUTBOX_LEASE = 10 * time.Minute
+)
+
+type (
+	// EmailOutboxService manages the transactional email outbox.
+	// Enqueue writes an email to the outbox within the caller's transaction, one entry per recipient.
+	// DispatchDue delivers due emails, scheduling retries with exponential backoff and dead-lettering exhausted ones.
+	// Requeue schedules a dead-lettered email for a fresh round of delivery attempts.
+	// GetAllWithPagination lists outbox emails filtered by status.
+	EmailOutboxService interface {
+		Enqueue(ctx context.Context, tx *gorm.DB, msg mailer.Message) error
+		DispatchDue(ctx context.Context) (int, error)
+		Requeue(ctx context.Context, id string) (dto.EmailOutboxResponse, error)
+		GetAllWithPagination(
+			ctx context.Context,
+			req dto.EmailOutboxListRequest,
+		) (dto.EmailOutboxPaginationResponse, error)
+	}
+
+	// emailOutboxService implements EmailOutboxService; mailer delivers the emails.
+	emailOutboxService struct {
+		outboxRepo repository.EmailOutboxRepository
+		mailer     mailer.Mailer
+		now        func() time.Time
+	}
+)
+
+// NewEmailOutboxService creates an EmailOutboxService that delivers emails with the given mailer.
+func NewEmailOutboxService(
+	outboxRepo repository.EmailOutboxRepository,
+	mailer mailer.Mailer,
+) EmailOutboxService {
+	return &emailOutboxService{
+		outboxRepo: outboxRepo,
+		mailer:     mailer,
+		now:        time.Now,
+	}
+}
+
+// Enqueue writes a pending email to the outbox using tx, so it is only delivered if the caller's transaction commits.
+func (s *emailOutboxService) Enqueue(ctx context.Context, tx *gorm.DB, msg mailer.Message) error {
+	return enqueueEmail(ctx, s.outboxRepo, tx, msg, s.now())
+}
+
+// enqueueEmail writes msg to the outbox using tx as one pending entry per recipient, due at now.
+// It is shared by the services that queue emails without delivering them.
+func enqueueEmail(
+	ctx context.Context,
+	outboxRepo repository.EmailOutboxRepository,
+	tx *gorm.DB,
+	msg mailer.Message,
+	now time.Time,
+) error {
+	if len(msg.To) == 0 {
+		return errors.Join(dto.ErrEnqueueEmail, mailer.ErrNoRecipients)
+	}
+
+	for _, recipient := range msg.To {
+		_, err := outboxRepo.Create(
+			ctx, tx, entity.EmailOutbox{
+				Recipient:     recipient,
+				Subject:       msg.Subject,
+				Body:          msg.HTML,
+				TextBody:      msg.Text,
+				Status:        constants.ENUM_OUTBOX_PENDING,
+				MaxAttempts:   OUTBOX_MAX_ATTEMPTS,
+				NextAttemptAt: now,
+			},
+		)
+		if err != nil {
+			return errors.Join(dto.ErrEnqueueEmail, err)
+		}
+	}
+
+	return nil
+}
+
+// DispatchDue leases a batch of due emails for OUTBOX_LEASE, delivers them outside of any transaction and records
+// the outcome of each in an update of its own, returning how many were attempted. An outcome that cannot be recorded
+// leaves its email sending until the lease expires, when it is delivered again. An outcome is only recorded while
+// the lease of its email still holds: a delivery that outlasted the lease reports dto.ErrEmailOutboxLeaseLost
+// instead of overwriting the outcome of the dispatcher that claimed the email next.
+func (s *emailOutboxService) DispatchDue(ctx context.Context) (int, error) {
+	now := s.now()
+	messages, err := s.outboxRepo.ClaimDue(ctx, nil, now, now.Add(OUTBOX_LEASE), OUTBOX_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, message := range messages {
+		sendErr := s.mailer.Send(
+			ctx, mailer.Message{
+				To:      []string{message.Recipient},
+				Subject: message.Subject,
+				HTML:    message.Body,
+				Text:    message.TextBody,
+			},
+		)
+		leaseUntil := message.NextAttemptAt
+		recordAttempt(&message, sendErr, s.now())
+
+		if err := s.outboxRepo.SaveOutcome(ctx, nil, message, leaseUntil); err != nil {
+			errs = append(errs, fmt.Errorf("outbox email %s: %w", message.ID, err))
+		}
+	}
+
+	return len(messages), errors.Join(errs...)
+}
+
+// Requeue resets the attempts of a dead-lettered email and schedules it for immediate delivery.
+func (s *emailOutboxService) Requeue(ctx context.Context, id string) (dto.EmailOutboxResponse, error) {
+	message, err := s.outboxRepo.GetById(ctx, nil, id)
+	if err != nil {
+		return dto.EmailOutboxResponse{}, lookupError(err, dto.ErrEmailOutboxNotFound, "get outbox email")
+	}
+
+	if message.Status != constants.ENUM_OUTBOX_DEAD {
+		return dto.EmailOutboxResponse{}, dto.ErrEmailOutboxNotDead
+	}
+
+	message.Status = constants.ENUM_OUTBOX_PENDING
+	message.Attempts = 0
+	message.NextAttemptAt = s.now()
+	if err := s.outboxRepo.Save(ctx, nil, message); err != nil {
+		return dto.EmailOutboxResponse{}, err
+	}
+
+	return toEmailOutboxResponse(message), nil
+}
+
+// GetAllWithPagination lists outbox emails, newest first, optionally filtered by status.
+func (s *emailOutboxService) GetAllWithPagination(
+	ctx context.Context,
+	req dto.EmailOutboxListRequest,
+) (dto.EmailOutboxPaginationResponse, error) {
+	messages, pagination, err := s.outboxRepo.GetAllWithPagination(ctx, nil, req.Status, req.PaginationRequest)
+	if err != nil {
+		return dto.EmailOutboxPaginationResponse{}, err
+	}
+
+	data := make([]dto.EmailOutboxResponse, 0, len(messages))
+	for _, message := range messages {
+		data = append(data, toEmailOutboxResponse(message))
+	}
+
+	return dto.EmailOutboxPaginationResponse{
+		Data:               data,
+		PaginationResponse: pagination,
+	}, nil
+}
+
+// recordAttempt updates message with the outcome of a delivery attempt: sent on success, otherwise a retry scheduled
+// with exponential backoff, or dead once the maximum number of attempts is reached or the recipient is suppressed.
+func recordAttempt(message *entity.EmailOutbox, sendErr error, now time.Time) {
+	message.Attempts++
+
+	if sendErr == nil {
+		message.Status = constants.ENUM_OUTBOX_SENT
+		message.SentAt = &now
+		message.LastError = ""
+		return
+	}
+
+	message.LastError = sendErr.Error()
+
+	maxAttempts := message.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = OUTBOX_MAX_ATTEMPTS
+	}
+
+	if message.Attempts >= maxAttempts || errors.Is(sendErr, mailer.ErrRecipientSuppressed) {
+		message.Status = constants.ENUM_OUTBOX_DEAD
+		return
+	}
+
+	message.Status = constants.ENUM_OUTBOX_PENDING
+	message.NextAttemptAt = now.Add(outboxBackoff(message.Attempts))
+}
+
+// outboxBackoff returns the delay before the retry following the given number of failed attempts.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := OUTBOX_BASE_BACKOFF
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= OUTBOX_MAX_BACKOFF {
+			return OUTBOX_MAX_BACKOFF
+		}
+	}
+
+	return backoff
+}
+
+// toEmailOutboxResponse maps an outbox entity to the response returned by the admin API.
+func toEmailOutboxResponse(message entity.EmailOutbox) dto.EmailOutboxResponse {
+	return dto.EmailOutboxResponse{
+		ID:            message.ID.String(),
+		Recipient:     message.Recipient,
+		Subject:       message.Subject,
+		Status:        message.Status,
+		Attempts:      message.Attempts,
+		MaxAttempts:   message.MaxAttempts,
+		NextAttemptAt: message.NextAttemptAt,
+		LastError:     message.LastError,
+		SentAt:        message.SentAt,
+		CreatedAt:     message.CreatedAt,
+	}
+}
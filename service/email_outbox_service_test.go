@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+// mockEmailOutboxRepository is a testify mock of the outbox repository methods used without a transaction.
+type mockEmailOutboxRepository struct {
+	repository.EmailOutboxRepository
+	mock.Mock
+}
+
+// Create records the queued message.
+func (m *mockEmailOutboxRepository) Create(
+	_ context.Context,
+	_ *gorm.DB,
+	message entity.EmailOutbox,
+) (entity.EmailOutbox, error) {
+	args := m.Called(message)
+	return message, args.Error(0)
+}
+
+// GetById returns the mocked message.
+func (m *mockEmailOutboxRepository) GetById(_ context.Context, _ *gorm.DB, id string) (entity.EmailOutbox, error) {
+	args := m.Called(id)
+	return args.Get(0).(entity.EmailOutbox), args.Error(1)
+}
+
+// Save records the saved message.
+func (m *mockEmailOutboxRepository) Save(_ context.Context, _ *gorm.DB, message entity.EmailOutbox) error {
+	return m.Called(message).Error(0)
+}
+
+// TestOutboxBackoff verifies that the retry delay doubles per attempt and is capped.
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 4, want: 4 * time.Minute},
+		{attempts: 20, want: OUTBOX_MAX_BACKOFF},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, outboxBackoff(tt.attempts), "attempts=%d", tt.attempts)
+	}
+}
+
+// TestRecordAttempt verifies the status transitions of an outbox message after a delivery attempt.
+func TestRecordAttempt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		message         entity.EmailOutbox
+		sendErr         error
+		wantStatus      string
+		wantNextAttempt time.Time
+		wantLastError   string
+		wantSent        bool
+	}{
+		{
+			name:       "success marks sent",
+			message:    entity.EmailOutbox{Status: constants.ENUM_OUTBOX_PENDING, MaxAttempts: 3, LastError: "old"},
+			wantStatus: constants.ENUM_OUTBOX_SENT,
+			wantSent:   true,
+		},
+		{
+			name:            "failure schedules retry",
+			message:         entity.EmailOutbox{Status: constants.ENUM_OUTBOX_PENDING, Attempts: 1, MaxAttempts: 3},
+			sendErr:         errors.New("smtp down"),
+			wantStatus:      constants.ENUM_OUTBOX_PENDING,
+			wantNextAttempt: now.Add(time.Minute),
+			wantLastError:   "smtp down",
+		},
+		{
+			name:          "suppressed recipient dead-letters immediately",
+			message:       entity.EmailOutbox{Status: constants.ENUM_OUTBOX_PENDING, MaxAttempts: 3},
+			sendErr:       mailer.ErrRecipientSuppressed,
+			wantStatus:    constants.ENUM_OUTBOX_DEAD,
+			wantLastError: mailer.ErrRecipientSuppressed.Error(),
+		},
+		{
+			name:          "last failure dead-letters",
+			message:       entity.EmailOutbox{Status: constants.ENUM_OUTBOX_PENDING, Attempts: 2, MaxAttempts: 3},
+			sendErr:       errors.New("mailbox unavailable"),
+			wantStatus:    constants.ENUM_OUTBOX_DEAD,
+			wantLastError: "mailbox unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				message := tt.message
+				recordAttempt(&message, tt.sendErr, now)
+
+				assert.Equal(t, tt.message.Attempts+1, message.Attempts)
+				assert.Equal(t, tt.wantStatus, message.Status)
+				assert.Equal(t, tt.wantLastError, message.LastError)
+				assert.Equal(t, tt.wantSent, message.SentAt != nil)
+				if !tt.wantNextAttempt.IsZero() {
+					assert.Equal(t, tt.wantNextAttempt, message.NextAttemptAt)
+				}
+			},
+		)
+	}
+}
+
+// TestEmailOutboxService_Enqueue verifies that Enqueue writes one pending entry per recipient, due immediately.
+func TestEmailOutboxService_Enqueue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockEmailOutboxRepository{}
+	for _, recipient := range []string{"user@example.com", "other@example.com"} {
+		repo.On(
+			"Create", entity.EmailOutbox{
+				Recipient:     recipient,
+				Subject:       "Hello",
+				Body:          "<p>Hi</p>",
+				TextBody:      "Hi",
+				Status:        constants.ENUM_OUTBOX_PENDING,
+				MaxAttempts:   OUTBOX_MAX_ATTEMPTS,
+				NextAttemptAt: now,
+			},
+		).Return(nil).Once()
+	}
+
+	s := &emailOutboxService{outboxRepo: repo, now: func() time.Time { return now }}
+
+	err := s.Enqueue(
+		context.Background(), nil, mailer.Message{
+			To:      []string{"user@example.com", "other@example.com"},
+			Subject: "Hello",
+			HTML:    "<p>Hi</p>",
+			Text:    "Hi",
+		},
+	)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+
+	err = s.Enqueue(context.Background(), nil, mailer.Message{Subject: "Hello"})
+	assert.ErrorIs(t, err, mailer.ErrNoRecipients)
+}
+
+// TestEmailOutboxService_Requeue verifies that only dead messages can be requeued and that attempts are reset.
+func TestEmailOutboxService_Requeue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(repo *mockEmailOutboxRepository)
+		wantErr error
+	}{
+		{
+			name: "dead message is requeued",
+			setup: func(repo *mockEmailOutboxRepository) {
+				repo.On("GetById", id.String()).Return(
+					entity.EmailOutbox{ID: id, Status: constants.ENUM_OUTBOX_DEAD, Attempts: 8, LastError: "boom"}, nil,
+				)
+				repo.On(
+					"Save", entity.EmailOutbox{
+						ID:            id,
+						Status:        constants.ENUM_OUTBOX_PENDING,
+						NextAttemptAt: now,
+						LastError:     "boom",
+					},
+				).Return(nil)
+			},
+		},
+		{
+			name: "sent message is rejected",
+			setup: func(repo *mockEmailOutboxRepository) {
+				repo.On("GetById", id.String()).Return(
+					entity.EmailOutbox{ID: id, Status: constants.ENUM_OUTBOX_SENT}, nil,
+				)
+			},
+			wantErr: dto.ErrEmailOutboxNotDead,
+		},
+		{
+			name: "missing message is reported",
+			setup: func(repo *mockEmailOutboxRepository) {
+				repo.On("GetById", id.String()).Return(entity.EmailOutbox{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: dto.ErrEmailOutboxNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				repo := &mockEmailOutboxRepository{}
+				tt.setup(repo)
+				s := &emailOutboxService{outboxRepo: repo, now: func() time.Time { return now }}
+
+				result, err := s.Requeue(context.Background(), id.String())
+
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, constants.ENUM_OUTBOX_PENDING, result.Status)
+				assert.Zero(t, result.Attempts)
+				repo.AssertExpectations(t)
+			},
+		)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+type (
+	// EmailSuppressionService maintains the list of addresses the mailer must not send to.
+	// HandleEvents and HandleDSN suppress addresses reported by bounce and complaint notifications, in the generic
+	// JSON format or as RFC 3464 delivery status notifications. Suppressed implements mailer.SuppressionList.
+	// GetAllWithPagination lists suppressed addresses and Clear removes one so it receives email again.
+	EmailSuppressionService interface {
+		mailer.SuppressionList
+		HandleEvents(ctx context.Context, events []dto.EmailEvent) (dto.EmailEventResult, error)
+		HandleDSN(ctx context.Context, raw io.Reader) (dto.EmailEventResult, error)
+		GetAllWithPagination(
+			ctx context.Context,
+			req dto.PaginationRequest,
+		) (dto.EmailSuppressionPaginationResponse, error)
+		Clear(ctx context.Context, email string) error
+	}
+
+	// emailSuppressionService implements EmailSuppressionService.
+	emailSuppressionService struct {
+		suppressionRepo repository.EmailSuppressionRepository
+		userRepo        repository.UserRepository
+		txManager       repository.TxManager
+	}
+)
+
+// NewEmailSuppressionService creates an EmailSuppressionService that also flags the affected users.
+func NewEmailSuppressionService(
+	suppressionRepo repository.EmailSuppressionRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TxManager,
+) EmailSuppressionService {
+	return &emailSuppressionService{
+		suppressionRepo: suppressionRepo,
+		userRepo:        userRepo,
+		txManager:       txManager,
+	}
+}
+
+// HandleEvents suppresses the addresses of complaints and hard bounces; soft bounces are ignored.
+func (s *emailSuppressionService) HandleEvents(
+	ctx context.Context,
+	events []dto.EmailEvent,
+) (dto.EmailEventResult, error) {
+	var result dto.EmailEventResult
+	for _, event := range events {
+		reason, ok := suppressionReason(event)
+		if !ok {
+			result.Ignored++
+			continue
+		}
+
+		if err := s.suppress(ctx, event.Email, reason, eventDetail(event)); err != nil {
+			return result, err
+		}
+		result.Suppressed++
+	}
+
+	return result, nil
+}
+
+// HandleDSN parses a delivery status notification and suppresses the recipients whose delivery failed permanently.
+func (s *emailSuppressionService) HandleDSN(ctx context.Context, raw io.Reader) (dto.EmailEventResult, error) {
+	recipients, err := mailer.ParseDSN(raw)
+	if err != nil {
+		return dto.EmailEventResult{}, err
+	}
+
+	events := make([]dto.EmailEvent, 0, len(recipients))
+	for _, recipient := range recipients {
+		bounceType := dto.EMAIL_BOUNCE_SOFT
+		if recipient.Permanent() {
+			bounceType = dto.EMAIL_BOUNCE_HARD
+		}
+
+		events = append(
+			events, dto.EmailEvent{
+				Type:       dto.EMAIL_EVENT_BOUNCE,
+				Email:      recipient.Recipient,
+				BounceType: bounceType,
+				Status:     recipient.Status,
+				Diagnostic: recipient.DiagnosticCode,
+			},
+		)
+	}
+
+	return s.HandleEvents(ctx, events)
+}
+
+// Suppressed returns the lower-cased addresses among recipients that are on the suppression list.
+func (s *emailSuppressionService) Suppressed(ctx context.Context, recipients []string) ([]string, error) {
+	return s.suppressionRepo.FindSuppressed(ctx, nil, recipients)
+}
+
+// GetAllWithPagination lists suppressed addresses, most recently updated first.
+func (s *emailSuppressionService) GetAllWithPagination(
+	ctx context.Context,
+	req dto.PaginationRequest,
+) (dto.EmailSuppressionPaginationResponse, error) {
+	suppressions, pagination, err := s.suppressionRepo.GetAllWithPagination(ctx, nil, req)
+	if err != nil {
+		return dto.EmailSuppressionPaginationResponse{}, err
+	}
+
+	data := make([]dto.EmailSuppressionResponse, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		data = append(
+			data, dto.EmailSuppressionResponse{
+				Email:     suppression.Email,
+				Reason:    suppression.Reason,
+				Detail:    suppression.Detail,
+				CreatedAt: suppression.CreatedAt,
+				UpdatedAt: suppression.UpdatedAt,
+			},
+		)
+	}
+
+	return dto.EmailSuppressionPaginationResponse{
+		Data:               data,
+		PaginationResponse: pagination,
+	}, nil
+}
+
+// Clear removes an address from the suppression list and unflags its user.
+func (s *emailSuppressionService) Clear(ctx context.Context, email string) error {
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			deleted, err := s.suppressionRepo.Delete(ctx, nil, email)
+			if err != nil {
+				return err
+			}
+			if !deleted {
+				return dto.ErrEmailSuppressionNotFound
+			}
+
+			return s.userRepo.SetEmailSuppressed(ctx, nil, email, false)
+		},
+	)
+}
+
+// suppress adds an address to the suppression list and flags its user in one transaction.
+func (s *emailSuppressionService) suppress(ctx context.Context, email string, reason string, detail string) error {
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			err := s.suppressionRepo.Upsert(
+				ctx, nil, entity.EmailSuppression{
+					Email:  email,
+					Reason: reason,
+					Detail: detail,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			return s.userRepo.SetEmailSuppressed(ctx, nil, email, true)
+		},
+	)
+}
+
+// suppressionReason returns the suppression reason of an event, or false when the event must not suppress.
+func suppressionReason(event dto.EmailEvent) (string, bool) {
+	switch event.Type {
+	case dto.EMAIL_EVENT_COMPLAINT:
+		return constants.ENUM_SUPPRESSION_COMPLAINT, true
+	case dto.EMAIL_EVENT_BOUNCE:
+		hard := event.BounceType == dto.EMAIL_BOUNCE_HARD ||
+			(event.BounceType == "" && strings.HasPrefix(event.Status, "5"))
+		return constants.ENUM_SUPPRESSION_BOUNCE, hard
+	default:
+		return "", false
+	}
+}
+
+// eventDetail summarizes the status and diagnostic of an event for the admin listing.
+func eventDetail(event dto.EmailEvent) string {
+	switch {
+	case event.Status != "" && event.Diagnostic != "":
+		return fmt.Sprintf("%s %s", event.Status, event.Diagnostic)
+	case event.Status != "":
+		return event.Status
+	default:
+		return event.Diagnostic
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+)
+
+// TestSuppressionReason tests that complaints and hard bounces suppress an address while soft bounces do not.
+func TestSuppressionReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      dto.EmailEvent
+		wantReason string
+		wantOK     bool
+	}{
+		{
+			name:       "complaint",
+			event:      dto.EmailEvent{Type: dto.EMAIL_EVENT_COMPLAINT},
+			wantReason: constants.ENUM_SUPPRESSION_COMPLAINT,
+			wantOK:     true,
+		},
+		{
+			name:       "hard bounce",
+			event:      dto.EmailEvent{Type: dto.EMAIL_EVENT_BOUNCE, BounceType: dto.EMAIL_BOUNCE_HARD},
+			wantReason: constants.ENUM_SUPPRESSION_BOUNCE,
+			wantOK:     true,
+		},
+		{
+			name:       "soft bounce",
+			event:      dto.EmailEvent{Type: dto.EMAIL_EVENT_BOUNCE, BounceType: dto.EMAIL_BOUNCE_SOFT, Status: "5.1.1"},
+			wantReason: constants.ENUM_SUPPRESSION_BOUNCE,
+			wantOK:     false,
+		},
+		{
+			name:       "permanent status without bounce type",
+			event:      dto.EmailEvent{Type: dto.EMAIL_EVENT_BOUNCE, Status: "5.1.1"},
+			wantReason: constants.ENUM_SUPPRESSION_BOUNCE,
+			wantOK:     true,
+		},
+		{
+			name:       "transient status without bounce type",
+			event:      dto.EmailEvent{Type: dto.EMAIL_EVENT_BOUNCE, Status: "4.2.2"},
+			wantReason: constants.ENUM_SUPPRESSION_BOUNCE,
+			wantOK:     false,
+		},
+		{
+			name:   "unknown type",
+			event:  dto.EmailEvent{Type: "delivery"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				reason, ok := suppressionReason(tt.event)
+
+				assert.Equal(t, tt.wantOK, ok)
+				if tt.wantOK {
+					assert.Equal(t, tt.wantReason, reason)
+				}
+			},
+		)
+	}
+}
+
+// TestEventDetail tests that the status and diagnostic of an event are combined for the admin listing.
+func TestEventDetail(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    dto.EmailEvent
+		expected string
+	}{
+		{
+			name:     "status and diagnostic",
+			event:    dto.EmailEvent{Status: "5.1.1", Diagnostic: "smtp; 550 user unknown"},
+			expected: "5.1.1 smtp; 550 user unknown",
+		},
+		{name: "status only", event: dto.EmailEvent{Status: "5.1.1"}, expected: "5.1.1"},
+		{name: "diagnostic only", event: dto.EmailEvent{Diagnostic: "spam"}, expected: "spam"},
+		{name: "neither", event: dto.EmailEvent{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, eventDetail(tt.event))
+			},
+		)
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/sms"
+)
+
+const (
+	// PHONE_CODE_LENGTH is the number of digits of the codes sent to verify phone numbers.
+	PHONE_CODE_LENGTH = 6
+
+	// PHONE_CODE_TTL is how long a phone verification code stays valid.
+	PHONE_CODE_TTL = 10 * time.Minute
+
+	// PHONE_CODE_MAX_ATTEMPTS is the number of wrong codes that can be tried before a new code must be requested.
+	PHONE_CODE_MAX_ATTEMPTS = 5
+
+	// PHONE_CODE_RESEND_INTERVAL is how long a user must wait before another code is sent to the same phone number.
+	PHONE_CODE_RESEND_INTERVAL = time.Minute
+)
+
+type (
+	// PhoneVerificationService proves that users receive text messages at their phone number. SendCode texts a
+	// one-time code to the phone number of a user and VerifyCode marks the number as verified when the code matches.
+	PhoneVerificationService interface {
+		SendCode(ctx context.Context, userId string) (dto.PhoneVerificationResponse, error)
+		VerifyCode(ctx context.Context, userId string, req dto.VerifyPhoneRequest) (dto.PhoneVerificationResponse, error)
+	}
+
+	// phoneVerificationService implements PhoneVerificationService, keeping the hashed codes in the database.
+	phoneVerificationService struct {
+		verificationRepo repository.PhoneVerificationRepository
+		userRepo         repository.UserRepository
+		auditRepo        repository.AuditEventRepository
+		sender           sms.SMSSender
+		appName          string
+		txManager        repository.TxManager
+		now              func() time.Time
+	}
+)
+
+// NewPhoneVerificationService creates a new PhoneVerificationService that texts codes through sender, signed with
+// appName, and stores them in transactions of txManager. Verified phone numbers are recorded in the audit log of
+// auditRepo.
+func NewPhoneVerificationService(
+	verificationRepo repository.PhoneVerificationRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditEventRepository,
+	sender sms.SMSSender,
+	txManager repository.TxManager,
+	appName string,
+) PhoneVerificationService {
+	return &phoneVerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		auditRepo:        auditRepo,
+		sender:           sender,
+		appName:          appName,
+		txManager:        txManager,
+		now:              time.Now,
+	}
+}
+
+// SendCode texts a new code to the unverified phone number of a user, replacing the code sent before. It returns
+// dto.ErrPhoneCodeResendTooSoon when a code was sent to the same number less than PHONE_CODE_RESEND_INTERVAL ago. The
+// code is only stored once the text message is accepted by the SMS backend.
+func (s *phoneVerificationService) SendCode(ctx context.Context, userId string) (dto.PhoneVerificationResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.PhoneVerificationResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	if user.PhoneNumber == "" {
+		return dto.PhoneVerificationResponse{}, dto.ErrPhoneNumberRequired
+	}
+
+	if user.PhoneVerifiedAt != nil {
+		return dto.PhoneVerificationResponse{}, dto.ErrPhoneAlreadyVerified
+	}
+
+	code, codeHash, err := newPhoneCode()
+	if err != nil {
+		return dto.PhoneVerificationResponse{}, err
+	}
+
+	now := s.now()
+	verification := entity.PhoneVerification{
+		UserID:      user.ID,
+		PhoneNumber: user.PhoneNumber,
+		CodeHash:    codeHash,
+		ExpiresAt:   now.Add(PHONE_CODE_TTL),
+	}
+
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			previous, err := s.verificationRepo.GetByUserId(ctx, nil, userId)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err == nil && previous.PhoneNumber == user.PhoneNumber &&
+				now.Sub(previous.CreatedAt) < PHONE_CODE_RESEND_INTERVAL {
+				return dto.ErrPhoneCodeResendTooSoon
+			}
+
+			if err := s.verificationRepo.DeleteByUserId(ctx, nil, userId); err != nil {
+				return err
+			}
+
+			if _, err := s.verificationRepo.Create(ctx, nil, verification); err != nil {
+				return err
+			}
+
+			message := sms.Message{
+				To: user.PhoneNumber,
+				Body: fmt.Sprintf(
+					"%s is your %s verification code. It expires in %d minutes.",
+					code, s.appName, int(PHONE_CODE_TTL.Minutes()),
+				),
+			}
+			if err := s.sender.Send(ctx, message); err != nil {
+				return errors.Join(dto.ErrSendPhoneCode, err)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return dto.PhoneVerificationResponse{}, err
+	}
+
+	return dto.PhoneVerificationResponse{
+		PhoneNumber: user.PhoneNumber,
+		ExpiresAt:   &verification.ExpiresAt,
+	}, nil
+}
+
+// VerifyCode marks the phone number of a user as verified when code is the last code texted to it and removes the
+// code. It returns dto.ErrPhoneCodeExpired once the code is older than PHONE_CODE_TTL and
+// dto.ErrPhoneCodeAttemptsExceeded once PHONE_CODE_MAX_ATTEMPTS wrong codes were tried; every wrong code is counted
+// even though dto.ErrPhoneCodeInvalid is returned.
+func (s *phoneVerificationService) VerifyCode(
+	ctx context.Context,
+	userId string,
+	req dto.VerifyPhoneRequest,
+) (dto.PhoneVerificationResponse, error) {
+	var (
+		user    entity.User
+		codeErr error
+	)
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			verification, err := s.verificationRepo.GetByUserId(ctx, nil, userId)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.ErrPhoneCodeInvalid
+			}
+			if err != nil {
+				return err
+			}
+
+			current, err := s.userRepo.GetUserById(ctx, nil, userId)
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			if current.PhoneVerifiedAt != nil {
+				return dto.ErrPhoneAlreadyVerified
+			}
+
+			if verification.PhoneNumber != current.PhoneNumber {
+				return dto.ErrPhoneCodeInvalid
+			}
+
+			now := s.now()
+			if now.After(verification.ExpiresAt) {
+				return dto.ErrPhoneCodeExpired
+			}
+
+			if verification.Attempts >= PHONE_CODE_MAX_ATTEMPTS {
+				return dto.ErrPhoneCodeAttemptsExceeded
+			}
+
+			if ok, _ := helpers.CheckPassword(verification.CodeHash, []byte(req.Code)); !ok {
+				codeErr = dto.ErrPhoneCodeInvalid
+				return s.verificationRepo.IncrementAttempts(ctx, nil, verification.ID.String())
+			}
+
+			current.PhoneVerifiedAt = &now
+			user, err = s.userRepo.UpdateColumns(ctx, nil, current, "phone_verified_at")
+			if err != nil {
+				return err
+			}
+
+			if err := s.verificationRepo.DeleteByUserId(ctx, nil, userId); err != nil {
+				return err
+			}
+
+			return recordAuditEvent(
+				ctx, s.auditRepo, nil, current.ID, constants.ENUM_AUDIT_PHONE_VERIFIED,
+				map[string]string{"phone_number": current.PhoneNumber},
+			)
+		},
+	)
+	if err != nil {
+		return dto.PhoneVerificationResponse{}, err
+	}
+	if codeErr != nil {
+		return dto.PhoneVerificationResponse{}, codeErr
+	}
+
+	return dto.PhoneVerificationResponse{
+		PhoneNumber: user.PhoneNumber,
+		VerifiedAt:  user.PhoneVerifiedAt,
+	}, nil
+}
+
+// newPhoneCode returns a random numeric code of PHONE_CODE_LENGTH digits and the bcrypt hash under which it is stored.
+func newPhoneCode() (string, string, error) {
+	limit := big.NewInt(1)
+	for range PHONE_CODE_LENGTH {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", "", err
+	}
+
+	code := fmt.Sprintf("%0*d", PHONE_CODE_LENGTH, n)
+	codeHash, err := helpers.HashPassword(code)
+	if err != nil {
+		return "", "", err
+	}
+
+	return code, codeHash, nil
+}
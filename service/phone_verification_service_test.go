@@ -0,0 +1,21 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+)
+
+// TestNewPhoneCode tests that phone verification codes have PHONE_CODE_LENGTH digits and are stored as bcrypt hashes.
+func TestNewPhoneCode(t *testing.T) {
+	code, codeHash, err := newPhoneCode()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9]{6}$`, code)
+	assert.NotContains(t, codeHash, code)
+
+	ok, err := helpers.CheckPassword(codeHash, []byte(code))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// UPLOAD_PURGE_INTERVAL is how often the upload janitor removes expired uploads.
+const UPLOAD_PURGE_INTERVAL = time.Hour
+
+// UploadJanitor removes expired resumable uploads in the background until its context is cancelled.
+type UploadJanitor struct {
+	uploads  UploadService
+	interval time.Duration
+}
+
+// NewUploadJanitor creates an UploadJanitor that purges expired uploads every interval.
+func NewUploadJanitor(uploads UploadService, interval time.Duration) *UploadJanitor {
+	return &UploadJanitor{
+		uploads:  uploads,
+		interval: interval,
+	}
+}
+
+// Run purges expired uploads immediately and then every interval until ctx is cancelled.
+func (j *UploadJanitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := j.uploads.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("upload janitor: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("upload janitor: purged %d expired uploads", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
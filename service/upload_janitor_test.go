@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUploads is an UploadService stub that counts purge runs and cancels the janitor after a scripted number of them.
+type fakeUploads struct {
+	UploadService
+	mu     sync.Mutex
+	calls  int
+	stopAt int
+	cancel context.CancelFunc
+}
+
+// PurgeExpired records the call, fails every other run and cancels the janitor once stopAt runs happened.
+func (f *fakeUploads) PurgeExpired(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls >= f.stopAt {
+		f.cancel()
+	}
+	if f.calls%2 == 0 {
+		return 0, errors.New("database unavailable")
+	}
+
+	return 1, nil
+}
+
+// TestUploadJanitor_Run verifies that the janitor keeps purging after failures and stops on cancellation.
+func TestUploadJanitor_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	uploads := &fakeUploads{stopAt: 3, cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		NewUploadJanitor(uploads, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("janitor did not stop after cancellation")
+	}
+
+	assert.GreaterOrEqual(t, uploads.calls, 3, "a failed purge should not stop the janitor")
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+const (
+	// UPLOAD_MAX_SIZE is the largest file accepted through a resumable upload.
+	UPLOAD_MAX_SIZE = 256 << 20
+
+	// UPLOAD_MAX_CHUNK_SIZE is the largest chunk accepted in a single request.
+	UPLOAD_MAX_CHUNK_SIZE = 8 << 20
+
+	// UPLOAD_TTL is how long an upload is kept after its last chunk, or after its completion, before it is purged.
+	UPLOAD_TTL = 24 * time.Hour
+
+	// UPLOAD_URL_TTL is how long the signed download URLs of completed uploads stay valid.
+	UPLOAD_URL_TTL = time.Hour
+
+	// UPLOAD_PURGE_BATCH_SIZE is the number of expired uploads removed per query when purging.
+	UPLOAD_PURGE_BATCH_SIZE = 100
+)
+
+type (
+	// UploadService implements resumable uploads: a user creates an upload, sends its bytes as chunks at the current
+	// offset, and completes it once every byte has arrived. Chunks and completed files are kept in storage.
+	// Create starts an upload, Get reports its progress, WriteChunk appends a chunk, Complete assembles the chunks into
+	// a single file, Cancel removes an upload, AttachAvatar uses a completed upload as the profile image of its owner
+	// and PurgeExpired removes uploads past their expiry time.
+	UploadService interface {
+		Create(ctx context.Context, userId string, req dto.UploadCreateRequest) (dto.UploadResponse, error)
+		Get(ctx context.Context, userId string, uploadId string) (dto.UploadResponse, error)
+		WriteChunk(
+			ctx context.Context,
+			userId string,
+			uploadId string,
+			offset int64,
+			checksum string,
+			chunk io.Reader,
+		) (dto.UploadResponse, error)
+		Complete(ctx context.Context, userId string, uploadId string) (dto.UploadResponse, error)
+		Cancel(ctx context.Context, userId string, uploadId string) error
+		AttachAvatar(ctx context.Context, userId string, uploadId string) (dto.UserResponse, error)
+		PurgeExpired(ctx context.Context) (int, error)
+	}
+
+	// uploadService implements UploadService, keeping upload progress in the database and bytes in files.
+	uploadService struct {
+		uploadRepo  repository.UploadRepository
+		userService UserService
+		files       storage.Driver
+		txManager   repository.TxManager
+		now         func() time.Time
+	}
+)
+
+// NewUploadService creates a new UploadService that stores chunks and completed files in files.
+func NewUploadService(
+	uploadRepo repository.UploadRepository,
+	userService UserService,
+	files storage.Driver,
+	txManager repository.TxManager,
+) UploadService {
+	return &uploadService{
+		uploadRepo:  uploadRepo,
+		userService: userService,
+		files:       files,
+		txManager:   txManager,
+		now:         time.Now,
+	}
+}
+
+// Create starts an upload of req.Size bytes owned by the user. The content type defaults to the one implied by the
+// file name extension.
+func (s *uploadService) Create(ctx context.Context, userId string, req dto.UploadCreateRequest) (
+	dto.UploadResponse,
+	error,
+) {
+	ownerID, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.UploadResponse{}, dto.ErrUserNotFound
+	}
+	if req.Size > UPLOAD_MAX_SIZE {
+		return dto.UploadResponse{}, fmt.Errorf("%w of %d bytes", dto.ErrUploadTooLarge, UPLOAD_MAX_SIZE)
+	}
+
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(req.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	upload, err := s.uploadRepo.Create(
+		ctx, nil, entity.Upload{
+			UserID:      ownerID,
+			Filename:    path.Base(strings.ReplaceAll(req.Filename, "\\", "/")),
+			ContentType: contentType,
+			Size:        req.Size,
+			Checksum:    strings.ToLower(req.Checksum),
+			Status:      constants.ENUM_UPLOAD_PENDING,
+			ExpiresAt:   s.now().Add(UPLOAD_TTL),
+		},
+	)
+	if err != nil {
+		return dto.UploadResponse{}, err
+	}
+
+	return s.toUploadResponse(ctx, upload)
+}
+
+// Get returns the progress of an upload owned by the user.
+func (s *uploadService) Get(ctx context.Context, userId string, uploadId string) (dto.UploadResponse, error) {
+	upload, err := s.owned(ctx, nil, userId, uploadId, false)
+	if err != nil {
+		return dto.UploadResponse{}, err
+	}
+
+	return s.toUploadResponse(ctx, upload)
+}
+
+// WriteChunk appends chunk to an upload. The chunk must start at the number of bytes received so far, must not exceed
+// UPLOAD_MAX_CHUNK_SIZE or the remaining size and, when checksum is given, must match it. Every chunk extends the
+// expiry time of the upload. Chunks are written under their offset, so a chunk that is retried after a failure
+// replaces its earlier copy.
+func (s *uploadService) WriteChunk(
+	ctx context.Context,
+	userId string,
+	uploadId string,
+	offset int64,
+	checksum string,
+	chunk io.Reader,
+) (dto.UploadResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(chunk, UPLOAD_MAX_CHUNK_SIZE+1))
+	if err != nil {
+		return dto.UploadResponse{}, err
+	}
+	if len(data) > UPLOAD_MAX_CHUNK_SIZE {
+		return dto.UploadResponse{}, dto.ErrUploadChunkTooLarge
+	}
+	if err := verifyChunkChecksum(checksum, data); err != nil {
+		return dto.UploadResponse{}, err
+	}
+
+	var upload entity.Upload
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			upload, err = s.owned(ctx, nil, userId, uploadId, true)
+			if err != nil {
+				return err
+			}
+			if err := s.checkPending(upload); err != nil {
+				return err
+			}
+			if offset != upload.Received {
+				return fmt.Errorf("%w: expected offset %d", dto.ErrUploadOffsetMismatch, upload.Received)
+			}
+			if int64(len(data)) > upload.Size-upload.Received {
+				return dto.ErrUploadChunkTooLarge
+			}
+
+			if len(data) > 0 {
+				if err := s.files.Put(ctx, chunkKey(upload.ID, offset), bytes.NewReader(data), ""); err != nil {
+					return err
+				}
+			}
+
+			upload.Received += int64(len(data))
+			upload.ExpiresAt = s.now().Add(UPLOAD_TTL)
+			upload, err = s.uploadRepo.Save(ctx, nil, upload)
+			return err
+		},
+	)
+	if err != nil {
+		return dto.UploadResponse{}, err
+	}
+
+	return s.toUploadResponse(ctx, upload)
+}
+
+// Complete assembles the chunks of a fully received upload into a single file and verifies the declared checksum.
+// When the assembled file does not match the checksum, the received bytes are discarded and the upload starts over
+// at offset 0. A completed upload expires UPLOAD_TTL after completion unless it is attached first.
+func (s *uploadService) Complete(ctx context.Context, userId string, uploadId string) (dto.UploadResponse, error) {
+	var (
+		upload   entity.Upload
+		mismatch bool
+	)
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			var err error
+			upload, err = s.owned(ctx, nil, userId, uploadId, true)
+			if err != nil {
+				return err
+			}
+			if err := s.checkPending(upload); err != nil {
+				return err
+			}
+			if upload.Received != upload.Size {
+				return fmt.Errorf("%w: %d of %d bytes received", dto.ErrUploadIncomplete, upload.Received, upload.Size)
+			}
+
+			digest := sha256.New()
+			key := fileKey(upload.ID)
+			if err := s.assemble(ctx, upload, key, digest); err != nil {
+				return err
+			}
+
+			if upload.Checksum != "" && hex.EncodeToString(digest.Sum(nil)) != upload.Checksum {
+				mismatch = true
+				upload.Received = 0
+				upload.ExpiresAt = s.now().Add(UPLOAD_TTL)
+				upload, err = s.uploadRepo.Save(ctx, nil, upload)
+				return err
+			}
+
+			completedAt := s.now()
+			upload.Status = constants.ENUM_UPLOAD_COMPLETED
+			upload.StorageKey = key
+			upload.CompletedAt = &completedAt
+			upload.ExpiresAt = completedAt.Add(UPLOAD_TTL)
+			upload, err = s.uploadRepo.Save(ctx, nil, upload)
+			return err
+		},
+	)
+	if err != nil {
+		return dto.UploadResponse{}, err
+	}
+
+	s.deleteFiles(context.WithoutCancel(ctx), upload, mismatch)
+	if mismatch {
+		return dto.UploadResponse{}, fmt.Errorf("%w: the upload was reset to offset 0", dto.ErrUploadChecksumMismatch)
+	}
+
+	return s.toUploadResponse(ctx, upload)
+}
+
+// Cancel removes an upload owned by the user together with its chunks and file.
+func (s *uploadService) Cancel(ctx context.Context, userId string, uploadId string) error {
+	upload, err := s.owned(ctx, nil, userId, uploadId, false)
+	if err != nil {
+		return err
+	}
+
+	if err := s.uploadRepo.Delete(ctx, nil, upload.ID.String()); err != nil {
+		return err
+	}
+
+	s.deleteFiles(context.WithoutCancel(ctx), upload, true)
+	return nil
+}
+
+// AttachAvatar uses a completed upload as the profile image of its owner. The image goes through the same
+// validation as a direct avatar upload, and the upload is removed once the avatar is saved.
+func (s *uploadService) AttachAvatar(ctx context.Context, userId string, uploadId string) (dto.UserResponse, error) {
+	upload, err := s.owned(ctx, nil, userId, uploadId, false)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	if upload.Status != constants.ENUM_UPLOAD_COMPLETED {
+		return dto.UserResponse{}, dto.ErrUploadNotCompleted
+	}
+	if s.now().After(upload.ExpiresAt) {
+		return dto.UserResponse{}, dto.ErrUploadExpired
+	}
+
+	body, _, err := s.files.Get(ctx, upload.StorageKey)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	defer func() { _ = body.Close() }()
+
+	user, err := s.userService.UpdateAvatar(ctx, userId, body)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	if err := s.uploadRepo.Delete(ctx, nil, upload.ID.String()); err != nil {
+		return dto.UserResponse{}, err
+	}
+	s.deleteFiles(context.WithoutCancel(ctx), upload, true)
+
+	return user, nil
+}
+
+// PurgeExpired removes every upload past its expiry time, abandoned or completed, together with its chunks and file,
+// and returns how many were removed.
+func (s *uploadService) PurgeExpired(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		uploads, err := s.uploadRepo.GetExpired(ctx, nil, s.now(), UPLOAD_PURGE_BATCH_SIZE)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, upload := range uploads {
+			s.deleteFiles(ctx, upload, true)
+			if err := s.uploadRepo.Delete(ctx, nil, upload.ID.String()); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(uploads) < UPLOAD_PURGE_BATCH_SIZE || ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
+	}
+}
+
+// owned fetches an upload, locking it when lock is set, and hides uploads of other users as not found.
+func (s *uploadService) owned(
+	ctx context.Context,
+	tx *gorm.DB,
+	userId string,
+	uploadId string,
+	lock bool,
+) (entity.Upload, error) {
+	if _, err := uuid.Parse(uploadId); err != nil {
+		return entity.Upload{}, dto.ErrUploadNotFound
+	}
+
+	var (
+		upload entity.Upload
+		err    error
+	)
+	if lock {
+		upload, err = s.uploadRepo.Lock(ctx, tx, uploadId)
+	} else {
+		upload, err = s.uploadRepo.GetById(ctx, tx, uploadId)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.Upload{}, dto.ErrUploadNotFound
+	}
+	if err != nil {
+		return entity.Upload{}, err
+	}
+	if upload.UserID.String() != userId {
+		return entity.Upload{}, dto.ErrUploadNotFound
+	}
+
+	return upload, nil
+}
+
+// checkPending rejects uploads that are completed or past their expiry time.
+func (s *uploadService) checkPending(upload entity.Upload) error {
+	if upload.Status != constants.ENUM_UPLOAD_PENDING {
+		return dto.ErrUploadNotPending
+	}
+	if s.now().After(upload.ExpiresAt) {
+		return dto.ErrUploadExpired
+	}
+
+	return nil
+}
+
+// assemble streams the chunks of upload, in offset order, into the file at key while feeding them to digest.
+// It fails when the chunks do not cover the upload without gaps.
+func (s *uploadService) assemble(ctx context.Context, upload entity.Upload, key string, digest hash.Hash) error {
+	chunks, err := s.files.List(ctx, UploadChunkPrefix(upload.ID))
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		var written int64
+		for _, chunk := range chunks {
+			offset, err := strconv.ParseInt(path.Base(chunk.Key), 10, 64)
+			if err != nil || offset != written {
+				_ = writer.CloseWithError(fmt.Errorf("upload chunks are not contiguous at offset %d", written))
+				return
+			}
+
+			body, _, err := s.files.Get(ctx, chunk.Key)
+			if err != nil {
+				_ = writer.CloseWithError(err)
+				return
+			}
+			n, err := io.Copy(writer, body)
+			_ = body.Close()
+			if err != nil {
+				_ = writer.CloseWithError(err)
+				return
+			}
+			written += n
+		}
+
+		if written != upload.Size {
+			_ = writer.CloseWithError(fmt.Errorf("upload chunks hold %d of %d bytes", written, upload.Size))
+			return
+		}
+		_ = writer.Close()
+	}()
+
+	err = s.files.Put(ctx, key, io.TeeReader(reader, digest), upload.ContentType)
+	_ = reader.CloseWithError(err)
+	if err != nil {
+		_ = s.files.Delete(context.WithoutCancel(ctx), key)
+	}
+
+	return err
+}
+
+// deleteFiles removes the chunks of an upload and, when withFile is set, its assembled file, ignoring failures.
+func (s *uploadService) deleteFiles(ctx context.Context, upload entity.Upload, withFile bool) {
+	deleteUploadFiles(ctx, s.files, upload, withFile)
+}
+
+// deleteUploadFiles removes the chunks of an upload from files and, when withFile is set, its assembled file,
+// ignoring failures.
+func deleteUploadFiles(ctx context.Context, files storage.Driver, upload entity.Upload, withFile bool) {
+	chunks, _ := files.List(ctx, UploadChunkPrefix(upload.ID))
+	for _, chunk := range chunks {
+		_ = files.Delete(ctx, chunk.Key)
+	}
+
+	if withFile {
+		_ = files.Delete(ctx, fileKey(upload.ID))
+	}
+}
+
+// toUploadResponse maps an upload to the UploadResponse returned to callers, with a signed URL once it is completed.
+func (s *uploadService) toUploadResponse(ctx context.Context, upload entity.Upload) (dto.UploadResponse, error) {
+	response := dto.UploadResponse{
+		ID:          upload.ID.String(),
+		Filename:    upload.Filename,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		Offset:      upload.Received,
+		Checksum:    upload.Checksum,
+		Status:      upload.Status,
+		ExpiresAt:   upload.ExpiresAt,
+	}
+
+	if upload.Status == constants.ENUM_UPLOAD_COMPLETED {
+		url, err := s.files.SignedURL(ctx, upload.StorageKey, UPLOAD_URL_TTL)
+		if err != nil {
+			return dto.UploadResponse{}, err
+		}
+		response.URL = url
+	}
+
+	return response, nil
+}
+
+// verifyChunkChecksum checks data against a checksum given as "sha256 <base64 digest>". An empty checksum is accepted.
+func verifyChunkChecksum(checksum string, data []byte) error {
+	if checksum == "" {
+		return nil
+	}
+
+	algorithm, encoded, ok := strings.Cut(strings.TrimSpace(checksum), " ")
+	if !ok || !strings.EqualFold(algorithm, "sha256") {
+		return dto.ErrUploadChecksumUnsupported
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(expected) != sha256.Size {
+		return dto.ErrUploadChecksumUnsupported
+	}
+
+	actual := sha256.Sum256(data)
+	if !bytes.Equal(actual[:], expected) {
+		return dto.ErrUploadChecksumMismatch
+	}
+
+	return nil
+}
+
+// UploadChunkPrefix returns the storage prefix below which the chunks of an upload are kept. It is exported for the
+// orphan file cleanup, which must keep the chunks of uploads in progress.
+func UploadChunkPrefix(uploadID uuid.UUID) string {
+	return "uploads/" + uploadID.String() + "/chunks/"
+}
+
+// chunkKey returns the storage key of the chunk of an upload starting at offset. Offsets are zero-padded so keys
+// list in offset order.
+func chunkKey(uploadID uuid.UUID, offset int64) string {
+	return fmt.Sprintf("%s%020d", UploadChunkPrefix(uploadID), offset)
+}
+
+// fileKey returns the storage key of the file assembled from the chunks of an upload.
+func fileKey(uploadID uuid.UUID) string {
+	return "uploads/" + uploadID.String() + "/file"
+}
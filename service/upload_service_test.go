@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestVerifyChunkChecksum tests that chunk checksums are accepted only as matching base64 SHA-256 digests.
+func TestVerifyChunkChecksum(t *testing.T) {
+	digest := sha256.Sum256([]byte("chunk"))
+	valid := base64.StdEncoding.EncodeToString(digest[:])
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  error
+	}{
+		{name: "no checksum", checksum: ""},
+		{name: "matching checksum", checksum: "sha256 " + valid},
+		{name: "algorithm is case-insensitive", checksum: "SHA256 " + valid},
+		{
+			name:     "mismatching checksum",
+			checksum: "sha256 " + base64.StdEncoding.EncodeToString(make([]byte, sha256.Size)),
+			wantErr:  dto.ErrUploadChecksumMismatch,
+		},
+		{name: "unsupported algorithm", checksum: "md5 " + valid, wantErr: dto.ErrUploadChecksumUnsupported},
+		{name: "missing digest", checksum: "sha256", wantErr: dto.ErrUploadChecksumUnsupported},
+		{name: "invalid base64", checksum: "sha256 !!!", wantErr: dto.ErrUploadChecksumUnsupported},
+		{
+			name:     "hex digest",
+			checksum: "sha256 " + hex.EncodeToString(digest[:]),
+			wantErr:  dto.ErrUploadChecksumUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := verifyChunkChecksum(tt.checksum, []byte("chunk"))
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			},
+		)
+	}
+}
+
+// TestChunkKey tests that chunk keys list in offset order.
+func TestChunkKey(t *testing.T) {
+	id := uuid.New()
+
+	assert.True(t, strings.HasPrefix(chunkKey(id, 0), UploadChunkPrefix(id)))
+	assert.Less(t, chunkKey(id, 9), chunkKey(id, 10))
+	assert.Less(t, chunkKey(id, 8<<20), chunkKey(id, 16<<20))
+}
+
+// TestUploadService_Assemble tests that chunks are joined in offset order and that gaps or missing bytes are
+// rejected without leaving a partial file behind.
+func TestUploadService_Assemble(t *testing.T) {
+	tests := []struct {
+		name    string
+		chunks  map[int64]string
+		size    int64
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "contiguous chunks",
+			chunks: map[int64]string{0: "hello ", 6: "resumable ", 16: "world"},
+			size:   21,
+			want:   "hello resumable world",
+		},
+		{
+			name:    "gap between chunks",
+			chunks:  map[int64]string{0: "hello ", 10: "world"},
+			size:    15,
+			wantErr: "not contiguous",
+		},
+		{
+			name:    "missing last chunk",
+			chunks:  map[int64]string{0: "hello "},
+			size:    11,
+			wantErr: "6 of 11 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				files := storage.NewMemoryDriver()
+				s := &uploadService{files: files}
+				upload := entity.Upload{ID: uuid.New(), Size: tt.size, ContentType: "text/plain"}
+
+				for offset, data := range tt.chunks {
+					require.NoError(t, files.Put(ctx, chunkKey(upload.ID, offset), strings.NewReader(data), ""))
+				}
+
+				digest := sha256.New()
+				err := s.assemble(ctx, upload, fileKey(upload.ID), digest)
+				if tt.wantErr != "" {
+					assert.ErrorContains(t, err, tt.wantErr)
+					_, err := files.Stat(ctx, fileKey(upload.ID))
+					assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+					return
+				}
+
+				require.NoError(t, err)
+				body, info, err := files.Get(ctx, fileKey(upload.ID))
+				require.NoError(t, err)
+				data, err := io.ReadAll(body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, string(data))
+				assert.Equal(t, "text/plain", info.ContentType)
+
+				expected := sha256.Sum256([]byte(tt.want))
+				assert.Equal(t, expected[:], digest.Sum(nil))
+			},
+		)
+	}
+}
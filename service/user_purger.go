@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// USER_PURGE_INTERVAL is how often the user purger removes accounts whose deletion grace period ended.
+const USER_PURGE_INTERVAL = time.Hour
+
+// UserPurger permanently removes deleted accounts in the background until its context is cancelled.
+type UserPurger struct {
+	users    UserService
+	interval time.Duration
+}
+
+// NewUserPurger creates a UserPurger that purges deleted accounts every interval.
+func NewUserPurger(users UserService, interval time.Duration) *UserPurger {
+	return &UserPurger{
+		users:    users,
+		interval: interval,
+	}
+}
+
+// Run purges deleted accounts immediately and then every interval until ctx is cancelled.
+func (p *UserPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.users.PurgeDeleted(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("user purger: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("user purger: purged %d deleted accounts", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePurgeUsers is a UserService stub that counts purge runs and cancels the purger after a scripted number of them.
+type fakePurgeUsers struct {
+	UserService
+	mu     sync.Mutex
+	calls  int
+	stopAt int
+	cancel context.CancelFunc
+}
+
+// PurgeDeleted records the call, fails every other run and cancels the purger once stopAt runs happened.
+func (f *fakePurgeUsers) PurgeDeleted(context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.calls >= f.stopAt {
+		f.cancel()
+	}
+	if f.calls%2 == 0 {
+		return 0, errors.New("database unavailable")
+	}
+
+	return 1, nil
+}
+
+// TestUserPurger_Run verifies that the purger keeps running after failures and stops on cancellation.
+func TestUserPurger_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	users := &fakePurgeUsers{stopAt: 3, cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		NewUserPurger(users, time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after cancellation")
+	}
+
+	assert.GreaterOrEqual(t, users.calls, 3, "a failed purge should not stop the purger")
+}
@@ -3,14 +3,21 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"html/template"
-	"os"
-	"path"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
@@ -18,7 +25,10 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/media"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
 	"github.com/Caknoooo/go-gin-clean-starter/utils"
 )
 
@@ -26,27 +36,53 @@ type (
 	// UserService defines the methods required for managing user operations and authentication.
 	// Register creates a new user with the provided creation request.
 	// GetAllUserWithPagination retrieves paginated lists of users based on the request criteria.
+	// Search finds users by name, email address or phone number, most relevant first.
 	// GetUserById fetches user details using a specific user ID.
 	// GetUserByEmail retrieves user details using their email address.
 	// SendVerificationEmail sends a verification email to the specified user.
 	// VerifyEmail processes email verification requests and returns a response.
 	// Update modifies user information based on the update request and user ID.
-	// Delete removes a user from the system using their user ID.
+	// Patch applies a JSON merge patch to the profile of a user.
+	// ConfirmEmailChange applies a pending change of email address confirmed from the new address.
+	// Delete schedules the account of a user for deletion after a grace period.
 	// Verify authenticates a user and generates a token based on login request details.
 	// RefreshToken generates a new access token using a valid refresh token.
 	// RevokeRefreshToken revokes all refresh tokens for a specified user ID.
+	// CreateAdmin creates a verified administrator account without sending a verification email.
+	// ResetPassword replaces the password of a user and revokes their sessions.
+	// MarkEmailVerified marks the email of a user as verified without a verification token.
+	// ChangeRole assigns a new role to a user.
+	// UpdateAvatar replaces the profile image of a user with the image read from a reader.
+	// DeleteAvatar removes the profile image of a user.
+	// Deactivate switches the account of a user off until it is restored.
+	// Restore reactivates the deactivated or deleted account of the user signing in and signs them in.
+	// RestoreById reactivates a deactivated or deleted account on behalf of its owner.
+	// PurgeDeleted permanently removes the accounts whose deletion grace period ended.
 	UserService interface {
 		Register(ctx context.Context, req dto.UserCreateRequest) (dto.UserResponse, error)
 		GetAllUserWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.UserPaginationResponse, error)
+		Search(ctx context.Context, req dto.UserSearchRequest) (dto.UserSearchResponse, error)
 		GetUserById(ctx context.Context, userId string) (dto.UserResponse, error)
 		GetUserByEmail(ctx context.Context, email string) (dto.UserResponse, error)
 		SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error
 		VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error)
 		Update(ctx context.Context, req dto.UserUpdateRequest, userId string) (dto.UserUpdateResponse, error)
+		Patch(ctx context.Context, req dto.UserPatchRequest, userId string) (dto.UserUpdateResponse, error)
+		ConfirmEmailChange(ctx context.Context, req dto.ConfirmEmailChangeRequest) (dto.UserResponse, error)
 		Delete(ctx context.Context, userId string) error
 		Verify(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error)
 		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.TokenResponse, error)
 		RevokeRefreshToken(ctx context.Context, userID string) error
+		CreateAdmin(ctx context.Context, req dto.AdminCreateRequest) (dto.UserResponse, error)
+		ResetPassword(ctx context.Context, userId string, password string) error
+		MarkEmailVerified(ctx context.Context, userId string) (dto.UserResponse, error)
+		ChangeRole(ctx context.Context, userId string, role string) (dto.UserResponse, error)
+		UpdateAvatar(ctx context.Context, userId string, image io.Reader) (dto.UserResponse, error)
+		DeleteAvatar(ctx context.Context, userId string) (dto.UserResponse, error)
+		Deactivate(ctx context.Context, userId string) error
+		Restore(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error)
+		RestoreById(ctx context.Context, userId string) (dto.UserResponse, error)
+		PurgeDeleted(ctx context.Context) (int, error)
 	}
 
 	// userService is a struct that implements the UserService interface and manages user-related operations.
@@ -54,22 +90,58 @@ type (
 		userRepo         repository.UserRepository
 		refreshTokenRepo repository.RefreshTokenRepository
 		jwtService       JWTService
-		db               *gorm.DB
+		outboxRepo       repository.EmailOutboxRepository
+		uploadRepo       repository.UploadRepository
+		exportRepo       repository.DataExportRepository
+		emailChangeRepo  repository.EmailChangeRepository
+		auditRepo        repository.AuditEventRepository
+		renderer         mailer.Renderer
+		files            storage.Driver
+		txManager        repository.TxManager
+		phoneCountryCode string
+	}
+
+	// emailChangeRequest is a pending change of email address ready to be stored, with the emails announcing it.
+	emailChangeRequest struct {
+		change entity.EmailChange
+		emails []mailer.Message
+		now    time.Time
 	}
 )
 
 // NewUserService initializes and returns a new instance of UserService with the provided dependencies.
+// Emails are rendered with renderer, queued in the email outbox and delivered by the email dispatcher. Profile images
+// are kept in files, as are the uploads and data exports removed when an account is purged. Writes spanning several
+// repositories run in transactions of txManager, which also record security-relevant actions in the audit log of
+// auditRepo. Phone numbers are written in E.164 format, with phoneCountryCode as
+// the country code of numbers written without one.
 func NewUserService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	outboxRepo repository.EmailOutboxRepository,
+	uploadRepo repository.UploadRepository,
+	exportRepo repository.DataExportRepository,
+	emailChangeRepo repository.EmailChangeRepository,
+	auditRepo repository.AuditEventRepository,
 	jwtService JWTService,
-	db *gorm.DB,
+	renderer mailer.Renderer,
+	files storage.Driver,
+	txManager repository.TxManager,
+	phoneCountryCode string,
 ) UserService {
 	return &userService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
 		jwtService:       jwtService,
-		db:               db,
+		outboxRepo:       outboxRepo,
+		uploadRepo:       uploadRepo,
+		exportRepo:       exportRepo,
+		emailChangeRepo:  emailChangeRepo,
+		auditRepo:        auditRepo,
+		renderer:         renderer,
+		files:            files,
+		txManager:        txManager,
+		phoneCountryCode: phoneCountryCode,
 	}
 }
 
@@ -79,19 +151,29 @@ const (
 
 	// VERIFY_EMAIL_ROUTE specifies the endpoint for email verification during the user registration process.
 	VERIFY_EMAIL_ROUTE = "register/verify_email"
-)
 
-// SafeRollback ensures that a transaction is safely rolled back in case of a panic and re-panics after rollback.
-func SafeRollback(tx *gorm.DB) {
-	if r := recover(); r != nil {
-		tx.Rollback()
-		panic(r)
-	}
-}
+	// PROFILE_IMAGE_URL_TTL is how long the signed profile image URLs returned in user responses stay valid.
+	PROFILE_IMAGE_URL_TTL = time.Hour
+
+	// USER_DELETION_GRACE_PERIOD is how long a deleted account can still be restored before it is purged.
+	USER_DELETION_GRACE_PERIOD = 30 * 24 * time.Hour
 
-// Register handles user registration by creating a new user, verifying email uniqueness, and sending a verification email.
+	// USER_PURGE_BATCH_SIZE is the number of accounts fetched per query when purging.
+	USER_PURGE_BATCH_SIZE = 100
+
+	// CONFIRM_EMAIL_CHANGE_ROUTE specifies the page confirming a change of email address, linked from the email sent
+	// to the new address.
+	CONFIRM_EMAIL_CHANGE_ROUTE = "user/confirm_email_change"
+
+	// EMAIL_CHANGE_TTL is how long the link confirming a change of email address stays valid.
+	EMAIL_CHANGE_TTL = 24 * time.Hour
+)
+
+// Register handles user registration by creating a new user, verifying email uniqueness, and queueing a verification
+// email in the same transaction. The profile image is stored first and removed again if the user is not created. The
+// phone number is written in E.164 format and helpers.ErrInvalidPhoneNumber is returned when it cannot be.
 func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (dto.UserResponse, error) {
-	var filename string
+	var imageKey string
 
 	_, flag, err := s.userRepo.CheckEmail(ctx, nil, req.Email)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -102,120 +184,159 @@ func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (
 		return dto.UserResponse{}, dto.ErrEmailAlreadyExists
 	}
 
-	if req.Image != nil {
-		imageId := uuid.New()
-		ext := utils.GetExtensions(req.Image.Filename)
+	phoneNumber, err := s.normalizePhoneNumber(req.PhoneNumber)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
 
-		filename = fmt.Sprintf("profile/%s.%s", imageId, ext)
-		if err := utils.UploadFile(req.Image, filename); err != nil {
+	if req.Image != nil {
+		imageKey, err = s.storeUploadedProfileImage(ctx, req.Image)
+		if err != nil {
 			return dto.UserResponse{}, err
 		}
 	}
 
+	committed := false
+	defer func() {
+		if !committed {
+			s.deleteProfileImage(context.WithoutCancel(ctx), imageKey)
+		}
+	}()
+
 	user := entity.User{
 		Name:        req.Name,
-		PhoneNumber: req.PhoneNumber,
-		ImageUrl:    filename,
+		PhoneNumber: phoneNumber,
+		ImageUrl:    imageKey,
 		Role:        constants.ENUM_ROLE_USER,
 		Email:       req.Email,
 		Password:    req.Password,
 		IsVerified:  false,
 	}
 
-	userReg, err := s.userRepo.Register(ctx, nil, user)
+	verificationEmail, err := makeVerificationEmail(s.renderer, user.Email, req.Locale)
 	if err != nil {
-		return dto.UserResponse{}, dto.ErrCreateUser
+		return dto.UserResponse{}, err
 	}
 
-	draftEmail, err := makeVerificationEmail(userReg.Email)
+	var userReg entity.User
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			registered, err := s.userRepo.Register(ctx, nil, user)
+			if err != nil {
+				return errors.Join(dto.ErrCreateUser, err)
+			}
+			userReg = registered
+
+			if err := recordAuditEvent(
+				ctx, s.auditRepo, nil, registered.ID, constants.ENUM_AUDIT_ACCOUNT_CREATED, nil,
+			); err != nil {
+				return err
+			}
+
+			return enqueueEmail(ctx, s.outboxRepo, nil, verificationEmail, time.Now())
+		},
+	)
 	if err != nil {
 		return dto.UserResponse{}, err
 	}
+	committed = true
+
+	return s.toUserResponse(ctx, userReg)
+}
+
+// storeUploadedProfileImage stores a profile image received as a multipart file and returns its key.
+func (s *userService) storeUploadedProfileImage(ctx context.Context, image *multipart.FileHeader) (string, error) {
+	if image.Size > media.MAX_IMAGE_SIZE {
+		return "", media.ErrImageTooLarge
+	}
 
-	err = utils.SendMail(userReg.Email, draftEmail["subject"], draftEmail["body"])
+	file, err := image.Open()
 	if err != nil {
-		return dto.UserResponse{}, err
+		return "", err
 	}
+	defer func() { _ = file.Close() }()
 
-	return dto.UserResponse{
-		ID:          userReg.ID.String(),
-		Name:        userReg.Name,
-		PhoneNumber: userReg.PhoneNumber,
-		ImageUrl:    userReg.ImageUrl,
-		Role:        userReg.Role,
-		Email:       userReg.Email,
-		IsVerified:  userReg.IsVerified,
-	}, nil
+	return s.storeProfileImage(ctx, file)
 }
 
-// makeVerificationEmail generates a verification email draft with a secure token embedded in the verification link.
-// It returns a map containing the subject and body of the email or an error if the operation fails.
-func makeVerificationEmail(receiverEmail string) (map[string]string, error) {
-	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
-	plainText := receiverEmail + "_" + expired
-	token, err := utils.AESEncrypt(plainText)
+// storeProfileImage validates and re-encodes a profile image, stores it together with its thumbnails under a new key
+// in the profile directory and returns the key.
+// The key extension follows the re-encoded format, never the client file name.
+func (s *userService) storeProfileImage(ctx context.Context, image io.Reader) (string, error) {
+	processed, err := media.Process(image, media.MAX_IMAGE_SIZE)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	verifyLink := LOCAL_URL + "/" + VERIFY_EMAIL_ROUTE + "?token=" + token
+	key := fmt.Sprintf("profile/%s.%s", uuid.New(), processed.Ext)
+	if err := s.files.Put(ctx, key, bytes.NewReader(processed.Data), processed.ContentType); err != nil {
+		return "", err
+	}
 
-	templatePath := "utils/email-template/base_mail.html"
-	projectPath, err := helpers.GetProjectRoot()
-	if err != nil {
-		return nil, err
+	for _, size := range media.THUMBNAIL_SIZES {
+		thumbnail := bytes.NewReader(processed.Thumbnails[size])
+		if err := s.files.Put(ctx, media.ThumbnailKey(key, size), thumbnail, processed.ContentType); err != nil {
+			s.deleteProfileImage(context.WithoutCancel(ctx), key)
+			return "", err
+		}
 	}
-	templatePath = path.Join(projectPath, templatePath)
-	readHtml, err := os.ReadFile(templatePath)
-	if err != nil {
-		return nil, err
+
+	return key, nil
+}
+
+// deleteProfileImage removes a stored profile image and its thumbnails, ignoring failures.
+// Empty keys and external URLs are left alone.
+func (s *userService) deleteProfileImage(ctx context.Context, key string) {
+	if key == "" || storage.IsExternalURL(key) {
+		return
 	}
 
-	data := struct {
-		Email  string
-		Verify string
-	}{
-		Email:  receiverEmail,
-		Verify: verifyLink,
+	_ = s.files.Delete(ctx, key)
+	for _, size := range media.THUMBNAIL_SIZES {
+		_ = s.files.Delete(ctx, media.ThumbnailKey(key, size))
 	}
+}
 
-	tmpl, err := template.New("custom").Parse(string(readHtml))
+// makeVerificationEmail renders the verify_email template in the given locale for receiverEmail, embedding a
+// secure token in the verification link.
+func makeVerificationEmail(renderer mailer.Renderer, receiverEmail string, locale string) (mailer.Message, error) {
+	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
+	plainText := receiverEmail + "_" + expired
+	token, err := utils.AESEncrypt(plainText)
 	if err != nil {
-		return nil, err
+		return mailer.Message{}, err
 	}
 
-	var strMail bytes.Buffer
-	if err := tmpl.Execute(&strMail, data); err != nil {
-		return nil, err
-	}
+	verifyLink := LOCAL_URL + "/" + VERIFY_EMAIL_ROUTE + "?token=" + token
 
-	draftEmail := map[string]string{
-		"subject": "Cakno - Go Gin Template",
-		"body":    strMail.String(),
+	msg, err := renderer.Render(
+		"verify_email", locale, map[string]any{
+			"Email":      receiverEmail,
+			"VerifyLink": verifyLink,
+		},
+	)
+	if err != nil {
+		return mailer.Message{}, err
 	}
 
-	return draftEmail, nil
+	msg.To = []string{receiverEmail}
+	return msg, nil
 }
 
-// SendVerificationEmail sends a verification email to the user specified in the request.
-// It retrieves the user by email, creates a verification email draft, and sends the email.
+// SendVerificationEmail queues a verification email for the user specified in the request.
+// It retrieves the user by email, creates a verification email draft, and writes it to the email outbox.
 func (s *userService) SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error {
 	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
 	if err != nil {
-		return dto.ErrEmailNotFound
+		return lookupError(err, dto.ErrEmailNotFound, "get user by email")
 	}
 
-	draftEmail, err := makeVerificationEmail(user.Email)
+	verificationEmail, err := makeVerificationEmail(s.renderer, user.Email, req.Locale)
 	if err != nil {
 		return err
 	}
 
-	err = utils.SendMail(user.Email, draftEmail["subject"], draftEmail["body"])
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return enqueueEmail(ctx, s.outboxRepo, nil, verificationEmail, time.Now())
 }
 
 // VerifyEmail verifies a user's email using a token, updating the user's verified status if the token is valid and unexpired.
@@ -248,7 +369,7 @@ func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 
 	user, err := s.userRepo.GetUserByEmail(ctx, nil, email)
 	if err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrUserNotFound
+		return dto.VerifyEmailResponse{}, lookupError(err, dto.ErrUserNotFound, "get user by email")
 	}
 
 	if user.IsVerified {
@@ -259,10 +380,14 @@ func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 		ctx, nil, entity.User{
 			ID:         user.ID,
 			IsVerified: true,
+			Versioned:  user.Versioned,
 		},
 	)
+	if errors.Is(err, dto.ErrVersionConflict) {
+		return dto.VerifyEmailResponse{}, err
+	}
 	if err != nil {
-		return dto.VerifyEmailResponse{}, dto.ErrUpdateUser
+		return dto.VerifyEmailResponse{}, errors.Join(dto.ErrUpdateUser, err)
 	}
 
 	return dto.VerifyEmailResponse{
@@ -283,27 +408,47 @@ func (s *userService) GetAllUserWithPagination(
 
 	var users []dto.UserResponse
 	for _, user := range dataWithPaginate.Users {
-		data := dto.UserResponse{
-			ID:          user.ID.String(),
-			Name:        user.Name,
-			Email:       user.Email,
-			Role:        user.Role,
-			PhoneNumber: user.PhoneNumber,
-			ImageUrl:    user.ImageUrl,
-			IsVerified:  user.IsVerified,
+		data, err := s.toUserResponse(ctx, user)
+		if err != nil {
+			return dto.UserPaginationResponse{}, err
 		}
 
 		users = append(users, data)
 	}
 
 	return dto.UserPaginationResponse{
-		Data: users,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    dataWithPaginate.Page,
-			PerPage: dataWithPaginate.PerPage,
-			MaxPage: dataWithPaginate.MaxPage,
-			Count:   dataWithPaginate.Count,
-		},
+		Data:               users,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
+	}, nil
+}
+
+// Search finds users by name, email address or phone number and returns them most relevant first, with the matched
+// fields highlighted.
+func (s *userService) Search(ctx context.Context, req dto.UserSearchRequest) (dto.UserSearchResponse, error) {
+	found, err := s.userRepo.Search(ctx, nil, req)
+	if err != nil {
+		return dto.UserSearchResponse{}, err
+	}
+
+	results := make([]dto.UserSearchResult, 0, len(found.Hits))
+	for _, hit := range found.Hits {
+		user, err := s.toUserResponse(ctx, hit.User)
+		if err != nil {
+			return dto.UserSearchResponse{}, err
+		}
+
+		results = append(
+			results, dto.UserSearchResult{
+				UserResponse: user,
+				Rank:         hit.Rank,
+				Highlights:   hit.Highlights,
+			},
+		)
+	}
+
+	return dto.UserSearchResponse{
+		Data:               results,
+		PaginationResponse: found.PaginationResponse,
 	}, nil
 }
 
@@ -311,172 +456,687 @@ func (s *userService) GetAllUserWithPagination(
 func (s *userService) GetUserById(ctx context.Context, userId string) (dto.UserResponse, error) {
 	user, err := s.userRepo.GetUserById(ctx, nil, userId)
 	if err != nil {
-		return dto.UserResponse{}, dto.ErrGetUserById
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
 	}
 
-	return dto.UserResponse{
-		ID:          user.ID.String(),
-		Name:        user.Name,
-		PhoneNumber: user.PhoneNumber,
-		Role:        user.Role,
-		Email:       user.Email,
-		ImageUrl:    user.ImageUrl,
-		IsVerified:  user.IsVerified,
-	}, nil
+	return s.toUserResponse(ctx, user)
 }
 
 // GetUserByEmail retrieves a user by their email address and returns a UserResponse or an error if the operation fails.
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (dto.UserResponse, error) {
 	emails, err := s.userRepo.GetUserByEmail(ctx, nil, email)
 	if err != nil {
-		return dto.UserResponse{}, dto.ErrGetUserByEmail
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user by email")
 	}
 
-	return dto.UserResponse{
-		ID:          emails.ID.String(),
-		Name:        emails.Name,
-		PhoneNumber: emails.PhoneNumber,
-		Role:        emails.Role,
-		Email:       emails.Email,
-		ImageUrl:    emails.ImageUrl,
-		IsVerified:  emails.IsVerified,
-	}, nil
+	return s.toUserResponse(ctx, emails)
 }
 
 // Update modifies user details based on the provided request data and user ID, returning the updated user or an error.
+// Empty fields of req are left unchanged, and a new email address is applied once confirmed, as for Patch. It returns dto.ErrVersionConflict when req.IfMatch does not match the
+// current version of the user, or when the user is changed by another request before the update is written.
 func (s *userService) Update(ctx context.Context, req dto.UserUpdateRequest, userId string) (
 	dto.UserUpdateResponse,
 	error,
+) {
+	changes := map[string]string{}
+	if req.Name != "" {
+		changes["name"] = req.Name
+	}
+	if req.Email != "" {
+		changes["email"] = req.Email
+	}
+	if req.PhoneNumber != "" {
+		changes["phone_number"] = req.PhoneNumber
+	}
+
+	patch, err := json.Marshal(changes)
+	if err != nil {
+		return dto.UserUpdateResponse{}, err
+	}
+
+	patchReq := dto.UserPatchRequest{Patch: patch, IfMatch: req.IfMatch, Locale: req.Locale}
+	return s.Patch(ctx, patchReq, userId)
+}
+
+// Patch applies the JSON merge patch of req to the profile of a user and writes the fields it changed. The patched
+// profile is validated as a whole, so clearing a required field with null is refused, and a patch that changes
+// nothing writes nothing. A new email address is not written: it becomes a pending change, confirmed with a link
+// emailed to the new address while the current address is notified, and it is refused with dto.ErrEmailAlreadyExists
+// when another account uses it. A new phone number is written in E.164 format and is no longer verified. It returns
+// dto.ErrVersionConflict when req.IfMatch does not match the current version of the user, or when the user is changed
+// by another request before the patch is written.
+func (s *userService) Patch(ctx context.Context, req dto.UserPatchRequest, userId string) (
+	dto.UserUpdateResponse,
+	error,
 ) {
 	user, err := s.userRepo.GetUserById(ctx, nil, userId)
 	if err != nil {
-		return dto.UserUpdateResponse{}, dto.ErrUserNotFound
+		return dto.UserUpdateResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
 	}
 
-	data := entity.User{
-		ID:          user.ID,
-		Name:        req.Name,
-		PhoneNumber: req.PhoneNumber,
-		Role:        user.Role,
-		Email:       req.Email,
+	if req.IfMatch != "" && !utils.MatchesETag(req.IfMatch, user.Version) {
+		return dto.UserUpdateResponse{}, dto.ErrVersionConflict
 	}
 
-	userUpdate, err := s.userRepo.Update(ctx, nil, data)
+	current := dto.UserProfile{
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+	}
+	profile, err := patchUserProfile(current, req.Patch, s.phoneCountryCode)
 	if err != nil {
-		return dto.UserUpdateResponse{}, dto.ErrUpdateUser
+		return dto.UserUpdateResponse{}, err
+	}
+
+	changed := []string{}
+	if profile.Name != current.Name {
+		changed = append(changed, "name")
+	}
+	if profile.PhoneNumber != current.PhoneNumber {
+		changed = append(changed, "phone_number")
+	}
+
+	var pendingEmail string
+	var emailChange emailChangeRequest
+	if profile.Email != current.Email {
+		if err := s.checkEmailAvailable(ctx, profile.Email, user.ID); err != nil {
+			return dto.UserUpdateResponse{}, err
+		}
+
+		emailChange, err = makeEmailChangeRequest(s.renderer, user, profile.Email, req.Locale, time.Now())
+		if err != nil {
+			return dto.UserUpdateResponse{}, err
+		}
+		pendingEmail = profile.Email
+	}
+
+	versioned := user.Versioned
+	if len(changed) > 0 || pendingEmail != "" {
+		err = s.txManager.WithinTransaction(
+			ctx, func(ctx context.Context) error {
+				if len(changed) > 0 {
+					data := entity.User{
+						ID:          user.ID,
+						Name:        profile.Name,
+						PhoneNumber: profile.PhoneNumber,
+						Versioned:   user.Versioned,
+					}
+
+					columns := changed
+					if slices.Contains(changed, "phone_number") {
+						columns = append(slices.Clone(changed), "phone_verified_at")
+					}
+
+					updated, err := s.userRepo.UpdateColumns(ctx, nil, data, columns...)
+					if err != nil {
+						return err
+					}
+					versioned = updated.Versioned
+				}
+
+				if pendingEmail != "" {
+					return s.requestEmailChange(ctx, emailChange)
+				}
+
+				return nil
+			},
+		)
+		if errors.Is(err, dto.ErrVersionConflict) || errors.Is(err, dto.ErrEmailAlreadyExists) ||
+			repository.IsUniqueViolation(err) {
+			return dto.UserUpdateResponse{}, err
+		}
+		if err != nil {
+			return dto.UserUpdateResponse{}, errors.Join(dto.ErrUpdateUser, err)
+		}
 	}
 
 	return dto.UserUpdateResponse{
-		ID:          userUpdate.ID.String(),
-		Name:        userUpdate.Name,
-		PhoneNumber: userUpdate.PhoneNumber,
-		Role:        userUpdate.Role,
-		Email:       userUpdate.Email,
-		IsVerified:  user.IsVerified,
+		ID:            user.ID.String(),
+		Name:          profile.Name,
+		PhoneNumber:   profile.PhoneNumber,
+		Role:          user.Role,
+		Email:         user.Email,
+		IsVerified:    user.IsVerified,
+		Version:       versioned.Version,
+		ChangedFields: changed,
+		PendingEmail:  pendingEmail,
 	}, nil
 }
 
-// Delete removes a user and their associated refresh tokens from the database, ensuring proper error handling and rollback.
-func (s *userService) Delete(ctx context.Context, userId string) error {
-	tx := s.db.Begin()
-	defer SafeRollback(tx)
+// checkEmailAvailable returns dto.ErrEmailAlreadyExists when email is the address of an account other than the user
+// identified by userId.
+func (s *userService) checkEmailAvailable(ctx context.Context, email string, userId uuid.UUID) error {
+	owner, found, err := s.userRepo.CheckEmail(ctx, nil, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if found && owner.ID != userId {
+		return dto.ErrEmailAlreadyExists
+	}
+
+	return nil
+}
+
+// normalizePhoneNumber writes phoneNumber in E.164 format, leaving it empty when it is empty.
+func (s *userService) normalizePhoneNumber(phoneNumber string) (string, error) {
+	if phoneNumber == "" {
+		return "", nil
+	}
 
-	user, err := s.userRepo.GetUserById(ctx, tx, userId)
+	return helpers.NormalizePhoneNumber(phoneNumber, s.phoneCountryCode)
+}
+
+// makeEmailChangeRequest prepares a pending change of the email address of user to newEmail: the change to store,
+// the confirmation email with its link sent to newEmail and the notice sent to the current address.
+func makeEmailChangeRequest(
+	renderer mailer.Renderer,
+	user entity.User,
+	newEmail string,
+	locale string,
+	now time.Time,
+) (emailChangeRequest, error) {
+	token, tokenHash, err := newEmailChangeToken()
 	if err != nil {
-		tx.Rollback()
-		return dto.ErrUserNotFound
+		return emailChangeRequest{}, err
 	}
 
-	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, user.ID.String()); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	expiresAt := now.Add(EMAIL_CHANGE_TTL)
+	confirmation, err := renderer.Render(
+		"confirm_email_change", locale, map[string]any{
+			"Email":       newEmail,
+			"ConfirmLink": LOCAL_URL + "/" + CONFIRM_EMAIL_CHANGE_ROUTE + "?token=" + token,
+			"ExpiresAt":   expiresAt.UTC().Format("2006-01-02 15:04 MST"),
+		},
+	)
+	if err != nil {
+		return emailChangeRequest{}, err
 	}
+	confirmation.To = []string{newEmail}
 
-	err = s.userRepo.Delete(ctx, tx, user.ID.String())
+	notice, err := renderer.Render(
+		"email_change_notice", locale, map[string]any{
+			"Email":    user.Email,
+			"NewEmail": newEmail,
+		},
+	)
 	if err != nil {
-		tx.Rollback()
-		return dto.ErrDeleteUser
+		return emailChangeRequest{}, err
+	}
+	notice.To = []string{user.Email}
+
+	return emailChangeRequest{
+		change: entity.EmailChange{
+			UserID:    user.ID,
+			NewEmail:  newEmail,
+			TokenHash: tokenHash,
+			ExpiresAt: expiresAt,
+		},
+		emails: []mailer.Message{confirmation, notice},
+		now:    now,
+	}, nil
+}
+
+// requestEmailChange replaces the pending email changes of a user with a new one, records it in the audit log and
+// queues its emails.
+func (s *userService) requestEmailChange(ctx context.Context, req emailChangeRequest) error {
+	if err := s.emailChangeRepo.DeleteByUserId(ctx, nil, req.change.UserID.String()); err != nil {
+		return err
+	}
+
+	if _, err := s.emailChangeRepo.Create(ctx, nil, req.change); err != nil {
+		return err
+	}
+
+	if err := recordAuditEvent(
+		ctx, s.auditRepo, nil, req.change.UserID, constants.ENUM_AUDIT_EMAIL_CHANGE_REQUESTED,
+		map[string]string{"new_email": req.change.NewEmail},
+	); err != nil {
+		return err
 	}
 
-	return tx.Commit().Error
+	for _, email := range req.emails {
+		if err := enqueueEmail(ctx, s.outboxRepo, nil, email, req.now); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// Verify authenticates a user by validating their credentials, generating tokens, and saving the refresh token to the database.
-func (s *userService) Verify(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error) {
-	tx := s.db.Begin()
-	defer SafeRollback(tx)
+// newEmailChangeToken returns a random token for the link confirming an email change, and the hash of the token
+// stored in its place.
+func newEmailChangeToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashEmailChangeToken(token), nil
+}
+
+// hashEmailChangeToken returns the hex-encoded SHA-256 hash of an email change token.
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	user, err := s.userRepo.GetUserByEmail(ctx, tx, req.Email)
+// ConfirmEmailChange applies the pending email change confirmed by the token of req and marks the new address as
+// verified. The address is checked again, so the change is refused with dto.ErrEmailAlreadyExists when another
+// account took it in the meantime. Every pending change of the user is discarded once one is applied.
+func (s *userService) ConfirmEmailChange(ctx context.Context, req dto.ConfirmEmailChangeRequest) (
+	dto.UserResponse,
+	error,
+) {
+	var user entity.User
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			change, err := s.emailChangeRepo.GetByTokenHash(ctx, nil, hashEmailChangeToken(req.Token))
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.ErrTokenInvalid
+			}
+			if err != nil {
+				return err
+			}
+
+			if time.Now().After(change.ExpiresAt) {
+				return dto.ErrTokenExpired
+			}
+
+			current, err := s.userRepo.GetUserById(ctx, nil, change.UserID.String())
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			if err := s.checkEmailAvailable(ctx, change.NewEmail, current.ID); err != nil {
+				return err
+			}
+
+			oldEmail := current.Email
+			current.Email = change.NewEmail
+			current.IsVerified = true
+			updated, err := s.userRepo.UpdateColumns(ctx, nil, current, "email", "is_verified")
+			if repository.IsUniqueViolation(err) {
+				return dto.ErrEmailAlreadyExists
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := s.emailChangeRepo.DeleteByUserId(ctx, nil, current.ID.String()); err != nil {
+				return err
+			}
+
+			if err := recordAuditEvent(
+				ctx, s.auditRepo, nil, current.ID, constants.ENUM_AUDIT_EMAIL_CHANGED,
+				map[string]string{"old_email": oldEmail, "new_email": change.NewEmail},
+			); err != nil {
+				return err
+			}
+
+			user = updated
+			return nil
+		},
+	)
 	if err != nil {
-		tx.Rollback()
-		return dto.TokenResponse{}, errors.New("invalid email or password")
+		return dto.UserResponse{}, err
 	}
 
-	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
-	if err != nil || !checkPassword {
-		tx.Rollback()
-		return dto.TokenResponse{}, errors.New("invalid email or password")
+	return s.toUserResponse(ctx, user)
+}
+
+// patchUserProfile applies a JSON merge patch to profile and validates the result with the validator of gin, so that
+// its errors are reported like those of request bodies. Members of the patch that are not fields of the profile, or
+// hold a value of the wrong type, make the patch invalid. A changed phone number is written
+// in E.164 format, taking numbers in national format to be in the country with countryCode.
+func patchUserProfile(profile dto.UserProfile, patch []byte, countryCode string) (dto.UserProfile, error) {
+	document, err := json.Marshal(profile)
+	if err != nil {
+		return dto.UserProfile{}, err
 	}
 
-	accessToken := s.jwtService.GenerateAccessToken(user.ID.String(), user.Role)
+	patched, err := utils.MergePatch(document, patch)
+	if err != nil {
+		return dto.UserProfile{}, err
+	}
 
-	refreshTokenString, expiresAt := s.jwtService.GenerateRefreshToken()
+	var result dto.UserProfile
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return dto.UserProfile{}, errors.Join(utils.ErrInvalidMergePatch, err)
+	}
 
-	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, user.ID.String()); err != nil {
-		tx.Rollback()
-		return dto.TokenResponse{}, err
+	if err := binding.Validator.ValidateStruct(result); err != nil {
+		return dto.UserProfile{}, err
 	}
 
-	refreshToken := entity.RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshTokenString,
-		ExpiresAt: expiresAt,
+	if result.PhoneNumber != "" && result.PhoneNumber != profile.PhoneNumber {
+		result.PhoneNumber, err = helpers.NormalizePhoneNumber(result.PhoneNumber, countryCode)
+		if err != nil {
+			return dto.UserProfile{}, err
+		}
 	}
 
-	if _, err := s.refreshTokenRepo.Create(ctx, tx, refreshToken); err != nil {
-		tx.Rollback()
+	return result, nil
+}
+
+// Delete schedules the account of a user for deletion: it is soft-deleted, marked as pending deletion and purged once
+// USER_DELETION_GRACE_PERIOD has passed, unless it is restored first. Its refresh tokens are revoked right away, while
+// its files are kept until the purge.
+func (s *userService) Delete(ctx context.Context, userId string) error {
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			user, err := s.userRepo.GetUserById(ctx, nil, userId)
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			if err := s.refreshTokenRepo.DeleteByUserID(ctx, nil, user.ID.String()); err != nil {
+				return fmt.Errorf("failed to delete refresh tokens: %w", err)
+			}
+
+			now := time.Now()
+			purgeAt := now.Add(USER_DELETION_GRACE_PERIOD)
+			user.Status = constants.ENUM_USER_PENDING_DELETION
+			user.PurgeAt = &purgeAt
+			user.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+			ok, err := s.userRepo.UpdateStatus(
+				ctx, nil, user, constants.ENUM_USER_ACTIVE, constants.ENUM_USER_DEACTIVATED,
+			)
+			if err != nil {
+				return errors.Join(dto.ErrDeleteUser, err)
+			}
+			if !ok {
+				return dto.ErrDeleteUser
+			}
+
+			return recordAuditEvent(ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_ACCOUNT_DELETED, nil)
+		},
+	)
+}
+
+// Deactivate switches the account of a user off and revokes its refresh tokens. A deactivated account cannot sign in
+// until it is restored, but unlike a deleted account it keeps its email address and is never purged. Deactivating a
+// deactivated account does nothing.
+func (s *userService) Deactivate(ctx context.Context, userId string) error {
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			user, err := s.userRepo.GetUserById(ctx, nil, userId)
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+			if user.Status == constants.ENUM_USER_DEACTIVATED {
+				return nil
+			}
+
+			user.Status = constants.ENUM_USER_DEACTIVATED
+			if _, err := s.userRepo.UpdateStatus(ctx, nil, user, constants.ENUM_USER_ACTIVE); err != nil {
+				return errors.Join(dto.ErrUpdateUser, err)
+			}
+
+			if err := recordAuditEvent(
+				ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_ACCOUNT_DEACTIVATED, nil,
+			); err != nil {
+				return err
+			}
+
+			return s.refreshTokenRepo.DeleteByUserID(ctx, nil, user.ID.String())
+		},
+	)
+}
+
+// Restore reactivates the deactivated account, or the account pending deletion, that matches the email address and
+// password of req, and signs the user in. When several deleted accounts used the address, the most recently changed
+// one with a matching password is restored. The account is only restored when the user is signed in.
+func (s *userService) Restore(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error) {
+	users, err := s.userRepo.GetRestorableUsersByEmail(ctx, nil, req.Email)
+	if err != nil {
 		return dto.TokenResponse{}, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return dto.TokenResponse{}, err
+	for _, user := range users {
+		checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
+		if err != nil || !checkPassword {
+			continue
+		}
+
+		var token dto.TokenResponse
+		err = s.txManager.WithinTransaction(
+			ctx, func(ctx context.Context) error {
+				if _, err := s.restore(ctx, user); err != nil {
+					return err
+				}
+
+				verified, err := s.Verify(ctx, req)
+				token = verified
+				return err
+			},
+		)
+		if err != nil {
+			return dto.TokenResponse{}, err
+		}
+
+		return token, nil
 	}
 
-	return dto.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshTokenString,
-		Role:         user.Role,
-	}, nil
+	return dto.TokenResponse{}, dto.ErrInvalidCredentials
 }
 
-// RefreshToken refreshes the user's access and refresh tokens using a valid refresh token, ensuring proper validation and handling.
-func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.TokenResponse, error) {
-	tx := s.db.Begin()
-	defer SafeRollback(tx)
+// RestoreById reactivates a deactivated account or an account pending deletion without its password, for
+// administrators acting on behalf of the owner.
+func (s *userService) RestoreById(ctx context.Context, userId string) (dto.UserResponse, error) {
+	user, err := s.userRepo.GetUserByIdWithDeleted(ctx, nil, userId)
+	if err != nil {
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			user, err = s.restore(ctx, user)
+			return err
+		},
+	)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	return s.toUserResponse(ctx, user)
+}
+
+// restore makes user active again and records it in the audit log, so it must run in a transaction. An account
+// pending deletion cannot be restored once another account has registered its email address.
+func (s *userService) restore(ctx context.Context, user entity.User) (entity.User, error) {
+	if user.DeletedAt.Valid {
+		_, taken, err := s.userRepo.CheckEmail(ctx, nil, user.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, err
+		}
+		if taken {
+			return entity.User{}, dto.ErrEmailAlreadyExists
+		}
+	}
 
-	dbToken, err := s.refreshTokenRepo.FindByToken(ctx, tx, req.RefreshToken)
+	user.Status = constants.ENUM_USER_ACTIVE
+	user.PurgeAt = nil
+	user.DeletedAt = gorm.DeletedAt{}
+
+	ok, err := s.userRepo.UpdateStatus(
+		ctx, nil, user, constants.ENUM_USER_DEACTIVATED, constants.ENUM_USER_PENDING_DELETION,
+	)
 	if err != nil {
-		tx.Rollback()
-		return dto.TokenResponse{}, errors.New(dto.MESSAGE_FAILED_INVALID_REFRESH_TOKEN)
+		return entity.User{}, err
+	}
+	if !ok {
+		return entity.User{}, dto.ErrAccountNotRestorable
+	}
+
+	if err := recordAuditEvent(
+		ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_ACCOUNT_RESTORED, nil,
+	); err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// PurgeDeleted permanently removes every account whose deletion grace period has ended, together with its profile
+// image, its uploads, its data exports and its refresh tokens, and returns how many accounts were removed.
+func (s *userService) PurgeDeleted(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		users, err := s.userRepo.GetPurgeable(ctx, nil, time.Now(), USER_PURGE_BATCH_SIZE)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			removed, err := s.purge(ctx, user)
+			if err != nil {
+				return purged, err
+			}
+			if removed {
+				purged++
+			}
+		}
+
+		if len(users) < USER_PURGE_BATCH_SIZE || ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
 	}
+}
 
-	if time.Now().After(dbToken.ExpiresAt) {
-		tx.Rollback()
-		return dto.TokenResponse{}, errors.New(dto.MESSAGE_FAILED_EXPIRED_REFRESH_TOKEN)
+// purge removes a single account. It first marks the account as purged, so it can no longer be restored and an
+// interrupted purge is picked up again, then deletes its files and finally its row. It reports false when the
+// account was restored in the meantime.
+func (s *userService) purge(ctx context.Context, user entity.User) (bool, error) {
+	user.Status = constants.ENUM_USER_PURGED
+	ok, err := s.userRepo.UpdateStatus(
+		ctx, nil, user, constants.ENUM_USER_PENDING_DELETION, constants.ENUM_USER_PURGED,
+	)
+	if err != nil || !ok {
+		return false, err
 	}
 
-	user, err := s.userRepo.GetUserById(ctx, tx, dbToken.UserID.String())
+	uploads, err := s.uploadRepo.GetByUserId(ctx, nil, user.ID.String())
 	if err != nil {
-		tx.Rollback()
-		return dto.TokenResponse{}, dto.ErrUserNotFound
+		return false, err
+	}
+	for _, upload := range uploads {
+		deleteUploadFiles(ctx, s.files, upload, true)
 	}
 
+	exports, err := s.exportRepo.GetByUserId(ctx, nil, user.ID.String())
+	if err != nil {
+		return false, err
+	}
+	for _, export := range exports {
+		if export.StorageKey != "" {
+			_ = s.files.Delete(ctx, export.StorageKey)
+		}
+	}
+	s.deleteProfileImage(ctx, user.ImageUrl)
+
+	if err := s.userRepo.Purge(ctx, nil, user.ID.String()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UpdateAvatar stores an image as the new profile image of a user and removes the previous image and its thumbnails
+// once the user points at the new one.
+func (s *userService) UpdateAvatar(ctx context.Context, userId string, image io.Reader) (
+	dto.UserResponse,
+	error,
+) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	imageKey, err := s.storeProfileImage(ctx, image)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	if err := s.userRepo.UpdateImageUrl(ctx, nil, user.ID.String(), imageKey); err != nil {
+		s.deleteProfileImage(context.WithoutCancel(ctx), imageKey)
+		return dto.UserResponse{}, errors.Join(dto.ErrUpdateUser, err)
+	}
+
+	s.deleteProfileImage(context.WithoutCancel(ctx), user.ImageUrl)
+	user.ImageUrl = imageKey
+
+	return s.toUserResponse(ctx, user)
+}
+
+// DeleteAvatar clears the profile image of a user and removes the stored image and its thumbnails.
+// Users without a profile image are returned unchanged.
+func (s *userService) DeleteAvatar(ctx context.Context, userId string) (dto.UserResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	if user.ImageUrl != "" {
+		if err := s.userRepo.UpdateImageUrl(ctx, nil, user.ID.String(), ""); err != nil {
+			return dto.UserResponse{}, errors.Join(dto.ErrUpdateUser, err)
+		}
+
+		s.deleteProfileImage(context.WithoutCancel(ctx), user.ImageUrl)
+		user.ImageUrl = ""
+	}
+
+	return s.toUserResponse(ctx, user)
+}
+
+// Verify authenticates a user by validating their credentials, generating tokens, and saving the refresh token to the database.
+// Every successful sign-in is recorded in the audit log.
+func (s *userService) Verify(ctx context.Context, req dto.UserLoginRequest) (dto.TokenResponse, error) {
+	var token dto.TokenResponse
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+			if err != nil {
+				return lookupError(err, dto.ErrInvalidCredentials, "get user by email")
+			}
+
+			checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
+			if err != nil || !checkPassword {
+				return dto.ErrInvalidCredentials
+			}
+
+			if user.Status != constants.ENUM_USER_ACTIVE {
+				return dto.ErrAccountDeactivated
+			}
+
+			token, err = s.issueTokens(ctx, user)
+			if err != nil {
+				return err
+			}
+
+			return recordAuditEvent(ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_LOGIN, nil)
+		},
+	)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	return token, nil
+}
+
+// issueTokens generates an access token and a refresh token for user, replacing the refresh tokens issued to them
+// before.
+func (s *userService) issueTokens(ctx context.Context, user entity.User) (dto.TokenResponse, error) {
 	accessToken := s.jwtService.GenerateAccessToken(user.ID.String(), user.Role)
 
 	refreshTokenString, expiresAt := s.jwtService.GenerateRefreshToken()
 
-	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, user.ID.String()); err != nil {
-		tx.Rollback()
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, nil, user.ID.String()); err != nil {
 		return dto.TokenResponse{}, err
 	}
 
@@ -486,12 +1146,7 @@ func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequ
 		ExpiresAt: expiresAt,
 	}
 
-	if _, err := s.refreshTokenRepo.Create(ctx, tx, refreshToken); err != nil {
-		tx.Rollback()
-		return dto.TokenResponse{}, err
-	}
-
-	if err := tx.Commit().Error; err != nil {
+	if _, err := s.refreshTokenRepo.Create(ctx, nil, refreshToken); err != nil {
 		return dto.TokenResponse{}, err
 	}
 
@@ -502,25 +1157,240 @@ func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequ
 	}, nil
 }
 
+// RefreshToken refreshes the user's access and refresh tokens using a valid refresh token, ensuring proper validation and handling.
+func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.TokenResponse, error) {
+	var token dto.TokenResponse
+	err := s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			dbToken, err := s.refreshTokenRepo.FindByToken(ctx, nil, req.RefreshToken)
+			if err != nil {
+				return lookupError(err, dto.ErrRefreshTokenInvalid, "get refresh token")
+			}
+
+			if time.Now().After(dbToken.ExpiresAt) {
+				return dto.ErrRefreshTokenExpired
+			}
+
+			user, err := s.userRepo.GetUserById(ctx, nil, dbToken.UserID.String())
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			if user.Status != constants.ENUM_USER_ACTIVE {
+				return dto.ErrAccountDeactivated
+			}
+
+			token, err = s.issueTokens(ctx, user)
+			return err
+		},
+	)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	return token, nil
+}
+
 // RevokeRefreshToken revokes all refresh tokens associated with a user by their ID, ensuring proper transaction handling.
 func (s *userService) RevokeRefreshToken(ctx context.Context, userID string) error {
-	tx := s.db.Begin()
-	defer SafeRollback(tx)
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			if _, err := s.userRepo.GetUserById(ctx, nil, userID); err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			return s.refreshTokenRepo.DeleteByUserID(ctx, nil, userID)
+		},
+	)
+}
 
-	_, err := s.userRepo.GetUserById(ctx, tx, userID)
+// CreateAdmin creates a verified administrator account and records it in the audit log, returning an error if the
+// email is already registered.
+func (s *userService) CreateAdmin(ctx context.Context, req dto.AdminCreateRequest) (dto.UserResponse, error) {
+	if len(req.Password) < 8 {
+		return dto.UserResponse{}, dto.ErrPasswordTooShort
+	}
+
+	_, flag, err := s.userRepo.CheckEmail(ctx, nil, req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.UserResponse{}, err
+	}
+
+	if flag {
+		return dto.UserResponse{}, dto.ErrEmailAlreadyExists
+	}
+
+	phoneNumber, err := s.normalizePhoneNumber(req.PhoneNumber)
 	if err != nil {
-		tx.Rollback()
-		return dto.ErrUserNotFound
+		return dto.UserResponse{}, err
 	}
 
-	if err := s.refreshTokenRepo.DeleteByUserID(ctx, tx, userID); err != nil {
-		tx.Rollback()
-		return err
+	var user entity.User
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			registered, err := s.userRepo.Register(
+				ctx, nil, entity.User{
+					Name:        req.Name,
+					PhoneNumber: phoneNumber,
+					Role:        constants.ENUM_ROLE_ADMIN,
+					Email:       req.Email,
+					Password:    req.Password,
+					IsVerified:  true,
+				},
+			)
+			if err != nil {
+				return errors.Join(dto.ErrCreateUser, err)
+			}
+			user = registered
+
+			return recordAuditEvent(
+				ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_ACCOUNT_CREATED,
+				map[string]string{"role": constants.ENUM_ROLE_ADMIN},
+			)
+		},
+	)
+	if err != nil {
+		return dto.UserResponse{}, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return err
+	return s.toUserResponse(ctx, user)
+}
+
+// ResetPassword hashes and stores a new password for the user and revokes all of their refresh tokens in one transaction,
+// which also records the change in the audit log.
+func (s *userService) ResetPassword(ctx context.Context, userId string, password string) error {
+	if len(password) < 8 {
+		return dto.ErrPasswordTooShort
 	}
 
-	return nil
+	return s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			user, err := s.userRepo.GetUserById(ctx, nil, userId)
+			if err != nil {
+				return lookupError(err, dto.ErrUserNotFound, "get user")
+			}
+
+			if _, err := s.userRepo.Update(
+				ctx, nil, entity.User{ID: user.ID, Password: password, Versioned: user.Versioned},
+			); err != nil {
+				return errors.Join(dto.ErrUpdateUser, err)
+			}
+
+			if err := s.refreshTokenRepo.DeleteByUserID(ctx, nil, user.ID.String()); err != nil {
+				return fmt.Errorf("failed to delete refresh tokens: %w", err)
+			}
+
+			return recordAuditEvent(ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_PASSWORD_CHANGED, nil)
+		},
+	)
+}
+
+// MarkEmailVerified marks the user's email as verified, returning an error if it already is.
+func (s *userService) MarkEmailVerified(ctx context.Context, userId string) (dto.UserResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	if user.IsVerified {
+		return dto.UserResponse{}, dto.ErrAccountAlreadyVerified
+	}
+
+	updated, err := s.userRepo.Update(
+		ctx, nil, entity.User{ID: user.ID, IsVerified: true, Versioned: user.Versioned},
+	)
+	if errors.Is(err, dto.ErrVersionConflict) {
+		return dto.UserResponse{}, err
+	}
+	if err != nil {
+		return dto.UserResponse{}, errors.Join(dto.ErrUpdateUser, err)
+	}
+
+	user.IsVerified = true
+	user.Versioned = updated.Versioned
+	return s.toUserResponse(ctx, user)
+}
+
+// ChangeRole assigns the given role to the user and records it in the audit log, returning an error if the role is
+// unknown.
+func (s *userService) ChangeRole(ctx context.Context, userId string, role string) (dto.UserResponse, error) {
+	if role != constants.ENUM_ROLE_ADMIN && role != constants.ENUM_ROLE_USER {
+		return dto.UserResponse{}, dto.ErrInvalidRole
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.UserResponse{}, lookupError(err, dto.ErrUserNotFound, "get user")
+	}
+
+	var updated entity.User
+	err = s.txManager.WithinTransaction(
+		ctx, func(ctx context.Context) error {
+			updated, err = s.userRepo.Update(
+				ctx, nil, entity.User{ID: user.ID, Role: role, Versioned: user.Versioned},
+			)
+			if err != nil {
+				return errors.Join(dto.ErrUpdateUser, err)
+			}
+
+			return recordAuditEvent(
+				ctx, s.auditRepo, nil, user.ID, constants.ENUM_AUDIT_ROLE_CHANGED,
+				map[string]string{"old_role": user.Role, "new_role": role},
+			)
+		},
+	)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	user.Role = role
+	user.Versioned = updated.Versioned
+	return s.toUserResponse(ctx, user)
+}
+
+// lookupError maps the error of a failed lookup to notFound when the record does not exist. Any other error, such as
+// a lost database connection, is wrapped with the operation so it is reported as an internal error with its cause.
+func lookupError(err error, notFound error, operation string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+
+	return fmt.Errorf("%s: %w", operation, err)
+}
+
+// toUserResponse maps a user entity to the UserResponse returned to callers, resolving the stored image key to a
+// signed URL.
+func (s *userService) toUserResponse(ctx context.Context, user entity.User) (dto.UserResponse, error) {
+	imageUrl, err := storage.ResolveURL(ctx, s.files, user.ImageUrl, PROFILE_IMAGE_URL_TTL)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	var thumbnails map[string]string
+	if user.ImageUrl != "" && !storage.IsExternalURL(user.ImageUrl) {
+		thumbnails = make(map[string]string, len(media.THUMBNAIL_SIZES))
+		for _, size := range media.THUMBNAIL_SIZES {
+			thumbnailKey := media.ThumbnailKey(user.ImageUrl, size)
+			thumbnails[strconv.Itoa(size)], err = s.files.SignedURL(ctx, thumbnailKey, PROFILE_IMAGE_URL_TTL)
+			if err != nil {
+				return dto.UserResponse{}, err
+			}
+		}
+	}
+
+	return dto.UserResponse{
+		ID:              user.ID.String(),
+		Name:            user.Name,
+		PhoneNumber:     user.PhoneNumber,
+		Role:            user.Role,
+		Email:           user.Email,
+		ImageUrl:        imageUrl,
+		ImageThumbnails: thumbnails,
+		IsVerified:      user.IsVerified,
+		EmailSuppressed: user.EmailSuppressed,
+		Status:          user.Status,
+		PurgeAt:         user.PurgeAt,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+		Version:         user.Version,
+	}, nil
 }
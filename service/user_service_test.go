@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+// TestPatchUserProfile tests applying merge patches to user profiles, validating the result and normalizing changed
+// phone numbers.
+func TestPatchUserProfile(t *testing.T) {
+	profile := dto.UserProfile{
+		Name:        "Jane Doe",
+		Email:       "jane@example.com",
+		PhoneNumber: "081234567890",
+	}
+
+	tests := []struct {
+		name       string
+		patch      string
+		expected   dto.UserProfile
+		err        error
+		validation bool
+	}{
+		{
+			name:     "empty patch",
+			patch:    `{}`,
+			expected: profile,
+		},
+		{
+			name:  "replace fields",
+			patch: `{"name":"Janet Doe","email":"janet@example.com"}`,
+			expected: dto.UserProfile{
+				Name:        "Janet Doe",
+				Email:       "janet@example.com",
+				PhoneNumber: "081234567890",
+			},
+		},
+		{
+			name:     "null clears optional field",
+			patch:    `{"phone_number":null}`,
+			expected: dto.UserProfile{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			name:       "null clears required field",
+			patch:      `{"email":null}`,
+			validation: true,
+		},
+		{
+			name:  "phone number written in E.164 format",
+			patch: `{"phone_number":"0812 3456 7891"}`,
+			expected: dto.UserProfile{
+				Name:        "Jane Doe",
+				Email:       "jane@example.com",
+				PhoneNumber: "+6281234567891",
+			},
+		},
+		{
+			name:  "invalid phone number",
+			patch: `{"phone_number":"+0812345678"}`,
+			err:   helpers.ErrInvalidPhoneNumber,
+		},
+		{
+			name:       "invalid value",
+			patch:      `{"phone_number":"123"}`,
+			validation: true,
+		},
+		{
+			name:  "unknown field",
+			patch: `{"role":"admin"}`,
+			err:   utils.ErrInvalidMergePatch,
+		},
+		{
+			name:  "wrong type",
+			patch: `{"name":42}`,
+			err:   utils.ErrInvalidMergePatch,
+		},
+		{
+			name:  "malformed patch",
+			patch: `{"name":`,
+			err:   utils.ErrInvalidMergePatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				result, err := patchUserProfile(profile, []byte(tt.patch), "62")
+				switch {
+				case tt.validation:
+					var validationErrors validator.ValidationErrors
+					assert.ErrorAs(t, err, &validationErrors)
+				case tt.err != nil:
+					assert.ErrorIs(t, err, tt.err)
+				default:
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, result)
+				}
+			},
+		)
+	}
+}
+
+// TestNewEmailChangeToken tests that email change tokens are random and stored as their SHA-256 hash.
+func TestNewEmailChangeToken(t *testing.T) {
+	token, tokenHash, err := newEmailChangeToken()
+	assert.NoError(t, err)
+	assert.Len(t, tokenHash, 64)
+	assert.Equal(t, tokenHash, hashEmailChangeToken(token))
+	assert.NotContains(t, tokenHash, token)
+
+	other, otherHash, err := newEmailChangeToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, tokenHash, otherHash)
+}
+
+// TestLookupError tests that only missing records are reported as not found, while other lookup failures keep their
+// cause and are reported as internal errors.
+func TestLookupError(t *testing.T) {
+	errConnection := errors.New("pq: connection refused")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected *apperror.Error
+		cause    error
+	}{
+		{
+			name:     "record not found",
+			err:      gorm.ErrRecordNotFound,
+			expected: dto.ErrUserNotFound,
+		},
+		{
+			name:     "wrapped record not found",
+			err:      fmt.Errorf("query: %w", gorm.ErrRecordNotFound),
+			expected: dto.ErrUserNotFound,
+		},
+		{
+			name:     "database failure",
+			err:      errConnection,
+			expected: apperror.ErrInternal,
+			cause:    errConnection,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := lookupError(tt.err, dto.ErrUserNotFound, "get user")
+
+				assert.Same(t, tt.expected, apperror.From(err))
+				if tt.cause != nil {
+					assert.ErrorIs(t, err, tt.cause)
+					assert.Equal(t, "get user: pq: connection refused", err.Error())
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,80 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTP_SEND_TIMEOUT bounds a request to the SMS gateway when the caller's context has no earlier deadline.
+const HTTP_SEND_TIMEOUT = 10 * time.Second
+
+type (
+	// httpSender delivers text messages by posting them as JSON to the endpoint of an SMS gateway.
+	httpSender struct {
+		url    string
+		token  string
+		from   string
+		client *http.Client
+	}
+
+	// httpPayload is the JSON body posted to the SMS gateway.
+	httpPayload struct {
+		To   string `json:"to"`
+		From string `json:"from,omitempty"`
+		Body string `json:"body"`
+	}
+)
+
+// NewHTTPSender creates an SMSSender that posts every text message to url, authenticated with token as a bearer token
+// when it is set. A nil client uses a client that times out after HTTP_SEND_TIMEOUT.
+func NewHTTPSender(url, token, from string, client *http.Client) SMSSender {
+	if client == nil {
+		client = &http.Client{Timeout: HTTP_SEND_TIMEOUT}
+	}
+
+	return &httpSender{
+		url:    url,
+		token:  token,
+		from:   from,
+		client: client,
+	}
+}
+
+// Send posts msg to the gateway and fails unless it answers with a 2xx status.
+func (s *httpSender) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+
+	payload, err := json.Marshal(httpPayload{To: msg.To, From: s.from, Body: msg.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("SMS gateway answered %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHTTPSender verifies the request posted to the SMS gateway and the handling of its answers.
+func TestHTTPSender(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		status      int
+		errContains string
+	}{
+		{name: "accepted with token", token: "secret", status: http.StatusAccepted},
+		{name: "accepted without token", status: http.StatusOK},
+		{name: "rejected", status: http.StatusBadRequest, errContains: "400 Bad Request: invalid number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				var (
+					payload       httpPayload
+					authorization string
+					contentType   string
+				)
+				server := httptest.NewServer(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							authorization = r.Header.Get("Authorization")
+							contentType = r.Header.Get("Content-Type")
+							assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+							w.WriteHeader(tt.status)
+							_, _ = w.Write([]byte("invalid number\n"))
+						},
+					),
+				)
+				defer server.Close()
+
+				sender := NewHTTPSender(server.URL, tt.token, "App", server.Client())
+				err := sender.Send(context.Background(), Message{To: "+6281234567890", Body: "Your code is 123456"})
+
+				if tt.errContains != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tt.errContains)
+				} else {
+					require.NoError(t, err)
+				}
+
+				assert.Equal(t, httpPayload{To: "+6281234567890", From: "App", Body: "Your code is 123456"}, payload)
+				assert.Equal(t, "application/json", contentType)
+				if tt.token != "" {
+					assert.Equal(t, "Bearer "+tt.token, authorization)
+				} else {
+					assert.Empty(t, authorization)
+				}
+			},
+		)
+	}
+}
+
+// TestHTTPSender_NoRecipient verifies that a text message without a recipient is refused before any request is made.
+func TestHTTPSender_NoRecipient(t *testing.T) {
+	sender := NewHTTPSender("http://127.0.0.1:0", "", "", nil)
+	assert.ErrorIs(t, sender.Send(context.Background(), Message{Body: "lost"}), ErrNoRecipient)
+}
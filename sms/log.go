@@ -0,0 +1,39 @@
+package sms
+
+import (
+	"context"
+	"log"
+)
+
+// logSender writes every text message to a logger instead of sending it.
+type logSender struct {
+	from   string
+	logger *log.Logger
+}
+
+// NewLogSender creates an SMSSender that logs text messages sent from the given sender; a nil logger uses the
+// standard logger.
+func NewLogSender(from string, logger *log.Logger) SMSSender {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &logSender{
+		from:   from,
+		logger: logger,
+	}
+}
+
+// Send logs the recipient and body of msg.
+func (s *logSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+
+	s.logger.Printf("sms from=%q to=%q\n%s", s.from, msg.To, msg.Body)
+	return nil
+}
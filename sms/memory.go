@@ -0,0 +1,61 @@
+package sms
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySender keeps every sent text message in memory so tests can assert on what was delivered.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemorySender creates an empty MemorySender.
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+// Send records msg.
+func (s *MemorySender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Sent returns a copy of every recorded message, oldest first.
+func (s *MemorySender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Last returns the most recently recorded message and whether there is one.
+func (s *MemorySender) Last() (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.messages) == 0 {
+		return Message{}, false
+	}
+
+	return s.messages[len(s.messages)-1], true
+}
+
+// Reset discards every recorded message.
+func (s *MemorySender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+}
@@ -0,0 +1,34 @@
+package sms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMemorySender verifies that the memory backend records and resets sent text messages.
+func TestMemorySender(t *testing.T) {
+	s := NewMemorySender()
+	ctx := context.Background()
+
+	_, ok := s.Last()
+	assert.False(t, ok)
+
+	assert.NoError(t, s.Send(ctx, Message{To: "+6281234567890", Body: "first"}))
+	assert.NoError(t, s.Send(ctx, Message{To: "+6281234567891", Body: "second"}))
+	assert.ErrorIs(t, s.Send(ctx, Message{Body: "third"}), ErrNoRecipient)
+
+	assert.Len(t, s.Sent(), 2)
+
+	last, ok := s.Last()
+	assert.True(t, ok)
+	assert.Equal(t, "second", last.Body)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, s.Send(canceled, Message{To: "+6281234567890"}), context.Canceled)
+
+	s.Reset()
+	assert.Empty(t, s.Sent())
+}
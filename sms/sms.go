@@ -0,0 +1,40 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// ErrNoRecipient indicates that a text message was sent without a recipient.
+var ErrNoRecipient = errors.New("text message has no recipient")
+
+type (
+	// Message is a text message to deliver to the phone number To, in E.164 format.
+	Message struct {
+		To   string
+		Body string
+	}
+
+	// SMSSender delivers text messages through a configured backend.
+	SMSSender interface {
+		Send(ctx context.Context, msg Message) error
+	}
+)
+
+// New creates the SMSSender selected by the driver in cfg.
+func New(cfg *config.SMSConfig) (SMSSender, error) {
+	switch cfg.Driver {
+	case constants.ENUM_SMS_DRIVER_HTTP:
+		return NewHTTPSender(cfg.HTTPURL, cfg.HTTPToken, cfg.From, nil), nil
+	case constants.ENUM_SMS_DRIVER_LOG:
+		return NewLogSender(cfg.From, nil), nil
+	case constants.ENUM_SMS_DRIVER_MEMORY:
+		return NewMemorySender(), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS driver %q", cfg.Driver)
+	}
+}
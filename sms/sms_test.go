@@ -0,0 +1,72 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNew verifies that New returns the backend selected by the configured driver.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.SMSConfig
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "http",
+			cfg:  &config.SMSConfig{Driver: constants.ENUM_SMS_DRIVER_HTTP, HTTPURL: "https://sms.example.com"},
+			want: &httpSender{},
+		},
+		{
+			name: "log",
+			cfg:  &config.SMSConfig{Driver: constants.ENUM_SMS_DRIVER_LOG},
+			want: &logSender{},
+		},
+		{
+			name: "memory",
+			cfg:  &config.SMSConfig{Driver: constants.ENUM_SMS_DRIVER_MEMORY},
+			want: &MemorySender{},
+		},
+		{
+			name:    "unknown",
+			cfg:     &config.SMSConfig{Driver: "pigeon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := New(tt.cfg)
+
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.IsType(t, tt.want, got)
+			},
+		)
+	}
+}
+
+// TestLogSender verifies that the log backend writes the recipient and body of every text message.
+func TestLogSender(t *testing.T) {
+	var buf bytes.Buffer
+	sender := NewLogSender("App", log.New(&buf, "", 0))
+
+	require.NoError(t, sender.Send(context.Background(), Message{To: "+6281234567890", Body: "Your code is 123456"}))
+	assert.Contains(t, buf.String(), `from="App" to="+6281234567890"`)
+	assert.Contains(t, buf.String(), "Your code is 123456")
+
+	assert.ErrorIs(t, sender.Send(context.Background(), Message{Body: "lost"}), ErrNoRecipient)
+}
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LOCAL_TEMP_FILE_PREFIX starts the names of the temporary files that uploads are written to before being renamed.
+const LOCAL_TEMP_FILE_PREFIX = ".upload-"
+
+// LocalDriver stores objects as files below a root directory.
+// Its signed URLs point at baseURL, where the application serves objects after checking them with VerifySignedURL.
+type LocalDriver struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalDriver creates a LocalDriver storing files below root and signing URLs below baseURL with signingKey.
+func NewLocalDriver(root string, baseURL string, signingKey []byte) *LocalDriver {
+	return &LocalDriver{
+		root:       root,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+		now:        time.Now,
+	}
+}
+
+// Put writes body to the file of key through a temporary file, so readers never see a partial object.
+func (d *LocalDriver) Put(ctx context.Context, key string, body io.Reader, _ string) error {
+	filePath, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), LOCAL_TEMP_FILE_PREFIX+"*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// Get opens the file of key.
+func (d *LocalDriver) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	filePath, err := d.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, ObjectInfo{}, notFound(err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, ObjectInfo{}, err
+	}
+
+	return file, fileInfo(key, stat), nil
+}
+
+// Delete removes the file of key.
+func (d *LocalDriver) Delete(ctx context.Context, key string) error {
+	filePath, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Stat describes the file of key.
+func (d *LocalDriver) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	filePath, err := d.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return ObjectInfo{}, notFound(err)
+	}
+
+	return fileInfo(key, stat), nil
+}
+
+// List walks the root directory and describes every file whose key starts with prefix, sorted by key.
+// Temporary files of uploads in progress are skipped.
+func (d *LocalDriver) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var infos []ObjectInfo
+	err := filepath.WalkDir(
+		d.root, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && filePath == d.root {
+					return fs.SkipAll
+				}
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), LOCAL_TEMP_FILE_PREFIX) {
+				return nil
+			}
+
+			relative, err := filepath.Rel(d.root, filePath)
+			if err != nil {
+				return err
+			}
+			key := filepath.ToSlash(relative)
+			if !strings.HasPrefix(key, prefix) {
+				return nil
+			}
+
+			stat, err := entry.Info()
+			if err != nil {
+				return notFound(err)
+			}
+			infos = append(infos, fileInfo(key, stat))
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// SignedURL returns baseURL/key with an expiry time and an HMAC-SHA256 signature of both in the query.
+func (d *LocalDriver) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	expiresAt := strconv.FormatInt(d.now().Add(expires).Unix(), 10)
+	query := url.Values{
+		"expires":   {expiresAt},
+		"signature": {d.signature(key, expiresAt)},
+	}
+
+	return d.baseURL + "/" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+// VerifySignedURL checks the expiry time and signature that SignedURL put in the query of a URL for key.
+func (d *LocalDriver) VerifySignedURL(key string, query url.Values) error {
+	expiresAt := query.Get("expires")
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(d.signature(key, expiresAt))) {
+		return ErrInvalidSignature
+	}
+
+	seconds, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d.now().After(time.Unix(seconds, 0)) {
+		return ErrURLExpired
+	}
+
+	return nil
+}
+
+// signature returns the hex HMAC-SHA256 of key and its expiry time.
+func (d *LocalDriver) signature(key string, expiresAt string) string {
+	mac := hmac.New(sha256.New, d.signingKey)
+	mac.Write([]byte(key))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(expiresAt))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path returns the file path of key below the root directory.
+func (d *LocalDriver) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(d.root, filepath.FromSlash(key)), nil
+}
+
+// fileInfo describes the file of key.
+func fileInfo(key string, stat fs.FileInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: contentTypeOf(key),
+		ModTime:     stat.ModTime(),
+	}
+}
+
+// notFound maps a missing file to ErrObjectNotFound.
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrObjectNotFound
+	}
+
+	return err
+}
+
+// escapeKey escapes every segment of key for use in a URL path.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocalDriver tests storing, reading, describing and deleting files below the root directory.
+func TestLocalDriver(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	driver := NewLocalDriver(root, "/api/storage", []byte("key"))
+
+	require.NoError(t, driver.Put(ctx, "profile/nested/a.png", strings.NewReader("png bytes"), "image/png"))
+
+	data, err := os.ReadFile(filepath.Join(root, "profile", "nested", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png bytes", string(data))
+
+	entries, err := os.ReadDir(filepath.Join(root, "profile", "nested"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary upload files should not be left behind")
+
+	info, err := driver.Stat(ctx, "profile/nested/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("png bytes")), info.Size)
+	assert.Equal(t, "image/png", info.ContentType)
+
+	reader, _, err := driver.Get(ctx, "profile/nested/a.png")
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "png bytes", string(data))
+
+	require.NoError(t, driver.Delete(ctx, "profile/nested/a.png"))
+	_, err = driver.Stat(ctx, "profile/nested/a.png")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	_, _, err = driver.Get(ctx, "profile/nested/a.png")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	assert.NoError(t, driver.Delete(ctx, "profile/nested/a.png"), "deleting a missing file should succeed")
+
+	err = driver.Put(ctx, "../escape.png", strings.NewReader("x"), "image/png")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+// TestLocalDriver_List tests that files below the root are listed by prefix while uploads in progress are skipped.
+func TestLocalDriver_List(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "files")
+	driver := NewLocalDriver(root, "/api/storage", []byte("key"))
+
+	infos, err := driver.List(ctx, "")
+	require.NoError(t, err, "a missing root should list nothing")
+	assert.Empty(t, infos)
+
+	for _, key := range []string{"profile/b.png", "profile/a.png", "exports/a.zip"} {
+		require.NoError(t, driver.Put(ctx, key, strings.NewReader(key), ""))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "profile", LOCAL_TEMP_FILE_PREFIX+"1"), nil, 0644))
+
+	infos, err = driver.List(ctx, "profile/")
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "profile/a.png", infos[0].Key)
+	assert.Equal(t, "profile/b.png", infos[1].Key)
+	assert.Equal(t, int64(len("profile/a.png")), infos[0].Size)
+
+	infos, err = driver.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, infos, 3)
+}
+
+// TestLocalDriver_SignedURL tests that signed URLs are accepted until they expire and rejected when altered.
+func TestLocalDriver_SignedURL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	driver := NewLocalDriver(t.TempDir(), "https://cdn.example.com/files/", []byte("key"))
+	driver.now = func() time.Time { return now }
+
+	signedURL, err := driver.SignedURL(context.Background(), "profile/a b.png", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signedURL, "https://cdn.example.com/files/profile/a%20b.png?"))
+
+	u, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	query := u.Query()
+
+	tamperedExpiry := url.Values{"expires": {"9999999999"}, "signature": {query.Get("signature")}}
+
+	tests := []struct {
+		name    string
+		key     string
+		query   url.Values
+		at      time.Time
+		wantErr error
+	}{
+		{name: "valid", key: "profile/a b.png", query: query, at: now.Add(59 * time.Minute)},
+		{name: "expired", key: "profile/a b.png", query: query, at: now.Add(61 * time.Minute), wantErr: ErrURLExpired},
+		{name: "other key", key: "profile/c.png", query: query, at: now, wantErr: ErrInvalidSignature},
+		{name: "extended expiry", key: "profile/a b.png", query: tamperedExpiry, at: now, wantErr: ErrInvalidSignature},
+		{name: "missing signature", key: "profile/a b.png", query: url.Values{}, at: now, wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				driver.now = func() time.Time { return tt.at }
+
+				err := driver.VerifySignedURL(tt.key, tt.query)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryObject is an object held by a MemoryDriver.
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+}
+
+// MemoryDriver keeps every object in memory so tests can assert on what was stored.
+type MemoryDriver struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+// NewMemoryDriver creates an empty MemoryDriver.
+func NewMemoryDriver() *MemoryDriver {
+	return &MemoryDriver{
+		objects: make(map[string]memoryObject),
+	}
+}
+
+// Put records the content of body under key.
+func (d *MemoryDriver) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = contentTypeOf(key)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.objects[key] = memoryObject{
+		data: data,
+		info: ObjectInfo{Key: key, Size: int64(len(data)), ContentType: contentType, ModTime: time.Now()},
+	}
+	return nil
+}
+
+// Get returns a reader over the object of key.
+func (d *MemoryDriver) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	object, err := d.object(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	return io.NopCloser(bytes.NewReader(object.data)), object.info, nil
+}
+
+// Delete forgets the object of key.
+func (d *MemoryDriver) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.objects, key)
+	return nil
+}
+
+// Stat describes the object of key.
+func (d *MemoryDriver) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	object, err := d.object(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return object.info, nil
+}
+
+// SignedURL returns a memory:// URL naming key; it is only meant to be compared in tests.
+func (d *MemoryDriver) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	query := url.Values{"expires": {expires.String()}}
+	return "memory://" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+// List describes every object whose key starts with prefix, sorted by key.
+func (d *MemoryDriver) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var infos []ObjectInfo
+	for key, object := range d.objects {
+		if strings.HasPrefix(key, prefix) {
+			infos = append(infos, object.info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+
+	return infos, nil
+}
+
+// Keys returns the keys of every stored object in lexical order.
+func (d *MemoryDriver) Keys() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := make([]string, 0, len(d.objects))
+	for key := range d.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// object returns the stored object of key.
+func (d *MemoryDriver) object(ctx context.Context, key string) (memoryObject, error) {
+	if err := ValidateKey(key); err != nil {
+		return memoryObject{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return memoryObject{}, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	object, ok := d.objects[key]
+	if !ok {
+		return memoryObject{}, ErrObjectNotFound
+	}
+
+	return object, nil
+}
@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+// S3_MAX_URL_EXPIRY is the longest validity Signature Version 4 allows for a presigned URL.
+const S3_MAX_URL_EXPIRY = 7 * 24 * time.Hour
+
+// S3Driver stores objects in a bucket of an S3-compatible service, such as AWS S3 or MinIO.
+// Requests are signed with AWS Signature Version 4 and signed URLs are presigned GET requests.
+type S3Driver struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	signer    sigV4Signer
+	client    *http.Client
+	now       func() time.Time
+}
+
+// s3ListResult is the document returned by S3 for a ListObjectsV2 request.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// s3Error is the error document returned by S3 for failed requests.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// NewS3Driver creates an S3Driver for the bucket in cfg. An empty endpoint selects AWS S3 in the configured region.
+func NewS3Driver(cfg *config.S3Config) (*S3Driver, error) {
+	if cfg == nil || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3 storage requires a bucket, an access key and a secret key")
+	}
+
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", rawEndpoint)
+	}
+
+	return &S3Driver{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		signer: sigV4Signer{
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			region:    cfg.Region,
+		},
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+// EnsureBucket creates the bucket unless it already exists and belongs to the configured account.
+func (d *S3Driver) EnsureBucket(ctx context.Context) error {
+	var body []byte
+	if d.signer.region != "us-east-1" {
+		body = []byte(
+			"<CreateBucketConfiguration xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\">" +
+				"<LocationConstraint>" + d.signer.region + "</LocationConstraint></CreateBucketConfiguration>",
+		)
+	}
+
+	resp, err := d.send(ctx, http.MethodPut, d.bucketURL(), body, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusConflict {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var document s3Error
+		if xml.Unmarshal(raw, &document) == nil && document.Code == "BucketAlreadyOwnedByYou" {
+			return nil
+		}
+
+		return fmt.Errorf("s3 create bucket %q: %s", d.bucket, resp.Status)
+	}
+
+	return d.check(resp, d.bucket)
+}
+
+// Put uploads body under key. The body is read into memory first because S3 needs its length and hash up front.
+func (d *S3Driver) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = contentTypeOf(key)
+	}
+
+	resp, err := d.do(ctx, http.MethodPut, key, data, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return d.check(resp, key)
+}
+
+// Get downloads the object of key; the caller must close the returned reader.
+func (d *S3Driver) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	resp, err := d.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	if err := d.check(resp, key); err != nil {
+		_ = resp.Body.Close()
+		return nil, ObjectInfo{}, err
+	}
+
+	return resp.Body, objectInfo(key, resp), nil
+}
+
+// Delete removes the object of key.
+func (d *S3Driver) Delete(ctx context.Context, key string) error {
+	resp, err := d.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return d.check(resp, key)
+}
+
+// Stat describes the object of key with a HEAD request.
+func (d *S3Driver) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := d.do(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := d.check(resp, key); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return objectInfo(key, resp), nil
+}
+
+// SignedURL returns a presigned GET URL for key, valid for at most S3_MAX_URL_EXPIRY.
+func (d *S3Driver) SignedURL(_ context.Context, key string, expires time.Duration) (string, error) {
+	u, err := d.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	if expires < time.Second || expires > S3_MAX_URL_EXPIRY {
+		return "", fmt.Errorf("signed URL expiry must be between 1s and %s", S3_MAX_URL_EXPIRY)
+	}
+
+	d.signer.presign(http.MethodGet, u, expires, d.now())
+	return u.String(), nil
+}
+
+// List describes every object whose key starts with prefix, following ListObjectsV2 pages until the listing ends.
+// S3 returns keys in lexical order and does not report content types in listings.
+func (d *S3Driver) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var (
+		infos []ObjectInfo
+		token string
+	)
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		u := d.bucketURL()
+		u.RawQuery = query.Encode()
+
+		resp, err := d.send(ctx, http.MethodGet, u, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result s3ListResult
+		err = d.check(resp, prefix)
+		if err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			infos = append(infos, ObjectInfo{Key: object.Key, Size: object.Size, ModTime: object.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return infos, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request for key with an optional body and extra headers.
+func (d *S3Driver) do(
+	ctx context.Context,
+	method string,
+	key string,
+	body []byte,
+	headers map[string]string,
+) (*http.Response, error) {
+	u, err := d.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.send(ctx, method, u, body, headers)
+}
+
+// send sends a signed request to u with an optional body and extra headers.
+func (d *S3Driver) send(
+	ctx context.Context,
+	method string,
+	u *url.URL,
+	body []byte,
+	headers map[string]string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		req.Body, req.ContentLength = http.NoBody, 0
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	d.signer.sign(req, hashHex(body), d.now())
+	return d.client.Do(req)
+}
+
+// bucketURL returns the URL of the bucket, with the bucket in the path or in the host name.
+func (d *S3Driver) bucketURL() *url.URL {
+	u := *d.endpoint
+	u.Path = strings.TrimRight(u.Path, "/") + "/"
+	if d.pathStyle {
+		u.Path += d.bucket
+	} else {
+		u.Host = d.bucket + "." + u.Host
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = ""
+
+	return &u
+}
+
+// objectURL returns the URL of key below the bucket URL.
+func (d *S3Driver) objectURL(key string) (*url.URL, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	u := d.bucketURL()
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	u.RawPath = uriEncode(u.Path, false)
+
+	return u, nil
+}
+
+// check turns an unsuccessful response into an error, mapping a missing object to ErrObjectNotFound.
+func (d *S3Driver) check(resp *http.Response, key string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+
+	var document s3Error
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := xml.Unmarshal(raw, &document); err != nil || document.Code == "" {
+		return fmt.Errorf("s3 %s %q: %s", resp.Request.Method, key, resp.Status)
+	}
+
+	return fmt.Errorf("s3 %s %q: %s: %s", resp.Request.Method, key, document.Code, document.Message)
+}
+
+// objectInfo describes the object of key from the headers of a GET or HEAD response.
+func objectInfo(key string, resp *http.Response) ObjectInfo {
+	info := ObjectInfo{
+		Key:         key,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	info.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return info
+}
@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+// FAKE_S3_PAGE_SIZE is the number of keys fakeS3 returns per listing page.
+const FAKE_S3_PAGE_SIZE = 2
+
+// fakeS3 is a path-style, in-memory stand-in for an S3-compatible server that rejects badly signed requests.
+type fakeS3 struct {
+	mu      sync.Mutex
+	signer  sigV4Signer
+	objects map[string]fakeS3Object
+}
+
+// fakeS3Object is an object held by fakeS3.
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+}
+
+// ServeHTTP verifies the signature of a request and applies it to the stored objects.
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.verify(r) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code><Message>bad signature</Message></Error>")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		s.list(w, r)
+		return
+	}
+
+	object, ok := s.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = fakeS3Object{data: data, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet, http.MethodHead:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Last-Modified", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		_, _ = w.Write(object.data)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list answers a ListObjectsV2 request in pages of FAKE_S3_PAGE_SIZE keys, using the last returned key as the
+// continuation token.
+func (s *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	bucketPath := strings.TrimSuffix(r.URL.Path, "/") + "/"
+	prefix := bucketPath + r.URL.Query().Get("prefix")
+	after := r.URL.Query().Get("continuation-token")
+
+	var keys []string
+	for path := range s.objects {
+		key := strings.TrimPrefix(path, bucketPath)
+		if strings.HasPrefix(path, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	truncated := len(keys) > FAKE_S3_PAGE_SIZE
+	if truncated {
+		keys = keys[:FAKE_S3_PAGE_SIZE]
+	}
+
+	var body strings.Builder
+	body.WriteString("<ListBucketResult>")
+	for _, key := range keys {
+		fmt.Fprintf(
+			&body, "<Contents><Key>%s</Key><LastModified>2025-01-01T00:00:00.000Z</LastModified><Size>%d</Size></Contents>",
+			key, len(s.objects[bucketPath+key].data),
+		)
+	}
+	fmt.Fprintf(&body, "<IsTruncated>%t</IsTruncated>", truncated)
+	if truncated {
+		fmt.Fprintf(&body, "<NextContinuationToken>%s</NextContinuationToken>", keys[len(keys)-1])
+	}
+	body.WriteString("</ListBucketResult>")
+
+	_, _ = io.WriteString(w, body.String())
+}
+
+// verify recomputes the signature of a request as received, from its headers or its presigned query.
+func (s *fakeS3) verify(r *http.Request) bool {
+	query := r.URL.Query()
+	if signature := query.Get("X-Amz-Signature"); signature != "" {
+		now, err := time.Parse(sigV4TimeFormat, query.Get("X-Amz-Date"))
+		if err != nil {
+			return false
+		}
+		query.Del("X-Amz-Signature")
+
+		_, canonicalHeaders := canonicalizeHeaders(map[string]string{"host": r.Host})
+		canonical := canonicalRequest(r.Method, r.URL, query, canonicalHeaders, "host", unsignedPayload)
+		return signature == s.signer.signature(now, s.signer.scope(now), canonical)
+	}
+
+	authorization := r.Header.Get("Authorization")
+	signedStart := strings.Index(authorization, "SignedHeaders=")
+	signatureStart := strings.Index(authorization, ",Signature=")
+	if signedStart < 0 || signatureStart < signedStart {
+		return false
+	}
+	signedHeaders := authorization[signedStart+len("SignedHeaders=") : signatureStart]
+
+	headers := map[string]string{}
+	for _, name := range strings.Split(signedHeaders, ";") {
+		if name == "host" {
+			headers[name] = r.Host
+		} else {
+			headers[name] = r.Header.Get(name)
+		}
+	}
+
+	now, err := time.Parse(sigV4TimeFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	_, canonicalHeaders := canonicalizeHeaders(headers)
+	canonical := canonicalRequest(
+		r.Method, r.URL, query, canonicalHeaders, signedHeaders, r.Header.Get("X-Amz-Content-Sha256"),
+	)
+	return authorization[signatureStart+len(",Signature="):] == s.signer.signature(now, s.signer.scope(now), canonical)
+}
+
+// newFakeS3Driver starts a fakeS3 server and returns a path-style S3Driver pointing at it.
+func newFakeS3Driver(t *testing.T, secretKey string) (*S3Driver, *fakeS3) {
+	fake := &fakeS3{
+		signer:  sigV4Signer{accessKey: "minio", secretKey: "minio-secret", region: "us-east-1"},
+		objects: make(map[string]fakeS3Object),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	driver, err := NewS3Driver(
+		&config.S3Config{
+			Endpoint:  server.URL,
+			Region:    "us-east-1",
+			Bucket:    "avatars",
+			AccessKey: "minio",
+			SecretKey: secretKey,
+			PathStyle: true,
+		},
+	)
+	require.NoError(t, err)
+
+	return driver, fake
+}
+
+// TestS3Driver tests creating the bucket and storing, reading, describing, presigning and deleting objects against a signature-checking
+// S3 stand-in.
+func TestS3Driver(t *testing.T) {
+	ctx := context.Background()
+	driver, fake := newFakeS3Driver(t, "minio-secret")
+	key := "profile/an image+1.png"
+
+	require.NoError(t, driver.EnsureBucket(ctx))
+	require.NoError(t, driver.Put(ctx, key, strings.NewReader("png bytes"), "image/png"))
+	assert.Contains(t, fake.objects, "/avatars/"+key)
+
+	info, err := driver.Stat(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("png bytes")), info.Size)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.False(t, info.ModTime.IsZero())
+
+	reader, _, err := driver.Get(ctx, key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "png bytes", string(data))
+
+	signedURL, err := driver.SignedURL(ctx, key, time.Hour)
+	require.NoError(t, err)
+	resp, err := http.Get(signedURL)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "png bytes", string(body))
+
+	require.NoError(t, driver.Delete(ctx, key))
+	_, err = driver.Stat(ctx, key)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	_, _, err = driver.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	assert.NoError(t, driver.Delete(ctx, key), "deleting a missing object should succeed")
+}
+
+// TestS3Driver_List tests that listings are filtered by prefix and follow continuation tokens across pages.
+func TestS3Driver_List(t *testing.T) {
+	ctx := context.Background()
+	driver, _ := newFakeS3Driver(t, "minio-secret")
+
+	for _, key := range []string{"profile/c.png", "profile/a.png", "profile/b.png", "exports/a.zip"} {
+		require.NoError(t, driver.Put(ctx, key, strings.NewReader(key), ""))
+	}
+
+	infos, err := driver.List(ctx, "profile/")
+	require.NoError(t, err)
+
+	keys := make([]string, 0, len(infos))
+	for _, info := range infos {
+		keys = append(keys, info.Key)
+	}
+	assert.Equal(t, []string{"profile/a.png", "profile/b.png", "profile/c.png"}, keys)
+	assert.Equal(t, int64(len("profile/a.png")), infos[0].Size)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), infos[0].ModTime)
+}
+
+// TestS3Driver_Errors tests that rejected requests, invalid keys and expiries are reported.
+func TestS3Driver_Errors(t *testing.T) {
+	ctx := context.Background()
+	driver, _ := newFakeS3Driver(t, "wrong-secret")
+
+	err := driver.Put(ctx, "profile/a.png", strings.NewReader("x"), "image/png")
+	assert.ErrorContains(t, err, "SignatureDoesNotMatch")
+
+	err = driver.Put(ctx, "../a.png", strings.NewReader("x"), "image/png")
+	assert.True(t, errors.Is(err, ErrInvalidKey))
+
+	_, err = driver.SignedURL(ctx, "profile/a.png", 8*24*time.Hour)
+	assert.ErrorContains(t, err, "signed URL expiry")
+}
+
+// TestS3Driver_ObjectURL tests path-style and virtual-hosted object URLs.
+func TestS3Driver_ObjectURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.S3Config
+		expected string
+	}{
+		{
+			name:     "virtual-hosted AWS endpoint",
+			config:   config.S3Config{Region: "eu-west-1", Bucket: "files", AccessKey: "a", SecretKey: "s"},
+			expected: "https://files.s3.eu-west-1.amazonaws.com/profile/a%20b.png",
+		},
+		{
+			name: "path-style custom endpoint",
+			config: config.S3Config{
+				Endpoint: "http://minio:9000", Region: "us-east-1", Bucket: "files", AccessKey: "a", SecretKey: "s",
+				PathStyle: true,
+			},
+			expected: "http://minio:9000/files/profile/a%20b.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				driver, err := NewS3Driver(&tt.config)
+				require.NoError(t, err)
+
+				u, err := driver.objectURL("profile/a b.png")
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, u.String())
+			},
+		)
+	}
+}
+
+// TestNewS3Driver_InvalidConfig tests that incomplete settings are rejected.
+func TestNewS3Driver_InvalidConfig(t *testing.T) {
+	_, err := NewS3Driver(&config.S3Config{Bucket: "files"})
+	assert.Error(t, err)
+
+	_, err = NewS3Driver(&config.S3Config{Endpoint: "minio:9000", Bucket: "files", AccessKey: "a", SecretKey: "s"})
+	assert.ErrorContains(t, err, "invalid S3 endpoint")
+}
+
+// TestS3Driver_PresignedQuery tests that a presigned URL carries every Signature Version 4 query parameter.
+func TestS3Driver_PresignedQuery(t *testing.T) {
+	driver, _ := newFakeS3Driver(t, "minio-secret")
+
+	signedURL, err := driver.SignedURL(context.Background(), "profile/a.png", 15*time.Minute)
+	require.NoError(t, err)
+
+	u, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	for _, name := range []string{
+		"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires", "X-Amz-SignedHeaders", "X-Amz-Signature",
+	} {
+		assert.NotEmpty(t, u.Query().Get(name), name)
+	}
+	assert.Equal(t, "900", u.Query().Get("X-Amz-Expires"))
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sigV4Algorithm names the AWS Signature Version 4 algorithm.
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+	// sigV4Service is the AWS service name that S3 requests are signed for.
+	sigV4Service = "s3"
+
+	// sigV4TimeFormat is the layout of the X-Amz-Date value.
+	sigV4TimeFormat = "20060102T150405Z"
+
+	// unsignedPayload is the payload hash of presigned URLs, whose body is not known when they are signed.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// sigV4Signer signs S3 requests with AWS Signature Version 4.
+type sigV4Signer struct {
+	accessKey string
+	secretKey string
+	region    string
+}
+
+// sign adds the X-Amz-Date, X-Amz-Content-Sha256 and Authorization headers to req.
+// The host, Content-Type, Content-MD5, Date, Range and every X-Amz-* header are signed.
+func (s sigV4Signer) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(sigV4TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": requestHost(req)}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "content-type", lower == "content-md5", lower == "date", lower == "range",
+			strings.HasPrefix(lower, "x-amz-"):
+			headers[lower] = strings.Join(values, ",")
+		}
+	}
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(headers)
+	scope := s.scope(now)
+	signature := s.signature(
+		now, scope, canonicalRequest(
+			req.Method, req.URL, req.URL.Query(), canonicalHeaders, signedHeaders, payloadHash,
+		),
+	)
+
+	req.Header.Set(
+		"Authorization",
+		sigV4Algorithm+" Credential="+s.accessKey+"/"+scope+
+			",SignedHeaders="+signedHeaders+",Signature="+signature,
+	)
+}
+
+// presign adds the X-Amz-* query parameters that authorize method on u until expires has elapsed.
+func (s sigV4Signer) presign(method string, u *url.URL, expires time.Duration, now time.Time) {
+	now = now.UTC()
+	scope := s.scope(now)
+
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", now.Format(sigV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(map[string]string{"host": u.Host})
+	signature := s.signature(
+		now, scope, canonicalRequest(method, u, query, canonicalHeaders, signedHeaders, unsignedPayload),
+	)
+	query.Set("X-Amz-Signature", signature)
+
+	u.RawQuery = canonicalQuery(query)
+}
+
+// scope returns the credential scope of requests signed at now.
+func (s sigV4Signer) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/" + sigV4Service + "/aws4_request"
+}
+
+// signature returns the hex signature of a canonical request.
+func (s sigV4Signer) signature(now time.Time, scope string, canonical string) string {
+	stringToSign := sigV4Algorithm + "\n" + now.Format(sigV4TimeFormat) + "\n" + scope + "\n" + hashHex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, sigV4Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalRequest builds the canonical form of a request that Signature Version 4 signs.
+func canonicalRequest(
+	method string,
+	u *url.URL,
+	query url.Values,
+	canonicalHeaders string,
+	signedHeaders string,
+	payloadHash string,
+) string {
+	uri := uriEncode(u.Path, false)
+	if uri == "" {
+		uri = "/"
+	}
+
+	return strings.Join(
+		[]string{method, uri, canonicalQuery(query), canonicalHeaders, signedHeaders, payloadHash}, "\n",
+	)
+}
+
+// canonicalizeHeaders returns the sorted, semicolon-separated header names and their canonical name:value lines.
+func canonicalizeHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name)
+		canonical.WriteString(":")
+		canonical.WriteString(strings.Join(strings.Fields(headers[name]), " "))
+		canonical.WriteString("\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// canonicalQuery encodes query sorted by name with the URI encoding Signature Version 4 expects.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte except unreserved characters, and slashes unless encodeSlash is set.
+func uriEncode(value string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hexDigits[c>>4])
+			encoded.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+
+	return encoded.String()
+}
+
+// requestHost returns the host a request is sent to.
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+
+	return req.URL.Host
+}
+
+// hashHex returns the hex SHA-256 of data.
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+var (
+	// ErrObjectNotFound indicates that no object is stored under the requested key.
+	ErrObjectNotFound = apperror.New(apperror.KIND_NOT_FOUND, "OBJECT_NOT_FOUND", "object not found")
+
+	// ErrInvalidKey indicates that an object key is empty, absolute or escapes its parent directory.
+	ErrInvalidKey = apperror.New(apperror.KIND_VALIDATION, "INVALID_OBJECT_KEY", "invalid object key")
+
+	// ErrInvalidSignature indicates that a signed URL was not produced by this driver or was altered.
+	ErrInvalidSignature = apperror.New(apperror.KIND_FORBIDDEN, "INVALID_SIGNED_URL", "invalid signed URL")
+
+	// ErrURLExpired indicates that a signed URL is past its expiry time.
+	ErrURLExpired = apperror.New(apperror.KIND_FORBIDDEN, "SIGNED_URL_EXPIRED", "signed URL has expired")
+)
+
+type (
+	// ObjectInfo describes a stored object.
+	ObjectInfo struct {
+		Key         string
+		Size        int64
+		ContentType string
+		ModTime     time.Time
+	}
+
+	// Driver stores files under slash-separated keys such as "profile/<id>.png".
+	// Put replaces any object already stored under the key. Delete succeeds when the object does not exist.
+	// SignedURL returns a URL that downloads the object without credentials until expires has elapsed.
+	// List describes every object whose key starts with prefix, sorted by key.
+	Driver interface {
+		Put(ctx context.Context, key string, body io.Reader, contentType string) error
+		Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+		Delete(ctx context.Context, key string) error
+		Stat(ctx context.Context, key string) (ObjectInfo, error)
+		SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+		List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	}
+
+	// SignedURLVerifier is implemented by drivers whose signed URLs are served by the application itself.
+	SignedURLVerifier interface {
+		VerifySignedURL(key string, query url.Values) error
+	}
+)
+
+// New creates the Driver selected by the driver in cfg.
+func New(cfg *config.StorageConfig) (Driver, error) {
+	switch cfg.Driver {
+	case constants.ENUM_STORAGE_DRIVER_LOCAL:
+		return NewLocalDriver(cfg.LocalRoot, cfg.BaseURL, cfg.SigningKey), nil
+	case constants.ENUM_STORAGE_DRIVER_S3:
+		return NewS3Driver(cfg.S3)
+	case constants.ENUM_STORAGE_DRIVER_MEMORY:
+		return NewMemoryDriver(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// ValidateKey rejects keys that are empty, absolute, not in clean form or that climb out of the storage root.
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return nil
+}
+
+// ResolveURL returns a URL at which the object stored under key can be downloaded.
+// An empty key resolves to an empty URL and an absolute http(s) URL is returned unchanged, so values stored before
+// the object keys were introduced keep working.
+func ResolveURL(ctx context.Context, driver Driver, key string, expires time.Duration) (string, error) {
+	if key == "" || IsExternalURL(key) {
+		return key, nil
+	}
+
+	return driver.SignedURL(ctx, key, expires)
+}
+
+// IsExternalURL reports whether value is an absolute http(s) URL rather than an object key.
+func IsExternalURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// contentTypeOf guesses the content type of key from its extension.
+func contentTypeOf(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+// TestNew tests that the configured driver is created.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      config.StorageConfig
+		wantType    Driver
+		errContains string
+	}{
+		{
+			name:     "local",
+			config:   config.StorageConfig{Driver: constants.ENUM_STORAGE_DRIVER_LOCAL, LocalRoot: "files"},
+			wantType: &LocalDriver{},
+		},
+		{
+			name:     "memory",
+			config:   config.StorageConfig{Driver: constants.ENUM_STORAGE_DRIVER_MEMORY},
+			wantType: &MemoryDriver{},
+		},
+		{
+			name: "s3",
+			config: config.StorageConfig{
+				Driver: constants.ENUM_STORAGE_DRIVER_S3,
+				S3:     &config.S3Config{Region: "us-east-1", Bucket: "files", AccessKey: "a", SecretKey: "s"},
+			},
+			wantType: &S3Driver{},
+		},
+		{
+			name:        "s3 without settings",
+			config:      config.StorageConfig{Driver: constants.ENUM_STORAGE_DRIVER_S3},
+			errContains: "S3 storage requires",
+		},
+		{
+			name:        "unknown",
+			config:      config.StorageConfig{Driver: "floppy"},
+			errContains: "unknown storage driver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				driver, err := New(&tt.config)
+
+				if tt.errContains != "" {
+					assert.ErrorContains(t, err, tt.errContains)
+					return
+				}
+				require.NoError(t, err)
+				assert.IsType(t, tt.wantType, driver)
+			},
+		)
+	}
+}
+
+// TestValidateKey tests that only relative, clean keys inside the storage root are accepted.
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: "profile/a.png"},
+		{key: "a.png"},
+		{key: "", wantErr: true},
+		{key: "/profile/a.png", wantErr: true},
+		{key: "../a.png", wantErr: true},
+		{key: "profile/../../a.png", wantErr: true},
+		{key: "profile//a.png", wantErr: true},
+		{key: "profile\\a.png", wantErr: true},
+		{key: "profile/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.key, func(t *testing.T) {
+				err := ValidateKey(tt.key)
+				if tt.wantErr {
+					assert.ErrorIs(t, err, ErrInvalidKey)
+				} else {
+					assert.NoError(t, err)
+				}
+			},
+		)
+	}
+}
+
+// TestResolveURL tests that keys are signed while empty values and absolute URLs are returned unchanged.
+func TestResolveURL(t *testing.T) {
+	driver := NewMemoryDriver()
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{name: "empty", key: "", expected: ""},
+		{name: "absolute URL", key: "https://example.com/a.png", expected: "https://example.com/a.png"},
+		{name: "object key", key: "profile/a.png", expected: "memory://profile/a.png?expires=1h0m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := ResolveURL(context.Background(), driver, tt.key, time.Hour)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			},
+		)
+	}
+}
+
+// TestMemoryDriver tests that objects are recorded, listed and forgotten.
+func TestMemoryDriver(t *testing.T) {
+	ctx := context.Background()
+	driver := NewMemoryDriver()
+
+	require.NoError(t, driver.Put(ctx, "b.txt", strings.NewReader("b"), ""))
+	require.NoError(t, driver.Put(ctx, "a.png", strings.NewReader("a"), "image/png"))
+	assert.Equal(t, []string{"a.png", "b.txt"}, driver.Keys())
+
+	require.NoError(t, driver.Put(ctx, "profile/c.png", strings.NewReader("c"), "image/png"))
+	infos, err := driver.List(ctx, "profile/")
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "profile/c.png", infos[0].Key)
+
+	info, err := driver.Stat(ctx, "b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", info.ContentType)
+
+	require.NoError(t, driver.Delete(ctx, "a.png"))
+	_, _, err = driver.Get(ctx, "a.png")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/command"
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/script"
 	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
 )
 
@@ -89,7 +91,7 @@ func (suite *CommandTestSuite) TestCommands_Migrate() {
 
 // TestCommands_Seed validates that the seeding process populates the database and correctly sets the seed flag behavior.
 func (suite *CommandTestSuite) TestCommands_Seed() {
-	err := suite.db.AutoMigrate(&entity.User{})
+	err := suite.db.AutoMigrate(&entity.User{}, &migrations.SeedHistory{})
 	require.NoError(suite.T(), err, "Failed to auto migrate database")
 
 	os.Args = []string{"cmd", "--seed"}
@@ -105,6 +107,9 @@ func (suite *CommandTestSuite) TestCommands_Seed() {
 
 // TestCommands_Script verifies the behavior of the Commands function when the --script flag is provided with a script name.
 func (suite *CommandTestSuite) TestCommands_Script() {
+	err := suite.db.AutoMigrate(&script.ScriptRun{})
+	require.NoError(suite.T(), err, "Failed to auto migrate database")
+
 	os.Args = []string{"cmd", "--script:example_script"}
 
 	result := command.Commands(suite.injector)
@@ -81,7 +81,7 @@ func CloseDatabaseConnection(db *gorm.DB) error {
 }
 
 // SetUpDatabaseConnection initializes and returns a GORM database connection using environment variables for configuration.
-// It enables the `uuid-ossp` PostgreSQL extension if not already enabled. Panics on connection or setup failure.
+// It enables the `uuid-ossp` and `pg_trgm` PostgreSQL extensions if not already enabled. Panics on connection or setup failure.
 func SetUpDatabaseConnection() *gorm.DB {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
@@ -102,5 +102,10 @@ func SetUpDatabaseConnection() *gorm.DB {
 		panic(fmt.Errorf("failed to enable uuid-ossp extension: %w", err))
 	}
 
+	err = db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	if err != nil {
+		panic(fmt.Errorf("failed to enable pg_trgm extension: %w", err))
+	}
+
 	return db
 }
@@ -0,0 +1,104 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+)
+
+// TestMailPreviewController tests listing and previewing email templates in every output format.
+func TestMailPreviewController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	previewController := controller.NewMailPreviewController(
+		mailer.NewRenderer(&config.MailTemplateConfig{DefaultLocale: "en", AppName: "Acme"}),
+	)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/api/dev/mail", previewController.List)
+	router.GET("/api/dev/mail/:template", previewController.Preview)
+
+	tests := []struct {
+		name            string
+		url             string
+		acceptLanguage  string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "List templates",
+			url:             "/api/dev/mail",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `"name":"verify_email"`,
+		},
+		{
+			name:            "Preview HTML",
+			url:             "/api/dev/mail/verify_email",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html",
+			wantBody:        "Verify My Account",
+		},
+		{
+			name:            "Preview text in locale from header",
+			url:             "/api/dev/mail/verify_email?format=text",
+			acceptLanguage:  "id-ID",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain",
+			wantBody:        "Halo, jane.doe@example.com",
+		},
+		{
+			name:            "Preview JSON with locale query",
+			url:             "/api/dev/mail/verify_email?format=json&locale=id",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			wantBody:        `"subject":"Verifikasi akun Acme Anda"`,
+		},
+		{
+			name:       "Unknown template",
+			url:        "/api/dev/mail/missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid format",
+			url:        "/api/dev/mail/verify_email?format=pdf",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+				if tt.acceptLanguage != "" {
+					req.Header.Set("Accept-Language", tt.acceptLanguage)
+				}
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantStatus, w.Code)
+				if tt.wantContentType != "" {
+					assert.Contains(t, w.Header().Get("Content-Type"), tt.wantContentType)
+				}
+				if tt.wantBody != "" {
+					assert.Contains(t, w.Body.String(), tt.wantBody)
+				}
+				if tt.wantContentType == "application/json" {
+					assert.True(t, json.Valid(w.Body.Bytes()))
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,85 @@
+package controller_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// TestStorageController_Download tests that stored files are only served through valid signed URLs.
+func TestStorageController_Download(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	files := storage.NewLocalDriver(t.TempDir(), "/api/storage", []byte("signing-key"))
+	require.NoError(t, files.Put(ctx, "profile/a.png", strings.NewReader("png bytes"), "image/png"))
+
+	signedURL, err := files.SignedURL(ctx, "profile/a.png", time.Minute)
+	require.NoError(t, err)
+	missingURL, err := files.SignedURL(ctx, "profile/missing.png", time.Minute)
+	require.NoError(t, err)
+	expiredURL, err := files.SignedURL(ctx, "profile/a.png", -time.Minute)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/api/storage/*key", controller.NewStorageController(files).Download)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "valid signed URL", url: signedURL, wantStatus: http.StatusOK, wantBody: "png bytes"},
+		{name: "unsigned URL", url: "/api/storage/profile/a.png", wantStatus: http.StatusForbidden},
+		{
+			name:       "signature of another file",
+			url:        strings.Replace(signedURL, "a.png", "b.png", 1),
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "expired URL", url: expiredURL, wantStatus: http.StatusForbidden},
+		{name: "missing file", url: missingURL, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.wantStatus, w.Code)
+				if tt.wantBody != "" {
+					assert.Equal(t, tt.wantBody, w.Body.String())
+					assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"driver without application-served URLs", func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.ErrorHandler())
+			router.GET("/api/storage/*key", controller.NewStorageController(storage.NewMemoryDriver()).Download)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, signedURL, nil))
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		},
+	)
+}
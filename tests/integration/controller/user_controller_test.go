@@ -6,11 +6,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -19,12 +24,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 
+	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
 	"github.com/Caknoooo/go-gin-clean-starter/middleware"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
 	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
 	"github.com/Caknoooo/go-gin-clean-starter/utils"
 )
@@ -63,14 +71,16 @@ func TestMain(m *testing.M) {
 	if err := db.AutoMigrate(
 		&entity.User{},
 		&entity.RefreshToken{},
+		&entity.EmailOutbox{},
+		&entity.Upload{},
+		&entity.EmailChange{},
+		&entity.AuditEvent{},
 	); err != nil {
 		panic(fmt.Sprintf("Failed to migrate tables: %v", err))
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 	userController = controller.NewUserController(userService)
 
 	code := m.Run()
@@ -85,13 +95,26 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// pngImage returns an opaque w by h PNG image.
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 200, G: 80, B: 40, A: 255}}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
 // TestRegister tests the user registration functionality, handling various scenarios like success and validation errors.
 func TestRegister(t *testing.T) {
 	tests := []struct {
 		name         string
 		payload      dto.UserCreateRequest
+		imageContent []byte
 		expectedCode int
 		checkData    bool
+		fieldErrors  []utils.FieldError
 	}{
 		{
 			name: "Success register",
@@ -103,6 +126,30 @@ func TestRegister(t *testing.T) {
 			expectedCode: http.StatusOK,
 			checkData:    true,
 		},
+		{
+			name: "Success register with profile image",
+			payload: dto.UserCreateRequest{
+				Name:     "Image User",
+				Email:    "image@example.com",
+				Password: "password123",
+				Image:    &multipart.FileHeader{Filename: "avatar.png"},
+			},
+			imageContent: pngImage(t, 600, 400),
+			expectedCode: http.StatusOK,
+			checkData:    true,
+		},
+		{
+			name: "Reject script disguised as profile image",
+			payload: dto.UserCreateRequest{
+				Name:     "Script User",
+				Email:    "script@example.com",
+				Password: "password123",
+				Image:    &multipart.FileHeader{Filename: "avatar.php"},
+			},
+			imageContent: []byte("<?php system($_GET['cmd']); ?>"),
+			expectedCode: http.StatusUnsupportedMediaType,
+			checkData:    false,
+		},
 		{
 			name: "Invalid email format",
 			payload: dto.UserCreateRequest{
@@ -112,6 +159,20 @@ func TestRegister(t *testing.T) {
 			},
 			expectedCode: http.StatusBadRequest,
 			checkData:    false,
+			fieldErrors: []utils.FieldError{
+				{Field: "email", Code: "email", Message: "email must be a valid email address"},
+			},
+		},
+		{
+			name: "Invalid phone number",
+			payload: dto.UserCreateRequest{
+				Name:        "Test User",
+				Email:       "phone@example.com",
+				Password:    "password123",
+				PhoneNumber: "+0812345678",
+			},
+			expectedCode: http.StatusBadRequest,
+			checkData:    false,
 		},
 		{
 			name: "Password too short",
@@ -122,6 +183,14 @@ func TestRegister(t *testing.T) {
 			},
 			expectedCode: http.StatusBadRequest,
 			checkData:    false,
+			fieldErrors: []utils.FieldError{
+				{
+					Field:   "password",
+					Code:    "min",
+					Message: "password must be at least 8 characters in length",
+					Params:  map[string]string{"min": "8"},
+				},
+			},
 		},
 	}
 
@@ -129,6 +198,7 @@ func TestRegister(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.POST("/user", userController.Register)
 
 				body := new(bytes.Buffer)
@@ -150,7 +220,7 @@ func TestRegister(t *testing.T) {
 					if err != nil {
 						t.Fatal(err)
 					}
-					_, err = part.Write([]byte("fake image content"))
+					_, err = part.Write(tt.imageContent)
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -171,6 +241,15 @@ func TestRegister(t *testing.T) {
 
 				assert.Equal(t, tt.expectedCode, rr.Code)
 
+				if tt.fieldErrors != nil {
+					var response struct {
+						Error []utils.FieldError `json:"error"`
+					}
+					err = json.Unmarshal(rr.Body.Bytes(), &response)
+					assert.NoError(t, err)
+					assert.Equal(t, tt.fieldErrors, response.Error)
+				}
+
 				if tt.checkData {
 					var response struct {
 						Status  bool             `json:"status"`
@@ -183,6 +262,13 @@ func TestRegister(t *testing.T) {
 					assert.Equal(t, tt.payload.Name, response.Data.Name)
 					assert.Equal(t, tt.payload.Email, response.Data.Email)
 					assert.False(t, response.Data.IsVerified)
+					if tt.payload.Image != nil {
+						assert.Regexp(t, `^memory://profile/[0-9a-f-]+\.jpg\?`, response.Data.ImageUrl)
+						assert.Regexp(
+							t, `^memory://profile/[0-9a-f-]+_64\.jpg\?`, response.Data.ImageThumbnails["64"],
+						)
+						assert.Len(t, response.Data.ImageThumbnails, 3)
+					}
 				}
 
 				if tt.checkData {
@@ -193,7 +279,7 @@ func TestRegister(t *testing.T) {
 	}
 }
 
-// TestGetAllUser tests the GetAllUser API endpoint to validate user retrieval with pagination and search functionality.
+// TestGetAllUser tests the GetAllUser API endpoint to validate user retrieval with pagination, search, filters and sorting.
 func TestGetAllUser(t *testing.T) {
 	testUsers := []dto.UserCreateRequest{
 		{
@@ -214,6 +300,7 @@ func TestGetAllUser(t *testing.T) {
 	}
 
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 	router.POST("/user", userController.Register)
 
 	for _, user := range testUsers {
@@ -271,6 +358,34 @@ func TestGetAllUser(t *testing.T) {
 			expectedLen:  1,
 			checkMeta:    false,
 		},
+		{
+			name:         "Search ignores case and matches email",
+			queryParams:  "search=BOB@EXAMPLE",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+			checkMeta:    false,
+		},
+		{
+			name:         "Filter and sort",
+			queryParams:  "filter[role]=user&filter[name][like]=o&sort=-name&fields=id,name",
+			expectedCode: http.StatusOK,
+			expectedLen:  3,
+			checkMeta:    false,
+		},
+		{
+			name:         "Filter outside the allowlist",
+			queryParams:  "filter[password]=password123",
+			expectedCode: http.StatusBadRequest,
+			expectedLen:  0,
+			checkMeta:    false,
+		},
+		{
+			name:         "Sort outside the allowlist",
+			queryParams:  "sort=password",
+			expectedCode: http.StatusBadRequest,
+			expectedLen:  0,
+			checkMeta:    false,
+		},
 		{
 			name:         "Invalid page number",
 			queryParams:  "page=abc",
@@ -284,6 +399,7 @@ func TestGetAllUser(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.GET("/user", userController.GetAllUser)
 
 				url := "/user"
@@ -343,10 +459,8 @@ func TestMe(t *testing.T) {
 		Password: "password123",
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 	registeredUser, err := userService.Register(context.Background(), registerPayload)
 	assert.NoError(t, err)
 
@@ -379,6 +493,7 @@ func TestMe(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 
 				router.Use(middleware.Authenticate(jwtService))
 
@@ -410,6 +525,7 @@ func TestMe(t *testing.T) {
 					assert.Equal(t, registeredUser.ID, response.Data.ID)
 					assert.Equal(t, registerPayload.Name, response.Data.Name)
 					assert.Equal(t, registerPayload.Email, response.Data.Email)
+					assert.Equal(t, utils.ETag(1), rr.Header().Get("ETag"))
 				}
 			},
 		)
@@ -420,12 +536,7 @@ func TestMe(t *testing.T) {
 
 // TestLogin is a test function that validates the login functionality, including proper authentication and error handling.
 func TestLogin(t *testing.T) {
-	userService := service.NewUserService(
-		repository.NewUserRepository(db),
-		repository.NewRefreshTokenRepository(db),
-		service.NewJWTService(),
-		db,
-	)
+	userService := newUserService(db, service.NewJWTService(), storage.NewMemoryDriver())
 	userController := controller.NewUserController(userService)
 
 	testUser := dto.UserCreateRequest{
@@ -440,6 +551,7 @@ func TestLogin(t *testing.T) {
 	}
 
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 	router.POST("/user/register", userController.Register)
 
 	registerReq, err := http.NewRequest("POST", "/user/register", bytes.NewBuffer(userBytes))
@@ -477,7 +589,7 @@ func TestLogin(t *testing.T) {
 				Email:    "nonexistent@example.com",
 				Password: "password123",
 			},
-			expectedCode: http.StatusBadRequest,
+			expectedCode: http.StatusUnauthorized,
 			checkTokens:  false,
 		},
 		{
@@ -486,7 +598,7 @@ func TestLogin(t *testing.T) {
 				Email:    "login_test@example.com",
 				Password: "wrongpassword",
 			},
-			expectedCode: http.StatusBadRequest,
+			expectedCode: http.StatusUnauthorized,
 			checkTokens:  false,
 		},
 		{
@@ -511,6 +623,7 @@ func TestLogin(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.POST("/user/login", userController.Login)
 
 				payloadBytes, err := json.Marshal(tt.payload)
@@ -542,16 +655,17 @@ func TestLogin(t *testing.T) {
 					assert.Equal(t, dto.MESSAGE_SUCCESS_LOGIN, response.Message)
 					assert.NotEmpty(t, response.Data.AccessToken)
 					assert.NotEmpty(t, response.Data.RefreshToken)
-				} else if tt.expectedCode == http.StatusBadRequest {
+				} else {
 					var response struct {
 						Status  bool   `json:"status"`
 						Message string `json:"message"`
-						Error   string `json:"error"`
+						Code    string `json:"code"`
 					}
 					err = json.Unmarshal(rr.Body.Bytes(), &response)
 					assert.NoError(t, err)
 					assert.False(t, response.Status)
 					assert.NotEmpty(t, response.Message)
+					assert.NotEmpty(t, response.Code)
 				}
 			},
 		)
@@ -609,7 +723,7 @@ func TestSendVerificationEmail(t *testing.T) {
 			payload: dto.SendVerificationEmailRequest{
 				Email: "not_registered@example.com",
 			},
-			expectedCode: http.StatusBadRequest,
+			expectedCode: http.StatusNotFound,
 			wantSuccess:  false,
 		},
 	}
@@ -618,6 +732,7 @@ func TestSendVerificationEmail(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.POST("/user/send_verification_email", userController.SendVerificationEmail)
 
 				payloadBytes, err := json.Marshal(tt.payload)
@@ -691,17 +806,14 @@ func TestVerifyEmail(t *testing.T) {
 		}
 	}()
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
-
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	userController := controller.NewUserController(userService)
 
@@ -759,6 +871,7 @@ func TestVerifyEmail(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.POST("/user/verify_email", userController.VerifyEmail)
 
 				reqBody, err := json.Marshal(tt.payload)
@@ -848,17 +961,14 @@ func TestUpdate(t *testing.T) {
 		}
 	}()
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.EmailChange{}, &entity.AuditEvent{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
-
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	userController := controller.NewUserController(userService)
 
@@ -879,6 +989,7 @@ func TestUpdate(t *testing.T) {
 		payload      dto.UserUpdateRequest
 		userID       string
 		token        string
+		ifMatch      string
 		expectedCode int
 		checkData    bool
 	}{
@@ -891,6 +1002,7 @@ func TestUpdate(t *testing.T) {
 			},
 			userID:       registeredUser.ID,
 			token:        token,
+			ifMatch:      utils.ETag(1),
 			expectedCode: http.StatusOK,
 			checkData:    true,
 		},
@@ -903,6 +1015,7 @@ func TestUpdate(t *testing.T) {
 			},
 			userID:       registeredUser.ID,
 			token:        token,
+			ifMatch:      utils.ETag(1),
 			expectedCode: http.StatusBadRequest,
 			checkData:    false,
 		},
@@ -918,12 +1031,34 @@ func TestUpdate(t *testing.T) {
 			expectedCode: http.StatusUnauthorized,
 			checkData:    false,
 		},
+		{
+			name: "Stale If-Match",
+			payload: dto.UserUpdateRequest{
+				Name: "Stale User",
+			},
+			userID:       registeredUser.ID,
+			token:        token,
+			ifMatch:      utils.ETag(1),
+			expectedCode: http.StatusPreconditionFailed,
+			checkData:    false,
+		},
+		{
+			name: "Missing If-Match",
+			payload: dto.UserUpdateRequest{
+				Name: "Unconditional User",
+			},
+			userID:       registeredUser.ID,
+			token:        token,
+			expectedCode: http.StatusPreconditionRequired,
+			checkData:    false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 
 				router.Use(middleware.Authenticate(jwtService))
 
@@ -942,6 +1077,9 @@ func TestUpdate(t *testing.T) {
 				if tt.token != "" {
 					req.Header.Set("Authorization", "Bearer "+tt.token)
 				}
+				if tt.ifMatch != "" {
+					req.Header.Set("If-Match", tt.ifMatch)
+				}
 
 				rr := httptest.NewRecorder()
 
@@ -958,6 +1096,7 @@ func TestUpdate(t *testing.T) {
 					err = json.Unmarshal(rr.Body.Bytes(), &response)
 					assert.NoError(t, err, "Failed to unmarshal response for test: %s", tt.name)
 					assert.True(t, response.Status, "Response status should be true for test: %s", tt.name)
+					assert.Equal(t, utils.ETag(2), rr.Header().Get("ETag"))
 					assert.Equal(
 						t,
 						dto.MESSAGE_SUCCESS_UPDATE_USER,
@@ -981,11 +1120,18 @@ func TestUpdate(t *testing.T) {
 					)
 					assert.Equal(
 						t,
-						tt.payload.Email,
+						registerReq.Email,
 						response.Data.Email,
 						"Email mismatch in response for test: %s",
 						tt.name,
 					)
+					assert.Equal(
+						t,
+						tt.payload.Email,
+						response.Data.PendingEmail,
+						"Pending email mismatch in response for test: %s",
+						tt.name,
+					)
 
 					user, err := userService.GetUserById(context.Background(), registeredUser.ID)
 					assert.NoError(t, err, "Failed to fetch user from database for test: %s", tt.name)
@@ -997,18 +1143,144 @@ func TestUpdate(t *testing.T) {
 						"PhoneNumber mismatch in database for test: %s",
 						tt.name,
 					)
-					assert.Equal(t, tt.payload.Email, user.Email, "Email mismatch in database for test: %s", tt.name)
+					assert.Equal(t, registerReq.Email, user.Email, "Email mismatch in database for test: %s", tt.name)
+					assert.Equal(t, []string{"name", "phone_number"}, response.Data.ChangedFields)
 				}
 			},
 		)
 	}
 
+	t.Run(
+		"Merge patch clears phone number", func(t *testing.T) {
+			router := gin.Default()
+			router.Use(middleware.ErrorHandler())
+			router.Use(middleware.Authenticate(jwtService))
+			router.PATCH("/user", userController.Update)
+
+			req, err := http.NewRequest("PATCH", "/user", strings.NewReader(`{"phone_number":null,"name":"Updated User"}`))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set("Content-Type", utils.MERGE_PATCH_CONTENT_TYPE)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("If-Match", utils.ETag(2))
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, utils.ETag(3), rr.Header().Get("ETag"))
+
+			var response struct {
+				Data dto.UserUpdateResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, []string{"phone_number"}, response.Data.ChangedFields)
+			assert.Empty(t, response.Data.PhoneNumber)
+			assert.Equal(t, registerReq.Email, response.Data.Email)
+
+			user, err := userService.GetUserById(context.Background(), registeredUser.ID)
+			assert.NoError(t, err)
+			assert.Empty(t, user.PhoneNumber)
+		},
+	)
+
 	err = db.Exec("DELETE FROM users WHERE email = ?", registerReq.Email).Error
 	if err != nil {
 		t.Fatalf("Failed to clean up test user: %v", err)
 	}
 }
 
+// TestConfirmEmailChange tests the ConfirmEmailChange endpoint of the UserController: a pending change requested with
+// PATCH /user is applied with the token emailed to the new address, once, and refused for unknown tokens.
+func TestConfirmEmailChange(t *testing.T) {
+	registerPayload := dto.UserCreateRequest{
+		Name:     "Email Change User",
+		Email:    "email_change_old@example.com",
+		Password: "password123",
+	}
+
+	jwtService := service.NewJWTService()
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
+	registeredUser, err := userService.Register(context.Background(), registerPayload)
+	assert.NoError(t, err)
+
+	_, err = userService.Update(
+		context.Background(),
+		dto.UserUpdateRequest{Email: "email_change_new@example.com"},
+		registeredUser.ID,
+	)
+	assert.NoError(t, err)
+
+	var confirmation entity.EmailOutbox
+	err = db.Where("recipient = ?", "email_change_new@example.com").Take(&confirmation).Error
+	assert.NoError(t, err)
+	match := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(confirmation.TextBody)
+	if !assert.Len(t, match, 2) {
+		t.FailNow()
+	}
+
+	tests := []struct {
+		name         string
+		payload      string
+		expectedCode int
+		checkData    bool
+	}{
+		{
+			name:         "Success confirm email change",
+			payload:      `{"token":"` + match[1] + `"}`,
+			expectedCode: http.StatusOK,
+			checkData:    true,
+		},
+		{
+			name:         "Token already used",
+			payload:      `{"token":"` + match[1] + `"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Missing token",
+			payload:      `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
+				router.POST("/user/email/confirm", userController.ConfirmEmailChange)
+
+				req, err := http.NewRequest("POST", "/user/email/confirm", strings.NewReader(tt.payload))
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Content-Type", "application/json")
+
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+
+				assert.Equal(t, tt.expectedCode, rr.Code)
+
+				if tt.checkData {
+					var response struct {
+						Status  bool             `json:"status"`
+						Message string           `json:"message"`
+						Data    dto.UserResponse `json:"data"`
+					}
+					assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+					assert.True(t, response.Status)
+					assert.Equal(t, dto.MESSAGE_SUCCESS_CONFIRM_EMAIL_CHANGE, response.Message)
+					assert.Equal(t, "email_change_new@example.com", response.Data.Email)
+					assert.True(t, response.Data.IsVerified)
+				}
+			},
+		)
+	}
+
+	db.Exec("DELETE FROM users WHERE id = ?", registeredUser.ID)
+}
+
 // TestDelete tests the functionality of deleting a user and validates different scenarios such as success and unauthorized access.
 func TestDelete(t *testing.T) {
 	registerReq := dto.UserCreateRequest{
@@ -1017,10 +1289,8 @@ func TestDelete(t *testing.T) {
 		Password: "password123",
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 	registeredUser, err := userService.Register(context.Background(), registerReq)
 	assert.NoError(t, err)
 
@@ -1052,6 +1322,7 @@ func TestDelete(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 
 				router.Use(middleware.Authenticate(jwtService))
 
@@ -1091,6 +1362,153 @@ func TestDelete(t *testing.T) {
 	db.Exec("DELETE FROM users WHERE email = ?", registerReq.Email)
 }
 
+// TestAvatar tests uploading, replacing and deleting the profile image of the authenticated user, and that replaced
+// images, deleted images and the images of deleted users are removed from storage.
+func TestAvatar(t *testing.T) {
+	ctx := context.Background()
+	files := storage.NewMemoryDriver()
+	jwtService := service.NewJWTService()
+	userService := newUserService(db, jwtService, files)
+	avatarController := controller.NewUserController(userService)
+
+	registeredUser, err := userService.Register(
+		ctx, dto.UserCreateRequest{Name: "Avatar User", Email: "avatar@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM users WHERE email = ?", "avatar@example.com")
+
+	token := jwtService.GenerateAccessToken(registeredUser.ID, registeredUser.Role)
+
+	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Authenticate(jwtService))
+	router.PUT("/user/avatar", avatarController.UpdateAvatar)
+	router.DELETE("/user/avatar", avatarController.DeleteAvatar)
+
+	var previousImage string
+	tests := []struct {
+		name         string
+		method       string
+		image        []byte
+		expectedCode int
+		wantFiles    int
+		wantImage    bool
+	}{
+		{
+			name:         "Upload avatar",
+			method:       http.MethodPut,
+			image:        pngImage(t, 300, 200),
+			expectedCode: http.StatusOK,
+			wantFiles:    4,
+			wantImage:    true,
+		},
+		{
+			name:         "Replace avatar removes previous files",
+			method:       http.MethodPut,
+			image:        pngImage(t, 100, 100),
+			expectedCode: http.StatusOK,
+			wantFiles:    4,
+			wantImage:    true,
+		},
+		{
+			name:         "Reject unsupported image type",
+			method:       http.MethodPut,
+			image:        []byte("<?php echo 1; ?>"),
+			expectedCode: http.StatusUnsupportedMediaType,
+			wantFiles:    4,
+		},
+		{
+			name:         "Missing image",
+			method:       http.MethodPut,
+			expectedCode: http.StatusBadRequest,
+			wantFiles:    4,
+		},
+		{
+			name:         "Delete avatar",
+			method:       http.MethodDelete,
+			expectedCode: http.StatusOK,
+			wantFiles:    0,
+		},
+		{
+			name:         "Delete missing avatar",
+			method:       http.MethodDelete,
+			expectedCode: http.StatusOK,
+			wantFiles:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				body := new(bytes.Buffer)
+				contentType := ""
+				if tt.method == http.MethodPut {
+					writer := multipart.NewWriter(body)
+					if tt.image != nil {
+						part, err := writer.CreateFormFile("image", "avatar.png")
+						require.NoError(t, err)
+						_, err = part.Write(tt.image)
+						require.NoError(t, err)
+					}
+					require.NoError(t, writer.Close())
+					contentType = writer.FormDataContentType()
+				}
+
+				req, err := http.NewRequest(tt.method, "/user/avatar", body)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+				if contentType != "" {
+					req.Header.Set("Content-Type", contentType)
+				}
+
+				rr := httptest.NewRecorder()
+				router.ServeHTTP(rr, req)
+
+				assert.Equal(t, tt.expectedCode, rr.Code)
+				assert.Len(t, files.Keys(), tt.wantFiles)
+
+				if tt.expectedCode != http.StatusOK {
+					return
+				}
+
+				var response struct {
+					Data dto.UserResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+				if tt.wantImage {
+					assert.Regexp(t, `^memory://profile/[0-9a-f-]+\.jpg\?`, response.Data.ImageUrl)
+					assert.NotEqual(t, previousImage, response.Data.ImageUrl)
+					assert.Len(t, response.Data.ImageThumbnails, 3)
+				} else {
+					assert.Empty(t, response.Data.ImageUrl)
+					assert.Empty(t, response.Data.ImageThumbnails)
+				}
+				previousImage = response.Data.ImageUrl
+			},
+		)
+	}
+
+	_, err = userService.UpdateAvatar(ctx, registeredUser.ID, bytes.NewReader(pngImage(t, 64, 64)))
+	require.NoError(t, err)
+	assert.Len(t, files.Keys(), 4)
+
+	require.NoError(t, userService.Delete(ctx, registeredUser.ID))
+	assert.Len(t, files.Keys(), 4, "files should be kept while the account can be restored")
+
+	require.NoError(
+		t,
+		db.Unscoped().
+			Model(&entity.User{}).
+			Where("id = ?", registeredUser.ID).
+			Update("purge_at", time.Now().Add(-time.Minute)).Error,
+	)
+	purged, err := userService.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Empty(t, files.Keys(), "purging a user should remove their files")
+}
+
 // TestRefreshToken tests the refresh token functionality, covering success, invalid token, and empty token scenarios.
 func TestRefreshToken(t *testing.T) {
 	registerReq := dto.UserCreateRequest{
@@ -1099,10 +1517,8 @@ func TestRefreshToken(t *testing.T) {
 		Password: "password123",
 	}
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	_, err := userService.Register(context.Background(), registerReq)
 	assert.NoError(t, err)
@@ -1149,6 +1565,7 @@ func TestRefreshToken(t *testing.T) {
 		t.Run(
 			tt.name, func(t *testing.T) {
 				router := gin.Default()
+				router.Use(middleware.ErrorHandler())
 				router.POST("/user/refresh", userController.Refresh)
 
 				payloadBytes, err := json.Marshal(tt.payload)
@@ -1197,3 +1614,22 @@ func TestRefreshToken(t *testing.T) {
 	db.Exec("DELETE FROM users WHERE email = ?", registerReq.Email)
 	db.Exec("DELETE FROM refresh_tokens WHERE token = ?", refreshToken)
 }
+
+// newUserService creates a UserService backed by db, with jwtService and files and the repositories, renderer and
+// transaction manager the application provides.
+func newUserService(db *gorm.DB, jwtService service.JWTService, files storage.Driver) service.UserService {
+	return service.NewUserService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		repository.NewEmailOutboxRepository(db),
+		repository.NewUploadRepository(db),
+		repository.NewDataExportRepository(db),
+		repository.NewEmailChangeRepository(db),
+		repository.NewAuditEventRepository(db),
+		jwtService,
+		mailer.NewRenderer(config.NewMailTemplateConfig()),
+		files,
+		repository.NewTxManager(db),
+		config.NewPhoneConfig().DefaultCountryCode,
+	)
+}
@@ -0,0 +1,144 @@
+package mailer_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+)
+
+// SMTPMailerIntegrationTestSuite is a test suite for verifying the SMTP mailer against a MailHog SMTP server.
+// It sets up and tears down the SMTP container during the test lifecycle.
+type SMTPMailerIntegrationTestSuite struct {
+	suite.Suite
+	smtpContainer testcontainers.Container
+	apiURL        string
+	mailer        mailer.Mailer
+}
+
+// SetupSuite starts the MailHog container and builds an SMTP mailer pointing at it.
+func (suite *SMTPMailerIntegrationTestSuite) SetupSuite() {
+	ctx := context.Background()
+
+	smtpReq := testcontainers.ContainerRequest{
+		Image:        "mailhog/mailhog",
+		ExposedPorts: []string{"1025/tcp", "8025/tcp"},
+		WaitingFor:   wait.ForListeningPort("1025/tcp"),
+	}
+	smtpContainer, err := testcontainers.GenericContainer(
+		ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: smtpReq,
+			Started:          true,
+		},
+	)
+	require.NoError(suite.T(), err)
+	suite.smtpContainer = smtpContainer
+
+	smtpHost, err := smtpContainer.Host(ctx)
+	require.NoError(suite.T(), err)
+
+	smtpPort, err := smtpContainer.MappedPort(ctx, "1025")
+	require.NoError(suite.T(), err)
+
+	apiPort, err := smtpContainer.MappedPort(ctx, "8025")
+	require.NoError(suite.T(), err)
+	suite.apiURL = fmt.Sprintf("http://%s:%s", smtpHost, apiPort.Port())
+
+	suite.mailer = mailer.NewSMTPMailer(
+		&config.MailConfig{
+			Driver: constants.ENUM_MAIL_DRIVER_SMTP,
+			From:   "Test Sender <test@example.com>",
+			SMTP: &config.EmailConfig{
+				Host:         smtpHost,
+				Port:         smtpPort.Int(),
+				AuthEmail:    "test@example.com",
+				AuthPassword: "password123",
+			},
+			Encryption: constants.ENUM_SMTP_ENCRYPTION_STARTTLS,
+			PoolSize:   2,
+		},
+	)
+}
+
+// TearDownSuite stops the SMTP container.
+func (suite *SMTPMailerIntegrationTestSuite) TearDownSuite() {
+	timeout := 10 * time.Second
+
+	if suite.smtpContainer != nil {
+		_ = suite.smtpContainer.Stop(context.Background(), &timeout)
+	}
+}
+
+// TestSend_Integration validates that messages are delivered over pooled connections and invalid messages are rejected.
+func (suite *SMTPMailerIntegrationTestSuite) TestSend_Integration() {
+	tests := []struct {
+		name      string
+		msg       mailer.Message
+		wantError bool
+	}{
+		{
+			name: "Successfully send email",
+			msg: mailer.Message{
+				To:      []string{"recipient@example.com"},
+				Subject: "Test Subject",
+				HTML:    "<p>Test Body</p>",
+				Text:    "Test Body",
+			},
+			wantError: false,
+		},
+		{
+			name: "Reuse pooled connection",
+			msg: mailer.Message{
+				To:      []string{"recipient@example.com"},
+				Subject: "Second Subject",
+				HTML:    "<p>Test Body</p>",
+			},
+			wantError: false,
+		},
+		{
+			name:      "Invalid recipient email",
+			msg:       mailer.Message{Subject: "Test Subject", HTML: "<p>Test Body</p>"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(
+			tt.name, func() {
+				err := suite.mailer.Send(context.Background(), tt.msg)
+				if tt.wantError {
+					assert.Error(suite.T(), err)
+				} else {
+					assert.NoError(suite.T(), err)
+				}
+			},
+		)
+	}
+
+	resp, err := http.Get(suite.apiURL + "/api/v2/messages")
+	require.NoError(suite.T(), err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var messages struct {
+		Total int `json:"total"`
+	}
+	require.NoError(suite.T(), json.NewDecoder(resp.Body).Decode(&messages))
+	assert.Equal(suite.T(), 2, messages.Total)
+}
+
+// TestSMTPMailerIntegrationTestSuite runs the SMTP mailer integration test suite.
+func TestSMTPMailerIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(SMTPMailerIntegrationTestSuite))
+}
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
@@ -191,6 +193,117 @@ func TestUserRepository(t *testing.T) {
 		},
 	)
 
+	t.Run(
+		"GetAllUserWithPagination filters, sorts and selects fields", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			for _, u := range []entity.User{
+				{Name: "alice", Email: "alice@example.com", Password: "password123", Role: "admin", IsVerified: true},
+				{Name: "Bob", Email: "bob@example.com", Password: "password123", Role: "user", IsVerified: true},
+				{Name: "Carol", Email: "carol@EXAMPLE.org", Password: "password123", Role: "user"},
+			} {
+				_, err := repo.Register(ctx, nil, u)
+				assert.NoError(t, err)
+			}
+
+			response, err := repo.GetAllUserWithPagination(
+				ctx, nil, dto.PaginationRequest{
+					Search: "example.ORG",
+				},
+			)
+			assert.NoError(t, err)
+			assert.Len(t, response.Users, 1)
+			assert.Equal(t, "Carol", response.Users[0].Name)
+
+			response, err = repo.GetAllUserWithPagination(
+				ctx, nil, dto.PaginationRequest{
+					Sort:   "-email",
+					Fields: "id,role",
+					Filters: []dto.Filter{
+						{Field: "is_verified", Operator: dto.FILTER_OPERATOR_EQ, Value: "true"},
+						{Field: "role", Operator: dto.FILTER_OPERATOR_IN, Value: "admin,user"},
+					},
+				},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(2), response.PaginationResponse.Count)
+			if assert.Len(t, response.Users, 2) {
+				assert.Equal(t, "bob@example.com", response.Users[0].Email, "sorted columns are always loaded")
+				assert.Equal(t, "alice@example.com", response.Users[1].Email)
+				assert.Equal(t, "user", response.Users[0].Role)
+				assert.Empty(t, response.Users[0].Name, "unselected columns are not loaded")
+				assert.NotEqual(t, uuid.Nil, response.Users[0].ID)
+			}
+
+			_, err = repo.GetAllUserWithPagination(
+				ctx, nil, dto.PaginationRequest{
+					Filters: []dto.Filter{{Field: "password", Operator: dto.FILTER_OPERATOR_EQ, Value: "x"}},
+				},
+			)
+			assert.ErrorIs(t, err, dto.ErrInvalidListQuery)
+		},
+	)
+
+	t.Run(
+		"GetAllUserWithPagination follows cursors in both directions", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			for _, name := range []string{"Dave", "Alice", "Carol", "Bob", "Erin"} {
+				_, err := repo.Register(
+					ctx, nil, entity.User{
+						Name: name, Email: strings.ToLower(name) + "@example.com", Password: "password123",
+					},
+				)
+				assert.NoError(t, err)
+			}
+
+			names := func(users []entity.User) []string {
+				result := make([]string, 0, len(users))
+				for _, user := range users {
+					result = append(result, user.Name)
+				}
+				return result
+			}
+
+			req := dto.PaginationRequest{PerPage: 2, Sort: "-name"}
+			first, err := repo.GetAllUserWithPagination(ctx, nil, req)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Erin", "Dave"}, names(first.Users))
+			assert.Equal(t, int64(5), first.Count)
+			assert.NotEmpty(t, first.NextCursor)
+			assert.Empty(t, first.PrevCursor)
+
+			_, err = repo.Register(
+				ctx, nil, entity.User{Name: "Aaron", Email: "aaron@example.com", Password: "password123"},
+			)
+			assert.NoError(t, err)
+
+			req.Cursor = first.NextCursor
+			second, err := repo.GetAllUserWithPagination(ctx, nil, req)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Carol", "Bob"}, names(second.Users), "inserted rows do not shift the page")
+			assert.Zero(t, second.Count, "cursor pages are not counted by default")
+			assert.Zero(t, second.Page)
+
+			req.Cursor = second.NextCursor
+			req.Count = dto.PAGINATION_COUNT_ESTIMATE
+			third, err := repo.GetAllUserWithPagination(ctx, nil, req)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Alice", "Aaron"}, names(third.Users))
+			assert.Empty(t, third.NextCursor)
+			assert.True(t, third.CountEstimated)
+
+			req.Cursor = third.PrevCursor
+			req.Count = ""
+			back, err := repo.GetAllUserWithPagination(ctx, nil, req)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Carol", "Bob"}, names(back.Users))
+			assert.NotEmpty(t, back.PrevCursor)
+
+			req.Sort = "name"
+			_, err = repo.GetAllUserWithPagination(ctx, nil, req)
+			assert.ErrorIs(t, err, dto.ErrInvalidCursor)
+		},
+	)
+
 	t.Run(
 		"GetUserById", func(t *testing.T) {
 			t.Cleanup(cleanDB)
@@ -273,6 +386,81 @@ func TestUserRepository(t *testing.T) {
 		},
 	)
 
+	t.Run(
+		"Search", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			users := []entity.User{
+				{Name: "Jane Doe", Email: "jane.doe@example.com", PhoneNumber: "081234567890"},
+				{Name: "Janet Jones", Email: "janet@example.org", PhoneNumber: "082200003333"},
+				{Name: "John Smith", Email: "john@acme.io", PhoneNumber: "089900001111"},
+			}
+			for _, user := range users {
+				user.ID = uuid.New()
+				user.Password = "password123"
+				user.Role = "user"
+				_, err := repo.Register(ctx, nil, user)
+				require.NoError(t, err)
+			}
+
+			tests := []struct {
+				name       string
+				query      string
+				expected   []string
+				highlights map[string]string
+			}{
+				{
+					name:       "name prefix",
+					query:      "jan",
+					expected:   []string{"Jane Doe", "Janet Jones"},
+					highlights: map[string]string{"name": "<mark>Jane</mark> Doe"},
+				},
+				{
+					name:     "misspelled name",
+					query:    "Smithh",
+					expected: []string{"John Smith"},
+				},
+				{
+					name:     "email domain",
+					query:    "acme",
+					expected: []string{"John Smith"},
+				},
+				{
+					name:       "phone digits",
+					query:      "1234 5678",
+					expected:   []string{"Jane Doe"},
+					highlights: map[string]string{"phone_number": "08<mark>12345678</mark>90"},
+				},
+				{name: "no match", query: "zzzz"},
+			}
+
+			for _, tt := range tests {
+				t.Run(
+					tt.name, func(t *testing.T) {
+						result, err := repo.Search(ctx, nil, dto.UserSearchRequest{Query: tt.query})
+						require.NoError(t, err)
+						assert.Equal(t, int64(len(tt.expected)), result.Count)
+
+						names := make([]string, 0, len(result.Hits))
+						for i, hit := range result.Hits {
+							names = append(names, hit.User.Name)
+							if i > 0 {
+								assert.GreaterOrEqual(t, result.Hits[i-1].Rank, hit.Rank)
+							}
+						}
+						assert.ElementsMatch(t, tt.expected, names)
+
+						if tt.highlights != nil {
+							require.NotEmpty(t, result.Hits)
+							for field, value := range tt.highlights {
+								assert.Equal(t, value, result.Hits[0].Highlights[field])
+							}
+						}
+					},
+				)
+			}
+		},
+	)
+
 	t.Run(
 		"Update", func(t *testing.T) {
 			t.Cleanup(cleanDB)
@@ -288,7 +476,7 @@ func TestUserRepository(t *testing.T) {
 					UpdatedAt: time.Now(),
 				},
 			}
-			_, err := repo.Register(ctx, nil, user)
+			registered, err := repo.Register(ctx, nil, user)
 			assert.NoError(t, err)
 
 			updatedUser := entity.User{
@@ -297,6 +485,7 @@ func TestUserRepository(t *testing.T) {
 				Email:       "updated@example.com",
 				PhoneNumber: "7777777777",
 				Role:        "admin",
+				Versioned:   registered.Versioned,
 				Timestamp: entity.Timestamp{
 					UpdatedAt: time.Now(),
 				},
@@ -308,6 +497,15 @@ func TestUserRepository(t *testing.T) {
 			assert.Equal(t, updatedUser.Email, result.Email)
 			assert.Equal(t, updatedUser.PhoneNumber, result.PhoneNumber)
 			assert.Equal(t, updatedUser.Role, result.Role)
+			assert.Equal(t, registered.Version+1, result.Version)
+
+			updatedUser.Name = "Stale User"
+			_, err = repo.Update(ctx, nil, updatedUser)
+			assert.ErrorIs(t, err, dto.ErrVersionConflict)
+
+			stored, err := repo.GetUserById(ctx, nil, user.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, "Updated User", stored.Name)
 		},
 	)
 
@@ -20,6 +20,7 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
 	"github.com/Caknoooo/go-gin-clean-starter/provider"
 	"github.com/Caknoooo/go-gin-clean-starter/routes"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
@@ -57,7 +58,7 @@ func TestMain(m *testing.M) {
 
 	db = container.SetUpDatabaseConnection()
 
-	if err := db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}); err != nil {
+	if err := db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}); err != nil {
 		panic(fmt.Sprintf("Failed to migrate tables: %v", err))
 	}
 
@@ -96,6 +97,7 @@ func TestMain(m *testing.M) {
 // TestUserRoutes tests various HTTP endpoints related to user routes, ensuring proper functionality and error handling.
 func TestUserRoutes(t *testing.T) {
 	router := gin.Default()
+	router.Use(middleware.ErrorHandler())
 
 	routes.User(router, injector)
 
@@ -195,21 +197,31 @@ func TestUserRoutes(t *testing.T) {
 			setupUser:    false,
 		},
 		{
-			name:         "GetAllUser - Valid request",
+			name:         "GetAllUser - No auth",
 			method:       "GET",
 			path:         "/api/user?page=1&per_page=10",
 			body:         nil,
 			contentType:  "",
-			expectedCode: http.StatusOK,
+			expectedCode: http.StatusUnauthorized,
 			setupUser:    false,
 		},
+		{
+			name:         "GetAllUser - Not an admin",
+			method:       "GET",
+			path:         "/api/user?page=1&per_page=10",
+			body:         nil,
+			contentType:  "",
+			authToken:    "",
+			expectedCode: http.StatusForbidden,
+			setupUser:    true,
+		},
 		{
 			name:         "Login - Invalid credentials",
 			method:       "POST",
 			path:         "/api/user/login",
 			body:         dto.UserLoginRequest{Email: "wrong@example.com", Password: "wrong"},
 			contentType:  "application/json",
-			expectedCode: http.StatusBadRequest,
+			expectedCode: http.StatusUnauthorized,
 			setupUser:    false,
 		},
 		{
@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// TestDataExportService tests requesting a personal data export, building its archive, emailing its download link
+// and purging it once it expires.
+func TestDataExportService(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(
+		&entity.User{},
+		&entity.RefreshToken{},
+		&entity.EmailOutbox{},
+		&entity.EmailSuppression{},
+		&entity.Upload{},
+		&entity.DataExport{},
+		&entity.AuditEvent{},
+	)
+	assert.NoError(t, err)
+
+	files := storage.NewMemoryDriver()
+	userRepo := repository.NewUserRepository(db)
+	exportRepo := repository.NewDataExportRepository(db)
+	userService := newUserService(db, service.NewJWTService(), files)
+	exportService := service.NewDataExportService(
+		exportRepo,
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		repository.NewUploadRepository(db),
+		repository.NewEmailOutboxRepository(db),
+		repository.NewAuditEventRepository(db),
+		mailer.NewRenderer(config.NewMailTemplateConfig()),
+		files,
+		repository.NewTxManager(db),
+	)
+	ctx := context.Background()
+
+	user, err := userRepo.Register(
+		ctx, nil, entity.User{Name: "Export Owner", Email: "export@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+	other, err := userRepo.Register(
+		ctx, nil, entity.User{Name: "Other User", Email: "other-export@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+	userID := user.ID.String()
+
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: user.Email, Password: "password123"})
+	require.NoError(t, err)
+	_, err = userService.UpdateAvatar(ctx, userID, bytes.NewReader(uploadImage(t, 64, 64)))
+	require.NoError(t, err)
+
+	var exportID string
+	t.Run(
+		"builds the archive and emails its download link", func(t *testing.T) {
+			requested, err := exportService.Request(ctx, userID, "en")
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_DATA_EXPORT_PENDING, requested.Status)
+			assert.Empty(t, requested.URL)
+			exportID = requested.ID
+
+			again, err := exportService.Request(ctx, userID, "en")
+			require.NoError(t, err)
+			assert.Equal(t, requested.ID, again.ID, "a pending export is reused")
+
+			_, err = exportService.Get(ctx, other.ID.String(), requested.ID)
+			assert.ErrorIs(t, err, dto.ErrDataExportNotFound, "exports of other users must stay hidden")
+
+			claimed, err := exportService.ProcessPending(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, claimed)
+
+			completed, err := exportService.Get(ctx, userID, requested.ID)
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_DATA_EXPORT_COMPLETED, completed.Status)
+			assert.NotEmpty(t, completed.URL)
+			assert.Positive(t, completed.Size)
+			require.NotNil(t, completed.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(service.DATA_EXPORT_TTL), *completed.ExpiresAt, time.Minute)
+
+			body, _, err := files.Get(ctx, "exports/"+requested.ID+".zip")
+			require.NoError(t, err)
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.NoError(t, body.Close())
+
+			archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			require.NoError(t, err)
+			names := make(map[string]*zip.File, len(archive.File))
+			for _, file := range archive.File {
+				names[file.Name] = file
+			}
+			for _, name := range []string{
+				"manifest.json", "profile.json", "sessions.json", "audit_events.json", "uploads.json",
+			} {
+				assert.Contains(t, names, name)
+			}
+
+			profile, err := names["profile.json"].Open()
+			require.NoError(t, err)
+			var exported map[string]any
+			require.NoError(t, json.NewDecoder(profile).Decode(&exported))
+			assert.Equal(t, user.Email, exported["email"])
+			assert.Contains(t, names, exported["image"], "the profile image is included")
+
+			auditEvents, err := names["audit_events.json"].Open()
+			require.NoError(t, err)
+			var events []struct {
+				Type    string            `json:"type"`
+				Details map[string]string `json:"details"`
+			}
+			require.NoError(t, json.NewDecoder(auditEvents).Decode(&events))
+			require.Len(t, events, 2, "only the recorded audit events are exported")
+			assert.Equal(t, constants.ENUM_AUDIT_LOGIN, events[0].Type)
+			assert.Equal(t, constants.ENUM_AUDIT_DATA_EXPORT_REQUESTED, events[1].Type)
+			assert.Equal(t, requested.ID, events[1].Details["export_id"])
+
+			var email entity.EmailOutbox
+			require.NoError(
+				t,
+				db.Where("recipient = ? AND subject LIKE ?", user.Email, "%data export%").Take(&email).Error,
+			)
+			assert.Contains(t, email.TextBody, "exports/"+requested.ID+".zip")
+		},
+	)
+
+	t.Run(
+		"purges expired exports", func(t *testing.T) {
+			require.NotEmpty(t, exportID)
+			require.NoError(
+				t,
+				db.Model(&entity.DataExport{}).
+					Where("id = ?", exportID).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error,
+			)
+
+			_, err := exportService.Get(ctx, userID, exportID)
+			assert.ErrorIs(t, err, dto.ErrDataExportExpired)
+
+			purged, err := exportService.PurgeExpired(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, purged)
+
+			_, err = exportService.Get(ctx, userID, exportID)
+			assert.ErrorIs(t, err, dto.ErrDataExportNotFound)
+			for _, key := range files.Keys() {
+				assert.False(t, strings.HasPrefix(key, "exports/"), "purged archives remain: %s", key)
+			}
+		},
+	)
+
+	t.Run(
+		"rejects deactivated users", func(t *testing.T) {
+			require.NoError(t, userService.Deactivate(ctx, other.ID.String()))
+
+			_, err := exportService.Request(ctx, other.ID.String(), "")
+			assert.ErrorIs(t, err, dto.ErrAccountDeactivated)
+		},
+	)
+}
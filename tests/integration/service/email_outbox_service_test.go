@@ -0,0 +1,233 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// TestEmailOutboxService_DispatchDue tests delivery, retry scheduling, lease expiry, dead-lettering and requeueing of
+// outbox emails.
+func TestEmailOutboxService_DispatchDue(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.EmailOutbox{})
+	assert.NoError(t, err)
+
+	mockMailer := &MockMailer{}
+	outboxRepo := repository.NewEmailOutboxRepository(db)
+	outboxService := service.NewEmailOutboxService(outboxRepo, mockMailer)
+	ctx := context.Background()
+
+	t.Run(
+		"delivers due email", func(t *testing.T) {
+			db.Exec("TRUNCATE TABLE email_outbox")
+			mockMailer.ExpectedCalls = nil
+			mockMailer.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+
+			msg := mailer.Message{To: []string{"ok@example.com"}, Subject: "Hello", HTML: "<p>Hi</p>"}
+			assert.NoError(t, outboxService.Enqueue(ctx, nil, msg))
+
+			attempted, err := outboxService.DispatchDue(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, attempted)
+
+			var message entity.EmailOutbox
+			assert.NoError(t, db.Where("recipient = ?", "ok@example.com").First(&message).Error)
+			assert.Equal(t, constants.ENUM_OUTBOX_SENT, message.Status)
+			assert.NotNil(t, message.SentAt)
+			mockMailer.AssertExpectations(t)
+		},
+	)
+
+	t.Run(
+		"schedules retry after failure", func(t *testing.T) {
+			db.Exec("TRUNCATE TABLE email_outbox")
+			mockMailer.ExpectedCalls = nil
+			mockMailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp down")).Once()
+
+			msg := mailer.Message{To: []string{"retry@example.com"}, Subject: "Hello", HTML: "<p>Hi</p>"}
+			assert.NoError(t, outboxService.Enqueue(ctx, nil, msg))
+
+			_, err := outboxService.DispatchDue(ctx)
+			assert.NoError(t, err)
+
+			var message entity.EmailOutbox
+			assert.NoError(t, db.Where("recipient = ?", "retry@example.com").First(&message).Error)
+			assert.Equal(t, constants.ENUM_OUTBOX_PENDING, message.Status)
+			assert.Equal(t, 1, message.Attempts)
+			assert.Equal(t, "smtp down", message.LastError)
+			assert.True(t, message.NextAttemptAt.After(time.Now()))
+
+			attempted, err := outboxService.DispatchDue(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, attempted, "email should not be retried before its backoff elapses")
+		},
+	)
+
+	t.Run(
+		"reclaims email whose lease expired", func(t *testing.T) {
+			db.Exec("TRUNCATE TABLE email_outbox")
+			mockMailer.ExpectedCalls = nil
+			mockMailer.On("Send", mock.Anything, mock.Anything).Return(nil).Once()
+
+			for recipient, leaseUntil := range map[string]time.Time{
+				"expired@example.com": time.Now().Add(-time.Minute),
+				"leased@example.com":  time.Now().Add(time.Minute),
+			} {
+				_, err := outboxRepo.Create(
+					ctx, nil, entity.EmailOutbox{
+						Recipient:     recipient,
+						Subject:       "Hello",
+						Body:          "<p>Hi</p>",
+						Status:        constants.ENUM_OUTBOX_SENDING,
+						MaxAttempts:   service.OUTBOX_MAX_ATTEMPTS,
+						NextAttemptAt: leaseUntil,
+					},
+				)
+				assert.NoError(t, err)
+			}
+
+			attempted, err := outboxService.DispatchDue(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, attempted, "only the email whose lease expired should be claimed")
+
+			var expired, leased entity.EmailOutbox
+			assert.NoError(t, db.Where("recipient = ?", "expired@example.com").First(&expired).Error)
+			assert.Equal(t, constants.ENUM_OUTBOX_SENT, expired.Status)
+			assert.NoError(t, db.Where("recipient = ?", "leased@example.com").First(&leased).Error)
+			assert.Equal(t, constants.ENUM_OUTBOX_SENDING, leased.Status)
+			mockMailer.AssertExpectations(t)
+		},
+	)
+
+	t.Run(
+		"dead-letters and requeues exhausted email", func(t *testing.T) {
+			db.Exec("TRUNCATE TABLE email_outbox")
+			mockMailer.ExpectedCalls = nil
+			mockMailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("mailbox unavailable")).Once()
+
+			message, err := outboxRepo.Create(
+				ctx, nil, entity.EmailOutbox{
+					Recipient:     "dead@example.com",
+					Subject:       "Hello",
+					Body:          "<p>Hi</p>",
+					Status:        constants.ENUM_OUTBOX_PENDING,
+					Attempts:      service.OUTBOX_MAX_ATTEMPTS - 1,
+					MaxAttempts:   service.OUTBOX_MAX_ATTEMPTS,
+					NextAttemptAt: time.Now().Add(-time.Minute),
+				},
+			)
+			assert.NoError(t, err)
+
+			_, err = outboxService.DispatchDue(ctx)
+			assert.NoError(t, err)
+
+			dead, err := outboxRepo.GetById(ctx, nil, message.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, constants.ENUM_OUTBOX_DEAD, dead.Status)
+
+			listed, err := outboxService.GetAllWithPagination(
+				ctx, dto.EmailOutboxListRequest{Status: constants.ENUM_OUTBOX_DEAD},
+			)
+			assert.NoError(t, err)
+			assert.Len(t, listed.Data, 1)
+
+			requeued, err := outboxService.Requeue(ctx, message.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, constants.ENUM_OUTBOX_PENDING, requeued.Status)
+			assert.Zero(t, requeued.Attempts)
+
+			_, err = outboxService.Requeue(ctx, message.ID.String())
+			assert.ErrorIs(t, err, dto.ErrEmailOutboxNotDead)
+		},
+	)
+
+	t.Run(
+		"discards outcome after lease was taken over", func(t *testing.T) {
+			db.Exec("TRUNCATE TABLE email_outbox")
+
+			_, err := outboxRepo.Create(
+				ctx, nil, entity.EmailOutbox{
+					Recipient:     "slow@example.com",
+					Subject:       "Hello",
+					Body:          "<p>Hi</p>",
+					Status:        constants.ENUM_OUTBOX_PENDING,
+					MaxAttempts:   service.OUTBOX_MAX_ATTEMPTS,
+					NextAttemptAt: time.Now().Add(-time.Minute),
+				},
+			)
+			assert.NoError(t, err)
+
+			claimed, err := outboxRepo.ClaimDue(ctx, nil, time.Now(), time.Now().Add(time.Minute), 10)
+			assert.NoError(t, err)
+			assert.Len(t, claimed, 1)
+			message := claimed[0]
+			leaseUntil := message.NextAttemptAt
+
+			takenOver := leaseUntil.Add(time.Minute)
+			assert.NoError(
+				t, db.Model(&entity.EmailOutbox{}).
+					Where("id = ?", message.ID).
+					Update("next_attempt_at", takenOver).Error,
+			)
+
+			now := time.Now()
+			message.Status = constants.ENUM_OUTBOX_SENT
+			message.Attempts = 1
+			message.SentAt = &now
+			err = outboxRepo.SaveOutcome(ctx, nil, message, leaseUntil)
+			assert.ErrorIs(t, err, dto.ErrEmailOutboxLeaseLost)
+
+			stored, err := outboxRepo.GetById(ctx, nil, message.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, constants.ENUM_OUTBOX_SENDING, stored.Status)
+			assert.Zero(t, stored.Attempts)
+
+			assert.NoError(t, outboxRepo.SaveOutcome(ctx, nil, message, stored.NextAttemptAt))
+			stored, err = outboxRepo.GetById(ctx, nil, message.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, constants.ENUM_OUTBOX_SENT, stored.Status)
+		},
+	)
+}
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// sampleDSN is a delivery status notification reporting one permanent and one transient failure.
+const sampleDSN = "From: MAILER-DAEMON@example.com\r\n" +
+	"To: no-reply@example.com\r\n" +
+	"Subject: Delivery Status Notification\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Delivery failed.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.com\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; dsn-hard@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 user unknown\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; dsn-soft@example.com\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"--b1--\r\n"
+
+// TestEmailSuppressionService tests suppressing addresses from webhook events and DSNs, flagging their users, filtering
+// them out of outgoing email and clearing them again.
+func TestEmailSuppressionService(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.EmailSuppression{})
+	assert.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(db)
+	suppressionRepo := repository.NewEmailSuppressionRepository(db)
+	suppressionService := service.NewEmailSuppressionService(suppressionRepo, userRepo, repository.NewTxManager(db))
+	ctx := context.Background()
+
+	user, err := userRepo.Register(
+		ctx, nil, entity.User{Name: "Bounced User", Email: "bounced@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+
+	t.Run(
+		"suppresses hard bounces and complaints", func(t *testing.T) {
+			result, err := suppressionService.HandleEvents(
+				ctx, []dto.EmailEvent{
+					{Type: dto.EMAIL_EVENT_BOUNCE, Email: "Bounced@Example.com", BounceType: dto.EMAIL_BOUNCE_HARD},
+					{Type: dto.EMAIL_EVENT_BOUNCE, Email: "soft@example.com", BounceType: dto.EMAIL_BOUNCE_SOFT},
+					{Type: dto.EMAIL_EVENT_COMPLAINT, Email: "spam@example.com"},
+				},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, dto.EmailEventResult{Suppressed: 2, Ignored: 1}, result)
+
+			flagged, err := userRepo.GetUserById(ctx, nil, user.ID.String())
+			assert.NoError(t, err)
+			assert.True(t, flagged.EmailSuppressed)
+
+			listed, err := suppressionService.GetAllWithPagination(ctx, dto.PaginationRequest{Search: "spam"})
+			assert.NoError(t, err)
+			require.Len(t, listed.Data, 1)
+			assert.Equal(t, constants.ENUM_SUPPRESSION_COMPLAINT, listed.Data[0].Reason)
+		},
+	)
+
+	t.Run(
+		"suppresses permanent failures from a DSN", func(t *testing.T) {
+			result, err := suppressionService.HandleDSN(ctx, strings.NewReader(sampleDSN))
+			assert.NoError(t, err)
+			assert.Equal(t, dto.EmailEventResult{Suppressed: 1, Ignored: 1}, result)
+
+			_, err = suppressionService.HandleDSN(ctx, strings.NewReader("Subject: hello\r\n\r\nnot a report"))
+			assert.ErrorIs(t, err, mailer.ErrNotDSN)
+		},
+	)
+
+	t.Run(
+		"mailer skips suppressed recipients", func(t *testing.T) {
+			memory := mailer.NewMemoryMailer()
+			mail := mailer.NewSuppressingMailer(memory, suppressionService)
+
+			err := mail.Send(ctx, mailer.Message{To: []string{"dsn-hard@example.com"}, Subject: "Hello"})
+			assert.ErrorIs(t, err, mailer.ErrRecipientSuppressed)
+
+			err = mail.Send(ctx, mailer.Message{To: []string{"dsn-soft@example.com"}, Subject: "Hello"})
+			assert.NoError(t, err)
+			assert.Len(t, memory.Sent(), 1)
+		},
+	)
+
+	t.Run(
+		"clears a suppression and unflags the user", func(t *testing.T) {
+			assert.NoError(t, suppressionService.Clear(ctx, "bounced@example.com"))
+
+			unflagged, err := userRepo.GetUserById(ctx, nil, user.ID.String())
+			assert.NoError(t, err)
+			assert.False(t, unflagged.EmailSuppressed)
+
+			err = suppressionService.Clear(ctx, "bounced@example.com")
+			assert.ErrorIs(t, err, dto.ErrEmailSuppressionNotFound)
+		},
+	)
+}
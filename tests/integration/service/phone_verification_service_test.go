@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/sms"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// TestPhoneVerificationService tests sending phone verification codes by text message and checking them, including
+// throttled resends, wrong and exhausted codes, and users without a phone number.
+func TestPhoneVerificationService(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.PhoneVerification{}, &entity.AuditEvent{})
+	assert.NoError(t, err)
+
+	sender := sms.NewMemorySender()
+	userRepo := repository.NewUserRepository(db)
+	verificationService := service.NewPhoneVerificationService(
+		repository.NewPhoneVerificationRepository(db),
+		userRepo,
+		repository.NewAuditEventRepository(db),
+		sender,
+		repository.NewTxManager(db),
+		"Test App",
+	)
+	ctx := context.Background()
+
+	register := func(t *testing.T, email string, phoneNumber string) string {
+		user, err := userRepo.Register(
+			ctx, nil, entity.User{
+				Name:        "Phone Owner",
+				Email:       email,
+				Password:    "password123",
+				PhoneNumber: phoneNumber,
+			},
+		)
+		require.NoError(t, err)
+		return user.ID.String()
+	}
+
+	lastCode := func(t *testing.T) string {
+		msg, ok := sender.Last()
+		require.True(t, ok)
+		require.GreaterOrEqual(t, len(msg.Body), service.PHONE_CODE_LENGTH)
+		return msg.Body[:service.PHONE_CODE_LENGTH]
+	}
+
+	t.Run(
+		"verifies the phone number with the texted code", func(t *testing.T) {
+			sender.Reset()
+			userId := register(t, "verify@example.com", "+6281234567890")
+
+			sent, err := verificationService.SendCode(ctx, userId)
+			require.NoError(t, err)
+			assert.Equal(t, "+6281234567890", sent.PhoneNumber)
+			require.NotNil(t, sent.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(service.PHONE_CODE_TTL), *sent.ExpiresAt, time.Minute)
+
+			msg, ok := sender.Last()
+			require.True(t, ok)
+			assert.Equal(t, "+6281234567890", msg.To)
+
+			var stored entity.PhoneVerification
+			require.NoError(t, db.First(&stored, "user_id = ?", userId).Error)
+			assert.NotContains(t, stored.CodeHash, lastCode(t))
+
+			verified, err := verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: lastCode(t)})
+			require.NoError(t, err)
+			require.NotNil(t, verified.VerifiedAt)
+
+			var dbUser entity.User
+			require.NoError(t, db.First(&dbUser, "id = ?", userId).Error)
+			assert.NotNil(t, dbUser.PhoneVerifiedAt)
+			assert.Equal(t, int64(2), dbUser.Version)
+
+			var remaining int64
+			db.Model(&entity.PhoneVerification{}).Where("user_id = ?", userId).Count(&remaining)
+			assert.Zero(t, remaining)
+
+			_, err = verificationService.SendCode(ctx, userId)
+			assert.ErrorIs(t, err, dto.ErrPhoneAlreadyVerified)
+		},
+	)
+
+	t.Run(
+		"throttles resends", func(t *testing.T) {
+			userId := register(t, "resend@example.com", "+6281234567891")
+
+			_, err := verificationService.SendCode(ctx, userId)
+			require.NoError(t, err)
+
+			_, err = verificationService.SendCode(ctx, userId)
+			assert.ErrorIs(t, err, dto.ErrPhoneCodeResendTooSoon)
+
+			db.Model(&entity.PhoneVerification{}).
+				Where("user_id = ?", userId).
+				Update("created_at", time.Now().Add(-service.PHONE_CODE_RESEND_INTERVAL))
+			_, err = verificationService.SendCode(ctx, userId)
+			assert.NoError(t, err)
+		},
+	)
+
+	t.Run(
+		"counts wrong codes until a new one is needed", func(t *testing.T) {
+			userId := register(t, "attempts@example.com", "+6281234567892")
+
+			_, err := verificationService.SendCode(ctx, userId)
+			require.NoError(t, err)
+			code := lastCode(t)
+			wrong := "000000"
+			if code == wrong {
+				wrong = "111111"
+			}
+
+			for range service.PHONE_CODE_MAX_ATTEMPTS {
+				_, err = verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: wrong})
+				assert.ErrorIs(t, err, dto.ErrPhoneCodeInvalid)
+			}
+
+			var stored entity.PhoneVerification
+			require.NoError(t, db.First(&stored, "user_id = ?", userId).Error)
+			assert.Equal(t, service.PHONE_CODE_MAX_ATTEMPTS, stored.Attempts)
+
+			_, err = verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: code})
+			assert.ErrorIs(t, err, dto.ErrPhoneCodeAttemptsExceeded)
+		},
+	)
+
+	t.Run(
+		"refuses expired codes", func(t *testing.T) {
+			userId := register(t, "expired@example.com", "+6281234567893")
+
+			_, err := verificationService.SendCode(ctx, userId)
+			require.NoError(t, err)
+			db.Model(&entity.PhoneVerification{}).
+				Where("user_id = ?", userId).
+				Update("expires_at", time.Now().Add(-time.Minute))
+
+			_, err = verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: lastCode(t)})
+			assert.ErrorIs(t, err, dto.ErrPhoneCodeExpired)
+		},
+	)
+
+	t.Run(
+		"refuses codes sent to a previous phone number", func(t *testing.T) {
+			userId := register(t, "changed@example.com", "+6281234567894")
+
+			_, err := verificationService.SendCode(ctx, userId)
+			require.NoError(t, err)
+			db.Model(&entity.User{}).Where("id = ?", userId).Update("phone_number", "+6281234567895")
+
+			_, err = verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: lastCode(t)})
+			assert.ErrorIs(t, err, dto.ErrPhoneCodeInvalid)
+		},
+	)
+
+	t.Run(
+		"requires a phone number", func(t *testing.T) {
+			userId := register(t, "nophone@example.com", "")
+
+			_, err := verificationService.SendCode(ctx, userId)
+			assert.ErrorIs(t, err, dto.ErrPhoneNumberRequired)
+
+			_, err = verificationService.VerifyCode(ctx, userId, dto.VerifyPhoneRequest{Code: "123456"})
+			assert.ErrorIs(t, err, dto.ErrPhoneCodeInvalid)
+		},
+	)
+}
@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// uploadChecksum returns the Upload-Checksum header value of a chunk.
+func uploadChecksum(data []byte) string {
+	digest := sha256.Sum256(data)
+	return "sha256 " + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// uploadImage returns a PNG image of the given size.
+func uploadImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 120, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+// TestUploadService tests sending an upload in chunks, resuming it, completing it against its checksum, attaching it
+// as an avatar and purging abandoned uploads.
+func TestUploadService(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.Upload{})
+	assert.NoError(t, err)
+
+	files := storage.NewMemoryDriver()
+	userRepo := repository.NewUserRepository(db)
+	userService := newUserService(db, &MockJWTService{}, files)
+	uploadRepo := repository.NewUploadRepository(db)
+	uploadService := service.NewUploadService(uploadRepo, userService, files, repository.NewTxManager(db))
+	ctx := context.Background()
+
+	owner, err := userRepo.Register(
+		ctx, nil, entity.User{Name: "Upload Owner", Email: "uploader@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+	other, err := userRepo.Register(
+		ctx, nil, entity.User{Name: "Other User", Email: "other-uploader@example.com", Password: "password123"},
+	)
+	require.NoError(t, err)
+	ownerID := owner.ID.String()
+
+	t.Run(
+		"resumes, completes and attaches an upload", func(t *testing.T) {
+			data := uploadImage(t, 64, 64)
+			digest := sha256.Sum256(data)
+			half := int64(len(data) / 2)
+
+			upload, err := uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{
+					Filename: "avatar.png",
+					Size:     int64(len(data)),
+					Checksum: hex.EncodeToString(digest[:]),
+				},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, "image/png", upload.ContentType)
+			assert.Equal(t, constants.ENUM_UPLOAD_PENDING, upload.Status)
+			assert.Zero(t, upload.Offset)
+
+			first := data[:half]
+			upload, err = uploadService.WriteChunk(
+				ctx, ownerID, upload.ID, 0, uploadChecksum(first), bytes.NewReader(first),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, half, upload.Offset)
+
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 0, "", bytes.NewReader(first))
+			assert.ErrorIs(t, err, dto.ErrUploadOffsetMismatch, "a chunk must be sent at the current offset")
+
+			_, err = uploadService.WriteChunk(
+				ctx, ownerID, upload.ID, half, uploadChecksum([]byte("other")), bytes.NewReader(data[half:]),
+			)
+			assert.ErrorIs(t, err, dto.ErrUploadChecksumMismatch, "a corrupted chunk must be rejected")
+
+			_, err = uploadService.Complete(ctx, ownerID, upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadIncomplete)
+
+			_, err = uploadService.Get(ctx, other.ID.String(), upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadNotFound, "uploads of other users must stay hidden")
+
+			progress, err := uploadService.Get(ctx, ownerID, upload.ID)
+			require.NoError(t, err)
+			assert.Equal(t, half, progress.Offset, "the client resumes from the reported offset")
+
+			upload, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, half, "", bytes.NewReader(data[half:]))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(data)), upload.Offset)
+
+			upload, err = uploadService.Complete(ctx, ownerID, upload.ID)
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_UPLOAD_COMPLETED, upload.Status)
+			assert.NotEmpty(t, upload.URL)
+			assert.Equal(t, []string{"uploads/" + upload.ID + "/file"}, files.Keys(), "chunks are removed")
+
+			user, err := uploadService.AttachAvatar(ctx, ownerID, upload.ID)
+			require.NoError(t, err)
+			assert.NotEmpty(t, user.ImageUrl)
+
+			_, err = uploadService.Get(ctx, ownerID, upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadNotFound, "an attached upload is removed")
+			for _, key := range files.Keys() {
+				assert.True(t, strings.HasPrefix(key, "profile/"), "only the avatar files remain, got %s", key)
+			}
+		},
+	)
+
+	t.Run(
+		"restarts an upload whose file does not match its checksum", func(t *testing.T) {
+			data := []byte("hello, resumable world")
+			digest := sha256.Sum256([]byte("something else"))
+
+			upload, err := uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{
+					Filename: "hello.txt",
+					Size:     int64(len(data)),
+					Checksum: hex.EncodeToString(digest[:]),
+				},
+			)
+			require.NoError(t, err)
+
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 0, "", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			_, err = uploadService.Complete(ctx, ownerID, upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadChecksumMismatch)
+
+			progress, err := uploadService.Get(ctx, ownerID, upload.ID)
+			require.NoError(t, err)
+			assert.Zero(t, progress.Offset)
+			assert.Equal(t, constants.ENUM_UPLOAD_PENDING, progress.Status)
+
+			_, err = uploadService.AttachAvatar(ctx, ownerID, upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadNotCompleted)
+
+			require.NoError(t, uploadService.Cancel(ctx, ownerID, upload.ID))
+		},
+	)
+
+	t.Run(
+		"rejects chunks beyond the declared size", func(t *testing.T) {
+			upload, err := uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{Filename: "small.bin", Size: 4},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, "application/octet-stream", upload.ContentType)
+
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 0, "", strings.NewReader("too long"))
+			assert.ErrorIs(t, err, dto.ErrUploadChunkTooLarge)
+
+			_, err = uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{Filename: "huge.bin", Size: service.UPLOAD_MAX_SIZE + 1},
+			)
+			assert.ErrorIs(t, err, dto.ErrUploadTooLarge)
+
+			require.NoError(t, uploadService.Cancel(ctx, ownerID, upload.ID))
+		},
+	)
+
+	t.Run(
+		"refuses saves based on an outdated version", func(t *testing.T) {
+			upload, err := uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{Filename: "race.bin", Size: 4},
+			)
+			require.NoError(t, err)
+
+			stale, err := uploadRepo.GetById(ctx, nil, upload.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), stale.Version)
+
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 0, "", strings.NewReader("ab"))
+			require.NoError(t, err)
+
+			stale.Received = 4
+			_, err = uploadRepo.Save(ctx, nil, stale)
+			assert.ErrorIs(t, err, dto.ErrUploadVersionConflict)
+
+			current, err := uploadRepo.GetById(ctx, nil, upload.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), current.Received, "the outdated save must not overwrite the progress")
+			assert.Equal(t, int64(2), current.Version)
+
+			require.NoError(t, uploadService.Cancel(ctx, ownerID, upload.ID))
+		},
+	)
+
+	t.Run(
+		"expires and purges abandoned uploads", func(t *testing.T) {
+			upload, err := uploadService.Create(
+				ctx, ownerID, dto.UploadCreateRequest{Filename: "abandoned.txt", Size: 10},
+			)
+			require.NoError(t, err)
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 0, "", strings.NewReader("hello"))
+			require.NoError(t, err)
+
+			err = db.Model(&entity.Upload{}).
+				Where("id = ?", upload.ID).
+				Update("expires_at", time.Now().Add(-time.Minute)).Error
+			require.NoError(t, err)
+
+			_, err = uploadService.WriteChunk(ctx, ownerID, upload.ID, 5, "", strings.NewReader("world"))
+			assert.ErrorIs(t, err, dto.ErrUploadExpired)
+
+			purged, err := uploadService.PurgeExpired(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, purged)
+
+			_, err = uploadService.Get(ctx, ownerID, upload.ID)
+			assert.ErrorIs(t, err, dto.ErrUploadNotFound)
+			for _, key := range files.Keys() {
+				assert.False(t, strings.HasPrefix(key, "uploads/"+upload.ID), "purged chunks remain: %s", key)
+			}
+		},
+	)
+}
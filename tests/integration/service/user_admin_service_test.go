@@ -0,0 +1,146 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// TestUserService_AdminOperations tests the administrative user operations used by the CLI.
+func TestUserService_AdminOperations(t *testing.T) {
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
+	assert.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	userService := newUserService(db, service.NewJWTService(), storage.NewMemoryDriver())
+
+	ctx := context.Background()
+
+	admin, err := userService.CreateAdmin(
+		ctx, dto.AdminCreateRequest{
+			Name:     "Admin User",
+			Email:    "admin@example.com",
+			Password: "password123",
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.ENUM_ROLE_ADMIN, admin.Role)
+	assert.True(t, admin.IsVerified)
+
+	t.Run(
+		"CreateAdmin rejects duplicate email", func(t *testing.T) {
+			_, err := userService.CreateAdmin(
+				ctx, dto.AdminCreateRequest{Name: "Other", Email: "admin@example.com", Password: "password123"},
+			)
+			assert.ErrorIs(t, err, dto.ErrEmailAlreadyExists)
+		},
+	)
+
+	t.Run(
+		"CreateAdmin rejects short password", func(t *testing.T) {
+			_, err := userService.CreateAdmin(
+				ctx, dto.AdminCreateRequest{Name: "Other", Email: "other@example.com", Password: "short"},
+			)
+			assert.ErrorIs(t, err, dto.ErrPasswordTooShort)
+		},
+	)
+
+	t.Run(
+		"ResetPassword changes password and revokes sessions", func(t *testing.T) {
+			_, err := refreshTokenRepo.Create(
+				ctx, nil, entity.RefreshToken{
+					UserID:    uuidOf(t, admin.ID),
+					Token:     "admin-token",
+					ExpiresAt: time.Now().Add(time.Hour),
+				},
+			)
+			assert.NoError(t, err)
+
+			assert.NoError(t, userService.ResetPassword(ctx, admin.ID, "newpassword456"))
+
+			user, err := userRepo.GetUserById(ctx, nil, admin.ID)
+			assert.NoError(t, err)
+			ok, err := helpers.CheckPassword(user.Password, []byte("newpassword456"))
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			_, err = refreshTokenRepo.FindByToken(ctx, nil, "admin-token")
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		},
+	)
+
+	t.Run(
+		"MarkEmailVerified verifies once", func(t *testing.T) {
+			user, err := userRepo.Register(
+				ctx, nil, entity.User{Name: "Plain User", Email: "plain@example.com", Password: "password123"},
+			)
+			assert.NoError(t, err)
+
+			verified, err := userService.MarkEmailVerified(ctx, user.ID.String())
+			assert.NoError(t, err)
+			assert.True(t, verified.IsVerified)
+
+			_, err = userService.MarkEmailVerified(ctx, user.ID.String())
+			assert.ErrorIs(t, err, dto.ErrAccountAlreadyVerified)
+		},
+	)
+
+	t.Run(
+		"ChangeRole validates role", func(t *testing.T) {
+			_, err := userService.ChangeRole(ctx, admin.ID, "root")
+			assert.ErrorIs(t, err, dto.ErrInvalidRole)
+
+			updated, err := userService.ChangeRole(ctx, admin.ID, constants.ENUM_ROLE_USER)
+			assert.NoError(t, err)
+			assert.Equal(t, constants.ENUM_ROLE_USER, updated.Role)
+		},
+	)
+}
+
+// uuidOf parses a user id returned by the service.
+func uuidOf(t *testing.T, id string) uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	return parsed
+}
@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
+)
+
+// TestUserService_Lifecycle tests deactivating, deleting, restoring and purging users.
+func TestUserService_Lifecycle(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		assert.NoError(t, err)
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.Upload{}, &entity.AuditEvent{})
+	assert.NoError(t, err)
+
+	files := storage.NewMemoryDriver()
+	userRepo := repository.NewUserRepository(db)
+	userService := newUserService(db, service.NewJWTService(), files)
+	uploadService := service.NewUploadService(repository.NewUploadRepository(db), userService, files, repository.NewTxManager(db))
+	ctx := context.Background()
+
+	register := func(t *testing.T, email string) entity.User {
+		user, err := userRepo.Register(
+			ctx, nil, entity.User{Name: "Lifecycle User", Email: email, Password: "password123", IsVerified: true},
+		)
+		require.NoError(t, err)
+		return user
+	}
+	expire := func(t *testing.T, userId string) {
+		require.NoError(
+			t,
+			db.Unscoped().
+				Model(&entity.User{}).
+				Where("id = ?", userId).
+				Update("purge_at", time.Now().Add(-time.Minute)).Error,
+		)
+	}
+
+	t.Run(
+		"deactivated users cannot log in until restored", func(t *testing.T) {
+			user := register(t, "deactivate@example.com")
+			login := dto.UserLoginRequest{Email: user.Email, Password: "password123"}
+
+			_, err := userService.Verify(ctx, login)
+			require.NoError(t, err)
+
+			require.NoError(t, userService.Deactivate(ctx, user.ID.String()))
+			require.NoError(t, userService.Deactivate(ctx, user.ID.String()), "deactivating twice is a no-op")
+
+			_, err = userService.Verify(ctx, login)
+			assert.ErrorIs(t, err, dto.ErrAccountDeactivated)
+
+			_, err = userService.Restore(ctx, dto.UserLoginRequest{Email: user.Email, Password: "wrong-password"})
+			assert.ErrorIs(t, err, dto.ErrInvalidCredentials)
+
+			tokens, err := userService.Restore(ctx, login)
+			require.NoError(t, err)
+			assert.NotEmpty(t, tokens.AccessToken)
+
+			restored, err := userService.GetUserById(ctx, user.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_USER_ACTIVE, restored.Status)
+
+			_, err = userService.Restore(ctx, login)
+			assert.ErrorIs(t, err, dto.ErrInvalidCredentials, "an active user has nothing to restore")
+		},
+	)
+
+	t.Run(
+		"deleted users free their email and are restored only while it is unused", func(t *testing.T) {
+			user := register(t, "reuse@example.com")
+			require.NoError(t, userService.Delete(ctx, user.ID.String()))
+
+			_, err := userService.Verify(ctx, dto.UserLoginRequest{Email: user.Email, Password: "password123"})
+			assert.ErrorIs(t, err, dto.ErrInvalidCredentials)
+
+			register(t, user.Email)
+
+			_, err = userService.RestoreById(ctx, user.ID.String())
+			assert.ErrorIs(t, err, dto.ErrEmailAlreadyExists)
+
+			deleted, err := userRepo.GetUserByIdWithDeleted(ctx, nil, user.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_USER_PENDING_DELETION, deleted.Status)
+		},
+	)
+
+	t.Run(
+		"admins restore users pending deletion", func(t *testing.T) {
+			user := register(t, "admin-restore@example.com")
+			require.NoError(t, userService.Delete(ctx, user.ID.String()))
+
+			restored, err := userService.RestoreById(ctx, user.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, constants.ENUM_USER_ACTIVE, restored.Status)
+			assert.Nil(t, restored.PurgeAt)
+
+			_, err = userService.RestoreById(ctx, user.ID.String())
+			assert.ErrorIs(t, err, dto.ErrAccountNotRestorable)
+		},
+	)
+
+	t.Run(
+		"purges users whose grace period ended", func(t *testing.T) {
+			expired := register(t, "purge@example.com")
+			kept := register(t, "keep@example.com")
+
+			_, err := userService.UpdateAvatar(ctx, expired.ID.String(), bytes.NewReader(uploadImage(t, 64, 64)))
+			require.NoError(t, err)
+			_, err = uploadService.Create(
+				ctx, expired.ID.String(), dto.UploadCreateRequest{Filename: "pending.txt", Size: 10},
+			)
+			require.NoError(t, err)
+
+			require.NoError(t, userService.Delete(ctx, expired.ID.String()))
+			require.NoError(t, userService.Delete(ctx, kept.ID.String()))
+			assert.NotEmpty(t, files.Keys(), "files are kept during the grace period")
+
+			purged, err := userService.PurgeDeleted(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, purged, "users within the grace period are kept")
+
+			expire(t, expired.ID.String())
+			purged, err = userService.PurgeDeleted(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, purged)
+
+			_, err = userRepo.GetUserByIdWithDeleted(ctx, nil, expired.ID.String())
+			assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			_, err = userService.RestoreById(ctx, expired.ID.String())
+			assert.ErrorIs(t, err, dto.ErrUserNotFound)
+
+			var uploads int64
+			require.NoError(t, db.Model(&entity.Upload{}).Where("user_id = ?", expired.ID).Count(&uploads).Error)
+			assert.Zero(t, uploads)
+			assert.Empty(t, files.Keys())
+
+			_, err = userRepo.GetUserByIdWithDeleted(ctx, nil, kept.ID.String())
+			assert.NoError(t, err)
+		},
+	)
+}
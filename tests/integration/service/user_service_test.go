@@ -4,25 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/helpers"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v4"
 	"gorm.io/gorm"
-	"os"
-	"path/filepath"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/mailer"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/tests/integration/container"
 	"github.com/Caknoooo/go-gin-clean-starter/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"gopkg.in/gomail.v2"
 )
 
 // MockJWTService is a mock implementation of a JWT service for simulating token-related operations in tests.
@@ -54,15 +56,15 @@ func (m *MockJWTService) GetUserIDByToken(token string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-// MockDialer is a mock implementation of a mail dialer used for testing purposes.
+// MockMailer is a mock implementation of mailer.Mailer used for testing purposes.
 // It embeds mock.Mock to provide functionality for method call assertions and stubbing.
-type MockDialer struct {
+type MockMailer struct {
 	mock.Mock
 }
 
-// DialAndSend simulates sending email messages, allowing assertions and stubbing in testing scenarios.
-func (m *MockDialer) DialAndSend(messages ...*gomail.Message) error {
-	args := m.Called(messages)
+// Send simulates sending an email message, allowing assertions and stubbing in testing scenarios.
+func (m *MockMailer) Send(ctx context.Context, msg mailer.Message) error {
+	args := m.Called(ctx, msg)
 	return args.Error(0)
 }
 
@@ -104,41 +106,12 @@ func TestUserService_Register(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
-	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
-
 	jwtService := &MockJWTService{}
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
-
-	tempDir := t.TempDir()
-	emailTemplatePath := filepath.Join(tempDir, "base_mail.html")
-	err = os.WriteFile(
-		emailTemplatePath, []byte(`
-		<html>
-			<body>
-				<p>Hello {{.Email}}</p>
-				<a href="{{.Verify}}">Verify Email</a>
-			</body>
-		</html>
-	`), 0644,
-	)
-	assert.NoError(t, err)
-
-	originalNewDialer := utils.NewDialer
-	utils.NewDialer = func(host string, port int, username, password string) utils.Dialer {
-		dialer := &MockDialer{}
-		dialer.On("DialAndSend", mock.Anything).Return(nil)
-		return dialer
-	}
-	defer func() { utils.NewDialer = originalNewDialer }()
-
-	originalPath := utils.PATH
-	utils.PATH = tempDir
-	defer func() { utils.PATH = originalPath }()
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	tests := []struct {
 		name          string
@@ -153,7 +126,7 @@ func TestUserService_Register(t *testing.T) {
 				Name:        "John Doe",
 				Email:       "john.doe@example.com",
 				Password:    "password123",
-				PhoneNumber: "1234567890",
+				PhoneNumber: "0812 3456 7890",
 			},
 			setup:         func() {},
 			expectedError: nil,
@@ -161,7 +134,7 @@ func TestUserService_Register(t *testing.T) {
 				assert.NotEmpty(t, user.ID)
 				assert.Equal(t, "John Doe", user.Name)
 				assert.Equal(t, "john.doe@example.com", user.Email)
-				assert.Equal(t, "1234567890", user.PhoneNumber)
+				assert.Equal(t, "+6281234567890", user.PhoneNumber)
 				assert.Equal(t, constants.ENUM_ROLE_USER, user.Role)
 				assert.False(t, user.IsVerified)
 
@@ -170,6 +143,13 @@ func TestUserService_Register(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, user.Name, dbUser.Name)
 				assert.Equal(t, user.Email, dbUser.Email)
+
+				var queued entity.EmailOutbox
+				err = db.Where("recipient = ?", user.Email).First(&queued).Error
+				assert.NoError(t, err)
+				assert.Equal(t, constants.ENUM_OUTBOX_PENDING, queued.Status)
+				assert.Contains(t, queued.Subject, "Verify your")
+				assert.Contains(t, queued.TextBody, "token=")
 			},
 		},
 		{
@@ -258,13 +238,12 @@ func TestUserService_GetAllUserWithPagination(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM users WHERE TRUE")
@@ -438,14 +417,13 @@ func TestUserService_GetUserById(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM users WHERE TRUE")
@@ -489,7 +467,7 @@ func TestUserService_GetUserById(t *testing.T) {
 			setup: func() string {
 				return uuid.New().String()
 			},
-			expectedError: dto.ErrGetUserById,
+			expectedError: dto.ErrUserNotFound,
 			validate: func(t *testing.T, user dto.UserResponse) {
 				assert.Empty(t, user.ID)
 			},
@@ -499,7 +477,7 @@ func TestUserService_GetUserById(t *testing.T) {
 			setup: func() string {
 				return "invalid-uuid-format"
 			},
-			expectedError: dto.ErrGetUserById,
+			expectedError: dto.ErrUserNotFound,
 			validate: func(t *testing.T, user dto.UserResponse) {
 				assert.Empty(t, user.ID)
 			},
@@ -560,14 +538,13 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM users WHERE TRUE")
@@ -611,7 +588,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 			setup: func() string {
 				return "nonexistent@example.com"
 			},
-			expectedError: dto.ErrGetUserByEmail,
+			expectedError: dto.ErrUserNotFound,
 			validate: func(t *testing.T, user dto.UserResponse) {
 				assert.Empty(t, user.ID)
 			},
@@ -621,7 +598,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 			setup: func() string {
 				return ""
 			},
-			expectedError: dto.ErrGetUserByEmail,
+			expectedError: dto.ErrUserNotFound,
 			validate: func(t *testing.T, user dto.UserResponse) {
 				assert.Empty(t, user.ID)
 			},
@@ -682,48 +659,22 @@ func TestUserService_SendVerificationEmail(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
-
-	tempDir := t.TempDir()
-	emailTemplatePath := filepath.Join(tempDir, "base_mail.html")
-	err = os.WriteFile(
-		emailTemplatePath, []byte(`
-		<html>
-			<body>
-				<p>Hello {{.Email}}</p>
-				<a href="{{.Verify}}">Verify Email</a>
-			</body>
-		</html>
-	`), 0644,
-	)
-	assert.NoError(t, err)
-
-	originalNewDialer := utils.NewDialer
-	mockDialer := &MockDialer{}
-	utils.NewDialer = func(host string, port int, username, password string) utils.Dialer {
-		return mockDialer
-	}
-	defer func() { utils.NewDialer = originalNewDialer }()
-
-	originalPath := utils.PATH
-	utils.PATH = tempDir
-	defer func() { utils.PATH = originalPath }()
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	tests := []struct {
 		name          string
 		setup         func() dto.SendVerificationEmailRequest
-		mockEmail     func(*MockDialer)
 		expectedError error
+		queued        int64
 	}{
 		{
-			name: "Successfully send verification email",
+			name: "Successfully queue verification email",
 			setup: func() dto.SendVerificationEmailRequest {
 				user := entity.User{
 					Name:        "Test User",
@@ -737,81 +688,40 @@ func TestUserService_SendVerificationEmail(t *testing.T) {
 				assert.NoError(t, err)
 				return dto.SendVerificationEmailRequest{Email: "test@example.com"}
 			},
-			mockEmail: func(m *MockDialer) {
-				m.On("DialAndSend", mock.Anything).Return(nil).Once()
-			},
 			expectedError: nil,
+			queued:        1,
 		},
 		{
 			name: "Email not found",
 			setup: func() dto.SendVerificationEmailRequest {
 				return dto.SendVerificationEmailRequest{Email: "nonexistent@example.com"}
 			},
-			mockEmail:     func(m *MockDialer) {},
 			expectedError: dto.ErrEmailNotFound,
-		},
-		{
-			name: "Email sending fails",
-			setup: func() dto.SendVerificationEmailRequest {
-				user := entity.User{
-					Name:        "Test User",
-					Email:       "test2@example.com",
-					Password:    "password123",
-					PhoneNumber: "1234567890",
-					Role:        "user",
-					IsVerified:  false,
-				}
-				_, err := userRepo.Register(context.Background(), nil, user)
-				assert.NoError(t, err)
-				return dto.SendVerificationEmailRequest{Email: "test2@example.com"}
-			},
-			mockEmail: func(m *MockDialer) {
-				m.On("DialAndSend", mock.Anything).Return(errors.New("smtp error")).Once()
-			},
-			expectedError: errors.New("smtp error"),
+			queued:        0,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
-
-				err := os.WriteFile(
-					emailTemplatePath, []byte(`
-                <html>
-                    <body>
-                        <p>Hello {{.Email}}</p>
-                        <a href="{{.Verify}}">Verify Email</a>
-                    </body>
-                </html>
-            `), 0644,
-				)
-				assert.NoError(t, err)
-
-				mockDialer := &MockDialer{}
-				utils.NewDialer = func(host string, port int, username, password string) utils.Dialer {
-					return mockDialer
-				}
+				db.Exec("TRUNCATE TABLE users, email_outbox RESTART IDENTITY CASCADE")
 
 				req := tt.setup()
 
-				tt.mockEmail(mockDialer)
-
-				err = userService.SendVerificationEmail(context.Background(), req)
+				err := userService.SendVerificationEmail(context.Background(), req)
 
 				if tt.expectedError != nil {
 					assert.Error(t, err)
-					if tt.name == "Template parsing fails" {
-						assert.ErrorContains(t, err, "template")
-					} else {
-						assert.Equal(t, tt.expectedError, err)
-					}
+					assert.Equal(t, tt.expectedError, err)
 				} else {
 					assert.NoError(t, err)
 				}
 
-				mockDialer.AssertExpectations(t)
+				var queued int64
+				db.Model(&entity.EmailOutbox{}).
+					Where("recipient = ? AND status = ?", req.Email, constants.ENUM_OUTBOX_PENDING).
+					Count(&queued)
+				assert.Equal(t, tt.queued, queued)
 			},
 		)
 	}
@@ -853,14 +763,13 @@ func TestUserService_VerifyEmail(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM users WHERE TRUE")
@@ -1057,14 +966,13 @@ func TestUserService_Update(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.EmailChange{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM users WHERE TRUE")
@@ -1081,13 +989,15 @@ func TestUserService_Update(t *testing.T) {
 		{
 			name: "Successfully update user",
 			setup: func() (string, dto.UserUpdateRequest) {
+				verifiedAt := time.Now()
 				user := entity.User{
-					Name:        "Original Name",
-					Email:       "original@example.com",
-					Password:    "password123",
-					PhoneNumber: "1234567890",
-					Role:        "user",
-					IsVerified:  true,
+					Name:            "Original Name",
+					Email:           "original@example.com",
+					Password:        "password123",
+					PhoneNumber:     "1234567890",
+					PhoneVerifiedAt: &verifiedAt,
+					Role:            "user",
+					IsVerified:      true,
 				}
 				createdUser, err := userRepo.Register(ctx, nil, user)
 				assert.NoError(t, err)
@@ -1095,7 +1005,7 @@ func TestUserService_Update(t *testing.T) {
 				updateReq := dto.UserUpdateRequest{
 					Name:        "Updated Name",
 					Email:       "updated@example.com",
-					PhoneNumber: "0987654321",
+					PhoneNumber: "0812 3456 7890",
 				}
 
 				return createdUser.ID.String(), updateReq
@@ -1103,17 +1013,91 @@ func TestUserService_Update(t *testing.T) {
 			expectedError: nil,
 			validate: func(t *testing.T, response dto.UserUpdateResponse, db *gorm.DB) {
 				assert.Equal(t, "Updated Name", response.Name)
-				assert.Equal(t, "updated@example.com", response.Email)
-				assert.Equal(t, "0987654321", response.PhoneNumber)
+				assert.Equal(t, "original@example.com", response.Email)
+				assert.Equal(t, "updated@example.com", response.PendingEmail)
+				assert.Equal(t, "+6281234567890", response.PhoneNumber)
 				assert.Equal(t, "user", response.Role)
 				assert.True(t, response.IsVerified)
+				assert.Equal(t, []string{"name", "phone_number"}, response.ChangedFields)
 
 				var dbUser entity.User
 				err := db.First(&dbUser, "id = ?", response.ID).Error
 				assert.NoError(t, err)
 				assert.Equal(t, "Updated Name", dbUser.Name)
-				assert.Equal(t, "updated@example.com", dbUser.Email)
-				assert.Equal(t, "0987654321", dbUser.PhoneNumber)
+				assert.Equal(t, "original@example.com", dbUser.Email)
+				assert.Equal(t, "+6281234567890", dbUser.PhoneNumber)
+				assert.Nil(t, dbUser.PhoneVerifiedAt)
+
+				var change entity.EmailChange
+				err = db.First(&change, "user_id = ?", response.ID).Error
+				assert.NoError(t, err)
+				assert.Equal(t, "updated@example.com", change.NewEmail)
+
+				var recipients []string
+				err = db.Model(&entity.EmailOutbox{}).Order("recipient").Pluck("recipient", &recipients).Error
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"original@example.com", "updated@example.com"}, recipients)
+			},
+		},
+		{
+			name: "Update to email of another user",
+			setup: func() (string, dto.UserUpdateRequest) {
+				for _, email := range []string{"owner@example.com", "taken@example.com"} {
+					_, err := userRepo.Register(
+						ctx, nil, entity.User{
+							Name:     "Some User",
+							Email:    email,
+							Password: "password123",
+							Role:     "user",
+						},
+					)
+					assert.NoError(t, err)
+				}
+
+				owner, err := userRepo.GetUserByEmail(ctx, nil, "owner@example.com")
+				assert.NoError(t, err)
+
+				return owner.ID.String(), dto.UserUpdateRequest{Email: "taken@example.com"}
+			},
+			expectedError: dto.ErrEmailAlreadyExists,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, db *gorm.DB) {
+				assert.Empty(t, response.ID)
+
+				var count int64
+				db.Model(&entity.EmailChange{}).Count(&count)
+				assert.Zero(t, count)
+			},
+		},
+		{
+			name: "Update with stale If-Match",
+			setup: func() (string, dto.UserUpdateRequest) {
+				user := entity.User{
+					Name:        "Original Name",
+					Email:       "stale@example.com",
+					Password:    "password123",
+					PhoneNumber: "1234567890",
+					Role:        "user",
+					IsVerified:  true,
+				}
+				createdUser, err := userRepo.Register(ctx, nil, user)
+				assert.NoError(t, err)
+
+				updateReq := dto.UserUpdateRequest{
+					Name:    "Stale Name",
+					IfMatch: utils.ETag(createdUser.Version + 1),
+				}
+
+				return createdUser.ID.String(), updateReq
+			},
+			expectedError: dto.ErrVersionConflict,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, db *gorm.DB) {
+				assert.Empty(t, response.ID)
+
+				var dbUser entity.User
+				err := db.First(&dbUser, "email = ?", "stale@example.com").Error
+				assert.NoError(t, err)
+				assert.Equal(t, "Original Name", dbUser.Name)
+				assert.Equal(t, int64(1), dbUser.Version)
 			},
 		},
 		{
@@ -1135,7 +1119,7 @@ func TestUserService_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(
 			tt.name, func(t *testing.T) {
-				db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
+				db.Exec("TRUNCATE TABLE users, email_outbox RESTART IDENTITY CASCADE")
 
 				userId, updateReq := tt.setup()
 
@@ -1154,7 +1138,312 @@ func TestUserService_Update(t *testing.T) {
 	}
 }
 
-// TestUserService_Delete tests the Delete method of UserService for various scenarios, ensuring users and related data are removed.
+// TestUserService_Patch tests applying JSON merge patches to the profile of a user, including clearing fields with
+// null, validating the patched profile and writing only the fields that changed.
+func TestUserService_Patch(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		if err != nil {
+			panic(err)
+		}
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.EmailChange{}, &entity.AuditEvent{})
+	assert.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(db)
+	jwtService := service.NewJWTService()
+
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
+
+	defer func() {
+		db.Exec("DELETE FROM users WHERE TRUE")
+	}()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		patch         string
+		expectedError error
+		validate      func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User)
+	}{
+		{
+			name:  "Null clears phone number",
+			patch: `{"phone_number":null}`,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User) {
+				assert.Equal(t, []string{"phone_number"}, response.ChangedFields)
+				assert.Empty(t, response.PhoneNumber)
+				assert.Empty(t, dbUser.PhoneNumber)
+				assert.Equal(t, "Original Name", dbUser.Name)
+				assert.Equal(t, int64(2), dbUser.Version)
+			},
+		},
+		{
+			name:  "Unchanged values are not written",
+			patch: `{"name":"Original Name","phone_number":"1234567890"}`,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User) {
+				assert.Empty(t, response.ChangedFields)
+				assert.Equal(t, int64(1), response.Version)
+				assert.Equal(t, int64(1), dbUser.Version)
+			},
+		},
+		{
+			name:          "Null cannot clear email",
+			patch:         `{"email":null}`,
+			expectedError: validator.ValidationErrors{},
+			validate: func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User) {
+				assert.Equal(t, "patch@example.com", dbUser.Email)
+			},
+		},
+		{
+			name:          "Invalid phone number is refused",
+			patch:         `{"phone_number":"+0812345678"}`,
+			expectedError: helpers.ErrInvalidPhoneNumber,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User) {
+				assert.Equal(t, "1234567890", dbUser.PhoneNumber)
+				assert.Equal(t, int64(1), dbUser.Version)
+			},
+		},
+		{
+			name:          "Unknown fields are refused",
+			patch:         `{"role":"admin"}`,
+			expectedError: utils.ErrInvalidMergePatch,
+			validate: func(t *testing.T, response dto.UserUpdateResponse, dbUser entity.User) {
+				assert.Equal(t, "user", dbUser.Role)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE")
+
+				user, err := userRepo.Register(
+					ctx, nil, entity.User{
+						Name:        "Original Name",
+						Email:       "patch@example.com",
+						Password:    "password123",
+						PhoneNumber: "1234567890",
+						Role:        "user",
+						IsVerified:  true,
+					},
+				)
+				assert.NoError(t, err)
+
+				req := dto.UserPatchRequest{Patch: []byte(tt.patch), IfMatch: utils.ETag(user.Version)}
+				response, err := userService.Patch(ctx, req, user.ID.String())
+
+				var validationErrors validator.ValidationErrors
+				switch {
+				case errors.As(tt.expectedError, &validationErrors):
+					assert.ErrorAs(t, err, &validationErrors)
+				case tt.expectedError != nil:
+					assert.ErrorIs(t, err, tt.expectedError)
+				default:
+					assert.NoError(t, err)
+				}
+
+				var dbUser entity.User
+				assert.NoError(t, db.First(&dbUser, "id = ?", user.ID).Error)
+				tt.validate(t, response, dbUser)
+			},
+		)
+	}
+}
+
+// TestUserService_ConfirmEmailChange tests confirming a change of email address with the token emailed to the new
+// address, including expired tokens and addresses taken by another account in the meantime.
+func TestUserService_ConfirmEmailChange(t *testing.T) {
+	container.LoadTestEnv()
+
+	dbContainer, err := container.StartTestContainer()
+	assert.NoError(t, err)
+	defer func(dbContainer *container.TestDatabaseContainer) {
+		err := dbContainer.Stop()
+		if err != nil {
+			panic(err)
+		}
+	}(dbContainer)
+
+	envVars := map[string]string{
+		"DB_HOST": dbContainer.Host,
+		"DB_PORT": dbContainer.Port,
+		"DB_USER": container.GetEnvWithDefault("DB_USER", "testuser"),
+		"DB_PASS": container.GetEnvWithDefault("DB_PASS", "testpassword"),
+		"DB_NAME": container.GetEnvWithDefault("DB_NAME", "testdb"),
+	}
+	if err := container.SetEnv(envVars); err != nil {
+		panic(fmt.Sprintf("Failed to set env vars: %v", err))
+	}
+
+	db := container.SetUpDatabaseConnection()
+	defer func(db *gorm.DB) {
+		err := container.CloseDatabaseConnection(db)
+		if err != nil {
+			panic(err)
+		}
+	}(db)
+
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.EmailChange{}, &entity.AuditEvent{})
+	assert.NoError(t, err)
+
+	userRepo := repository.NewUserRepository(db)
+	jwtService := service.NewJWTService()
+
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
+
+	defer func() {
+		db.Exec("DELETE FROM users WHERE TRUE")
+	}()
+
+	ctx := context.Background()
+	tokenPattern := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+	// requestChange registers a user and requests to change its address to new@example.com, returning the user and
+	// the token of the confirmation link emailed to the new address.
+	requestChange := func(t *testing.T) (entity.User, string) {
+		user, err := userRepo.Register(
+			ctx, nil, entity.User{
+				Name:       "Original Name",
+				Email:      "old@example.com",
+				Password:   "password123",
+				Role:       "user",
+				IsVerified: false,
+			},
+		)
+		assert.NoError(t, err)
+
+		response, err := userService.Update(ctx, dto.UserUpdateRequest{Email: "new@example.com"}, user.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "new@example.com", response.PendingEmail)
+
+		var confirmation entity.EmailOutbox
+		err = db.Where("recipient = ?", "new@example.com").Take(&confirmation).Error
+		assert.NoError(t, err)
+
+		match := tokenPattern.FindStringSubmatch(confirmation.TextBody)
+		if !assert.Len(t, match, 2) {
+			t.FailNow()
+		}
+
+		return user, match[1]
+	}
+
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, user entity.User, token string) string
+		expectedEmail string
+		expectedError error
+	}{
+		{
+			name: "Confirm email change",
+			setup: func(t *testing.T, user entity.User, token string) string {
+				return token
+			},
+			expectedEmail: "new@example.com",
+		},
+		{
+			name: "Unknown token",
+			setup: func(t *testing.T, user entity.User, token string) string {
+				return "unknown-token"
+			},
+			expectedEmail: "old@example.com",
+			expectedError: dto.ErrTokenInvalid,
+		},
+		{
+			name: "Expired token",
+			setup: func(t *testing.T, user entity.User, token string) string {
+				err := db.Model(&entity.EmailChange{}).
+					Where("user_id = ?", user.ID).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error
+				assert.NoError(t, err)
+				return token
+			},
+			expectedEmail: "old@example.com",
+			expectedError: dto.ErrTokenExpired,
+		},
+		{
+			name: "Address taken before confirmation",
+			setup: func(t *testing.T, user entity.User, token string) string {
+				_, err := userRepo.Register(
+					ctx, nil, entity.User{
+						Name:     "Other User",
+						Email:    "new@example.com",
+						Password: "password123",
+						Role:     "user",
+					},
+				)
+				assert.NoError(t, err)
+				return token
+			},
+			expectedEmail: "old@example.com",
+			expectedError: dto.ErrEmailAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				db.Exec("TRUNCATE TABLE users, email_outbox RESTART IDENTITY CASCADE")
+
+				user, token := requestChange(t)
+				token = tt.setup(t, user, token)
+
+				response, err := userService.ConfirmEmailChange(ctx, dto.ConfirmEmailChangeRequest{Token: token})
+
+				var dbUser entity.User
+				assert.NoError(t, db.First(&dbUser, "id = ?", user.ID).Error)
+				assert.Equal(t, tt.expectedEmail, dbUser.Email)
+
+				if tt.expectedError != nil {
+					assert.ErrorIs(t, err, tt.expectedError)
+					assert.False(t, dbUser.IsVerified)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, "new@example.com", response.Email)
+				assert.True(t, response.IsVerified)
+				assert.True(t, dbUser.IsVerified)
+
+				var pending int64
+				db.Model(&entity.EmailChange{}).Where("user_id = ?", user.ID).Count(&pending)
+				assert.Zero(t, pending)
+
+				_, err = userService.ConfirmEmailChange(ctx, dto.ConfirmEmailChangeRequest{Token: token})
+				assert.ErrorIs(t, err, dto.ErrTokenInvalid, "a token can only be used once")
+			},
+		)
+	}
+}
+
+// TestUserService_Delete tests the Delete method of UserService for various scenarios, ensuring users are hidden and
+// scheduled for purging and their refresh tokens are revoked.
 func TestUserService_Delete(t *testing.T) {
 	container.LoadTestEnv()
 
@@ -1186,14 +1475,14 @@ func TestUserService_Delete(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	ctx := context.Background()
 
@@ -1224,6 +1513,13 @@ func TestUserService_Delete(t *testing.T) {
 				assert.Error(t, err)
 				assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
 
+				deleted, err := userRepo.GetUserByIdWithDeleted(ctx, nil, userId)
+				assert.NoError(t, err)
+				assert.Equal(t, constants.ENUM_USER_PENDING_DELETION, deleted.Status)
+				if assert.NotNil(t, deleted.PurgeAt) {
+					assert.WithinDuration(t, time.Now().Add(service.USER_DELETION_GRACE_PERIOD), *deleted.PurgeAt, time.Minute)
+				}
+
 				var count int64
 				db.Model(&entity.RefreshToken{}).Where("user_id = ?", userId).Count(&count)
 				assert.Equal(t, int64(0), count)
@@ -1349,17 +1645,16 @@ func TestUserService_Verify(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
-	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 
 	mockJWTService := &MockJWTService{}
 	mockJWTService.On("GenerateAccessToken", mock.Anything, mock.Anything).Return("mock-access-token")
 	mockJWTService.On("GenerateRefreshToken").Return("mock-refresh-token", time.Now().Add(24*time.Hour))
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, mockJWTService, db)
+	userService := newUserService(db, mockJWTService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM refresh_tokens WHERE TRUE")
@@ -1407,6 +1702,11 @@ func TestUserService_Verify(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotEmpty(t, refreshToken.Token)
 				assert.True(t, refreshToken.ExpiresAt.After(time.Now()))
+
+				var event entity.AuditEvent
+				err = db.Where("user_id = ?", refreshToken.UserID).Take(&event).Error
+				assert.NoError(t, err, "the sign-in must be recorded in the audit log")
+				assert.Equal(t, constants.ENUM_AUDIT_LOGIN, event.Type)
 			},
 		},
 		{
@@ -1538,14 +1838,14 @@ func TestUserService_RefreshToken(t *testing.T) {
 		}
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	defer func() {
 		db.Exec("DELETE FROM refresh_tokens WHERE TRUE")
@@ -1708,14 +2008,14 @@ func TestUserService_RevokeRefreshToken(t *testing.T) {
 		assert.NoError(t, err)
 	}(db)
 
-	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{})
+	err = db.AutoMigrate(&entity.User{}, &entity.RefreshToken{}, &entity.EmailOutbox{}, &entity.AuditEvent{})
 	assert.NoError(t, err)
 
 	userRepo := repository.NewUserRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
 	jwtService := service.NewJWTService()
 
-	userService := service.NewUserService(userRepo, refreshTokenRepo, jwtService, db)
+	userService := newUserService(db, jwtService, storage.NewMemoryDriver())
 
 	ctx := context.Background()
 
@@ -1820,3 +2120,22 @@ func TestUserService_RevokeRefreshToken(t *testing.T) {
 		)
 	}
 }
+
+// newUserService creates a UserService backed by db, with jwtService and files and the repositories, renderer and
+// transaction manager the application provides.
+func newUserService(db *gorm.DB, jwtService service.JWTService, files storage.Driver) service.UserService {
+	return service.NewUserService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		repository.NewEmailOutboxRepository(db),
+		repository.NewUploadRepository(db),
+		repository.NewDataExportRepository(db),
+		repository.NewEmailChangeRepository(db),
+		repository.NewAuditEventRepository(db),
+		jwtService,
+		mailer.NewRenderer(config.NewMailTemplateConfig()),
+		files,
+		repository.NewTxManager(db),
+		config.NewPhoneConfig().DefaultCountryCode,
+	)
+}
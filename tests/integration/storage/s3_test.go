@@ -0,0 +1,123 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+)
+
+// S3DriverIntegrationTestSuite is a test suite for verifying the S3 driver against a MinIO server.
+// It sets up and tears down the MinIO container during the test lifecycle.
+type S3DriverIntegrationTestSuite struct {
+	suite.Suite
+	minioContainer testcontainers.Container
+	driver         *storage.S3Driver
+}
+
+// SetupSuite starts the MinIO container and creates the bucket used by the tests.
+func (suite *S3DriverIntegrationTestSuite) SetupSuite() {
+	ctx := context.Background()
+
+	minioReq := testcontainers.ContainerRequest{
+		Image:        "minio/minio",
+		ExposedPorts: []string{"9000/tcp"},
+		Cmd:          []string{"server", "/data"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     "minioadmin",
+			"MINIO_ROOT_PASSWORD": "minioadmin",
+		},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp"),
+	}
+	minioContainer, err := testcontainers.GenericContainer(
+		ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: minioReq,
+			Started:          true,
+		},
+	)
+	require.NoError(suite.T(), err)
+	suite.minioContainer = minioContainer
+
+	host, err := minioContainer.Host(ctx)
+	require.NoError(suite.T(), err)
+
+	port, err := minioContainer.MappedPort(ctx, "9000")
+	require.NoError(suite.T(), err)
+
+	suite.driver, err = storage.NewS3Driver(
+		&config.S3Config{
+			Endpoint:  fmt.Sprintf("http://%s:%s", host, port.Port()),
+			Region:    "us-east-1",
+			Bucket:    "avatars",
+			AccessKey: "minioadmin",
+			SecretKey: "minioadmin",
+			PathStyle: true,
+		},
+	)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.driver.EnsureBucket(ctx))
+	require.NoError(suite.T(), suite.driver.EnsureBucket(ctx), "creating an existing bucket should succeed")
+}
+
+// TearDownSuite stops the MinIO container.
+func (suite *S3DriverIntegrationTestSuite) TearDownSuite() {
+	timeout := 10 * time.Second
+
+	if suite.minioContainer != nil {
+		_ = suite.minioContainer.Stop(context.Background(), &timeout)
+	}
+}
+
+// TestObjectLifecycle_Integration validates storing, describing, downloading through a presigned URL, listing and
+// deleting an object.
+func (suite *S3DriverIntegrationTestSuite) TestObjectLifecycle_Integration() {
+	ctx := context.Background()
+	key := "profile/nested/an image.png"
+
+	require.NoError(suite.T(), suite.driver.Put(ctx, key, strings.NewReader("png bytes"), "image/png"))
+
+	info, err := suite.driver.Stat(ctx, key)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(len("png bytes")), info.Size)
+	assert.Equal(suite.T(), "image/png", info.ContentType)
+
+	signedURL, err := suite.driver.SignedURL(ctx, key, time.Minute)
+	require.NoError(suite.T(), err)
+
+	resp, err := http.Get(signedURL)
+	require.NoError(suite.T(), err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "png bytes", string(body))
+
+	infos, err := suite.driver.List(ctx, "profile/nested/")
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), infos, 1)
+	assert.Equal(suite.T(), key, infos[0].Key)
+	assert.Equal(suite.T(), int64(len("png bytes")), infos[0].Size)
+
+	require.NoError(suite.T(), suite.driver.Delete(ctx, key))
+
+	_, err = suite.driver.Stat(ctx, key)
+	assert.ErrorIs(suite.T(), err, storage.ErrObjectNotFound)
+	assert.NoError(suite.T(), suite.driver.Delete(ctx, key))
+}
+
+// TestS3DriverIntegrationTestSuite runs the S3 driver integration test suite.
+func TestS3DriverIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(S3DriverIntegrationTestSuite))
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag returns the entity tag of a resource at the given version, quoted as in the ETag header.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// MatchesETag reports whether an If-Match header value matches the entity tag of a resource at the given version.
+// The header may list several entity tags separated by commas, and "*" matches every version. Weak entity tags are
+// compared by their value.
+func MatchesETag(ifMatch string, version int64) bool {
+	etag := ETag(version)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
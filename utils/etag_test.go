@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestETag tests that entity tags are quoted versions.
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"1"`, ETag(1))
+	assert.Equal(t, `"42"`, ETag(42))
+}
+
+// TestMatchesETag tests matching If-Match header values against the entity tag of a version.
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		expected bool
+	}{
+		{name: "same version", ifMatch: `"3"`, expected: true},
+		{name: "other version", ifMatch: `"2"`},
+		{name: "unquoted", ifMatch: `3`},
+		{name: "list", ifMatch: `"1", "3"`, expected: true},
+		{name: "weak", ifMatch: `W/"3"`, expected: true},
+		{name: "any", ifMatch: `*`, expected: true},
+		{name: "empty", ifMatch: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, MatchesETag(tt.ifMatch, 3))
+			},
+		)
+	}
+}
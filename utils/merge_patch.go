@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Caknoooo/go-gin-clean-starter/apperror"
+)
+
+// MERGE_PATCH_CONTENT_TYPE is the media type of JSON merge patch documents.
+const MERGE_PATCH_CONTENT_TYPE = "application/merge-patch+json"
+
+// ErrInvalidMergePatch is returned when a merge patch or the document it is applied to is not valid JSON.
+var ErrInvalidMergePatch = apperror.New(apperror.KIND_VALIDATION, "INVALID_MERGE_PATCH", "invalid merge patch")
+
+// MergePatch applies a JSON merge patch (RFC 7396) to a JSON document and returns the patched document. Members of
+// the patch replace those of the document, null members remove them, and objects are merged recursively. A patch that
+// is not an object replaces the whole document.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target, changes any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, errors.Join(ErrInvalidMergePatch, err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, errors.Join(ErrInvalidMergePatch, err)
+	}
+
+	return json.Marshal(mergePatchValue(target, changes))
+}
+
+// mergePatchValue applies the decoded merge patch patch to the decoded JSON value target.
+func mergePatchValue(target, patch any) any {
+	members, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = mergePatchValue(object[name], value)
+	}
+
+	return object
+}
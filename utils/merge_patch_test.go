@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergePatch tests applying merge patches with the examples of RFC 7396.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		expected string
+		err      error
+	}{
+		{name: "replace member", document: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{name: "add member", document: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		{name: "remove member", document: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		{name: "keep other members", document: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		{name: "replace array", document: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{name: "replace with array", document: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		{
+			name:     "merge nested objects",
+			document: `{"a":{"b":"c"}}`,
+			patch:    `{"a":{"b":"d","c":null}}`,
+			expected: `{"a":{"b":"d"}}`,
+		},
+		{name: "replace non-object", document: `["a"]`, patch: `{"a":"b"}`, expected: `{"a":"b"}`},
+		{name: "replace document", document: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+		{name: "null patch", document: `{"a":"foo"}`, patch: `null`, expected: `null`},
+		{name: "null members are not added", document: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+		{name: "empty patch", document: `{"a":"b"}`, patch: `{}`, expected: `{"a":"b"}`},
+		{name: "invalid patch", document: `{}`, patch: `{"a":`, err: ErrInvalidMergePatch},
+		{name: "invalid document", document: ``, patch: `{}`, err: ErrInvalidMergePatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				result, err := MergePatch([]byte(tt.document), []byte(tt.patch))
+				if tt.err != nil {
+					assert.ErrorIs(t, err, tt.err)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expected, string(result))
+			},
+		)
+	}
+}
@@ -1,14 +1,21 @@
 package utils
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // Response represents a standard structure for API responses.
 // Status indicates the success or failure of the operation.
 // Message provides a human-readable message about the operation.
+// Code is a stable, machine-readable code identifying the failure (optional).
 // Error holds error details when the operation fails (optional).
 // Data contains the result of the operation, if any (optional).
 // Meta includes additional metadata related to the response (optional).
 type Response struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 	Error   any    `json:"error,omitempty"`
 	Data    any    `json:"data,omitempty"`
 	Meta    any    `json:"meta,omitempty"`
@@ -34,3 +41,70 @@ func BuildResponseFailed(message string, err string, data any) Response {
 	}
 	return res
 }
+
+// BuildResponseError constructs and returns a failed Response identified by code, with err describing the failure.
+func BuildResponseError(message string, code string, err string) Response {
+	return Response{
+		Status:  false,
+		Message: message,
+		Code:    code,
+		Error:   err,
+	}
+}
+
+// BuildResponseValidationFailed constructs and returns a failed Response whose error lists the fields of a request
+// that were refused.
+func BuildResponseValidationFailed(message string, code string, errs []FieldError) Response {
+	return Response{
+		Status:  false,
+		Message: message,
+		Code:    code,
+		Error:   errs,
+	}
+}
+
+// SelectFields returns data reduced to the given JSON fields. Data must encode to a JSON object or an array of
+// objects; it is returned unchanged when no fields are given.
+func SelectFields(data any, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(encoded, []byte("null")) {
+		return data, nil
+	}
+
+	pick := func(object map[string]json.RawMessage) map[string]json.RawMessage {
+		picked := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				picked[field] = value
+			}
+		}
+		return picked
+	}
+
+	if bytes.HasPrefix(encoded, []byte("[")) {
+		var objects []map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &objects); err != nil {
+			return nil, err
+		}
+
+		picked := make([]map[string]json.RawMessage, 0, len(objects))
+		for _, object := range objects {
+			picked = append(picked, pick(object))
+		}
+		return picked, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		return nil, err
+	}
+
+	return pick(object), nil
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
@@ -127,6 +128,49 @@ func TestBuildResponseFailed(t *testing.T) {
 	}
 }
 
+// TestBuildResponseError validates that failed responses carry their code next to the message.
+func TestBuildResponseError(t *testing.T) {
+	result := BuildResponseError("failed get user", "USER_NOT_FOUND", "user not found")
+	assert.Equal(
+		t, Response{Status: false, Message: "failed get user", Code: "USER_NOT_FOUND", Error: "user not found"}, result,
+	)
+
+	encoded, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t,
+		`{"status": false, "message": "failed get user", "code": "USER_NOT_FOUND", "error": "user not found"}`,
+		string(encoded),
+	)
+}
+
+// TestBuildResponseValidationFailed validates that the refused fields are returned as the error, encoded as an array.
+func TestBuildResponseValidationFailed(t *testing.T) {
+	errs := []FieldError{
+		{Field: "email", Code: "required", Message: "email is a required field"},
+		{Field: "password", Code: "min", Message: "password is too short", Params: map[string]string{"min": "8"}},
+	}
+
+	result := BuildResponseValidationFailed("failed get data from body", "VALIDATION_FAILED", errs)
+	assert.Equal(
+		t, Response{Status: false, Message: "failed get data from body", Code: "VALIDATION_FAILED", Error: errs}, result,
+	)
+
+	encoded, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t, `{
+			"status": false,
+			"message": "failed get data from body",
+			"code": "VALIDATION_FAILED",
+			"error": [
+				{"field": "email", "code": "required", "message": "email is a required field"},
+				{"field": "password", "code": "min", "message": "password is too short", "params": {"min": "8"}}
+			]
+		}`, string(encoded),
+	)
+}
+
 // TestEmptyObj validates the behavior and properties of an empty struct in Go, including its name, size, and equality.
 func TestEmptyObj(t *testing.T) {
 	var empty struct{}
@@ -135,3 +179,73 @@ func TestEmptyObj(t *testing.T) {
 
 	assert.True(t, reflect.DeepEqual(empty, struct{}{}))
 }
+
+// TestSelectFields validates that SelectFields keeps only the requested JSON fields of objects and arrays of objects.
+func TestSelectFields(t *testing.T) {
+	type item struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+
+	tests := []struct {
+		name     string
+		data     any
+		fields   []string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "array of objects",
+			data:     []item{{ID: "1", Email: "a@example.com", Role: "admin"}, {ID: "2", Email: "b@example.com"}},
+			fields:   []string{"id", "email", "missing"},
+			expected: `[{"email":"a@example.com","id":"1"},{"email":"b@example.com","id":"2"}]`,
+		},
+		{
+			name:     "single object",
+			data:     item{ID: "1", Role: "user"},
+			fields:   []string{"role"},
+			expected: `{"role":"user"}`,
+		},
+		{
+			name:     "no fields",
+			data:     item{ID: "1"},
+			expected: `{"id":"1","email":"","role":""}`,
+		},
+		{
+			name:     "empty array",
+			data:     []item{},
+			fields:   []string{"id"},
+			expected: `[]`,
+		},
+		{
+			name:     "nil",
+			data:     []item(nil),
+			fields:   []string{"id"},
+			expected: `null`,
+		},
+		{
+			name:    "not an object",
+			data:    "text",
+			fields:  []string{"id"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				selected, err := SelectFields(tt.data, tt.fields)
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+
+				assert.NoError(t, err)
+				encoded, err := json.Marshal(selected)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expected, string(encoded))
+			},
+		)
+	}
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	idtranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+const (
+	// VALIDATION_CODE_TYPE is the code of a field whose value has the wrong JSON type.
+	VALIDATION_CODE_TYPE = "type"
+
+	// VALIDATION_CODE_INVALID is the code of a request body that cannot be decoded at all.
+	VALIDATION_CODE_INVALID = "invalid"
+)
+
+type (
+	// FieldError describes why one field of a request was refused. Field is the path of the field by its JSON names,
+	// such as items[0].name, and is empty when the error concerns the whole request. Code is the name of the failed
+	// validation rule, such as required or min, Params holds the argument of the rule, keyed by its code, and Message
+	// is a human-readable explanation in the locale of the request.
+	FieldError struct {
+		Field   string            `json:"field"`
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Params  map[string]string `json:"params,omitempty"`
+	}
+
+	// validationTranslation is a message of the errors that are not reported by the validator, in one locale.
+	validationTranslation struct {
+		locale  string
+		code    string
+		message string
+	}
+)
+
+// validationTranslator holds the locales validation messages are translated to; English is the fallback.
+var validationTranslator = ut.New(en.New(), en.New(), id.New())
+
+// validationTranslations lists the messages of the errors that are not reported by the validator.
+var validationTranslations = []validationTranslation{
+	{locale: "en", code: VALIDATION_CODE_TYPE, message: "{0} must be of type {1}"},
+	{locale: "en", code: VALIDATION_CODE_INVALID, message: "the request body is invalid"},
+	{locale: "id", code: VALIDATION_CODE_TYPE, message: "{0} harus bertipe {1}"},
+	{locale: "id", code: VALIDATION_CODE_INVALID, message: "isi permintaan tidak valid"},
+}
+
+// RegisterValidator makes v name fields by their JSON names, falling back to their form names, and registers the
+// translations of its messages for every locale of the validation translator. The translations are shared by every
+// validator, so it can only be called once; the application registers the validator of gin.
+func RegisterValidator(v *validator.Validate) error {
+	v.RegisterTagNameFunc(fieldName)
+
+	registrations := map[string]func(*validator.Validate, ut.Translator) error{
+		"en": entranslations.RegisterDefaultTranslations,
+		"id": idtranslations.RegisterDefaultTranslations,
+	}
+	for locale, register := range registrations {
+		trans, _ := validationTranslator.GetTranslator(locale)
+		if err := register(v, trans); err != nil {
+			return fmt.Errorf("register %s validation translations: %w", locale, err)
+		}
+	}
+
+	for _, translation := range validationTranslations {
+		trans, _ := validationTranslator.GetTranslator(translation.locale)
+		if err := trans.Add(translation.code, translation.message, false); err != nil {
+			return fmt.Errorf("register %s validation translations: %w", translation.locale, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidationErrors describes why a request could not be bound or validated, in the closest locale to locale, which
+// may be given as an Accept-Language header value. Validation failures are reported per field, a value of the wrong
+// JSON type by the path of its field, and any other error as a single error without a field.
+func ValidationErrors(err error, locale string) []FieldError {
+	trans := findValidationTranslator(locale)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fieldErrors = append(fieldErrors, translateFieldError(fe, trans))
+		}
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		message, _ := trans.T(VALIDATION_CODE_TYPE, typeError.Field, typeError.Type.String())
+		return []FieldError{
+			{
+				Field:   typeError.Field,
+				Code:    VALIDATION_CODE_TYPE,
+				Message: message,
+				Params:  map[string]string{VALIDATION_CODE_TYPE: typeError.Type.String()},
+			},
+		}
+	}
+
+	message, _ := trans.T(VALIDATION_CODE_INVALID)
+	return []FieldError{{Code: VALIDATION_CODE_INVALID, Message: message}}
+}
+
+// translateFieldError converts an error reported by the validator into a FieldError translated with trans.
+func translateFieldError(fe validator.FieldError, trans ut.Translator) FieldError {
+	result := FieldError{
+		Field:   fieldPath(fe.Namespace()),
+		Code:    fe.Tag(),
+		Message: fe.Translate(trans),
+	}
+
+	if result.Message == fe.Error() {
+		result.Message = fmt.Sprintf("%s is invalid", fe.Field())
+	}
+	if fe.Param() != "" {
+		result.Params = map[string]string{fe.Tag(): fe.Param()}
+	}
+
+	return result
+}
+
+// fieldPath returns the path of a field below the validated struct from its namespace, such as items[0].name for
+// Request.items[0].name.
+func fieldPath(namespace string) string {
+	if _, path, found := strings.Cut(namespace, "."); found {
+		return path
+	}
+
+	return namespace
+}
+
+// fieldName returns the name of a struct field in validation errors: its JSON name, else its form name, else its Go
+// name.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// findValidationTranslator returns the translator of the first language of an Accept-Language value that validation
+// messages are translated to, trying each tag and then its primary language, or the English translator.
+func findValidationTranslator(locale string) ut.Translator {
+	var candidates []string
+	for _, tag := range strings.Split(locale, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "-", "_"))
+		if tag == "" {
+			continue
+		}
+
+		candidates = append(candidates, tag)
+		if primary, _, found := strings.Cut(tag, "_"); found {
+			candidates = append(candidates, primary)
+		}
+	}
+
+	trans, _ := validationTranslator.FindTranslator(candidates...)
+	return trans
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// validationTestItem is a nested struct validated by the validation tests.
+	validationTestItem struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	// validationTestRequest is a request validated by the validation tests.
+	validationTestRequest struct {
+		Email    string               `json:"email" binding:"required,email"`
+		Password string               `json:"password" binding:"required,min=8"`
+		Age      int                  `json:"age"`
+		Image    string               `form:"image" binding:"required"`
+		Code     string               `json:"code" binding:"omitempty,never"`
+		Items    []validationTestItem `json:"items" binding:"dive"`
+	}
+)
+
+// validationTestValidator validates with the binding tags and is registered once, as translations are shared.
+var validationTestValidator = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	if err := v.RegisterValidation("never", func(validator.FieldLevel) bool { return false }); err != nil {
+		panic(err)
+	}
+	if err := RegisterValidator(v); err != nil {
+		panic(err)
+	}
+	return v
+}()
+
+// TestValidationErrors tests describing validation, type and decoding errors by field, code and translated message.
+func TestValidationErrors(t *testing.T) {
+	valid := validationTestRequest{Email: "jane@example.com", Password: "password123", Image: "avatar.png"}
+
+	tests := []struct {
+		name     string
+		err      func() error
+		locale   string
+		expected []FieldError
+	}{
+		{
+			name: "fields are named by their JSON or form names",
+			err: func() error {
+				return validationTestValidator.Struct(validationTestRequest{Email: "jane", Password: "short"})
+			},
+			expected: []FieldError{
+				{Field: "email", Code: "email", Message: "email must be a valid email address"},
+				{
+					Field:   "password",
+					Code:    "min",
+					Message: "password must be at least 8 characters in length",
+					Params:  map[string]string{"min": "8"},
+				},
+				{Field: "image", Code: "required", Message: "image is a required field"},
+			},
+		},
+		{
+			name: "nested fields are named by their path",
+			err: func() error {
+				req := valid
+				req.Items = []validationTestItem{{Name: "first"}, {}}
+				return validationTestValidator.Struct(req)
+			},
+			expected: []FieldError{
+				{Field: "items[1].name", Code: "required", Message: "name is a required field"},
+			},
+		},
+		{
+			name:   "messages are translated to the requested language",
+			locale: "id-ID,id;q=0.9,en;q=0.8",
+			err: func() error {
+				req := valid
+				req.Image = ""
+				return validationTestValidator.Struct(req)
+			},
+			expected: []FieldError{{Field: "image", Code: "required", Message: "image wajib diisi"}},
+		},
+		{
+			name:   "unknown languages fall back to English",
+			locale: "fr",
+			err: func() error {
+				req := valid
+				req.Image = ""
+				return validationTestValidator.Struct(req)
+			},
+			expected: []FieldError{{Field: "image", Code: "required", Message: "image is a required field"}},
+		},
+		{
+			name: "rules without a translation get a generic message",
+			err: func() error {
+				req := valid
+				req.Code = "abc"
+				return validationTestValidator.Struct(req)
+			},
+			expected: []FieldError{{Field: "code", Code: "never", Message: "code is invalid"}},
+		},
+		{
+			name: "values of the wrong type",
+			err: func() error {
+				var req validationTestRequest
+				return json.Unmarshal([]byte(`{"age":"old"}`), &req)
+			},
+			expected: []FieldError{
+				{
+					Field:   "age",
+					Code:    VALIDATION_CODE_TYPE,
+					Message: "age must be of type int",
+					Params:  map[string]string{"type": "int"},
+				},
+			},
+		},
+		{
+			name:   "malformed bodies",
+			locale: "id",
+			err: func() error {
+				var req validationTestRequest
+				return json.Unmarshal([]byte(`{"email":`), &req)
+			},
+			expected: []FieldError{{Code: VALIDATION_CODE_INVALID, Message: "isi permintaan tidak valid"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := tt.err()
+				require.Error(t, err)
+				assert.Equal(t, tt.expected, ValidationErrors(err, tt.locale))
+			},
+		)
+	}
+}